@@ -0,0 +1,203 @@
+// Package anonymize deterministically pseudonymizes hostnames, IP addresses
+// and user names found in syslog messages, so corpora can be shared without
+// leaking data.
+// Pseudonyms are derived from a keyed hash: the same value always gets the
+// same pseudonym for a given key, keeping messages correlated.
+package anonymize
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"regexp"
+
+	"github.com/jeromer/syslogparser"
+	"github.com/jeromer/syslogparser/parsercommon"
+)
+
+var (
+	ipv4Re = regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`)
+	ipv6Re = regexp.MustCompile(`[0-9A-Fa-f]{0,4}(?::[0-9A-Fa-f]{0,4}){2,7}`)
+
+	// user=alice, ruser=alice, logname=alice, USER=alice...
+	userKeyRe = regexp.MustCompile(`(?i)\b((?:r|e)?user=|logname=|acct=)("?)([^\s";,]+)`)
+	// sshd: Failed password for (invalid user) alice from ...
+	userForRe = regexp.MustCompile(`\b(for (?:invalid user |illegal user )?)(\S+)( from )`)
+	// sudo: alice : TTY=pts/0 ; PWD=/home/alice ; USER=root ; COMMAND=/bin/ls
+	// at the start of the content or following the tag in raw messages
+	userSudoRe = regexp.MustCompile(`(^|: )(\s*)(\S+)( : TTY=)`)
+	// PWD=/home/alice
+	userHomeRe = regexp.MustCompile(`(/home/)([^/\s";,]+)`)
+	// pam_unix: session opened for user alice
+	userPamRe = regexp.MustCompile(`\b(for user |by user )([^\s(]+)`)
+	// pam_unix: session opened for user root by alice(uid=0)
+	userPamByRe = regexp.MustCompile(`\b(by )([^\s(]+)(\(uid=)`)
+)
+
+type Anonymizer struct {
+	key []byte
+}
+
+func NewAnonymizer(key []byte) *Anonymizer {
+	return &Anonymizer{
+		key: key,
+	}
+}
+
+// Returns the pseudonym of a hostname. IP addresses are kept as IP addresses
+// of the same family.
+func (a *Anonymizer) Hostname(h string) string {
+	if h == "" || parsercommon.IsNilString(h) {
+		return h
+	}
+
+	if ip := net.ParseIP(h); ip != nil {
+		return a.IP(ip)
+	}
+
+	return "host-" + hex.EncodeToString(a.sum("hostname", h)[:6])
+}
+
+// Returns the pseudonym of an IP address: an address of 10.0.0.0/8 for IPv4
+// and of fd00::/8 for IPv6.
+func (a *Anonymizer) IP(ip net.IP) string {
+	sum := a.sum("ip", ip.String())
+
+	if ip4 := ip.To4(); ip4 != nil {
+		return net.IP{10, sum[0], sum[1], sum[2]}.String()
+	}
+
+	ip6 := make(net.IP, net.IPv6len)
+	ip6[0] = 0xfd
+	copy(ip6[1:], sum)
+
+	return ip6.String()
+}
+
+// Returns the pseudonym of a user name
+func (a *Anonymizer) User(u string) string {
+	return "user-" + hex.EncodeToString(a.sum("user", u)[:6])
+}
+
+// Replaces IP addresses and user names found in free form text
+func (a *Anonymizer) Text(s string) string {
+	s = ipv4Re.ReplaceAllStringFunc(s, a.replaceIP)
+	s = ipv6Re.ReplaceAllStringFunc(s, a.replaceIP)
+
+	s = a.replaceUser(s, userKeyRe, 3)
+	s = a.replaceUser(s, userForRe, 2)
+	s = a.replaceUser(s, userSudoRe, 3)
+	s = a.replaceUser(s, userHomeRe, 2)
+	s = a.replaceUser(s, userPamRe, 2)
+	s = a.replaceUser(s, userPamByRe, 2)
+
+	return s
+}
+
+// Returns a copy of parts where the hostname is pseudonymized and IP
+// addresses and user names are replaced in every string field.
+func (a *Anonymizer) Parts(parts syslogparser.LogParts) syslogparser.LogParts {
+	hostname, _ := parts["hostname"].(string)
+	hostRe := hostnameRegexp(hostname)
+
+	anonymized := make(syslogparser.LogParts, len(parts))
+
+	for k, v := range parts {
+		s, ok := v.(string)
+		if !ok {
+			anonymized[k] = v
+			continue
+		}
+
+		if k == "hostname" {
+			anonymized[k] = a.Hostname(s)
+			continue
+		}
+
+		anonymized[k] = a.text(s, hostname, hostRe)
+	}
+
+	return anonymized
+}
+
+// Returns a pseudonymized copy of a raw message. The message is parsed to
+// find its hostname which is replaced everywhere in the message, along with
+// IP addresses and user names, so the result is consistent with Parts().
+func (a *Anonymizer) Raw(buff []byte) []byte {
+	hostname := findHostname(buff)
+
+	return []byte(
+		a.text(string(buff), hostname, hostnameRegexp(hostname)),
+	)
+}
+
+func (a *Anonymizer) text(s string, hostname string, hostRe *regexp.Regexp) string {
+	if hostRe != nil {
+		s = hostRe.ReplaceAllString(s, "${1}"+a.Hostname(hostname))
+	}
+
+	return a.Text(s)
+}
+
+func (a *Anonymizer) replaceIP(s string) string {
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return s
+	}
+
+	return a.IP(ip)
+}
+
+func (a *Anonymizer) replaceUser(s string, re *regexp.Regexp, group int) string {
+	return re.ReplaceAllStringFunc(s, func(m string) string {
+		sub := re.FindStringSubmatch(m)
+
+		result := ""
+		for i := 1; i < len(sub); i++ {
+			if i == group {
+				result += a.User(sub[i])
+				continue
+			}
+
+			result += sub[i]
+		}
+
+		return result
+	})
+}
+
+func (a *Anonymizer) sum(kind string, value string) []byte {
+	h := hmac.New(sha256.New, a.key)
+	h.Write([]byte(kind))
+	h.Write([]byte{0})
+	h.Write([]byte(value))
+
+	return h.Sum(nil)
+}
+
+func hostnameRegexp(hostname string) *regexp.Regexp {
+	// IP addresses are replaced with the other IP addresses
+	if hostname == "" || hostname == "-" || net.ParseIP(hostname) != nil {
+		return nil
+	}
+
+	return regexp.MustCompile(
+		`(^|[^A-Za-z0-9_.-])` + regexp.QuoteMeta(hostname) + `\b`,
+	)
+}
+
+func findHostname(buff []byte) string {
+	p, _, err := syslogparser.NewAutoParser(buff)
+	if err != nil {
+		return ""
+	}
+
+	if p.Parse() != nil {
+		return ""
+	}
+
+	h, _ := p.Dump()["hostname"].(string)
+
+	return h
+}
@@ -0,0 +1,186 @@
+package anonymize
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/jeromer/syslogparser"
+	"github.com/jeromer/syslogparser/rfc3164"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostname(t *testing.T) {
+	a := NewAnonymizer([]byte("secret"))
+
+	obtained := a.Hostname("mymachine")
+	require.True(t, strings.HasPrefix(obtained, "host-"))
+	require.Len(t, obtained, len("host-")+12)
+	require.Equal(t, obtained, a.Hostname("mymachine"))
+	require.NotEqual(t, obtained, a.Hostname("othermachine"))
+	require.NotEqual(t, obtained, NewAnonymizer([]byte("other")).Hostname("mymachine"))
+
+	require.Equal(t, "", a.Hostname(""))
+	require.Equal(t, "-", a.Hostname("-"))
+
+	ip := net.ParseIP(a.Hostname("192.168.65.1"))
+	require.NotNil(t, ip)
+	require.Equal(t, byte(10), ip.To4()[0])
+}
+
+func TestIP(t *testing.T) {
+	a := NewAnonymizer([]byte("secret"))
+
+	ip4 := net.ParseIP(a.IP(net.ParseIP("192.168.65.1")))
+	require.NotNil(t, ip4.To4())
+	require.Equal(t, byte(10), ip4.To4()[0])
+
+	ip6 := net.ParseIP(a.IP(net.ParseIP("2001:db8::1")))
+	require.Nil(t, ip6.To4())
+	require.Equal(t, byte(0xfd), ip6[0])
+}
+
+func TestText(t *testing.T) {
+	a := NewAnonymizer([]byte("secret"))
+
+	ip := a.IP(net.ParseIP("1.2.3.4"))
+	ip6 := a.IP(net.ParseIP("2001:db8::1"))
+	root := a.User("root")
+	alice := a.User("alice")
+
+	testCases := []struct {
+		description string
+		input       string
+		expected    string
+	}{
+		{
+			description: "sshd failed password",
+			input:       "Failed password for root from 1.2.3.4 port 22 ssh2",
+			expected:    "Failed password for " + root + " from " + ip + " port 22 ssh2",
+		},
+		{
+			description: "sshd invalid user",
+			input:       "Failed password for invalid user alice from 2001:db8::1 port 22 ssh2",
+			expected:    "Failed password for invalid user " + alice + " from " + ip6 + " port 22 ssh2",
+		},
+		{
+			description: "sudo",
+			input:       "alice : TTY=pts/0 ; PWD=/home ; USER=root ; COMMAND=/bin/ls",
+			expected:    alice + " : TTY=pts/0 ; PWD=/home ; USER=" + root + " ; COMMAND=/bin/ls",
+		},
+		{
+			description: "sudo home directory",
+			input:       "  alice : TTY=pts/0 ; PWD=/home/alice/src ; USER=root ; COMMAND=/bin/ls",
+			expected:    "  " + alice + " : TTY=pts/0 ; PWD=/home/" + alice + "/src ; USER=" + root + " ; COMMAND=/bin/ls",
+		},
+		{
+			description: "pam",
+			input:       "pam_unix(sudo:session): session opened for user root by alice(uid=0)",
+			expected:    "pam_unix(sudo:session): session opened for user " + root + " by " + alice + "(uid=0)",
+		},
+		{
+			description: "pam by user",
+			input:       "pam_unix(su:session): session opened for user root by user alice",
+			expected:    "pam_unix(su:session): session opened for user " + root + " by user " + alice,
+		},
+		{
+			description: "key value",
+			input:       `logname= uid=0 ruser="alice" rhost=1.2.3.4`,
+			expected:    `logname= uid=0 ruser="` + alice + `" rhost=` + ip,
+		},
+		{
+			description: "nothing to replace",
+			input:       "Oct 11 22:14:15 started in 10.5 seconds",
+			expected:    "Oct 11 22:14:15 started in 10.5 seconds",
+		},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(
+			t, tc.expected, a.Text(tc.input), tc.description,
+		)
+	}
+}
+
+func TestPartsAndRaw(t *testing.T) {
+	a := NewAnonymizer([]byte("secret"))
+
+	buff := []byte("<34>Oct 11 22:14:15 mymachine sshd[42]: Failed password for root from 1.2.3.4 port 22 on mymachine")
+
+	p := rfc3164.NewParser(buff)
+	err := p.Parse()
+	require.Nil(t, err)
+
+	parts := p.Dump()
+	anonymized := a.Parts(parts)
+
+	host := a.Hostname("mymachine")
+	content := "Failed password for " + a.User("root") + " from " + a.IP(net.ParseIP("1.2.3.4")) + " port 22 on " + host
+
+	require.Equal(t, host, anonymized["hostname"])
+	require.Equal(t, content, anonymized["content"])
+	require.Equal(t, "sshd", anonymized["tag"])
+	require.Equal(t, parts["timestamp"], anonymized["timestamp"])
+	require.Equal(t, parts["priority"], anonymized["priority"])
+
+	// original parts are left untouched
+	require.Equal(t, "mymachine", parts["hostname"])
+
+	raw := a.Raw(buff)
+	require.Equal(
+		t,
+		"<34>Oct 11 22:14:15 "+host+" sshd[42]: "+content,
+		string(raw),
+	)
+
+	p = rfc3164.NewParser(raw)
+	err = p.Parse()
+	require.Nil(t, err)
+
+	reparsed := p.Dump()
+	for _, k := range []string{"hostname", "tag", "content"} {
+		require.Equal(t, anonymized[k], reparsed[k], k)
+	}
+}
+
+func TestRawSudo(t *testing.T) {
+	a := NewAnonymizer([]byte("secret"))
+
+	buff := []byte("<85>Oct 11 22:14:15 mymachine sudo:   alice : TTY=pts/0 ; PWD=/home/alice ; USER=root ; COMMAND=/bin/ls")
+
+	alice := a.User("alice")
+	content := "  " + alice + " : TTY=pts/0 ; PWD=/home/" + alice + " ; USER=" + a.User("root") + " ; COMMAND=/bin/ls"
+
+	raw := a.Raw(buff)
+	require.Equal(
+		t,
+		"<85>Oct 11 22:14:15 "+a.Hostname("mymachine")+" sudo: "+content,
+		string(raw),
+	)
+
+	p := rfc3164.NewParser(buff)
+	require.Nil(t, p.Parse())
+
+	anonymized := a.Parts(p.Dump())
+
+	p = rfc3164.NewParser(raw)
+	require.Nil(t, p.Parse())
+
+	require.Equal(t, anonymized["content"], p.Dump()["content"])
+}
+
+func TestRawUnparsable(t *testing.T) {
+	a := NewAnonymizer([]byte("secret"))
+
+	raw := a.Raw([]byte("user=alice connected from 1.2.3.4"))
+
+	require.Equal(
+		t,
+		"user="+a.User("alice")+" connected from "+a.IP(net.ParseIP("1.2.3.4")),
+		string(raw),
+	)
+
+	require.Equal(
+		t, syslogparser.LogParts{}, a.Parts(syslogparser.LogParts{}),
+	)
+}
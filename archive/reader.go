@@ -0,0 +1,286 @@
+package archive
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidIndex = errors.New("Invalid index line")
+)
+
+// Returns a stream decompressing data read from r
+type Decompressor func(r io.Reader) (io.ReadCloser, error)
+
+func GzipDecompressor(r io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(r)
+}
+
+type Query struct {
+	from        time.Time
+	to          time.Time
+	hostname    string
+	maxSeverity int
+	decompress  Decompressor
+	dataExt     string
+}
+
+type indexEntry struct {
+	timestamp time.Time
+	offset    int64
+	length    int64
+	severity  int
+	hostname  string
+}
+
+// Creates a query matching every record
+func NewQuery() *Query {
+	return &Query{
+		maxSeverity: -1,
+		decompress:  GzipDecompressor,
+		dataExt:     DATA_EXT,
+	}
+}
+
+// Matches records timestamped in [from, to), a zero value leaves the
+// corresponding bound open
+func (q *Query) WithTimeRange(from time.Time, to time.Time) {
+	q.from = from
+	q.to = to
+}
+
+// Matches records from hostname only
+func (q *Query) WithHostname(hostname string) {
+	q.hostname = hostname
+}
+
+// Matches records with a severity of s or more severe, records without a
+// severity are excluded
+func (q *Query) WithMaxSeverity(s int) {
+	q.maxSeverity = s
+}
+
+// Reads data files named with ext, written using Writer.WithCompressor(),
+// decompressing them with d
+func (q *Query) WithDecompressor(ext string, d Decompressor) {
+	q.dataExt = ext
+	q.decompress = d
+}
+
+func (q *Query) match(e indexEntry) bool {
+	if !q.from.IsZero() && e.timestamp.Before(q.from) {
+		return false
+	}
+
+	if !q.to.IsZero() && !e.timestamp.Before(q.to) {
+		return false
+	}
+
+	if q.hostname != "" && e.hostname != q.hostname {
+		return false
+	}
+
+	if q.maxSeverity >= 0 && (e.severity < 0 || e.severity > q.maxSeverity) {
+		return false
+	}
+
+	return true
+}
+
+// Calls fn with the JSON encoding of every record stored in dir matching
+// q, in the order they were written. Only the index is inspected to
+// select records and data files without any match are not opened.
+// The record slice is only valid until fn returns.
+// The last file may be written while searching, records which did not reach
+// it yet are skipped.
+func Search(dir string, q *Query, fn func(record []byte) error) error {
+	indexes, err := filepath.Glob(filepath.Join(dir, "*"+INDEX_EXT))
+	if err != nil {
+		return err
+	}
+
+	// file names start with their creation time
+	sort.Strings(indexes)
+
+	for i, index := range indexes {
+		entries, err := readIndex(index, q)
+		if err != nil {
+			return err
+		}
+
+		if len(entries) == 0 {
+			continue
+		}
+
+		err = readRecords(
+			strings.TrimSuffix(index, INDEX_EXT)+q.dataExt,
+			q.decompress,
+			entries,
+			i == len(indexes)-1,
+			fn,
+		)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func readIndex(name string, q *Query) ([]indexEntry, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	var entries []indexEntry
+
+	r := bufio.NewReader(f)
+
+	for {
+		line, err := r.ReadString('\n')
+		if err == io.EOF {
+			// XXX : a line without LF is being written, ignore it
+			return entries, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := parseIndexLine(line[:len(line)-1])
+		if err != nil {
+			return nil, err
+		}
+
+		if q.match(e) {
+			entries = append(entries, e)
+		}
+	}
+}
+
+func parseIndexLine(line string) (indexEntry, error) {
+	e := indexEntry{}
+
+	fields := strings.Split(line, "\t")
+	if len(fields) != 5 {
+		return e, ErrInvalidIndex
+	}
+
+	dot := strings.IndexByte(fields[0], '.')
+	if dot < 0 {
+		return e, ErrInvalidIndex
+	}
+
+	sec, err := strconv.ParseInt(fields[0][:dot], 10, 64)
+	if err != nil {
+		return e, ErrInvalidIndex
+	}
+
+	nsec, err := strconv.ParseInt(fields[0][dot+1:], 10, 64)
+	if err != nil {
+		return e, ErrInvalidIndex
+	}
+
+	e.timestamp = time.Unix(sec, nsec)
+
+	e.offset, err = strconv.ParseInt(fields[1], 10, 64)
+	if err != nil || e.offset < 0 {
+		return e, ErrInvalidIndex
+	}
+
+	e.length, err = strconv.ParseInt(fields[2], 10, 64)
+	if err != nil || e.length <= 0 {
+		return e, ErrInvalidIndex
+	}
+
+	e.severity, err = strconv.Atoi(fields[3])
+	if err != nil {
+		return e, ErrInvalidIndex
+	}
+
+	e.hostname = fields[4]
+
+	return e, nil
+}
+
+// When live is set the file may be written, reaching its end before the last
+// entry is not an error
+func readRecords(name string, decompress Decompressor, entries []indexEntry, live bool, fn func([]byte) error) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	truncated := func(err error) bool {
+		return live && (err == io.EOF || err == io.ErrUnexpectedEOF)
+	}
+
+	gz, err := decompress(f)
+	if err != nil {
+		if truncated(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	defer gz.Close()
+
+	var pos int64
+	var buff []byte
+
+	for _, e := range entries {
+		if e.offset < pos {
+			return ErrInvalidIndex
+		}
+
+		// compressed streams can not seek, skip the records in between
+		_, err = io.CopyN(ioutil.Discard, gz, e.offset-pos)
+		if err != nil {
+			if truncated(err) {
+				return nil
+			}
+
+			return err
+		}
+
+		if int64(cap(buff)) < e.length {
+			buff = make([]byte, e.length)
+		}
+
+		buff = buff[:e.length]
+
+		_, err = io.ReadFull(gz, buff)
+		if err != nil {
+			if truncated(err) {
+				return nil
+			}
+
+			return err
+		}
+
+		pos = e.offset + e.length
+
+		err = fn(buff[:len(buff)-1])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
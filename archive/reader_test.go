@@ -0,0 +1,219 @@
+package archive
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jeromer/syslogparser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "syslogparser")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	w, err := NewWriter(dir)
+	require.Nil(t, err)
+
+	w.WithMaxSize(250)
+
+	for i := 0; i < 10; i++ {
+		require.Nil(t, w.Write(testParts(i)))
+	}
+
+	require.Nil(t, w.Write(syslogparser.LogParts{
+		"timestamp": time.Date(2003, time.October, 11, 22, 14, 0, 0, time.UTC),
+		"hostname":  "host0",
+	}))
+
+	require.Nil(t, w.Close())
+
+	at := func(sec int) time.Time {
+		return time.Date(2003, time.October, 11, 22, 14, sec, 0, time.UTC)
+	}
+
+	testCases := []struct {
+		description string
+		query       func(q *Query)
+		expected    []string
+	}{
+		{
+			description: "everything",
+			query:       func(q *Query) {},
+			expected: []string{
+				"host0", "host1", "host2", "host3", "host4",
+				"host5", "host6", "host7", "host8", "host9", "host0",
+			},
+		},
+		{
+			description: "time range",
+			query: func(q *Query) {
+				q.WithTimeRange(at(3), at(6))
+			},
+			expected: []string{"host3", "host4", "host5"},
+		},
+		{
+			description: "open time range",
+			query: func(q *Query) {
+				q.WithTimeRange(at(8), time.Time{})
+			},
+			expected: []string{"host8", "host9"},
+		},
+		{
+			description: "hostname",
+			query: func(q *Query) {
+				q.WithHostname("host0")
+			},
+			expected: []string{"host0", "host0"},
+		},
+		{
+			description: "severity",
+			query: func(q *Query) {
+				q.WithMaxSeverity(1)
+			},
+			expected: []string{"host0", "host1", "host8", "host9"},
+		},
+		{
+			description: "combined",
+			query: func(q *Query) {
+				q.WithTimeRange(at(5), at(10))
+				q.WithMaxSeverity(1)
+			},
+			expected: []string{"host8", "host9"},
+		},
+		{
+			description: "no match",
+			query: func(q *Query) {
+				q.WithHostname("unknown")
+			},
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		q := NewQuery()
+		tc.query(q)
+
+		var hostnames []string
+
+		err := Search(dir, q, func(record []byte) error {
+			var parts map[string]interface{}
+
+			err := json.Unmarshal(record, &parts)
+			if err != nil {
+				return err
+			}
+
+			hostnames = append(hostnames, parts["hostname"].(string))
+
+			return nil
+		})
+
+		require.Nil(t, err, tc.description)
+		require.Equal(t, tc.expected, hostnames, tc.description)
+	}
+}
+
+func TestSearchWhileWriting(t *testing.T) {
+	dir, err := ioutil.TempDir("", "syslogparser")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	w, err := NewWriter(dir)
+	require.Nil(t, err)
+	defer w.Close()
+
+	search := func() []string {
+		var hostnames []string
+
+		err := Search(dir, NewQuery(), func(record []byte) error {
+			var parts map[string]interface{}
+
+			err := json.Unmarshal(record, &parts)
+			if err != nil {
+				return err
+			}
+
+			hostnames = append(hostnames, parts["hostname"].(string))
+
+			return nil
+		})
+
+		require.Nil(t, err)
+
+		return hostnames
+	}
+
+	require.Nil(t, w.Write(testParts(0)))
+	require.Nil(t, w.Write(testParts(1)))
+
+	// nothing flushed yet
+	require.Nil(t, search())
+
+	require.Nil(t, w.Flush())
+	require.Nil(t, w.Write(testParts(2)))
+
+	require.Equal(t, []string{"host0", "host1"}, search())
+
+	// index pointing past the data flushed so far
+	f, err := os.OpenFile(listFiles(t, dir, INDEX_EXT)[0], os.O_APPEND|os.O_WRONLY, 0644)
+	require.Nil(t, err)
+
+	_, err = f.WriteString("1065910455.000000000\t100000\t20\t-1\thost9\n")
+	require.Nil(t, err)
+	require.Nil(t, f.Close())
+
+	require.Equal(t, []string{"host0", "host1"}, search())
+}
+
+func TestSearchInvalidIndex(t *testing.T) {
+	dir, err := ioutil.TempDir("", "syslogparser")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	err = ioutil.WriteFile(
+		filepath.Join(dir, "syslog"+INDEX_EXT), []byte("foo\tbar\n"), 0644,
+	)
+
+	require.Nil(t, err)
+
+	err = Search(dir, NewQuery(), func(record []byte) error {
+		return nil
+	})
+
+	require.Equal(t, ErrInvalidIndex, err)
+}
+
+func TestParseIndexLine(t *testing.T) {
+	e, err := parseIndexLine("1065910455.003000000\t12\t34\t5\thost")
+	require.Nil(t, err)
+
+	require.Equal(
+		t,
+		indexEntry{
+			timestamp: time.Unix(1065910455, 3000000),
+			offset:    12,
+			length:    34,
+			severity:  5,
+			hostname:  "host",
+		},
+		e,
+	)
+
+	for _, line := range []string{
+		"",
+		"1065910455\t12\t34\t5\thost",
+		"1065910455.0\t-1\t34\t5\thost",
+		"1065910455.0\t12\t0\t5\thost",
+		"1065910455.0\t12\t34\tx\thost",
+		"1065910455.0\t12\t34\t5",
+	} {
+		_, err := parseIndexLine(line)
+		require.Equal(t, ErrInvalidIndex, err, line)
+	}
+}
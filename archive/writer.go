@@ -0,0 +1,312 @@
+// Package archive stores parsed messages in size and time rotated, gzip
+// compressed NDJSON files, along with an index allowing to search them
+// without decoding every record.
+//
+// Every data file NAME.ndjson.gz has a NAME.idx index with one line per
+// record:
+//
+//	timestamp TAB offset TAB length TAB severity TAB hostname
+//
+// where timestamp is the unix time of the message as seconds.nanoseconds,
+// offset and length locate the record, including its trailing LF, in the
+// uncompressed data.
+//
+// Records are encoded as JSON and compressed with gzip by default, see
+// WithEncoder() and WithCompressor().
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jeromer/syslogparser"
+)
+
+const (
+	DATA_EXT  = ".ndjson.gz"
+	INDEX_EXT = ".idx"
+
+	// default maximum uncompressed size of a data file
+	MAX_FILE_SIZE = 64 * 1024 * 1024
+	// default maximum age of a data file
+	MAX_FILE_AGE = 24 * time.Hour
+
+	// size of the index lines kept in memory before both files are flushed
+	MAX_PENDING_INDEX = 64 * 1024
+)
+
+// Encodes a record, the writer appends the LF ending it
+type Encoder func(parts syslogparser.LogParts) ([]byte, error)
+
+// Compressed stream written to a data file. Flush() writes buffered data to
+// the file without closing the stream.
+type CompressWriter interface {
+	io.WriteCloser
+	Flush() error
+}
+
+// Returns a stream compressing data written to w
+type Compressor func(w io.Writer) CompressWriter
+
+// Encodes parts as JSON following the LogParts schema
+func JSONEncoder(parts syslogparser.LogParts) ([]byte, error) {
+	return json.Marshal(parts)
+}
+
+func GzipCompressor(w io.Writer) CompressWriter {
+	return gzip.NewWriter(w)
+}
+
+type Writer struct {
+	dir      string
+	maxSize  int64
+	maxAge   time.Duration
+	now      func() time.Time
+	encode   Encoder
+	compress Compressor
+	dataExt  string
+
+	data   *os.File
+	gz     CompressWriter
+	index  *os.File
+	offset int64
+	opened time.Time
+	seq    int
+
+	// index lines of records which may not have reached the data file yet,
+	// written once it has been flushed so the index never points past it
+	pending bytes.Buffer
+}
+
+// Creates a writer storing files in dir, which is created if needed
+func NewWriter(dir string) (*Writer, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Writer{
+		dir:      dir,
+		maxSize:  MAX_FILE_SIZE,
+		maxAge:   MAX_FILE_AGE,
+		now:      time.Now,
+		encode:   JSONEncoder,
+		compress: GzipCompressor,
+		dataExt:  DATA_EXT,
+	}, nil
+}
+
+// Rotates files once their uncompressed size reaches n bytes
+func (w *Writer) WithMaxSize(n int64) {
+	w.maxSize = n
+}
+
+// Rotates files once they have been opened for d
+func (w *Writer) WithMaxAge(d time.Duration) {
+	w.maxAge = d
+}
+
+// Encodes records with enc instead of JSONEncoder(). Records must not
+// contain LF for the data files to remain readable line by line.
+func (w *Writer) WithEncoder(enc Encoder) {
+	w.encode = enc
+}
+
+// Compresses data files with c instead of GzipCompressor(), naming them
+// with ext instead of DATA_EXT. Search them using Query.WithDecompressor().
+func (w *Writer) WithCompressor(ext string, c Compressor) {
+	w.dataExt = ext
+	w.compress = c
+}
+
+// Appends a record to the current file, rotating it if needed
+func (w *Writer) Write(parts syslogparser.LogParts) error {
+	record, err := w.encode(parts)
+	if err != nil {
+		return err
+	}
+
+	record = append(record, '\n')
+
+	if w.data != nil && w.shouldRotate(int64(len(record))) {
+		err = w.Rotate()
+		if err != nil {
+			return err
+		}
+	}
+
+	if w.data == nil {
+		err = w.open()
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = w.gz.Write(record)
+	if err != nil {
+		return err
+	}
+
+	ts := indexTimestamp(parts, w.now())
+
+	_, err = fmt.Fprintf(
+		&w.pending, "%d.%09d\t%d\t%d\t%d\t%s\n",
+		ts.Unix(),
+		ts.Nanosecond(),
+		w.offset,
+		len(record),
+		indexSeverity(parts),
+		indexHostname(parts),
+	)
+
+	if err != nil {
+		return err
+	}
+
+	w.offset += int64(len(record))
+
+	if w.pending.Len() >= MAX_PENDING_INDEX {
+		return w.Flush()
+	}
+
+	return nil
+}
+
+// Flushes buffered records to the current files, the data file first
+func (w *Writer) Flush() error {
+	if w.data == nil {
+		return nil
+	}
+
+	err := w.gz.Flush()
+	if err != nil {
+		return err
+	}
+
+	return w.flushIndex()
+}
+
+// Closes the current files, the next record will be written to new ones
+func (w *Writer) Rotate() error {
+	if w.data == nil {
+		return nil
+	}
+
+	errs := []error{
+		w.gz.Close(),
+		w.data.Close(),
+	}
+
+	// records may be missing from the data file otherwise
+	if errs[0] == nil && errs[1] == nil {
+		errs = append(errs, w.flushIndex())
+	}
+
+	errs = append(errs, w.index.Close())
+
+	w.data = nil
+	w.gz = nil
+	w.index = nil
+	w.pending.Reset()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w *Writer) Close() error {
+	return w.Rotate()
+}
+
+func (w *Writer) shouldRotate(n int64) bool {
+	if w.offset > 0 && w.offset+n > w.maxSize {
+		return true
+	}
+
+	return w.maxAge > 0 && w.now().Sub(w.opened) >= w.maxAge
+}
+
+func (w *Writer) flushIndex() error {
+	_, err := w.index.Write(w.pending.Bytes())
+	if err != nil {
+		return err
+	}
+
+	w.pending.Reset()
+
+	return nil
+}
+
+func (w *Writer) open() error {
+	w.opened = w.now()
+	w.seq++
+
+	base := filepath.Join(
+		w.dir,
+		fmt.Sprintf(
+			"syslog-%s-%04d",
+			w.opened.UTC().Format("20060102T150405.000000000Z"),
+			w.seq,
+		),
+	)
+
+	data, err := os.OpenFile(base+w.dataExt, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	index, err := os.OpenFile(base+INDEX_EXT, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		data.Close()
+		return err
+	}
+
+	w.data = data
+	w.gz = w.compress(data)
+	w.index = index
+	w.pending.Reset()
+	w.offset = 0
+
+	return nil
+}
+
+func indexTimestamp(parts syslogparser.LogParts, now time.Time) time.Time {
+	ts, ok := parts["timestamp"].(time.Time)
+	if !ok || ts.IsZero() {
+		return now
+	}
+
+	return ts
+}
+
+func indexSeverity(parts syslogparser.LogParts) int {
+	s, ok := parts["severity"].(int)
+	if !ok {
+		return -1
+	}
+
+	return s
+}
+
+func indexHostname(parts syslogparser.LogParts) string {
+	h, _ := parts["hostname"].(string)
+
+	// index fields are separated by tabs, lines by LF
+	for i := 0; i < len(h); i++ {
+		if h[i] == '\t' || h[i] == '\n' {
+			return ""
+		}
+	}
+
+	return h
+}
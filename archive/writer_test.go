@@ -0,0 +1,257 @@
+package archive
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeromer/syslogparser"
+	"github.com/stretchr/testify/require"
+)
+
+func readDataFile(t *testing.T, name string) []byte {
+	f, err := os.Open(name)
+	require.Nil(t, err)
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	require.Nil(t, err)
+
+	data, err := ioutil.ReadAll(gz)
+	require.Nil(t, err)
+
+	return data
+}
+
+func listFiles(t *testing.T, dir string, ext string) []string {
+	files, err := filepath.Glob(filepath.Join(dir, "*"+ext))
+	require.Nil(t, err)
+
+	sort.Strings(files)
+
+	return files
+}
+
+func testParts(i int) syslogparser.LogParts {
+	return syslogparser.LogParts{
+		"timestamp": time.Date(
+			2003, time.October, 11, 22, 14, i, 3000000, time.UTC,
+		),
+		"hostname": "host" + strconv.Itoa(i),
+		"severity": i % 8,
+		"content":  strings.Repeat("a", 10),
+	}
+}
+
+func TestWriter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "syslogparser")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	w, err := NewWriter(filepath.Join(dir, "archive"))
+	require.Nil(t, err)
+
+	w.WithMaxSize(250)
+
+	for i := 0; i < 5; i++ {
+		err = w.Write(testParts(i))
+		require.Nil(t, err)
+	}
+
+	require.Nil(t, w.Close())
+
+	dataFiles := listFiles(t, w.dir, DATA_EXT)
+	indexFiles := listFiles(t, w.dir, INDEX_EXT)
+
+	require.Len(t, dataFiles, 3)
+	require.Len(t, indexFiles, 3)
+
+	n := 0
+	for i, name := range dataFiles {
+		require.Equal(
+			t,
+			strings.TrimSuffix(name, DATA_EXT),
+			strings.TrimSuffix(indexFiles[i], INDEX_EXT),
+		)
+
+		data := readDataFile(t, name)
+		require.True(t, len(data) <= 250)
+
+		index, err := ioutil.ReadFile(indexFiles[i])
+		require.Nil(t, err)
+
+		for _, line := range strings.Split(strings.TrimSpace(string(index)), "\n") {
+			fields := strings.Split(line, "\t")
+			require.Len(t, fields, 5)
+
+			offset, err := strconv.Atoi(fields[1])
+			require.Nil(t, err)
+
+			length, err := strconv.Atoi(fields[2])
+			require.Nil(t, err)
+
+			expected := testParts(n)
+			require.Equal(
+				t,
+				strconv.FormatInt(expected["timestamp"].(time.Time).Unix(), 10)+".003000000",
+				fields[0],
+			)
+			require.Equal(t, strconv.Itoa(expected["severity"].(int)), fields[3])
+			require.Equal(t, expected["hostname"], fields[4])
+
+			var record map[string]interface{}
+			err = json.Unmarshal(data[offset:offset+length], &record)
+			require.Nil(t, err)
+			require.Equal(t, expected["hostname"], record["hostname"])
+
+			n++
+		}
+	}
+
+	require.Equal(t, 5, n)
+}
+
+func TestWriterMaxAge(t *testing.T) {
+	dir, err := ioutil.TempDir("", "syslogparser")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	w, err := NewWriter(dir)
+	require.Nil(t, err)
+
+	now := time.Date(2003, time.October, 11, 22, 14, 15, 0, time.UTC)
+	w.now = func() time.Time {
+		return now
+	}
+
+	w.WithMaxAge(time.Minute)
+
+	require.Nil(t, w.Write(testParts(0)))
+	require.Nil(t, w.Write(testParts(1)))
+
+	now = now.Add(time.Minute)
+	require.Nil(t, w.Write(testParts(2)))
+
+	require.Nil(t, w.Flush())
+	require.Nil(t, w.Close())
+
+	require.Len(t, listFiles(t, dir, DATA_EXT), 2)
+}
+
+func TestWriterMissingFields(t *testing.T) {
+	dir, err := ioutil.TempDir("", "syslogparser")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	w, err := NewWriter(dir)
+	require.Nil(t, err)
+
+	now := time.Date(2003, time.October, 11, 22, 14, 15, 0, time.UTC)
+	w.now = func() time.Time {
+		return now
+	}
+
+	require.Nil(t, w.Write(syslogparser.LogParts{"hostname": "a\tb"}))
+	require.Nil(t, w.Close())
+
+	index, err := ioutil.ReadFile(listFiles(t, dir, INDEX_EXT)[0])
+	require.Nil(t, err)
+
+	require.Equal(t, "1065910455.000000000\t0\t20\t-1\t\n", string(index))
+}
+
+type plainWriter struct {
+	io.Writer
+}
+
+func (w plainWriter) Flush() error {
+	return nil
+}
+
+func (w plainWriter) Close() error {
+	return nil
+}
+
+func TestWriterWithEncoderAndCompressor(t *testing.T) {
+	dir, err := ioutil.TempDir("", "syslogparser")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	w, err := NewWriter(dir)
+	require.Nil(t, err)
+
+	w.WithEncoder(func(parts syslogparser.LogParts) ([]byte, error) {
+		return []byte(parts["hostname"].(string)), nil
+	})
+
+	w.WithCompressor(".log", func(w io.Writer) CompressWriter {
+		return plainWriter{w}
+	})
+
+	for i := 0; i < 3; i++ {
+		require.Nil(t, w.Write(testParts(i)))
+	}
+
+	require.Nil(t, w.Close())
+
+	require.Empty(t, listFiles(t, dir, DATA_EXT))
+
+	files := listFiles(t, dir, ".log")
+	require.Len(t, files, 1)
+
+	data, err := ioutil.ReadFile(files[0])
+	require.Nil(t, err)
+	require.Equal(t, "host0\nhost1\nhost2\n", string(data))
+
+	q := NewQuery()
+	q.WithHostname("host1")
+	q.WithDecompressor(".log", func(r io.Reader) (io.ReadCloser, error) {
+		return ioutil.NopCloser(r), nil
+	})
+
+	var records []string
+
+	err = Search(dir, q, func(record []byte) error {
+		records = append(records, string(record))
+		return nil
+	})
+
+	require.Nil(t, err)
+	require.Equal(t, []string{"host1"}, records)
+}
+
+func TestWriterIndexFlushedAfterData(t *testing.T) {
+	dir, err := ioutil.TempDir("", "syslogparser")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	w, err := NewWriter(dir)
+	require.Nil(t, err)
+
+	for i := 0; i < 3; i++ {
+		require.Nil(t, w.Write(testParts(i)))
+	}
+
+	name := listFiles(t, dir, INDEX_EXT)[0]
+
+	// records may still be buffered by gzip
+	index, err := ioutil.ReadFile(name)
+	require.Nil(t, err)
+	require.Empty(t, index)
+
+	require.Nil(t, w.Flush())
+
+	index, err = ioutil.ReadFile(name)
+	require.Nil(t, err)
+	require.Equal(t, 3, strings.Count(string(index), "\n"))
+
+	require.Nil(t, w.Close())
+}
@@ -0,0 +1,32 @@
+package syslogparser
+
+import (
+	"github.com/jeromer/syslogparser/rfc3164"
+	"github.com/jeromer/syslogparser/rfc5424"
+)
+
+// Returns the parser of the RFC detected with DetectRFC(), ready to Parse()
+// buff. Options shared by both parsers can be set through LogParser, others
+// using a type assertion. Messages without PRI are given
+// parsercommon.DEFAULT_PRIORITY, see WithDefaultPriority().
+func NewAutoParser(buff []byte) (LogParser, RFC, error) {
+	rfc, err := DetectRFC(buff)
+	if err != nil {
+		return nil, rfc, err
+	}
+
+	switch rfc {
+	case RFC_3164:
+		p := rfc3164.NewParser(buff)
+		p.WithDefaultPriority()
+
+		return p, rfc, nil
+	case RFC_5424:
+		p := rfc5424.NewParser(buff)
+		p.WithDefaultPriority()
+
+		return p, rfc, nil
+	}
+
+	return nil, RFC_UNKNOWN, ErrUnknownRFC
+}
@@ -0,0 +1,76 @@
+package syslogparser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+	"github.com/jeromer/syslogparser/rfc3164"
+	"github.com/jeromer/syslogparser/rfc5424"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAutoParser(t *testing.T) {
+	testCases := []struct {
+		description      string
+		input            string
+		expectedRFC      RFC
+		expectedParser   LogParser
+		expectedPriority int
+		expectedErr      error
+	}{
+		{
+			description:      "rfc3164",
+			input:            "<34>Oct 11 22:14:15 mymachine su: 'su root' failed",
+			expectedRFC:      RFC_3164,
+			expectedParser:   &rfc3164.Parser{},
+			expectedPriority: 34,
+		},
+		{
+			description:      "rfc5424",
+			input:            "<165>1 2003-10-11T22:14:15.003Z mymachine su - ID47 - msg",
+			expectedRFC:      RFC_5424,
+			expectedParser:   &rfc5424.Parser{},
+			expectedPriority: 165,
+		},
+		{
+			description:      "rfc3164 without priority",
+			input:            "Oct 11 22:14:15 mymachine su: 'su root' failed",
+			expectedRFC:      RFC_3164,
+			expectedParser:   &rfc3164.Parser{},
+			expectedPriority: parsercommon.DEFAULT_PRIORITY,
+		},
+		{
+			description: "unknown",
+			input:       "hello world",
+			expectedRFC: RFC_UNKNOWN,
+			expectedErr: ErrUnknownRFC,
+		},
+		{
+			description: "empty",
+			input:       "",
+			expectedRFC: RFC_UNKNOWN,
+			expectedErr: ErrBufferTooShort,
+		},
+	}
+
+	for _, tc := range testCases {
+		p, rfc, err := NewAutoParser([]byte(tc.input))
+
+		require.Equal(t, tc.expectedErr, err, tc.description)
+		require.Equal(t, tc.expectedRFC, rfc, tc.description)
+
+		if tc.expectedErr != nil {
+			require.Nil(t, p, tc.description)
+			continue
+		}
+
+		require.IsType(t, tc.expectedParser, p, tc.description)
+
+		p.WithLocation(time.UTC)
+
+		err = p.Parse()
+		require.Nil(t, err, tc.description)
+		require.Equal(t, tc.expectedPriority, p.Dump()["priority"], tc.description)
+	}
+}
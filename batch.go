@@ -0,0 +1,136 @@
+package syslogparser
+
+import (
+	"context"
+	"sync"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+	"github.com/jeromer/syslogparser/rfc3164"
+	"github.com/jeromer/syslogparser/rfc5424"
+)
+
+// Parses all given messages using up to concurrency goroutines, each of them
+// reusing its own parsers. RFC is detected for every message.
+// Both returned slices have the same length as buffs: parts[i] and errs[i]
+// correspond to buffs[i]. Messages not parsed yet when ctx is done get
+// ctx.Err() as error.
+func ParseMany(ctx context.Context, buffs [][]byte, concurrency int) ([]LogParts, []error) {
+	parts := make([]LogParts, len(buffs))
+	errs := make([]error, len(buffs))
+
+	i := runBatch(ctx, len(buffs), concurrency, func(w *batchWorker, j int) {
+		parts[j], errs[j] = w.parse(buffs[j])
+	})
+
+	for ; i < len(buffs); i++ {
+		errs[i] = ctx.Err()
+	}
+
+	return parts, errs
+}
+
+// Calls fn for every index in [0, n) using up to concurrency goroutines,
+// each of them with its own worker.
+// Returns the first index for which fn was not called because ctx is done,
+// n otherwise.
+func runBatch(ctx context.Context, n int, concurrency int, fn func(w *batchWorker, i int)) int {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	if concurrency > n {
+		concurrency = n
+	}
+
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			defer wg.Done()
+
+			w := newBatchWorker()
+			for j := range jobs {
+				fn(w, j)
+			}
+		}()
+	}
+
+	i := 0
+
+loop:
+	for ; i < n; i++ {
+		select {
+		case <-ctx.Done():
+			break loop
+		case jobs <- i:
+		}
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	return i
+}
+
+type batchWorker struct {
+	rfc3164 *rfc3164.Parser
+	rfc5424 *rfc5424.Parser
+}
+
+func newBatchWorker() *batchWorker {
+	return &batchWorker{
+		rfc3164: rfc3164.NewParser(nil),
+		rfc5424: rfc5424.NewParser(nil),
+	}
+}
+
+type dumper interface {
+	Dump() LogParts
+	DumpMessage(m *parsercommon.Message)
+}
+
+func (w *batchWorker) parse(buff []byte) (LogParts, error) {
+	p, err := w.parser(buff)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.Dump(), nil
+}
+
+// Returns the parser which parsed buff
+func (w *batchWorker) parser(buff []byte) (dumper, error) {
+	var p interface {
+		LogParser
+		dumper
+	}
+
+	rfc, err := DetectRFC(buff)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reset() clears options, set them as NewAutoParser() does
+	switch rfc {
+	case RFC_3164:
+		w.rfc3164.Reset(buff)
+		w.rfc3164.WithDefaultPriority()
+		p = w.rfc3164
+	case RFC_5424:
+		w.rfc5424.Reset(buff)
+		w.rfc5424.WithDefaultPriority()
+		p = w.rfc5424
+	default:
+		return nil, ErrUnknownRFC
+	}
+
+	err = p.Parse()
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
@@ -0,0 +1,82 @@
+package syslogparser
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMany(t *testing.T) {
+	buffs := [][]byte{
+		[]byte("<34>Oct 11 22:14:15 mymachine su: 'su root' failed for lonvick on /dev/pts/8"),
+		[]byte("<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 - An application event log entry..."),
+		[]byte("<34>1 2003-13-11T22:14:15.003Z mymachine su - ID47 - message"),
+	}
+
+	parts, errs := ParseMany(context.Background(), buffs, 2)
+
+	require.Len(t, parts, len(buffs))
+	require.Len(t, errs, len(buffs))
+
+	require.Nil(t, errs[0])
+	require.Equal(t, "su", parts[0]["tag"])
+	require.Nil(t, parsercommon.Validate(parts[0]))
+
+	require.Nil(t, errs[1])
+	require.Equal(t, "evntslog", parts[1]["app_name"])
+	require.Nil(t, parsercommon.Validate(parts[1]))
+
+	require.NotNil(t, errs[2])
+	require.Nil(t, parts[2])
+}
+
+func TestParseManyCanceled(t *testing.T) {
+	buffs := [][]byte{
+		[]byte("<34>Oct 11 22:14:15 mymachine su: 'su root' failed for lonvick on /dev/pts/8"),
+		[]byte("<34>Oct 11 22:14:15 mymachine su: 'su root' failed for lonvick on /dev/pts/8"),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	parts, errs := ParseMany(ctx, buffs, 1)
+
+	require.Len(t, parts, len(buffs))
+	require.Len(t, errs, len(buffs))
+
+	for i := range buffs {
+		if errs[i] == nil {
+			require.Equal(t, "su", parts[i]["tag"])
+			continue
+		}
+
+		require.Equal(t, context.Canceled, errs[i])
+	}
+}
+
+func TestParseManyEmpty(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	parts, errs := ParseMany(ctx, nil, 4)
+
+	require.Empty(t, parts)
+	require.Empty(t, errs)
+}
+
+func BenchmarkParseMany(b *testing.B) {
+	buffs := make([][]byte, 1000)
+	for i := range buffs {
+		buffs[i] = []byte("<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 - An application event log entry...")
+	}
+
+	for i := 0; i < b.N; i++ {
+		_, errs := ParseMany(context.Background(), buffs, 4)
+		if errs[0] != nil {
+			panic(errs[0])
+		}
+	}
+}
@@ -0,0 +1,157 @@
+// Package cef parses Common Event Format payloads carried in syslog
+// messages, as sent by many security products:
+//
+//	CEF:0|Vendor|Product|1.0|100|Name|5|src=10.0.0.1 act=blocked
+//
+// Payload is a parsercommon.PayloadParser to be given to WithPayloadParser().
+package cef
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+)
+
+const (
+	// start of CEF payloads, followed by the version
+	CEF_PREFIX = "CEF:"
+
+	// key of the nested map returned by ParsePayload()
+	CEF_KEY = "cef"
+
+	// version, vendor, product, device version, signature ID, name, severity
+	HEADER_FIELDS = 7
+)
+
+var (
+	ErrNoCEF          = &parsercommon.ParserError{ErrorString: "No CEF prefix found"}
+	ErrInvalidHeader  = &parsercommon.ParserError{ErrorString: "Invalid CEF header"}
+	ErrInvalidVersion = &parsercommon.ParserError{ErrorString: "Invalid CEF version"}
+)
+
+var Payload = parsercommon.PayloadParserFunc(ParsePayload)
+
+// Extension values escapes
+var extensionReplacer = strings.NewReplacer(
+	`\\`, `\`,
+	`\=`, `=`,
+	`\n`, "\n",
+	`\r`, "\r",
+)
+
+// Header fields escapes
+var headerReplacer = strings.NewReplacer(
+	`\\`, `\`,
+	`\|`, `|`,
+)
+
+// Returns true when s holds a CEF payload
+func IsCEF(s string) bool {
+	return strings.Contains(s, CEF_PREFIX)
+}
+
+// Parses the CEF payload found in s, ie. the content of an RFC3164 message,
+// and returns it under CEF_KEY as a map holding "version" (an int),
+// "device_vendor", "device_product", "device_version", "signature_id",
+// "name", "severity" and "extensions", a map[string]string. Text before
+// CEF_PREFIX is ignored.
+func ParsePayload(s string) (parsercommon.LogParts, error) {
+	start := strings.Index(s, CEF_PREFIX)
+	if start < 0 {
+		return nil, ErrNoCEF
+	}
+
+	fields, ext, err := splitHeader(s[start+len(CEF_PREFIX):])
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return nil, parsercommon.Wrap(ErrInvalidVersion, err)
+	}
+
+	return parsercommon.LogParts{
+		CEF_KEY: map[string]interface{}{
+			"version":        version,
+			"device_vendor":  fields[1],
+			"device_product": fields[2],
+			"device_version": fields[3],
+			"signature_id":   fields[4],
+			"name":           fields[5],
+			"severity":       fields[6],
+			"extensions":     parseExtensions(ext),
+		},
+	}, nil
+}
+
+// Splits the HEADER_FIELDS pipe separated fields, "\|" being a literal pipe,
+// from the extensions
+func splitHeader(s string) ([]string, string, error) {
+	fields := make([]string, 0, HEADER_FIELDS)
+	from := 0
+
+	for i := 0; i < len(s) && len(fields) < HEADER_FIELDS; i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '|':
+			fields = append(fields, headerReplacer.Replace(s[from:i]))
+			from = i + 1
+		}
+	}
+
+	if len(fields) < HEADER_FIELDS {
+		return nil, "", ErrInvalidHeader
+	}
+
+	return fields, s[from:], nil
+}
+
+// "src=10.0.0.1 msg=Access denied act=blocked", values may hold spaces, a
+// key starting after the last space preceding an unescaped '='
+func parseExtensions(s string) map[string]string {
+	ext := map[string]string{}
+
+	type pair struct {
+		keyStart int
+		eq       int
+	}
+
+	var pairs []pair
+
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '=':
+			ks := strings.LastIndexByte(s[:i], ' ') + 1
+
+			previous := -1
+			if len(pairs) > 0 {
+				previous = pairs[len(pairs)-1].eq
+			}
+
+			// XXX : unescaped '=' in a value, kept in the value
+			if ks <= previous || ks == i {
+				continue
+			}
+
+			pairs = append(pairs, pair{keyStart: ks, eq: i})
+		}
+	}
+
+	for i, p := range pairs {
+		end := len(s)
+		if i+1 < len(pairs) {
+			end = pairs[i+1].keyStart
+		}
+
+		ext[s[p.keyStart:p.eq]] = extensionReplacer.Replace(
+			strings.TrimRight(s[p.eq+1:end], " "),
+		)
+	}
+
+	return ext
+}
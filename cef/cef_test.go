@@ -0,0 +1,142 @@
+package cef
+
+import (
+	"testing"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+	"github.com/jeromer/syslogparser/rfc3164"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePayload(t *testing.T) {
+	testCases := []struct {
+		description        string
+		input              string
+		expectedHeader     []string
+		expectedExtensions map[string]string
+	}{
+		{
+			description:    "extensions",
+			input:          "CEF:0|Security|threatmanager|1.0|100|worm successfully stopped|10|src=10.0.0.1 dst=2.1.2.2 spt=1232",
+			expectedHeader: []string{"Security", "threatmanager", "1.0", "100", "worm successfully stopped", "10"},
+			expectedExtensions: map[string]string{
+				"src": "10.0.0.1",
+				"dst": "2.1.2.2",
+				"spt": "1232",
+			},
+		},
+		{
+			description:    "escaped pipe in header",
+			input:          `CEF:0|security|threatmanager|1.0|100|detected a \| in message|10|src=10.0.0.1`,
+			expectedHeader: []string{"security", "threatmanager", "1.0", "100", "detected a | in message", "10"},
+			expectedExtensions: map[string]string{
+				"src": "10.0.0.1",
+			},
+		},
+		{
+			description:    "values with spaces and escapes",
+			input:          `CEF:0|Vendor|Product|2.0|42|Login|Low|msg=User admin \= root\nlogged in act=allowed fname=C:\\temp\\a b.txt`,
+			expectedHeader: []string{"Vendor", "Product", "2.0", "42", "Login", "Low"},
+			expectedExtensions: map[string]string{
+				"msg":   "User admin = root\nlogged in",
+				"act":   "allowed",
+				"fname": `C:\temp\a b.txt`,
+			},
+		},
+		{
+			description:        "no extension",
+			input:              "CEF:1|Vendor|Product|2.0|42|Login|3|",
+			expectedHeader:     []string{"Vendor", "Product", "2.0", "42", "Login", "3"},
+			expectedExtensions: map[string]string{},
+		},
+		{
+			description:    "text before prefix",
+			input:          "host01 CEF:0|Vendor|Product|2.0|42|Login|3|suser=bob",
+			expectedHeader: []string{"Vendor", "Product", "2.0", "42", "Login", "3"},
+			expectedExtensions: map[string]string{
+				"suser": "bob",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		obtained, err := ParsePayload(tc.input)
+		require.Nil(t, err, tc.description)
+
+		event := obtained[CEF_KEY].(map[string]interface{})
+		require.Equal(
+			t,
+			tc.expectedHeader,
+			[]string{
+				event["device_vendor"].(string),
+				event["device_product"].(string),
+				event["device_version"].(string),
+				event["signature_id"].(string),
+				event["name"].(string),
+				event["severity"].(string),
+			},
+			tc.description,
+		)
+		require.Equal(t, tc.expectedExtensions, event["extensions"], tc.description)
+	}
+}
+
+func TestParsePayloadVersion(t *testing.T) {
+	obtained, err := ParsePayload("CEF:1|Vendor|Product|2.0|42|Login|3|")
+	require.Nil(t, err)
+	require.Equal(t, 1, obtained[CEF_KEY].(map[string]interface{})["version"])
+}
+
+func TestParsePayloadErrors(t *testing.T) {
+	testCases := []struct {
+		description string
+		input       string
+		expectedErr error
+	}{
+		{
+			description: "no prefix",
+			input:       "'su root' failed for lonvick on /dev/pts/8",
+			expectedErr: ErrNoCEF,
+		},
+		{
+			description: "truncated header",
+			input:       "CEF:0|Vendor|Product|2.0|42|Login",
+			expectedErr: ErrInvalidHeader,
+		},
+		{
+			description: "escaped last pipe",
+			input:       `CEF:0|Vendor|Product|2.0|42|Login|3\|`,
+			expectedErr: ErrInvalidHeader,
+		},
+		{
+			description: "invalid version",
+			input:       "CEF:x|Vendor|Product|2.0|42|Login|3|",
+			expectedErr: ErrInvalidVersion,
+		},
+	}
+
+	for _, tc := range testCases {
+		_, err := ParsePayload(tc.input)
+		require.ErrorIs(t, err, tc.expectedErr, tc.description)
+	}
+}
+
+func TestIsCEF(t *testing.T) {
+	require.True(t, IsCEF("CEF:0|Vendor|Product|2.0|42|Login|3|"))
+	require.False(t, IsCEF("'su root' failed for lonvick on /dev/pts/8"))
+}
+
+func TestPayloadParser(t *testing.T) {
+	p := rfc3164.NewParser(
+		[]byte("<134>Oct 11 22:14:15 fw01 CEF:0|Vendor|Product|2.0|42|Login|3|suser=bob src=10.0.0.1"),
+	)
+	p.WithTag("cef")
+	p.WithPayloadParser(Payload, parsercommon.HEADER_WINS)
+
+	err := p.Parse()
+	require.Nil(t, err)
+
+	event := p.Dump()[CEF_KEY].(map[string]interface{})
+	require.Equal(t, "Login", event["name"])
+	require.Equal(t, map[string]string{"suser": "bob", "src": "10.0.0.1"}, event["extensions"])
+}
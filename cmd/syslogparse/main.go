@@ -0,0 +1,234 @@
+// Command syslogparse reads syslog messages, one per line, from files or
+// stdin, detects their RFC and prints them as NDJSON (one JSON object per
+// message).
+//
+//	syslogparse [-strict | -lenient] [-fail-fast] [-names] [-location Europe/Paris] [file ...]
+//
+// By default messages which can not be parsed are printed as
+// {"error": "...", "offset": 6, "field": "timestamp", "raw": "..."} objects,
+// offset and field locating the error when known. With -fail-fast the first
+// such message stops processing with a non zero exit code. -strict enforces
+// the grammar of the RFCs and -lenient tolerates common deviations, see
+// WithStrict() and WithLenient() of the parsers. With -names the facility
+// and severity keywords are added as "facility_name" and "severity_name".
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/jeromer/syslogparser"
+	"github.com/jeromer/syslogparser/parsercommon"
+	"github.com/jeromer/syslogparser/rfc3164"
+	"github.com/jeromer/syslogparser/rfc5424"
+)
+
+const (
+	// longest line accepted
+	MAX_LINE_LEN = 1024 * 1024
+)
+
+type config struct {
+	failFast bool
+	strict   bool
+	lenient  bool
+	names    bool
+	location *time.Location
+}
+
+func main() {
+	os.Exit(
+		run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr),
+	)
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("syslogparse", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	failFast := flags.Bool(
+		"fail-fast", false, "stop at the first message which can not be parsed",
+	)
+
+	strict := flags.Bool(
+		"strict", false, "enforce the grammar of the RFCs",
+	)
+
+	lenient := flags.Bool(
+		"lenient", false, "tolerate deviations commonly found in the wild",
+	)
+
+	names := flags.Bool(
+		"names", false, "add facility_name and severity_name",
+	)
+
+	location := flags.String(
+		"location", "UTC", "location of RFC3164 timestamps (ie. Europe/Paris or +02:00)",
+	)
+
+	err := flags.Parse(args)
+	if err != nil {
+		return 2
+	}
+
+	if *strict && *lenient {
+		fmt.Fprintln(stderr, "syslogparse: -strict and -lenient are exclusive")
+		return 2
+	}
+
+	loc, err := parsercommon.LoadLocation(*location)
+	if err != nil {
+		fmt.Fprintf(stderr, "syslogparse: invalid location %q: %v\n", *location, err)
+		return 2
+	}
+
+	cfg := &config{
+		failFast: *failFast,
+		strict:   *strict,
+		lenient:  *lenient,
+		names:    *names,
+		location: loc,
+	}
+
+	out := bufio.NewWriter(stdout)
+	defer out.Flush()
+
+	enc := json.NewEncoder(out)
+	enc.SetEscapeHTML(false)
+
+	if flags.NArg() == 0 {
+		return process(cfg, "-", stdin, enc, stderr)
+	}
+
+	for _, name := range flags.Args() {
+		f, err := os.Open(name)
+		if err != nil {
+			fmt.Fprintf(stderr, "syslogparse: %v\n", err)
+			return 1
+		}
+
+		code := process(cfg, name, f, enc, stderr)
+		f.Close()
+
+		if code != 0 {
+			return code
+		}
+	}
+
+	return 0
+}
+
+func process(cfg *config, name string, r io.Reader, enc *json.Encoder, stderr io.Writer) int {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 4096), MAX_LINE_LEN)
+
+	for line := 1; s.Scan(); line++ {
+		buff := s.Bytes()
+		if len(buff) > 0 && buff[len(buff)-1] == '\r' {
+			buff = buff[:len(buff)-1]
+		}
+
+		if len(buff) == 0 {
+			continue
+		}
+
+		var record interface{}
+
+		parts, err := parse(cfg, buff)
+		if err != nil {
+			if cfg.failFast {
+				fmt.Fprintf(stderr, "syslogparse: %s:%d: %s\n", name, line, errorDetail(err))
+				return 1
+			}
+
+			record = errorRecord(err, buff)
+		} else {
+			record = parts
+		}
+
+		err = enc.Encode(record)
+		if err != nil {
+			fmt.Fprintf(stderr, "syslogparse: %v\n", err)
+			return 1
+		}
+	}
+
+	if err := s.Err(); err != nil {
+		fmt.Fprintf(stderr, "syslogparse: %s: %v\n", name, err)
+		return 1
+	}
+
+	return 0
+}
+
+// {"error": "...", "raw": "..."} along with "offset" and "field" when the
+// error is located
+func errorRecord(err error, buff []byte) map[string]interface{} {
+	record := map[string]interface{}{
+		"error": err.Error(),
+		"raw":   string(buff),
+	}
+
+	if pe, ok := err.(*parsercommon.ParserError); ok && pe.Offset() >= 0 {
+		record["offset"] = pe.Offset()
+		record["field"] = pe.Field()
+	}
+
+	return record
+}
+
+func errorDetail(err error) string {
+	if pe, ok := err.(*parsercommon.ParserError); ok {
+		return pe.Detail()
+	}
+
+	return err.Error()
+}
+
+func parse(cfg *config, buff []byte) (syslogparser.LogParts, error) {
+	p, _, err := syslogparser.NewAutoParser(buff)
+	if err != nil {
+		return nil, err
+	}
+
+	p.WithLocation(cfg.location)
+
+	switch p := p.(type) {
+	case *rfc3164.Parser:
+		if cfg.strict {
+			p.WithStrict()
+		}
+
+		if cfg.lenient {
+			p.WithLenient()
+		}
+
+		if cfg.names {
+			p.WithNames()
+		}
+	case *rfc5424.Parser:
+		if cfg.strict {
+			p.WithStrict()
+		}
+
+		if cfg.lenient {
+			p.WithLenient()
+		}
+
+		if cfg.names {
+			p.WithNames()
+		}
+	}
+
+	err = p.Parse()
+	if err != nil {
+		return nil, err
+	}
+
+	return p.Dump(), nil
+}
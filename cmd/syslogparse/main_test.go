@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	input := strings.Join(
+		[]string{
+			"<34>Oct 11 22:14:15 mymachine su: 'su root' failed for lonvick on /dev/pts/8",
+			"",
+			"<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 - An application event log entry...\r",
+			"<34>1 2003-13-11T22:14:15.003Z mymachine su - ID47 - message",
+		},
+		"\n",
+	)
+
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+
+	code := run(
+		[]string{"-location", "+02:00"}, strings.NewReader(input), stdout, stderr,
+	)
+
+	require.Equal(t, 0, code)
+	require.Empty(t, stderr.String())
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	require.Len(t, lines, 3)
+
+	require.Contains(t, lines[0], `"hostname":"mymachine"`)
+	require.Contains(t, lines[0], `"tag":"su"`)
+	require.Contains(t, lines[0], `-10-11T22:14:15+02:00"`)
+
+	require.Contains(t, lines[1], `"app_name":"evntslog"`)
+	require.Contains(t, lines[1], `"timestamp":"2003-10-11T22:14:15.003Z"`)
+
+	require.Equal(
+		t,
+		`{"error":"Invalid month in timestamp","field":"timestamp","offset":6,"raw":"<34>1 2003-13-11T22:14:15.003Z mymachine su - ID47 - message"}`,
+		lines[2],
+	)
+}
+
+func TestRunNames(t *testing.T) {
+	stdout := new(bytes.Buffer)
+
+	code := run(
+		[]string{"-names"},
+		strings.NewReader("<34>Oct 11 22:14:15 mymachine su: 'su root' failed for lonvick on /dev/pts/8\n"),
+		stdout,
+		ioutil.Discard,
+	)
+
+	require.Equal(t, 0, code)
+	require.Contains(
+		t,
+		stdout.String(),
+		`"priority":34,"facility":4,"severity":2,"facility_name":"auth","severity_name":"crit",`,
+	)
+}
+
+func TestRunFailFast(t *testing.T) {
+	dir, err := ioutil.TempDir("", "syslogparser")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	name := filepath.Join(dir, "messages")
+	err = ioutil.WriteFile(
+		name,
+		[]byte("<34>Oct 11 22:14:15 mymachine su: ok\n<34>1 2003-13-11T22:14:15.003Z mymachine su - ID47 - message\n"),
+		0600,
+	)
+	require.Nil(t, err)
+
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+
+	code := run([]string{"-fail-fast", name}, nil, stdout, stderr)
+
+	require.Equal(t, 1, code)
+	require.Contains(t, stdout.String(), `"content":"ok"`)
+	require.Equal(
+		t,
+		"syslogparse: "+name+`:2: Invalid month in timestamp at offset 6 (timestamp): "2003-13-11T22:14"`+"\n",
+		stderr.String(),
+	)
+}
+
+func TestRunStrict(t *testing.T) {
+	input := strings.Join(
+		[]string{
+			"<165>1 2003-10-11T22:14:15.003Z mymachine evntslog - ID47 - ok",
+			"<165>2 2003-10-11T22:14:15.003Z mymachine evntslog - ID47 - version 2",
+			"<34>Oct 11 22:14:15 mymachine su: ok",
+			"Oct 11 22:14:15 mymachine su: no priority",
+		},
+		"\n",
+	)
+
+	stdout := new(bytes.Buffer)
+
+	code := run([]string{"-strict"}, strings.NewReader(input), stdout, ioutil.Discard)
+	require.Equal(t, 0, code)
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	require.Len(t, lines, 4)
+
+	require.Contains(t, lines[0], `"message":"ok"`)
+	require.Contains(t, lines[1], `"error":`)
+	require.Contains(t, lines[2], `"content":"ok"`)
+	require.Contains(t, lines[3], `"error":`)
+
+	// tolerated without -strict
+	stdout.Reset()
+
+	code = run(nil, strings.NewReader(input), stdout, ioutil.Discard)
+	require.Equal(t, 0, code)
+	require.NotContains(t, stdout.String(), `"error":`)
+}
+
+func TestRunLenient(t *testing.T) {
+	input := "<34>mymachine Oct 11 22:14:15 su: ok\n"
+
+	stdout := new(bytes.Buffer)
+
+	code := run([]string{"-lenient"}, strings.NewReader(input), stdout, ioutil.Discard)
+	require.Equal(t, 0, code)
+	require.Contains(t, stdout.String(), `"hostname":"mymachine"`)
+	require.Contains(t, stdout.String(), `"tag":"su"`)
+
+	// swapped header not recognized without -lenient
+	stdout.Reset()
+
+	code = run(nil, strings.NewReader(input), stdout, ioutil.Discard)
+	require.Equal(t, 0, code)
+	require.NotContains(t, stdout.String(), `"hostname":"mymachine"`)
+}
+
+func TestRunInvalidArgs(t *testing.T) {
+	stderr := new(bytes.Buffer)
+
+	code := run([]string{"-location", "Nowhere/Nothing"}, nil, ioutil.Discard, stderr)
+	require.Equal(t, 2, code)
+	require.Contains(t, stderr.String(), "invalid location")
+
+	code = run([]string{"-unknown"}, nil, ioutil.Discard, ioutil.Discard)
+	require.Equal(t, 2, code)
+
+	stderr.Reset()
+
+	code = run([]string{"-strict", "-lenient"}, nil, ioutil.Discard, stderr)
+	require.Equal(t, 2, code)
+	require.Contains(t, stderr.String(), "exclusive")
+
+	code = run([]string{"/nonexistent/file"}, nil, ioutil.Discard, ioutil.Discard)
+	require.Equal(t, 1, code)
+}
@@ -0,0 +1,203 @@
+package codec
+
+import (
+	"encoding/binary"
+	"math"
+	"time"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+)
+
+// https://tools.ietf.org/html/rfc8949#section-3.1
+const (
+	CBOR_UNSIGNED = 0
+	CBOR_NEGATIVE = 1
+	CBOR_BYTES    = 2
+	CBOR_TEXT     = 3
+	CBOR_ARRAY    = 4
+	CBOR_MAP      = 5
+	CBOR_TAG      = 6
+	CBOR_SIMPLE   = 7
+
+	// standard date/time string
+	CBOR_TAG_DATETIME = 0
+)
+
+const (
+	cborFalse   = 0xf4
+	cborTrue    = 0xf5
+	cborNull    = 0xf6
+	cborFloat32 = 0xfa
+	cborFloat64 = 0xfb
+)
+
+// Encodes parts to CBOR
+func MarshalCBOR(parts parsercommon.LogParts) ([]byte, error) {
+	return AppendCBOR(nil, parts)
+}
+
+// Appends the CBOR encoding of parts to dst
+func AppendCBOR(dst []byte, parts parsercommon.LogParts) ([]byte, error) {
+	return appendCBORValue(dst, parts)
+}
+
+func appendCBORHead(dst []byte, major byte, n uint64) []byte {
+	major <<= 5
+
+	switch {
+	case n < 24:
+		return append(dst, major|byte(n))
+	case n <= math.MaxUint8:
+		return append(dst, major|24, byte(n))
+	case n <= math.MaxUint16:
+		dst = append(dst, major|25)
+		return appendUint16(dst, uint16(n))
+	case n <= math.MaxUint32:
+		dst = append(dst, major|26)
+		return appendUint32(dst, uint32(n))
+	default:
+		dst = append(dst, major|27)
+		return appendUint64(dst, n)
+	}
+}
+
+func appendCBORInt(dst []byte, v int64) []byte {
+	if v < 0 {
+		return appendCBORHead(dst, CBOR_NEGATIVE, uint64(^v))
+	}
+
+	return appendCBORHead(dst, CBOR_UNSIGNED, uint64(v))
+}
+
+func appendCBORString(dst []byte, s string) []byte {
+	dst = appendCBORHead(dst, CBOR_TEXT, uint64(len(s)))
+	return append(dst, s...)
+}
+
+func appendCBORValue(dst []byte, v interface{}) ([]byte, error) {
+	var err error
+
+	switch v := v.(type) {
+	case nil:
+		return append(dst, cborNull), nil
+	case bool:
+		if v {
+			return append(dst, cborTrue), nil
+		}
+
+		return append(dst, cborFalse), nil
+	case int:
+		return appendCBORInt(dst, int64(v)), nil
+	case int8:
+		return appendCBORInt(dst, int64(v)), nil
+	case int16:
+		return appendCBORInt(dst, int64(v)), nil
+	case int32:
+		return appendCBORInt(dst, int64(v)), nil
+	case int64:
+		return appendCBORInt(dst, v), nil
+	case uint:
+		return appendCBORHead(dst, CBOR_UNSIGNED, uint64(v)), nil
+	case uint8:
+		return appendCBORHead(dst, CBOR_UNSIGNED, uint64(v)), nil
+	case uint16:
+		return appendCBORHead(dst, CBOR_UNSIGNED, uint64(v)), nil
+	case uint32:
+		return appendCBORHead(dst, CBOR_UNSIGNED, uint64(v)), nil
+	case uint64:
+		return appendCBORHead(dst, CBOR_UNSIGNED, v), nil
+	case float32:
+		dst = append(dst, cborFloat32)
+		return appendUint32(dst, math.Float32bits(v)), nil
+	case float64:
+		dst = append(dst, cborFloat64)
+		return appendUint64(dst, math.Float64bits(v)), nil
+	case string:
+		return appendCBORString(dst, v), nil
+	case []byte:
+		dst = appendCBORHead(dst, CBOR_BYTES, uint64(len(v)))
+		return append(dst, v...), nil
+	case time.Time:
+		if v.IsZero() {
+			return append(dst, cborNull), nil
+		}
+
+		dst = appendCBORHead(dst, CBOR_TAG, CBOR_TAG_DATETIME)
+		return appendCBORString(dst, v.Format(time.RFC3339Nano)), nil
+	case time.Duration:
+		return appendCBORInt(dst, int64(v)), nil
+	case []string:
+		dst = appendCBORHead(dst, CBOR_ARRAY, uint64(len(v)))
+		for _, s := range v {
+			dst = appendCBORString(dst, s)
+		}
+
+		return dst, nil
+	case []interface{}:
+		dst = appendCBORHead(dst, CBOR_ARRAY, uint64(len(v)))
+		for _, e := range v {
+			dst, err = appendCBORValue(dst, e)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return dst, nil
+	case map[string]string:
+		dst = appendCBORHead(dst, CBOR_MAP, uint64(len(v)))
+		for _, k := range sortedStringKeys(v) {
+			dst = appendCBORString(dst, k)
+			dst = appendCBORString(dst, v[k])
+		}
+
+		return dst, nil
+	case map[string]interface{}:
+		return appendCBORMap(dst, v, sortedKeys(v))
+	case parsercommon.LogParts:
+		if v == nil {
+			return append(dst, cborNull), nil
+		}
+
+		return appendCBORMap(dst, v, v.Keys())
+	}
+
+	return nil, ErrUnsupportedType
+}
+
+func appendCBORMap(dst []byte, m map[string]interface{}, keys []string) ([]byte, error) {
+	var err error
+
+	dst = appendCBORHead(dst, CBOR_MAP, uint64(len(keys)))
+
+	for _, k := range keys {
+		dst = appendCBORString(dst, k)
+
+		dst, err = appendCBORValue(dst, m[k])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return dst, nil
+}
+
+func appendUint16(dst []byte, v uint16) []byte {
+	var b [2]byte
+	binary.BigEndian.PutUint16(b[:], v)
+
+	return append(dst, b[:]...)
+}
+
+func appendUint32(dst []byte, v uint32) []byte {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], v)
+
+	return append(dst, b[:]...)
+}
+
+func appendUint64(dst []byte, v uint64) []byte {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], v)
+
+	return append(dst, b[:]...)
+}
@@ -0,0 +1,114 @@
+package codec
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+	"github.com/stretchr/testify/require"
+)
+
+// https://tools.ietf.org/html/rfc8949#appendix-A
+func TestAppendCBORValue(t *testing.T) {
+	testCases := []struct {
+		description string
+		input       interface{}
+		expected    string
+	}{
+		{"0", 0, "00"},
+		{"23", 23, "17"},
+		{"24", 24, "1818"},
+		{"100", 100, "1864"},
+		{"1000", 1000, "1903e8"},
+		{"1000000", 1000000, "1a000f4240"},
+		{"1000000000000", int64(1000000000000), "1b000000e8d4a51000"},
+		{"max uint64", uint64(18446744073709551615), "1bffffffffffffffff"},
+		{"-1", -1, "20"},
+		{"-100", int8(-100), "3863"},
+		{"-1000", int16(-1000), "3903e7"},
+		{"1.1", 1.1, "fb3ff199999999999a"},
+		{"float32", float32(100000.0), "fa47c35000"},
+		{"false", false, "f4"},
+		{"true", true, "f5"},
+		{"null", nil, "f6"},
+		{"empty string", "", "60"},
+		{"string", "IETF", "6449455446"},
+		{"bytes", []byte{1, 2, 3, 4}, "4401020304"},
+		{"empty array", []interface{}{}, "80"},
+		{"array", []interface{}{1, 2, 3}, "83010203"},
+		{"string array", []string{"a", "b"}, "8261616162"},
+		{"map", map[string]interface{}{"b": []interface{}{2, 3}, "a": 1}, "a26161016162820203"},
+		{"string map", map[string]string{"b": "B", "a": "A"}, "a26161614161626142"},
+		{
+			"timestamp",
+			time.Date(2013, time.March, 21, 20, 4, 0, 0, time.UTC),
+			"c074323031332d30332d32315432303a30343a30305a",
+		},
+		{"zero timestamp", time.Time{}, "f6"},
+		{"duration", time.Second, "1a3b9aca00"},
+		{"nil parts", parsercommon.LogParts(nil), "f6"},
+	}
+
+	for _, tc := range testCases {
+		obtained, err := appendCBORValue(nil, tc.input)
+
+		require.Nil(t, err, tc.description)
+		require.Equal(t, tc.expected, hex.EncodeToString(obtained), tc.description)
+	}
+}
+
+func TestMarshalCBOR(t *testing.T) {
+	obtained, err := MarshalCBOR(parsercommon.LogParts{
+		"zz":       true,
+		"hostname": "a",
+		"priority": 34,
+	})
+
+	require.Nil(t, err)
+
+	// {"priority": 34, "hostname": "a", "zz": true}
+	require.Equal(
+		t,
+		"a3"+"687072696f72697479"+"1822"+"68686f73746e616d65"+"6161"+"627a7a"+"f5",
+		hex.EncodeToString(obtained),
+	)
+}
+
+func TestMarshalCBORUnsupportedType(t *testing.T) {
+	_, err := MarshalCBOR(parsercommon.LogParts{
+		"foo": struct{}{},
+	})
+
+	require.Equal(t, ErrUnsupportedType, err)
+}
+
+func BenchmarkMarshalCBOR(b *testing.B) {
+	parts := benchmarkParts()
+
+	var buff []byte
+	var err error
+
+	for i := 0; i < b.N; i++ {
+		buff, err = AppendCBOR(buff[:0], parts)
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+func benchmarkParts() parsercommon.LogParts {
+	return parsercommon.LogParts{
+		"priority":        165,
+		"facility":        20,
+		"severity":        5,
+		"version":         1,
+		"timestamp":       time.Date(2003, time.October, 11, 22, 14, 15, 3000000, time.UTC),
+		"hostname":        "mymachine.example.com",
+		"app_name":        "evntslog",
+		"proc_id":         "-",
+		"msg_id":          "ID47",
+		"structured_data": `[exampleSDID@32473 iut="3" eventSource="Application" eventID="1011"]`,
+		"message":         "An application event log entry...",
+	}
+}
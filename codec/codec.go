@@ -0,0 +1,42 @@
+// Package codec encodes parsed messages to compact binary formats, CBOR
+// (RFC 8949) and MessagePack, for shipping them over constrained links or
+// into message streams.
+//
+// Keys are encoded in the same order as the JSON encoding of LogParts.
+// Timestamps use the standard representation of each format and zero
+// timestamps are encoded as nil. Supported value types are nil, booleans,
+// integers, floats, strings, byte slices, time.Time, time.Duration
+// (nanoseconds), []string, []interface{}, map[string]string,
+// map[string]interface{} and LogParts.
+package codec
+
+import (
+	"errors"
+	"sort"
+)
+
+var (
+	ErrUnsupportedType = errors.New("Unsupported value type")
+)
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func sortedStringKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
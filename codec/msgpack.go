@@ -0,0 +1,271 @@
+package codec
+
+import (
+	"math"
+	"time"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+)
+
+// https://github.com/msgpack/msgpack/blob/master/spec.md
+const (
+	// extension type -1
+	msgpackExtTimestamp = 0xff
+
+	msgpackNil      = 0xc0
+	msgpackFalse    = 0xc2
+	msgpackTrue     = 0xc3
+	msgpackBin8     = 0xc4
+	msgpackBin16    = 0xc5
+	msgpackBin32    = 0xc6
+	msgpackExt8     = 0xc7
+	msgpackFloat32  = 0xca
+	msgpackFloat64  = 0xcb
+	msgpackUint8    = 0xcc
+	msgpackUint16   = 0xcd
+	msgpackUint32   = 0xce
+	msgpackUint64   = 0xcf
+	msgpackInt8     = 0xd0
+	msgpackInt16    = 0xd1
+	msgpackInt32    = 0xd2
+	msgpackInt64    = 0xd3
+	msgpackFixExt4  = 0xd6
+	msgpackFixExt8  = 0xd7
+	msgpackStr8     = 0xd9
+	msgpackStr16    = 0xda
+	msgpackStr32    = 0xdb
+	msgpackArray16  = 0xdc
+	msgpackArray32  = 0xdd
+	msgpackMap16    = 0xde
+	msgpackMap32    = 0xdf
+	msgpackFixMap   = 0x80
+	msgpackFixArray = 0x90
+	msgpackFixStr   = 0xa0
+)
+
+// Encodes parts to MessagePack
+func MarshalMsgpack(parts parsercommon.LogParts) ([]byte, error) {
+	return AppendMsgpack(nil, parts)
+}
+
+// Appends the MessagePack encoding of parts to dst
+func AppendMsgpack(dst []byte, parts parsercommon.LogParts) ([]byte, error) {
+	return appendMsgpackValue(dst, parts)
+}
+
+func appendMsgpackUint(dst []byte, v uint64) []byte {
+	switch {
+	case v <= 0x7f:
+		return append(dst, byte(v))
+	case v <= math.MaxUint8:
+		return append(dst, msgpackUint8, byte(v))
+	case v <= math.MaxUint16:
+		dst = append(dst, msgpackUint16)
+		return appendUint16(dst, uint16(v))
+	case v <= math.MaxUint32:
+		dst = append(dst, msgpackUint32)
+		return appendUint32(dst, uint32(v))
+	default:
+		dst = append(dst, msgpackUint64)
+		return appendUint64(dst, v)
+	}
+}
+
+func appendMsgpackInt(dst []byte, v int64) []byte {
+	switch {
+	case v >= 0:
+		return appendMsgpackUint(dst, uint64(v))
+	case v >= -32:
+		return append(dst, byte(v))
+	case v >= math.MinInt8:
+		return append(dst, msgpackInt8, byte(v))
+	case v >= math.MinInt16:
+		dst = append(dst, msgpackInt16)
+		return appendUint16(dst, uint16(v))
+	case v >= math.MinInt32:
+		dst = append(dst, msgpackInt32)
+		return appendUint32(dst, uint32(v))
+	default:
+		dst = append(dst, msgpackInt64)
+		return appendUint64(dst, uint64(v))
+	}
+}
+
+// Appends the header of a str, bin, array or map of n elements, using the
+// 8 bits length format when b8 is not 0
+func appendMsgpackHead(dst []byte, b8 byte, b16 byte, b32 byte, n int) []byte {
+	switch {
+	case b8 != 0 && n <= math.MaxUint8:
+		return append(dst, b8, byte(n))
+	case n <= math.MaxUint16:
+		dst = append(dst, b16)
+		return appendUint16(dst, uint16(n))
+	default:
+		dst = append(dst, b32)
+		return appendUint32(dst, uint32(n))
+	}
+}
+
+func appendMsgpackString(dst []byte, s string) []byte {
+	if len(s) < 32 {
+		dst = append(dst, msgpackFixStr|byte(len(s)))
+	} else {
+		dst = appendMsgpackHead(
+			dst, msgpackStr8, msgpackStr16, msgpackStr32, len(s),
+		)
+	}
+
+	return append(dst, s...)
+}
+
+func appendMsgpackBin(dst []byte, b []byte) []byte {
+	dst = appendMsgpackHead(
+		dst, msgpackBin8, msgpackBin16, msgpackBin32, len(b),
+	)
+
+	return append(dst, b...)
+}
+
+func appendMsgpackArrayHead(dst []byte, n int) []byte {
+	if n < 16 {
+		return append(dst, msgpackFixArray|byte(n))
+	}
+
+	return appendMsgpackHead(dst, 0, msgpackArray16, msgpackArray32, n)
+}
+
+func appendMsgpackMapHead(dst []byte, n int) []byte {
+	if n < 16 {
+		return append(dst, msgpackFixMap|byte(n))
+	}
+
+	return appendMsgpackHead(dst, 0, msgpackMap16, msgpackMap32, n)
+}
+
+// https://github.com/msgpack/msgpack/blob/master/spec.md#timestamp-extension-type
+func appendMsgpackTime(dst []byte, t time.Time) []byte {
+	sec := t.Unix()
+	nsec := uint64(t.Nanosecond())
+
+	if sec>>34 == 0 {
+		data := nsec<<34 | uint64(sec)
+
+		if data&0xffffffff00000000 == 0 {
+			dst = append(dst, msgpackFixExt4, msgpackExtTimestamp)
+			return appendUint32(dst, uint32(data))
+		}
+
+		dst = append(dst, msgpackFixExt8, msgpackExtTimestamp)
+		return appendUint64(dst, data)
+	}
+
+	dst = append(dst, msgpackExt8, 12, msgpackExtTimestamp)
+	dst = appendUint32(dst, uint32(nsec))
+
+	return appendUint64(dst, uint64(sec))
+}
+
+func appendMsgpackValue(dst []byte, v interface{}) ([]byte, error) {
+	var err error
+
+	switch v := v.(type) {
+	case nil:
+		return append(dst, msgpackNil), nil
+	case bool:
+		if v {
+			return append(dst, msgpackTrue), nil
+		}
+
+		return append(dst, msgpackFalse), nil
+	case int:
+		return appendMsgpackInt(dst, int64(v)), nil
+	case int8:
+		return appendMsgpackInt(dst, int64(v)), nil
+	case int16:
+		return appendMsgpackInt(dst, int64(v)), nil
+	case int32:
+		return appendMsgpackInt(dst, int64(v)), nil
+	case int64:
+		return appendMsgpackInt(dst, v), nil
+	case uint:
+		return appendMsgpackUint(dst, uint64(v)), nil
+	case uint8:
+		return appendMsgpackUint(dst, uint64(v)), nil
+	case uint16:
+		return appendMsgpackUint(dst, uint64(v)), nil
+	case uint32:
+		return appendMsgpackUint(dst, uint64(v)), nil
+	case uint64:
+		return appendMsgpackUint(dst, v), nil
+	case float32:
+		dst = append(dst, msgpackFloat32)
+		return appendUint32(dst, math.Float32bits(v)), nil
+	case float64:
+		dst = append(dst, msgpackFloat64)
+		return appendUint64(dst, math.Float64bits(v)), nil
+	case string:
+		return appendMsgpackString(dst, v), nil
+	case []byte:
+		return appendMsgpackBin(dst, v), nil
+	case time.Time:
+		if v.IsZero() {
+			return append(dst, msgpackNil), nil
+		}
+
+		return appendMsgpackTime(dst, v), nil
+	case time.Duration:
+		return appendMsgpackInt(dst, int64(v)), nil
+	case []string:
+		dst = appendMsgpackArrayHead(dst, len(v))
+		for _, s := range v {
+			dst = appendMsgpackString(dst, s)
+		}
+
+		return dst, nil
+	case []interface{}:
+		dst = appendMsgpackArrayHead(dst, len(v))
+		for _, e := range v {
+			dst, err = appendMsgpackValue(dst, e)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return dst, nil
+	case map[string]string:
+		dst = appendMsgpackMapHead(dst, len(v))
+		for _, k := range sortedStringKeys(v) {
+			dst = appendMsgpackString(dst, k)
+			dst = appendMsgpackString(dst, v[k])
+		}
+
+		return dst, nil
+	case map[string]interface{}:
+		return appendMsgpackMap(dst, v, sortedKeys(v))
+	case parsercommon.LogParts:
+		if v == nil {
+			return append(dst, msgpackNil), nil
+		}
+
+		return appendMsgpackMap(dst, v, v.Keys())
+	}
+
+	return nil, ErrUnsupportedType
+}
+
+func appendMsgpackMap(dst []byte, m map[string]interface{}, keys []string) ([]byte, error) {
+	var err error
+
+	dst = appendMsgpackMapHead(dst, len(keys))
+
+	for _, k := range keys {
+		dst = appendMsgpackString(dst, k)
+
+		dst, err = appendMsgpackValue(dst, m[k])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return dst, nil
+}
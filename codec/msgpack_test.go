@@ -0,0 +1,111 @@
+package codec
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendMsgpackValue(t *testing.T) {
+	testCases := []struct {
+		description string
+		input       interface{}
+		expected    string
+	}{
+		{"0", 0, "00"},
+		{"127", 127, "7f"},
+		{"128", 128, "cc80"},
+		{"256", uint16(256), "cd0100"},
+		{"65536", 65536, "ce00010000"},
+		{"4294967296", uint64(4294967296), "cf0000000100000000"},
+		{"-1", -1, "ff"},
+		{"-32", -32, "e0"},
+		{"-33", int8(-33), "d0df"},
+		{"-129", -129, "d1ff7f"},
+		{"-32769", int32(-32769), "d2ffff7fff"},
+		{"-2147483649", int64(-2147483649), "d3ffffffff7fffffff"},
+		{"1.1", 1.1, "cb3ff199999999999a"},
+		{"float32", float32(1.5), "ca3fc00000"},
+		{"nil", nil, "c0"},
+		{"false", false, "c2"},
+		{"true", true, "c3"},
+		{"fixstr", "a", "a161"},
+		{"str8", strings.Repeat("a", 32), "d920" + strings.Repeat("61", 32)},
+		{"empty bytes", []byte{}, "c400"},
+		{"bytes", []byte{1, 2}, "c4020102"},
+		{"array", []interface{}{1, 2}, "920102"},
+		{"string array", []string{"a"}, "91a161"},
+		{"map", map[string]interface{}{"b": 2, "a": 1}, "82a16101a16202"},
+		{"string map", map[string]string{"a": "A"}, "81a161a141"},
+		{"timestamp32", time.Unix(0, 0), "d6ff00000000"},
+		{"timestamp64", time.Unix(1, 1), "d7ff0000000400000001"},
+		{"timestamp96", time.Unix(-1, 0), "c70cff00000000ffffffffffffffff"},
+		{"zero timestamp", time.Time{}, "c0"},
+		{"duration", time.Microsecond, "cd03e8"},
+		{"nil parts", parsercommon.LogParts(nil), "c0"},
+	}
+
+	for _, tc := range testCases {
+		obtained, err := appendMsgpackValue(nil, tc.input)
+
+		require.Nil(t, err, tc.description)
+		require.Equal(t, tc.expected, hex.EncodeToString(obtained), tc.description)
+	}
+}
+
+func TestAppendMsgpackValueLong(t *testing.T) {
+	s := strings.Repeat("a", 256)
+
+	obtained, err := appendMsgpackValue(nil, s)
+	require.Nil(t, err)
+	require.Equal(t, "da0100", hex.EncodeToString(obtained[:3]))
+
+	a := make([]interface{}, 16)
+
+	obtained, err = appendMsgpackValue(nil, a)
+	require.Nil(t, err)
+	require.Equal(t, "dc0010", hex.EncodeToString(obtained[:3]))
+}
+
+func TestMarshalMsgpack(t *testing.T) {
+	obtained, err := MarshalMsgpack(parsercommon.LogParts{
+		"zz":       true,
+		"hostname": "a",
+		"priority": 34,
+	})
+
+	require.Nil(t, err)
+
+	// {"priority": 34, "hostname": "a", "zz": true}
+	require.Equal(
+		t,
+		"83"+"a87072696f72697479"+"22"+"a8686f73746e616d65"+"a161"+"a27a7a"+"c3",
+		hex.EncodeToString(obtained),
+	)
+}
+
+func TestMarshalMsgpackUnsupportedType(t *testing.T) {
+	_, err := MarshalMsgpack(parsercommon.LogParts{
+		"foo": []int{1},
+	})
+
+	require.Equal(t, ErrUnsupportedType, err)
+}
+
+func BenchmarkMarshalMsgpack(b *testing.B) {
+	parts := benchmarkParts()
+
+	var buff []byte
+	var err error
+
+	for i := 0; i < b.N; i++ {
+		buff, err = AppendMsgpack(buff[:0], parts)
+		if err != nil {
+			panic(err)
+		}
+	}
+}
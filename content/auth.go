@@ -0,0 +1,104 @@
+package content
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+)
+
+const (
+	// reported as "result"
+	AUTH_SUCCESS = "success"
+	AUTH_FAILURE = "failure"
+)
+
+var (
+	ErrUnknownAuthEvent = &parsercommon.ParserError{ErrorString: "Unknown authentication event"}
+)
+
+// Content parser of sshd and sudo authentication logs
+var Auth = parsercommon.PayloadParserFunc(ParseAuth)
+
+// Accepted publickey for bob from 10.0.0.1 port 51234 ssh2: RSA SHA256:...
+// Failed password for invalid user admin from 198.51.100.7 port 40000 ssh2
+var sshdAuth = regexp.MustCompile(
+	`^(?P<result>Accepted|Failed) (?P<auth_method>\S+) for (?P<invalid>invalid user )?(?P<user>.*?) ` +
+		`from (?P<src_ip>\S+) port (?P<src_port>\d+)`,
+)
+
+// Invalid user admin from 198.51.100.7 port 40000
+var sshdInvalidUser = regexp.MustCompile(
+	`^Invalid user (?P<user>.*?) from (?P<src_ip>\S+)(?: port (?P<src_port>\d+))?`,
+)
+
+// bob : TTY=pts/0 ; PWD=/home/bob ; USER=root ; COMMAND=/usr/bin/apt update
+// bob : 3 incorrect password attempts ; TTY=pts/0 ; ... ; COMMAND=/bin/ls
+var sudoCommand = regexp.MustCompile(
+	`^\s*(?P<user>\S+) : (?:(?P<reason>[^;=]+) ; )?(?P<pairs>(?:[A-Z]+=[^;]*(?: ; |$))+)`,
+)
+
+var authInts = map[string]bool{
+	"src_port": true,
+}
+
+// sudo pairs and the keys they are reported as
+var sudoKeys = map[string]string{
+	"TTY":     "tty",
+	"PWD":     "pwd",
+	"USER":    "run_as",
+	"COMMAND": "command",
+}
+
+// Parses sshd logins ("Accepted password for ...", "Failed publickey for
+// ...", "Invalid user ...") and sudo command lines. Gives "user", "result",
+// AUTH_SUCCESS or AUTH_FAILURE, and for sshd "src_ip", "src_port",
+// "auth_method" and "invalid_user", for sudo "run_as", "tty", "pwd",
+// "command" and the "reason" of failures ("3 incorrect password attempts").
+func ParseAuth(payload string) (parsercommon.LogParts, error) {
+	if parts := match(sshdAuth, payload, authInts); parts != nil {
+		parts["invalid_user"] = parts["invalid"] != ""
+		delete(parts, "invalid")
+
+		parts["result"] = AUTH_FAILURE
+		if strings.HasPrefix(payload, "Accepted") {
+			parts["result"] = AUTH_SUCCESS
+		}
+
+		return parts, nil
+	}
+
+	if parts := match(sshdInvalidUser, payload, authInts); parts != nil {
+		if parts["src_port"] == "" {
+			delete(parts, "src_port")
+		}
+
+		parts["invalid_user"] = true
+		parts["result"] = AUTH_FAILURE
+
+		return parts, nil
+	}
+
+	if parts := match(sudoCommand, payload, nil); parts != nil {
+		pairs := parts["pairs"].(string)
+		delete(parts, "pairs")
+
+		for _, pair := range strings.Split(pairs, " ; ") {
+			kv := strings.SplitN(pair, "=", 2)
+			if k, ok := sudoKeys[kv[0]]; ok && len(kv) == 2 {
+				parts[k] = strings.TrimSpace(kv[1])
+			}
+		}
+
+		parts["result"] = AUTH_SUCCESS
+		if parts["reason"] != "" {
+			parts["result"] = AUTH_FAILURE
+		} else {
+			delete(parts, "reason")
+		}
+
+		return parts, nil
+	}
+
+	return nil, ErrUnknownAuthEvent
+}
@@ -0,0 +1,132 @@
+package content
+
+import (
+	"testing"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+	"github.com/jeromer/syslogparser/rfc3164"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAuth(t *testing.T) {
+	testCases := []struct {
+		description   string
+		input         string
+		expectedParts parsercommon.LogParts
+	}{
+		{
+			description: "sshd failed password",
+			input:       "Failed password for root from 198.51.100.7 port 40000 ssh2",
+			expectedParts: parsercommon.LogParts{
+				"result":       AUTH_FAILURE,
+				"auth_method":  "password",
+				"user":         "root",
+				"invalid_user": false,
+				"src_ip":       "198.51.100.7",
+				"src_port":     40000,
+			},
+		},
+		{
+			description: "sshd failed password, invalid user",
+			input:       "Failed password for invalid user admin from 198.51.100.7 port 40000 ssh2",
+			expectedParts: parsercommon.LogParts{
+				"result":       AUTH_FAILURE,
+				"auth_method":  "password",
+				"user":         "admin",
+				"invalid_user": true,
+				"src_ip":       "198.51.100.7",
+				"src_port":     40000,
+			},
+		},
+		{
+			description: "sshd accepted publickey",
+			input:       "Accepted publickey for bob from 10.0.0.1 port 51234 ssh2: RSA SHA256:abcdef",
+			expectedParts: parsercommon.LogParts{
+				"result":       AUTH_SUCCESS,
+				"auth_method":  "publickey",
+				"user":         "bob",
+				"invalid_user": false,
+				"src_ip":       "10.0.0.1",
+				"src_port":     51234,
+			},
+		},
+		{
+			description: "sshd invalid user",
+			input:       "Invalid user oracle from 198.51.100.7 port 40000",
+			expectedParts: parsercommon.LogParts{
+				"result":       AUTH_FAILURE,
+				"user":         "oracle",
+				"invalid_user": true,
+				"src_ip":       "198.51.100.7",
+				"src_port":     40000,
+			},
+		},
+		{
+			description: "sshd invalid user, no port",
+			input:       "Invalid user oracle from 198.51.100.7",
+			expectedParts: parsercommon.LogParts{
+				"result":       AUTH_FAILURE,
+				"user":         "oracle",
+				"invalid_user": true,
+				"src_ip":       "198.51.100.7",
+			},
+		},
+		{
+			description: "sudo command",
+			input:       "    bob : TTY=pts/0 ; PWD=/home/bob ; USER=root ; COMMAND=/usr/bin/apt update",
+			expectedParts: parsercommon.LogParts{
+				"result":  AUTH_SUCCESS,
+				"user":    "bob",
+				"tty":     "pts/0",
+				"pwd":     "/home/bob",
+				"run_as":  "root",
+				"command": "/usr/bin/apt update",
+			},
+		},
+		{
+			description: "sudo failure",
+			input:       "bob : 3 incorrect password attempts ; TTY=pts/0 ; PWD=/home/bob ; USER=root ; COMMAND=/bin/ls",
+			expectedParts: parsercommon.LogParts{
+				"result":  AUTH_FAILURE,
+				"reason":  "3 incorrect password attempts",
+				"user":    "bob",
+				"tty":     "pts/0",
+				"pwd":     "/home/bob",
+				"run_as":  "root",
+				"command": "/bin/ls",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		obtained, err := ParseAuth(tc.input)
+		require.Nil(t, err, tc.description)
+		require.Equal(t, tc.expectedParts, obtained, tc.description)
+	}
+}
+
+func TestParseAuthErrors(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"Connection closed by 198.51.100.7 port 40000 [preauth]",
+		"pam_unix(sudo:session): session opened for user root by bob(uid=0)",
+	} {
+		_, err := ParseAuth(input)
+		require.ErrorIs(t, err, ErrUnknownAuthEvent, input)
+	}
+}
+
+func TestAuthPayloadParser(t *testing.T) {
+	p := rfc3164.NewParser(
+		[]byte("<38>Oct 11 22:14:15 mymachine sshd[4321]: Failed password for root from 198.51.100.7 port 40000 ssh2"),
+	)
+	p.WithPayloadParser(Auth, parsercommon.HEADER_WINS)
+
+	err := p.Parse()
+	require.Nil(t, err)
+
+	obtained := p.Dump()
+	require.Equal(t, "mymachine", obtained["hostname"])
+	require.Equal(t, "root", obtained["user"])
+	require.Equal(t, AUTH_FAILURE, obtained["result"])
+}
@@ -0,0 +1,44 @@
+// Package content provides parsercommon.PayloadParser implementations for
+// the content of widespread applications, to be given to WithPayloadParser():
+//
+//	p := rfc3164.NewParser(b)
+//	p.WithPayloadParser(content.HAProxy, parsercommon.HEADER_WINS)
+package content
+
+import (
+	"regexp"
+	"strconv"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+)
+
+// Returns the named sub-expressions of re matched by s, those listed in
+// ints being converted to int, or nil when s does not match
+func match(re *regexp.Regexp, s string, ints map[string]bool) parsercommon.LogParts {
+	m := re.FindStringSubmatch(s)
+	if m == nil {
+		return nil
+	}
+
+	parts := parsercommon.LogParts{}
+
+	for i, name := range re.SubexpNames() {
+		if name == "" {
+			continue
+		}
+
+		if !ints[name] {
+			parts[name] = m[i]
+			continue
+		}
+
+		n, err := strconv.Atoi(m[i])
+		if err != nil {
+			continue
+		}
+
+		parts[name] = n
+	}
+
+	return parts
+}
@@ -0,0 +1,104 @@
+package content
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+)
+
+const (
+	// "06/Feb/2009:12:14:14.655"
+	HAPROXY_DATE_FORMAT = "02/Jan/2006:15:04:05.000"
+
+	HAPROXY_MODE_HTTP = "http"
+	HAPROXY_MODE_TCP  = "tcp"
+)
+
+var (
+	ErrInvalidHAProxyLog = &parsercommon.ParserError{ErrorString: "Invalid HAProxy log"}
+)
+
+// Content parser of HAProxy HTTP and TCP logs
+var HAProxy = parsercommon.PayloadParserFunc(ParseHAProxy)
+
+const haproxyPrefix = `^(?P<client_ip>\S+):(?P<client_port>\d+) \[(?P<accept_date>[^\]]+)\] ` +
+	`(?P<frontend>\S+) (?P<backend>[^/\s]+)/(?P<server>\S+) `
+
+// 10.0.1.2:33317 [06/Feb/2009:12:14:14.655] http-in static/srv1 10/0/30/69/109 200 2750 - - ---- 1/1/1/1/0 0/0 {1wt.eu} {} "GET /index.html HTTP/1.1"
+var haproxyHTTP = regexp.MustCompile(
+	haproxyPrefix +
+		`(?P<time_request>-?\d+)/(?P<time_queue>-?\d+)/(?P<time_connect>-?\d+)/(?P<time_response>-?\d+)/\+?(?P<time_total>-?\d+) ` +
+		`(?P<status_code>-?\d+) \+?(?P<bytes_read>\d+) \S+ \S+ (?P<termination_state>\S+) ` +
+		`(?P<actconn>\d+)/(?P<feconn>\d+)/(?P<beconn>\d+)/(?P<srv_conn>\d+)/\+?(?P<retries>\d+) ` +
+		`(?P<srv_queue>\d+)/(?P<backend_queue>\d+)(?: \{[^}]*\})*(?: "(?P<request>[^"]*)")?`,
+)
+
+// 10.0.1.2:33313 [06/Feb/2009:12:12:51.443] fnt bck/srv1 0/0/5007 212 -- 0/0/0/0/3 0/0
+var haproxyTCP = regexp.MustCompile(
+	haproxyPrefix +
+		`(?P<time_queue>-?\d+)/(?P<time_connect>-?\d+)/\+?(?P<time_total>-?\d+) ` +
+		`\+?(?P<bytes_read>\d+) (?P<termination_state>\S+) ` +
+		`(?P<actconn>\d+)/(?P<feconn>\d+)/(?P<beconn>\d+)/(?P<srv_conn>\d+)/\+?(?P<retries>\d+) ` +
+		`(?P<srv_queue>\d+)/(?P<backend_queue>\d+)`,
+)
+
+var haproxyInts = map[string]bool{
+	"client_port":   true,
+	"time_request":  true,
+	"time_queue":    true,
+	"time_connect":  true,
+	"time_response": true,
+	"time_total":    true,
+	"status_code":   true,
+	"bytes_read":    true,
+	"actconn":       true,
+	"feconn":        true,
+	"beconn":        true,
+	"srv_conn":      true,
+	"retries":       true,
+	"srv_queue":     true,
+	"backend_queue": true,
+}
+
+// Parses HAProxy logs in the default HTTP ("option httplog") and TCP
+// ("option tcplog") formats. Timers are reported in milliseconds, -1 when
+// the step was not reached, accept_date as a time.Time in UTC and the
+// request line of HTTP logs as "method", "uri" and "http_version". "mode"
+// is HAPROXY_MODE_HTTP or HAPROXY_MODE_TCP.
+func ParseHAProxy(payload string) (parsercommon.LogParts, error) {
+	mode := HAPROXY_MODE_HTTP
+
+	parts := match(haproxyHTTP, payload, haproxyInts)
+	if parts == nil {
+		mode = HAPROXY_MODE_TCP
+		parts = match(haproxyTCP, payload, haproxyInts)
+	}
+
+	if parts == nil {
+		return nil, ErrInvalidHAProxyLog
+	}
+
+	date, err := time.Parse(HAPROXY_DATE_FORMAT, parts["accept_date"].(string))
+	if err != nil {
+		return nil, parsercommon.Wrap(ErrInvalidHAProxyLog, err)
+	}
+
+	parts["accept_date"] = date
+	parts["mode"] = mode
+
+	if req, ok := parts["request"].(string); ok {
+		delete(parts, "request")
+
+		// "GET /index.html HTTP/1.1", "<BADREQ>" when invalid
+		f := strings.Fields(req)
+		if len(f) == 3 {
+			parts["method"] = f[0]
+			parts["uri"] = f[1]
+			parts["http_version"] = f[2]
+		}
+	}
+
+	return parts, nil
+}
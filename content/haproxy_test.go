@@ -0,0 +1,131 @@
+package content
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+	"github.com/jeromer/syslogparser/rfc3164"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHAProxy(t *testing.T) {
+	testCases := []struct {
+		description   string
+		input         string
+		expectedParts parsercommon.LogParts
+	}{
+		{
+			description: "http",
+			input:       `10.0.1.2:33317 [06/Feb/2009:12:14:14.655] http-in static/srv1 10/0/30/69/109 200 2750 - - ---- 1/1/1/1/0 0/0 {1wt.eu} {} "GET /index.html HTTP/1.1"`,
+			expectedParts: parsercommon.LogParts{
+				"mode":              HAPROXY_MODE_HTTP,
+				"client_ip":         "10.0.1.2",
+				"client_port":       33317,
+				"accept_date":       time.Date(2009, time.February, 6, 12, 14, 14, 655*1000*1000, time.UTC),
+				"frontend":          "http-in",
+				"backend":           "static",
+				"server":            "srv1",
+				"time_request":      10,
+				"time_queue":        0,
+				"time_connect":      30,
+				"time_response":     69,
+				"time_total":        109,
+				"status_code":       200,
+				"bytes_read":        2750,
+				"termination_state": "----",
+				"actconn":           1,
+				"feconn":            1,
+				"beconn":            1,
+				"srv_conn":          1,
+				"retries":           0,
+				"srv_queue":         0,
+				"backend_queue":     0,
+				"method":            "GET",
+				"uri":               "/index.html",
+				"http_version":      "HTTP/1.1",
+			},
+		},
+		{
+			description: "tcp",
+			input:       "10.0.1.2:33313 [06/Feb/2009:12:12:51.443] fnt bck/srv1 0/-1/+5007 212 sC 0/0/0/0/3 0/0",
+			expectedParts: parsercommon.LogParts{
+				"mode":              HAPROXY_MODE_TCP,
+				"client_ip":         "10.0.1.2",
+				"client_port":       33313,
+				"accept_date":       time.Date(2009, time.February, 6, 12, 12, 51, 443*1000*1000, time.UTC),
+				"frontend":          "fnt",
+				"backend":           "bck",
+				"server":            "srv1",
+				"time_queue":        0,
+				"time_connect":      -1,
+				"time_total":        5007,
+				"bytes_read":        212,
+				"termination_state": "sC",
+				"actconn":           0,
+				"feconn":            0,
+				"beconn":            0,
+				"srv_conn":          0,
+				"retries":           3,
+				"srv_queue":         0,
+				"backend_queue":     0,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		obtained, err := ParseHAProxy(tc.input)
+		require.Nil(t, err, tc.description)
+		require.Equal(t, tc.expectedParts, obtained, tc.description)
+	}
+}
+
+func TestParseHAProxyBadRequest(t *testing.T) {
+	obtained, err := ParseHAProxy(
+		`10.0.1.2:33317 [06/Feb/2009:12:14:14.655] http-in http-in/<NOSRV> -1/-1/-1/-1/0 400 187 - - PR-- 1/1/0/0/0 0/0 "<BADREQ>"`,
+	)
+	require.Nil(t, err)
+	require.Equal(t, 400, obtained["status_code"])
+	require.Equal(t, "<NOSRV>", obtained["server"])
+	require.Nil(t, obtained["method"])
+}
+
+func TestParseHAProxyErrors(t *testing.T) {
+	testCases := []struct {
+		description string
+		input       string
+	}{
+		{
+			description: "empty",
+			input:       "",
+		},
+		{
+			description: "not haproxy",
+			input:       "'su root' failed for lonvick on /dev/pts/8",
+		},
+		{
+			description: "invalid accept date",
+			input:       "10.0.1.2:33313 [06/Foo/2009:12:12:51.443] fnt bck/srv1 0/0/5007 212 -- 0/0/0/0/3 0/0",
+		},
+	}
+
+	for _, tc := range testCases {
+		_, err := ParseHAProxy(tc.input)
+		require.ErrorIs(t, err, ErrInvalidHAProxyLog, tc.description)
+	}
+}
+
+func TestHAProxyPayloadParser(t *testing.T) {
+	p := rfc3164.NewParser(
+		[]byte(`<134>Feb  6 12:14:14 localhost haproxy[14389]: 10.0.1.2:33317 [06/Feb/2009:12:14:14.655] http-in static/srv1 10/0/30/69/109 200 2750 - - ---- 1/1/1/1/0 0/0 "GET /index.html HTTP/1.1"`),
+	)
+	p.WithPayloadParser(HAProxy, parsercommon.HEADER_WINS)
+
+	err := p.Parse()
+	require.Nil(t, err)
+
+	obtained := p.Dump()
+	require.Equal(t, "haproxy", obtained["tag"])
+	require.Equal(t, 200, obtained["status_code"])
+	require.Equal(t, "static", obtained["backend"])
+}
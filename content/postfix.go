@@ -0,0 +1,54 @@
+package content
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+)
+
+const (
+	// suffix of the key holding the parenthesized text following a value,
+	// ie. "status_detail" for "status=sent (250 2.0.0 OK)"
+	DETAIL_SUFFIX = "_detail"
+)
+
+// Content parser of Postfix logs (postfix/smtpd, postfix/qmgr...)
+var Postfix = parsercommon.PayloadParserFunc(ParsePostfix)
+
+// "4F9D195432C: ", long queue IDs ("4LzZ4n1Jfnz9vF2: ") having no vowel, or
+// "NOQUEUE: " for rejected messages
+var postfixQueueId = regexp.MustCompile(
+	`^([0-9A-F]{6,15}|[0-9B-DF-HJ-NP-TV-Zb-df-hj-np-tv-z]{10,20}|NOQUEUE): `,
+)
+
+// "relay=mx.example.com[203.0.113.5]:25", "to=<user@example.com>",
+// "status=sent (250 2.0.0 OK)"
+var postfixPair = regexp.MustCompile(
+	`(?:^|[\s,;])([a-z][a-z_-]*)=(<[^>]*>|[^,;\s]*)(?: \(([^)]*)\))?`,
+)
+
+// Parses the content of Postfix logs:
+// 4F9D195432C: to=<user@example.com>, relay=mx.example.com[203.0.113.5]:25, dsn=2.0.0, status=sent (250 OK)
+// The queue ID is reported as "queue_id", key=value pairs as is, angle
+// brackets around addresses being removed, and the text in parentheses
+// after a value as the key followed by DETAIL_SUFFIX. Messages without queue
+// ID nor pairs, ie. "connect from ...", give no field.
+func ParsePostfix(payload string) (parsercommon.LogParts, error) {
+	parts := parsercommon.LogParts{}
+
+	if m := postfixQueueId.FindStringSubmatch(payload); m != nil {
+		parts["queue_id"] = m[1]
+		payload = payload[len(m[0]):]
+	}
+
+	for _, m := range postfixPair.FindAllStringSubmatch(payload, -1) {
+		parts[m[1]] = strings.TrimSuffix(strings.TrimPrefix(m[2], "<"), ">")
+
+		if m[3] != "" {
+			parts[m[1]+DETAIL_SUFFIX] = m[3]
+		}
+	}
+
+	return parts, nil
+}
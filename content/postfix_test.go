@@ -0,0 +1,93 @@
+package content
+
+import (
+	"testing"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+	"github.com/jeromer/syslogparser/rfc3164"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePostfix(t *testing.T) {
+	testCases := []struct {
+		description   string
+		input         string
+		expectedParts parsercommon.LogParts
+	}{
+		{
+			description: "smtp delivery",
+			input:       "4F9D195432C: to=<user@example.com>, relay=mx.example.com[203.0.113.5]:25, delay=0.57, delays=0.11/0.01/0.2/0.25, dsn=2.0.0, status=sent (250 2.0.0 OK 1234 - gsmtp)",
+			expectedParts: parsercommon.LogParts{
+				"queue_id":      "4F9D195432C",
+				"to":            "user@example.com",
+				"relay":         "mx.example.com[203.0.113.5]:25",
+				"delay":         "0.57",
+				"delays":        "0.11/0.01/0.2/0.25",
+				"dsn":           "2.0.0",
+				"status":        "sent",
+				"status_detail": "250 2.0.0 OK 1234 - gsmtp",
+			},
+		},
+		{
+			description: "qmgr, long queue ID",
+			input:       "4LzZ4n1Jfnz9vF2: from=<root@example.com>, size=1234, nrcpt=1 (queue active)",
+			expectedParts: parsercommon.LogParts{
+				"queue_id":     "4LzZ4n1Jfnz9vF2",
+				"from":         "root@example.com",
+				"size":         "1234",
+				"nrcpt":        "1",
+				"nrcpt_detail": "queue active",
+			},
+		},
+		{
+			description: "smtpd reject",
+			input:       "NOQUEUE: reject: RCPT from unknown[198.51.100.7]: 554 5.7.1 <spam@example.net>: Relay access denied; from=<spam@example.net> to=<user@example.org> proto=ESMTP helo=<mail.example.net>",
+			expectedParts: parsercommon.LogParts{
+				"queue_id": "NOQUEUE",
+				"from":     "spam@example.net",
+				"to":       "user@example.org",
+				"proto":    "ESMTP",
+				"helo":     "mail.example.net",
+			},
+		},
+		{
+			description: "cleanup",
+			input:       "4F9D195432C: message-id=<20190510115048.4F9D195432C@example.com>",
+			expectedParts: parsercommon.LogParts{
+				"queue_id":   "4F9D195432C",
+				"message-id": "20190510115048.4F9D195432C@example.com",
+			},
+		},
+		{
+			description:   "no queue ID",
+			input:         "connect from unknown[198.51.100.7]",
+			expectedParts: parsercommon.LogParts{},
+		},
+		{
+			description:   "warning is no queue ID",
+			input:         "warning: hostname example.net does not resolve",
+			expectedParts: parsercommon.LogParts{},
+		},
+	}
+
+	for _, tc := range testCases {
+		obtained, err := ParsePostfix(tc.input)
+		require.Nil(t, err, tc.description)
+		require.Equal(t, tc.expectedParts, obtained, tc.description)
+	}
+}
+
+func TestPostfixPayloadParser(t *testing.T) {
+	p := rfc3164.NewParser(
+		[]byte("<22>Oct 11 22:14:15 mail postfix/smtp[1234]: 4F9D195432C: to=<user@example.com>, relay=mx.example.com[203.0.113.5]:25, dsn=2.0.0, status=sent (250 OK)"),
+	)
+	p.WithPayloadParser(Postfix, parsercommon.HEADER_WINS)
+
+	err := p.Parse()
+	require.Nil(t, err)
+
+	obtained := p.Dump()
+	require.Equal(t, "postfix/smtp", obtained["tag"])
+	require.Equal(t, "4F9D195432C", obtained["queue_id"])
+	require.Equal(t, "sent", obtained["status"])
+}
@@ -0,0 +1,91 @@
+package syslogparser
+
+import (
+	"errors"
+	"reflect"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+)
+
+const (
+	// struct tag giving the key of a field, ie. `syslog:"hostname"`
+	DECODE_TAG = "syslog"
+)
+
+var (
+	ErrInvalidDecodeTarget = errors.New("Decode target must be a non-nil pointer to a struct")
+)
+
+// Copies parts into the struct v points to. Fields are matched by their
+// `syslog:"key"` tag, fields without tag or tagged "-" are left untouched as
+// are fields whose key is missing or nil.
+// Values must be assignable to the field, or both numbers, the field may be
+// a pointer to such a type. A *parsercommon.ValidationError wrapping
+// parsercommon.ErrInvalidType is returned otherwise.
+func Decode(parts LogParts, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return ErrInvalidDecodeTarget
+	}
+
+	rv = rv.Elem()
+	rt := rv.Type()
+
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+
+		key := f.Tag.Get(DECODE_TAG)
+		if key == "" || key == "-" || f.PkgPath != "" {
+			continue
+		}
+
+		value, ok := parts[key]
+		if !ok || value == nil {
+			continue
+		}
+
+		if !decodeValue(rv.Field(i), reflect.ValueOf(value)) {
+			return &parsercommon.ValidationError{
+				Key: key, Err: parsercommon.ErrInvalidType,
+			}
+		}
+	}
+
+	return nil
+}
+
+func decodeValue(field reflect.Value, value reflect.Value) bool {
+	if field.Kind() == reflect.Ptr && value.Kind() != reflect.Ptr {
+		elem := reflect.New(field.Type().Elem())
+		if !decodeValue(elem.Elem(), value) {
+			return false
+		}
+
+		field.Set(elem)
+
+		return true
+	}
+
+	if value.Type().AssignableTo(field.Type()) {
+		field.Set(value)
+		return true
+	}
+
+	if isNumber(value.Kind()) && isNumber(field.Kind()) {
+		field.Set(value.Convert(field.Type()))
+		return true
+	}
+
+	return false
+}
+
+func isNumber(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,81 @@
+package syslogparser
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+	"github.com/jeromer/syslogparser/rfc3164"
+	"github.com/stretchr/testify/require"
+)
+
+type event struct {
+	Host      string     `syslog:"hostname"`
+	Priority  uint8      `syslog:"priority"`
+	Severity  int64      `syslog:"severity"`
+	Timestamp time.Time  `syslog:"timestamp"`
+	Seen      *time.Time `syslog:"timestamp"`
+	Pid       *string    `syslog:"pid"`
+	Content   string     `syslog:"content"`
+	Missing   string     `syslog:"missing"`
+	Ignored   string     `syslog:"-"`
+	Untagged  string
+	private   string `syslog:"tag"`
+}
+
+func TestDecode(t *testing.T) {
+	p := rfc3164.NewParser(
+		[]byte("<34>Oct 11 22:14:15 mymachine sshd[42]: 'su root' failed"),
+	)
+
+	err := p.Parse()
+	require.Nil(t, err)
+
+	parts := p.Dump()
+
+	e := event{Ignored: "ignored", Untagged: "untagged", Missing: "missing"}
+
+	err = Decode(parts, &e)
+	require.Nil(t, err)
+
+	ts := parts["timestamp"].(time.Time)
+	pid := "42"
+
+	require.Equal(
+		t,
+		event{
+			Host:      "mymachine",
+			Priority:  34,
+			Severity:  2,
+			Timestamp: ts,
+			Seen:      &ts,
+			Pid:       &pid,
+			Content:   "'su root' failed",
+			Missing:   "missing",
+			Ignored:   "ignored",
+			Untagged:  "untagged",
+		},
+		e,
+	)
+}
+
+func TestDecodeErrors(t *testing.T) {
+	var e event
+
+	require.Equal(t, ErrInvalidDecodeTarget, Decode(LogParts{}, e))
+	require.Equal(t, ErrInvalidDecodeTarget, Decode(LogParts{}, (*event)(nil)))
+	require.Equal(t, ErrInvalidDecodeTarget, Decode(LogParts{}, new(int)))
+
+	err := Decode(LogParts{"hostname": 42}, &e)
+
+	var verr *parsercommon.ValidationError
+	require.True(t, errors.As(err, &verr))
+	require.Equal(t, "hostname", verr.Key)
+	require.ErrorIs(t, err, parsercommon.ErrInvalidType)
+
+	err = Decode(LogParts{"timestamp": nil, "hostname": "mymachine"}, &e)
+	require.Nil(t, err)
+	require.Equal(t, "mymachine", e.Host)
+	require.Nil(t, e.Seen)
+}
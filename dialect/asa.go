@@ -0,0 +1,240 @@
+package dialect
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/jeromer/syslogparser"
+	"github.com/jeromer/syslogparser/parsercommon"
+)
+
+const (
+	// reported as "parser" when diagnostics are enabled
+	CISCO_ASA_NAME = "cisco_asa"
+
+	// "Oct 11 2003 22:14:15", sent when "logging timestamp" is enabled
+	ASA_TIMESTAMP_FORMAT = "Jan _2 2006 15:04:05"
+)
+
+var (
+	ErrInvalidMessageId = &parsercommon.ParserError{ErrorString: "Invalid ASA message ID"}
+)
+
+var asaCode = []byte("%ASA-")
+
+// Cisco ASA messages:
+// <166>Oct 11 2003 22:14:15: %ASA-6-302013: Built outbound TCP connection ...
+var CiscoASA = syslogparser.Dialect{
+	Name:   CISCO_ASA_NAME,
+	Detect: IsCiscoASA,
+	New: func(buff []byte) syslogparser.LogParser {
+		return NewAsaParser(buff)
+	},
+}
+
+// Fields of well-known messages, indexed by message ID. Sub-expressions are
+// named after the keys added to Dump()
+var asaMessages = map[string]*regexp.Regexp{
+	// Built inbound TCP connection 123 for outside:10.0.0.1/1234 (10.0.0.1/1234) to inside:192.168.1.2/80 (...)
+	"302013": asaBuilt,
+	"302015": asaBuilt,
+
+	// Teardown TCP connection 123 for outside:10.0.0.1/1234 to inside:192.168.1.2/80 duration ...
+	"302014": asaTeardown,
+	"302016": asaTeardown,
+
+	// Deny tcp src outside:10.0.0.1/1234 dst inside:192.168.1.2/80 by access-group "acl_out" [...]
+	"106023": regexp.MustCompile(
+		`^(?P<action>Deny) (?P<protocol>\S+) src (?P<src_interface>[^:\s]+):(?P<src_ip>[^/\s]+)/(?P<src_port>\d+) ` +
+			`dst (?P<dst_interface>[^:\s]+):(?P<dst_ip>[^/\s]+)/(?P<dst_port>\d+) by access-group "(?P<acl>[^"]+)"`,
+	),
+
+	// access-list acl_out permitted tcp outside/10.0.0.1(1234) -> inside/192.168.1.2(80) hit-cnt 1 ...
+	"106100": regexp.MustCompile(
+		`^access-list (?P<acl>\S+) (?P<action>\S+) (?P<protocol>\S+) ` +
+			`(?P<src_interface>[^/\s]+)/(?P<src_ip>[^(\s]+)\((?P<src_port>\d+)\) -> ` +
+			`(?P<dst_interface>[^/\s]+)/(?P<dst_ip>[^(\s]+)\((?P<dst_port>\d+)\)`,
+	),
+}
+
+var asaBuilt = regexp.MustCompile(
+	`^(?P<action>Built) (?P<direction>inbound|outbound) (?P<protocol>\S+) connection (?P<connection_id>\d+) ` +
+		`for (?P<for_interface>[^:\s]+):(?P<for_ip>[^/\s]+)/(?P<for_port>\d+) (?:\(\S+\) )?` +
+		`to (?P<to_interface>[^:\s]+):(?P<to_ip>[^/\s]+)/(?P<to_port>\d+)`,
+)
+
+var asaTeardown = regexp.MustCompile(
+	`^(?P<action>Teardown) (?P<protocol>\S+) connection (?P<connection_id>\d+) ` +
+		`for (?P<src_interface>[^:\s]+):(?P<src_ip>[^/\s]+)/(?P<src_port>\d+) ` +
+		`to (?P<dst_interface>[^:\s]+):(?P<dst_ip>[^/\s]+)/(?P<dst_port>\d+)`,
+)
+
+// Parses Cisco ASA messages. The header, if any, is parsed by the RFC3164
+// parser, the %ASA-SEVERITY-ID code gives "asa_severity" and
+// "asa_message_id" and the key fields of well-known messages (connections
+// built and torn down, denied packets, access lists hits) are extracted:
+// "action", "protocol", "src_interface", "src_ip", "src_port",
+// "dst_interface", "dst_ip", "dst_port" and, when available, "direction",
+// "connection_id" and "acl".
+type AsaParser struct {
+	buff     []byte
+	location *time.Location
+	hostname string
+	diag     diagnostics
+
+	header    parsercommon.LogParts
+	severity  int
+	messageId string
+	content   string
+	fields    map[string]interface{}
+}
+
+func NewAsaParser(buff []byte) *AsaParser {
+	return &AsaParser{
+		buff:     buff,
+		location: time.UTC,
+	}
+}
+
+func (p *AsaParser) WithLocation(l *time.Location) {
+	p.location = l
+}
+
+func (p *AsaParser) WithHostname(h string) {
+	p.hostname = h
+}
+
+// Noop, both RFC3164 and ASA_TIMESTAMP_FORMAT timestamps are accepted
+func (p *AsaParser) WithTimestampFormat(s string) {}
+
+// Noop, the tag of ASA messages is their code
+func (p *AsaParser) WithTag(t string) {}
+
+// Adds the name of the parser ("parser") and the time spent parsing
+// ("parse_duration", a time.Duration) to Dump()
+func (p *AsaParser) WithDiagnostics() {
+	p.diag.enabled = true
+}
+
+func (p *AsaParser) Parse() error {
+	return p.diag.parse(p.parse)
+}
+
+func (p *AsaParser) parse() error {
+	i := bytes.Index(p.buff, asaCode)
+	if i < 0 {
+		return ErrInvalidMessageId
+	}
+
+	hdr, err := parseHeader(
+		bytes.TrimRight(p.buff[:i], " :"), p.location, "", ASA_TIMESTAMP_FORMAT,
+	)
+	if err != nil {
+		return err
+	}
+
+	p.header = hdr
+
+	return p.parseMessage(p.buff[i+len(asaCode):])
+}
+
+// "6-302013: Built outbound ..."
+func (p *AsaParser) parseMessage(b []byte) error {
+	end := bytes.IndexByte(b, ':')
+	if end < 3 || !parsercommon.IsDigit(b[0]) || b[1] != '-' {
+		return ErrInvalidMessageId
+	}
+
+	id := b[2:end]
+	for _, c := range id {
+		if !parsercommon.IsDigit(c) {
+			return ErrInvalidMessageId
+		}
+	}
+
+	p.severity = int(b[0] - '0')
+	p.messageId = string(id)
+	p.content = string(bytes.Trim(b[end+1:], " "))
+	p.fields = parseAsaFields(p.messageId, p.content)
+
+	return nil
+}
+
+func (p *AsaParser) Dump() parsercommon.LogParts {
+	parts := parsercommon.LogParts{}
+
+	for k, v := range p.header {
+		parts[k] = v
+	}
+
+	if p.hostname != "" {
+		parts["hostname"] = p.hostname
+	}
+
+	for k, v := range p.fields {
+		parts[k] = v
+	}
+
+	parts["tag"] = "%ASA-" + strconv.Itoa(p.severity) + "-" + p.messageId
+	parts["content"] = p.content
+	parts["asa_severity"] = p.severity
+	parts["asa_message_id"] = p.messageId
+
+	p.diag.dump(parts, CISCO_ASA_NAME)
+
+	return parts
+}
+
+func parseAsaFields(id string, content string) map[string]interface{} {
+	re, ok := asaMessages[id]
+	if !ok {
+		return nil
+	}
+
+	m := re.FindStringSubmatch(content)
+	if m == nil {
+		return nil
+	}
+
+	fields := make(map[string]interface{}, len(m))
+
+	for i, name := range re.SubexpNames() {
+		if name != "" {
+			fields[name] = m[i]
+		}
+	}
+
+	// XXX : "for" is the outside end of the connection, the source of
+	// XXX : inbound connections but the destination of outbound ones
+	if dir, ok := fields["direction"]; ok {
+		src, dst := "for_", "to_"
+		if dir == "outbound" {
+			src, dst = dst, src
+		}
+
+		for _, k := range []string{"interface", "ip", "port"} {
+			fields["src_"+k] = fields[src+k]
+			fields["dst_"+k] = fields[dst+k]
+
+			delete(fields, "for_"+k)
+			delete(fields, "to_"+k)
+		}
+	}
+
+	for _, k := range []string{"src_port", "dst_port", "connection_id"} {
+		if s, ok := fields[k].(string); ok {
+			if n, err := strconv.Atoi(s); err == nil {
+				fields[k] = n
+			}
+		}
+	}
+
+	return fields
+}
+
+// Returns true when buff has PRI and a %ASA-SEVERITY-ID code
+func IsCiscoASA(buff []byte) bool {
+	return len(buff) > 0 && buff[0] == '<' && bytes.Contains(buff, asaCode)
+}
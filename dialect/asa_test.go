@@ -0,0 +1,252 @@
+package dialect
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jeromer/syslogparser"
+	"github.com/jeromer/syslogparser/parsercommon"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAsaParser(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		description       string
+		input             string
+		expectedTimestamp time.Time
+		expectedHostname  string
+		expectedSeverity  int
+		expectedMessageId string
+		expectedFields    map[string]interface{}
+	}{
+		{
+			description:       "timestamp with year, outbound connection",
+			input:             "<166>Oct 11 2003 22:14:15: %ASA-6-302013: Built outbound TCP connection 123 for outside:10.0.0.1/443 (10.0.0.1/443) to inside:192.168.1.2/51234 (203.0.113.1/51234)",
+			expectedTimestamp: time.Date(2003, time.October, 11, 22, 14, 15, 0, time.UTC),
+			expectedHostname:  "",
+			expectedSeverity:  6,
+			expectedMessageId: "302013",
+			expectedFields: map[string]interface{}{
+				"action":        "Built",
+				"direction":     "outbound",
+				"protocol":      "TCP",
+				"connection_id": 123,
+				"src_interface": "inside",
+				"src_ip":        "192.168.1.2",
+				"src_port":      51234,
+				"dst_interface": "outside",
+				"dst_ip":        "10.0.0.1",
+				"dst_port":      443,
+			},
+		},
+		{
+			description:       "hostname, inbound connection",
+			input:             "<166>Oct 11 22:14:15 fw01 %ASA-6-302015: Built inbound UDP connection 7 for outside:10.0.0.1/53 (10.0.0.1/53) to inside:192.168.1.2/5353 (192.168.1.2/5353)",
+			expectedTimestamp: time.Date(now.Year(), time.October, 11, 22, 14, 15, 0, time.UTC),
+			expectedHostname:  "fw01",
+			expectedSeverity:  6,
+			expectedMessageId: "302015",
+			expectedFields: map[string]interface{}{
+				"action":        "Built",
+				"direction":     "inbound",
+				"protocol":      "UDP",
+				"connection_id": 7,
+				"src_interface": "outside",
+				"src_ip":        "10.0.0.1",
+				"src_port":      53,
+				"dst_interface": "inside",
+				"dst_ip":        "192.168.1.2",
+				"dst_port":      5353,
+			},
+		},
+		{
+			description:       "device id, teardown",
+			input:             "<166>Oct 11 2003 22:14:15 fw01 : %ASA-6-302014: Teardown TCP connection 123 for outside:10.0.0.1/443 to inside:192.168.1.2/51234 duration 0:00:30 bytes 1024 TCP FINs",
+			expectedTimestamp: time.Date(2003, time.October, 11, 22, 14, 15, 0, time.UTC),
+			expectedHostname:  "fw01",
+			expectedSeverity:  6,
+			expectedMessageId: "302014",
+			expectedFields: map[string]interface{}{
+				"action":        "Teardown",
+				"protocol":      "TCP",
+				"connection_id": 123,
+				"src_interface": "outside",
+				"src_ip":        "10.0.0.1",
+				"src_port":      443,
+				"dst_interface": "inside",
+				"dst_ip":        "192.168.1.2",
+				"dst_port":      51234,
+			},
+		},
+		{
+			description:       "no timestamp, denied by access group",
+			input:             `<164>%ASA-4-106023: Deny tcp src outside:198.51.100.7/40000 dst inside:192.168.1.2/22 by access-group "outside_in" [0x0, 0x0]`,
+			expectedTimestamp: time.Time{},
+			expectedHostname:  "",
+			expectedSeverity:  4,
+			expectedMessageId: "106023",
+			expectedFields: map[string]interface{}{
+				"action":        "Deny",
+				"protocol":      "tcp",
+				"src_interface": "outside",
+				"src_ip":        "198.51.100.7",
+				"src_port":      40000,
+				"dst_interface": "inside",
+				"dst_ip":        "192.168.1.2",
+				"dst_port":      22,
+				"acl":           "outside_in",
+			},
+		},
+		{
+			description:       "access list hit",
+			input:             "<166>Oct 11 22:14:15 fw01 %ASA-6-106100: access-list outside_in permitted tcp outside/198.51.100.7(40000) -> inside/192.168.1.2(443) hit-cnt 1 first hit [0x0, 0x0]",
+			expectedTimestamp: time.Date(now.Year(), time.October, 11, 22, 14, 15, 0, time.UTC),
+			expectedHostname:  "fw01",
+			expectedSeverity:  6,
+			expectedMessageId: "106100",
+			expectedFields: map[string]interface{}{
+				"action":        "permitted",
+				"protocol":      "tcp",
+				"src_interface": "outside",
+				"src_ip":        "198.51.100.7",
+				"src_port":      40000,
+				"dst_interface": "inside",
+				"dst_ip":        "192.168.1.2",
+				"dst_port":      443,
+				"acl":           "outside_in",
+			},
+		},
+		{
+			description:       "unknown message ID",
+			input:             "<165>Oct 11 22:14:15 fw01 %ASA-5-111008: User 'enable_15' executed the 'write memory' command.",
+			expectedTimestamp: time.Date(now.Year(), time.October, 11, 22, 14, 15, 0, time.UTC),
+			expectedHostname:  "fw01",
+			expectedSeverity:  5,
+			expectedMessageId: "111008",
+			expectedFields:    map[string]interface{}{},
+		},
+	}
+
+	for _, tc := range testCases {
+		p := NewAsaParser([]byte(tc.input))
+
+		err := p.Parse()
+		require.Nil(t, err, tc.description)
+
+		obtained := p.Dump()
+		require.Equal(t, tc.expectedTimestamp, obtained["timestamp"], tc.description)
+		require.Equal(t, tc.expectedHostname, obtained["hostname"], tc.description)
+		require.Equal(t, tc.expectedSeverity, obtained["asa_severity"], tc.description)
+		require.Equal(t, tc.expectedMessageId, obtained["asa_message_id"], tc.description)
+
+		for _, k := range []string{"action", "direction", "protocol", "connection_id", "src_interface", "src_ip", "src_port", "dst_interface", "dst_ip", "dst_port", "acl"} {
+			require.Equal(t, tc.expectedFields[k], obtained[k], tc.description+": "+k)
+		}
+	}
+}
+
+func TestAsaParserDump(t *testing.T) {
+	p := NewAsaParser(
+		[]byte("<166>Oct 11 22:14:15 fw01 %ASA-6-302014: Teardown TCP connection 1 for outside:10.0.0.1/443 to inside:192.168.1.2/51234"),
+	)
+	p.WithHostname("firewall")
+
+	err := p.Parse()
+	require.Nil(t, err)
+
+	obtained := p.Dump()
+	require.Equal(t, 166, obtained["priority"])
+	require.Equal(t, 20, obtained["facility"])
+	require.Equal(t, 6, obtained["severity"])
+	require.Equal(t, "firewall", obtained["hostname"])
+	require.Equal(t, "%ASA-6-302014", obtained["tag"])
+	require.Equal(t, "Teardown TCP connection 1 for outside:10.0.0.1/443 to inside:192.168.1.2/51234", obtained["content"])
+	require.NotContains(t, obtained, "parser")
+}
+
+func TestAsaParserWithDiagnostics(t *testing.T) {
+	buff := []byte(
+		"<166>Oct 11 22:14:15 fw01 %ASA-6-302014: Teardown TCP connection 1 for outside:10.0.0.1/443 to inside:192.168.1.2/51234",
+	)
+
+	p := NewAsaParser(buff)
+	p.WithDiagnostics()
+
+	err := p.Parse()
+	require.Nil(t, err)
+
+	obtained := p.Dump()
+	require.Equal(t, CISCO_ASA_NAME, obtained["parser"])
+	require.IsType(t, time.Duration(0), obtained["parse_duration"])
+}
+
+func TestAsaParserErrors(t *testing.T) {
+	testCases := []struct {
+		description string
+		input       string
+		expectedErr error
+	}{
+		{
+			description: "no priority",
+			input:       "Oct 11 22:14:15 fw01 %ASA-6-302014: Teardown",
+			expectedErr: parsercommon.ErrPriorityNoStart,
+		},
+		{
+			description: "invalid timestamp",
+			input:       "<166>Foo 11 22:14:15 fw01 %ASA-6-302014: Teardown",
+			expectedErr: parsercommon.ErrTimestampUnknownFormat,
+		},
+		{
+			description: "no message ID",
+			input:       "<166>Oct 11 22:14:15 fw01 Teardown",
+			expectedErr: ErrInvalidMessageId,
+		},
+		{
+			description: "invalid message ID",
+			input:       "<166>Oct 11 22:14:15 fw01 %ASA-6-30a014: Teardown",
+			expectedErr: ErrInvalidMessageId,
+		},
+	}
+
+	for _, tc := range testCases {
+		p := NewAsaParser([]byte(tc.input))
+
+		err := p.Parse()
+		require.ErrorIs(t, err, tc.expectedErr, tc.description)
+	}
+}
+
+func TestIsCiscoASA(t *testing.T) {
+	require.True(t, IsCiscoASA([]byte("<166>%ASA-6-302014: Teardown")))
+	require.False(t, IsCiscoASA([]byte("<189>145: *Oct 11 22:14:15.123: %LINK-3-UPDOWN: up")))
+	require.False(t, IsCiscoASA(nil))
+}
+
+func TestCiscoASADialect(t *testing.T) {
+	r := syslogparser.NewRegistry()
+	require.Nil(t, r.Register(CiscoASA))
+
+	p, name, err := r.NewAutoParser(
+		[]byte("<166>%ASA-6-302014: Teardown TCP connection 1 for outside:10.0.0.1/443 to inside:192.168.1.2/51234"),
+	)
+	require.Nil(t, err)
+	require.Equal(t, CISCO_ASA_NAME, name)
+	require.Nil(t, p.Parse())
+	require.Equal(t, "302014", p.Dump()["asa_message_id"])
+}
+
+func TestCiscoASADialectAfterIOS(t *testing.T) {
+	r := syslogparser.NewRegistry()
+	require.Nil(t, r.Register(CiscoIOS))
+	require.Nil(t, r.Register(CiscoASA))
+
+	p, name, err := r.NewAutoParser(
+		[]byte("<166>Oct 11 2003 22:14:15: %ASA-6-302013: Built outbound TCP connection 1 for outside:10.0.0.1/443 (10.0.0.1/443) to inside:192.168.1.2/51234 (192.168.1.2/51234)"),
+	)
+	require.Nil(t, err)
+	require.Equal(t, CISCO_ASA_NAME, name)
+	require.Nil(t, p.Parse())
+	require.Equal(t, "192.168.1.2", p.Dump()["src_ip"])
+}
@@ -0,0 +1,79 @@
+package dialect
+
+import (
+	"strings"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+)
+
+const (
+	// key of the nested map of fields returned by ParseCheckPoint()
+	CHECKPOINT_KEY = "checkpoint"
+)
+
+var (
+	ErrInvalidCheckPointField = &parsercommon.ParserError{ErrorString: "Invalid Check Point key: value field"}
+)
+
+// Content parser of Check Point logs sent by Log Exporter, to be given to
+// WithPayloadParser()
+var CheckPoint = parsercommon.PayloadParserFunc(ParseCheckPoint)
+
+// Parses semicolon separated "key: value" fields, values being optionally
+// double quoted, as a whole optionally enclosed in brackets:
+// [action:"Accept"; ifdir:"inbound"; origin:"10.0.0.1"; src:"192.0.2.1";]
+// time: 1589459114; product: VPN-1 & FireWall-1; src: 192.0.2.1;
+// Fields are returned as a map[string]string under CHECKPOINT_KEY.
+func ParseCheckPoint(payload string) (parsercommon.LogParts, error) {
+	fields := map[string]string{}
+
+	s := strings.TrimSpace(payload)
+	if strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]") {
+		s = strings.TrimSpace(s[1 : len(s)-1])
+	}
+
+	for len(s) > 0 {
+		colon := strings.IndexByte(s, ':')
+		if colon < 1 {
+			return nil, ErrInvalidCheckPointField
+		}
+
+		key := strings.TrimSpace(s[:colon])
+		if len(key) == 0 || strings.ContainsAny(key, " ;") {
+			return nil, ErrInvalidCheckPointField
+		}
+
+		s = strings.TrimLeft(s[colon+1:], " ")
+
+		var value string
+
+		if strings.HasPrefix(s, `"`) {
+			end := closingQuote(s)
+			if end < 0 {
+				return nil, ErrUnterminatedQuote
+			}
+
+			value = strings.Replace(s[1:end], `\"`, `"`, -1)
+			s = strings.TrimLeft(s[end+1:], " ")
+
+			if len(s) > 0 && s[0] != ';' {
+				return nil, ErrInvalidCheckPointField
+			}
+		} else {
+			end := strings.IndexByte(s, ';')
+			if end < 0 {
+				end = len(s)
+			}
+
+			value = strings.TrimSpace(s[:end])
+			s = s[end:]
+		}
+
+		fields[key] = value
+		s = strings.TrimLeft(strings.TrimPrefix(s, ";"), " ")
+	}
+
+	return parsercommon.LogParts{
+		CHECKPOINT_KEY: fields,
+	}, nil
+}
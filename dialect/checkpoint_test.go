@@ -0,0 +1,120 @@
+package dialect
+
+import (
+	"testing"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+	"github.com/jeromer/syslogparser/rfc5424"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCheckPoint(t *testing.T) {
+	testCases := []struct {
+		description    string
+		input          string
+		expectedFields map[string]string
+	}{
+		{
+			description: "log exporter",
+			input:       `[action:"Accept"; flags:"411908"; ifdir:"inbound"; origin:"10.0.0.1"; src:"192.0.2.1"; service:"443";]`,
+			expectedFields: map[string]string{
+				"action":  "Accept",
+				"flags":   "411908",
+				"ifdir":   "inbound",
+				"origin":  "10.0.0.1",
+				"src":     "192.0.2.1",
+				"service": "443",
+			},
+		},
+		{
+			description: "unquoted values",
+			input:       `time: 1589459114; product: VPN-1 & FireWall-1; src: 192.0.2.1; dst: 198.51.100.7`,
+			expectedFields: map[string]string{
+				"time":    "1589459114",
+				"product": "VPN-1 & FireWall-1",
+				"src":     "192.0.2.1",
+				"dst":     "198.51.100.7",
+			},
+		},
+		{
+			description: "separators within quotes",
+			input:       `msg:"a; b: \"c\""; proto:"6"`,
+			expectedFields: map[string]string{
+				"msg":   `a; b: "c"`,
+				"proto": "6",
+			},
+		},
+		{
+			description: "colon within value",
+			input:       `time:"2020-05-14T12:25:14Z"; s_port: 51234;`,
+			expectedFields: map[string]string{
+				"time":   "2020-05-14T12:25:14Z",
+				"s_port": "51234",
+			},
+		},
+		{
+			description:    "empty",
+			input:          "[]",
+			expectedFields: map[string]string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		obtained, err := ParseCheckPoint(tc.input)
+		require.Nil(t, err, tc.description)
+		require.Equal(
+			t,
+			parsercommon.LogParts{CHECKPOINT_KEY: tc.expectedFields},
+			obtained,
+			tc.description,
+		)
+	}
+}
+
+func TestParseCheckPointErrors(t *testing.T) {
+	testCases := []struct {
+		description string
+		input       string
+		expectedErr error
+	}{
+		{
+			description: "missing colon",
+			input:       `action:"Accept"; inbound`,
+			expectedErr: ErrInvalidCheckPointField,
+		},
+		{
+			description: "empty key",
+			input:       `:"Accept"`,
+			expectedErr: ErrInvalidCheckPointField,
+		},
+		{
+			description: "unterminated quote",
+			input:       `action:"Accept; ifdir: inbound`,
+			expectedErr: ErrUnterminatedQuote,
+		},
+		{
+			description: "garbage after quote",
+			input:       `action:"Accept"x; ifdir:"inbound"`,
+			expectedErr: ErrInvalidCheckPointField,
+		},
+	}
+
+	for _, tc := range testCases {
+		_, err := ParseCheckPoint(tc.input)
+		require.ErrorIs(t, err, tc.expectedErr, tc.description)
+	}
+}
+
+func TestCheckPointPayloadParser(t *testing.T) {
+	p := rfc5424.NewParser(
+		[]byte(`<134>1 2020-05-14T12:25:14Z cp-mgmt CheckPoint 24305 - - [action:"Drop"; ifdir:"inbound"; src:"192.0.2.1";]`),
+	)
+	p.WithPayloadParser(CheckPoint, parsercommon.HEADER_WINS)
+
+	err := p.Parse()
+	require.Nil(t, err)
+
+	fields := p.Dump()[CHECKPOINT_KEY].(map[string]string)
+	require.Equal(t, "Drop", fields["action"])
+	require.Equal(t, "192.0.2.1", fields["src"])
+}
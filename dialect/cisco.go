@@ -0,0 +1,363 @@
+package dialect
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jeromer/syslogparser"
+	"github.com/jeromer/syslogparser/parsercommon"
+)
+
+const (
+	// reported as "parser" when diagnostics are enabled
+	CISCO_IOS_NAME = "cisco_ios"
+
+	// "%LINK-3-UPDOWN"
+	MNEMONIC_START = '%'
+)
+
+var ciscoTimestampFormats = []string{
+	"Jan _2 15:04:05.000",
+	"Jan _2 15:04:05",
+	"Jan _2 2006 15:04:05.000",
+	"Jan _2 2006 15:04:05",
+}
+
+var (
+	ErrInvalidMnemonic = &parsercommon.ParserError{ErrorString: "Invalid mnemonic"}
+)
+
+// Cisco IOS messages:
+// <189>145: *Oct 11 22:14:15.123: %LINK-3-UPDOWN: Interface ...
+// <189>145: %LINK-3-UPDOWN: Interface ...
+// <189>Oct 11 22:14:15 router1 145: *Oct 11 22:14:15.123: %LINK-3-UPDOWN: ...
+var CiscoIOS = syslogparser.Dialect{
+	Name:   CISCO_IOS_NAME,
+	Detect: IsCiscoIOS,
+	New: func(buff []byte) syslogparser.LogParser {
+		return NewCiscoParser(buff)
+	},
+}
+
+// Parses Cisco IOS messages: an optional sequence number, an optional
+// timestamp, starred when the clock is not synchronized, and a
+// %FACILITY-SEVERITY-MNEMONIC code followed by the message. Messages relayed
+// by a syslog server may start with an RFC3164 header giving the hostname,
+// and the timestamp when IOS does not send any.
+type CiscoParser struct {
+	buff     []byte
+	cursor   parsercommon.Cursor
+	location *time.Location
+	hostname string
+	diag     diagnostics
+
+	priority     *parsercommon.Priority
+	header       parsercommon.LogParts
+	sequence     int
+	hasSeq       bool
+	hasTimestamp bool
+	synced       bool
+	timestamp    time.Time
+	facility     string
+	severity     int
+	mnemonic     string
+	content      string
+}
+
+func NewCiscoParser(buff []byte) *CiscoParser {
+	return &CiscoParser{
+		buff:     buff,
+		cursor:   parsercommon.NewCursor(buff, len(buff)),
+		location: time.UTC,
+	}
+}
+
+// Timezone of timestamps without one, UTC by default
+func (p *CiscoParser) WithLocation(l *time.Location) {
+	p.location = l
+}
+
+// IOS messages have no hostname, it can be set with WithHostname()
+func (p *CiscoParser) WithHostname(h string) {
+	p.hostname = h
+}
+
+// Noop, IOS timestamps have a fixed format
+func (p *CiscoParser) WithTimestampFormat(s string) {}
+
+// Noop, the tag of IOS messages is their mnemonic
+func (p *CiscoParser) WithTag(t string) {}
+
+// Adds the name of the parser ("parser") and the time spent parsing
+// ("parse_duration", a time.Duration) to Dump()
+func (p *CiscoParser) WithDiagnostics() {
+	p.diag.enabled = true
+}
+
+func (p *CiscoParser) Parse() error {
+	return p.diag.parse(p.parse)
+}
+
+func (p *CiscoParser) parse() error {
+	pri, err := p.cursor.ParsePriority()
+	if err != nil {
+		return p.cursor.Locate(err, 0, "priority")
+	}
+
+	p.priority = pri
+
+	from := p.cursor.Pos()
+
+	code := findMnemonic(p.cursor.Rest())
+	if code < 0 {
+		return p.cursor.Locate(ErrInvalidMnemonic, from, "tag")
+	}
+
+	err = p.parsePrefix(from, from+code)
+	if err != nil {
+		return p.cursor.Locate(err, from, "timestamp")
+	}
+
+	from = p.cursor.Pos()
+
+	err = p.parseMnemonic()
+	if err != nil {
+		return p.cursor.Locate(err, from, "tag")
+	}
+
+	p.content = string(bytes.Trim(p.cursor.Rest(), " "))
+
+	return nil
+}
+
+// Parses what lies between PRI, ending at start, and the code, at end: the
+// sequence number and timestamp, each of them optional, possibly preceded by
+// the RFC3164 header of a relay. The header ends at the first word from
+// which the rest can be parsed that way.
+func (p *CiscoParser) parsePrefix(start int, end int) error {
+	buff := p.buff
+
+	for i := start; i <= end; i++ {
+		if i > start && buff[i-1] != ' ' {
+			continue
+		}
+
+		p.cursor = parsercommon.NewCursor(buff[:end], end)
+		p.cursor.SetPos(i)
+		p.hasSeq, p.sequence = false, 0
+		p.hasTimestamp, p.timestamp = false, time.Time{}
+
+		p.parseSequence()
+
+		if !p.cursor.EOF() && (p.parseTimestamp() != nil || !p.cursor.EOF()) {
+			continue
+		}
+
+		if i > start {
+			hdr, err := parseHeader(bytes.TrimRight(buff[:i], " "), p.location, "")
+
+			// "Oct 11 22:14:15 router1", not a tag nor a message
+			if err != nil || hdr["content"] != "" {
+				continue
+			}
+
+			p.header = hdr
+		}
+
+		p.cursor = parsercommon.NewCursor(buff, len(buff))
+		p.cursor.SetPos(end)
+
+		return nil
+	}
+
+	p.cursor = parsercommon.NewCursor(buff, len(buff))
+
+	return parsercommon.ErrTimestampUnknownFormat
+}
+
+// "timestamp_synced" is only set when the message has an IOS timestamp
+func (p *CiscoParser) Dump() parsercommon.LogParts {
+	parts := parsercommon.LogParts{
+		"priority":       p.priority.P,
+		"facility":       p.priority.F.Value,
+		"severity":       p.priority.S.Value,
+		"version":        parsercommon.NO_VERSION,
+		"timestamp":      p.timestamp,
+		"hostname":       p.hostname,
+		"tag":            p.Tag(),
+		"content":        p.content,
+		"cisco_facility": p.facility,
+		"cisco_severity": p.severity,
+		"cisco_mnemonic": p.mnemonic,
+	}
+
+	if p.header != nil {
+		if p.hostname == "" {
+			parts["hostname"] = p.header["hostname"]
+		}
+
+		if !p.hasTimestamp {
+			parts["timestamp"] = p.header["timestamp"]
+		}
+	}
+
+	if p.hasTimestamp {
+		parts["timestamp_synced"] = p.synced
+	}
+
+	if p.hasSeq {
+		parts["sequence"] = p.sequence
+	}
+
+	p.diag.dump(parts, CISCO_IOS_NAME)
+
+	return parts
+}
+
+// "LINK-3-UPDOWN"
+func (p *CiscoParser) Tag() string {
+	return p.facility + "-" + strconv.Itoa(p.severity) + "-" + p.mnemonic
+}
+
+// "145: "
+func (p *CiscoParser) parseSequence() {
+	rest := p.cursor.Rest()
+
+	i := 0
+	for i < len(rest) && parsercommon.IsDigit(rest[i]) {
+		i++
+	}
+
+	if i == 0 || !bytes.HasPrefix(rest[i:], []byte(": ")) {
+		return
+	}
+
+	seq, err := strconv.Atoi(string(rest[:i]))
+	if err != nil {
+		return
+	}
+
+	p.sequence = seq
+	p.hasSeq = true
+	p.cursor.Advance(i + 2)
+}
+
+// "*Oct 11 22:14:15.123: " or "Oct 11 22:14:15 UTC: ", "*" or "." telling
+// the clock is not synchronized
+func (p *CiscoParser) parseTimestamp() error {
+	p.synced = true
+
+	if p.cursor.Expect('*') || p.cursor.Expect('.') {
+		p.synced = false
+	}
+
+	rest := p.cursor.Rest()
+
+	end := bytes.Index(rest, []byte(": "))
+	if end < 0 {
+		return parsercommon.ErrTimestampUnknownFormat
+	}
+
+	sub := string(rest[:end])
+	loc := p.location
+
+	// XXX : "Oct 11 22:14:15 UTC", the zone is ignored when unknown
+	if i := strings.LastIndexByte(sub, ' '); i > 0 && !parsercommon.IsDigit(sub[len(sub)-1]) {
+		if l, err := parsercommon.LoadLocation(sub[i+1:]); err == nil {
+			loc = l
+		}
+
+		sub = sub[:i]
+	}
+
+	for _, layout := range ciscoTimestampFormats {
+		ts, err := time.ParseInLocation(layout, sub, loc)
+		if err == nil {
+			p.timestamp = fixYear(ts)
+			p.hasTimestamp = true
+			p.cursor.Advance(end + 2)
+
+			return nil
+		}
+	}
+
+	return parsercommon.ErrTimestampUnknownFormat
+}
+
+// "%LINK-3-UPDOWN: "
+func (p *CiscoParser) parseMnemonic() error {
+	if !p.cursor.Expect(MNEMONIC_START) {
+		return ErrInvalidMnemonic
+	}
+
+	rest := p.cursor.Rest()
+
+	end := bytes.IndexByte(rest, ':')
+	if end < 0 {
+		return ErrInvalidMnemonic
+	}
+
+	f, s, m, ok := splitMnemonic(rest[:end])
+	if !ok {
+		return ErrInvalidMnemonic
+	}
+
+	p.facility, p.severity, p.mnemonic = f, s, m
+	p.cursor.Advance(end + 1)
+
+	return nil
+}
+
+// "LINK-3-UPDOWN" => "LINK", 3, "UPDOWN"
+func splitMnemonic(b []byte) (string, int, string, bool) {
+	i := bytes.IndexByte(b, '-')
+	if i <= 0 || i+3 > len(b) {
+		return "", 0, "", false
+	}
+
+	if !parsercommon.IsDigit(b[i+1]) || b[i+2] != '-' || i+3 == len(b) {
+		return "", 0, "", false
+	}
+
+	return string(b[:i]), int(b[i+1] - '0'), string(b[i+3:]), true
+}
+
+// Offset in b of the first %FACILITY-SEVERITY-MNEMONIC code starting b or
+// a word, -1 when there is none
+func findMnemonic(b []byte) int {
+	for i := 0; i < len(b); i++ {
+		if b[i] != MNEMONIC_START || (i > 0 && b[i-1] != ' ') {
+			continue
+		}
+
+		end := bytes.IndexByte(b[i:], ':')
+		if end < 0 {
+			return -1
+		}
+
+		if _, _, _, ok := splitMnemonic(b[i+1 : i+end]); ok {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Returns true when buff is an IOS message, ie. it can be parsed by
+// CiscoParser. ASA and FTD firewall codes (%ASA-6-302013) share the syntax
+// of IOS ones, they are rejected so detection does not depend on the order
+// dialects are registered in.
+func IsCiscoIOS(buff []byte) bool {
+	if len(buff) == 0 || buff[0] != '<' {
+		return false
+	}
+
+	p := NewCiscoParser(buff)
+	if p.Parse() != nil {
+		return false
+	}
+
+	return p.facility != "ASA" && p.facility != "FTD"
+}
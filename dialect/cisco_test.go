@@ -0,0 +1,247 @@
+package dialect
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jeromer/syslogparser"
+	"github.com/jeromer/syslogparser/parsercommon"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCiscoParser(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		description       string
+		input             string
+		expectedSequence  interface{}
+		expectedTimestamp time.Time
+		expectedSynced    bool
+		expectedFacility  string
+		expectedSeverity  int
+		expectedMnemonic  string
+		expectedContent   string
+	}{
+		{
+			description:       "sequence and starred timestamp",
+			input:             "<189>145: *Oct 11 22:14:15.123: %LINK-3-UPDOWN: Interface GigabitEthernet0/1, changed state to up",
+			expectedSequence:  145,
+			expectedTimestamp: time.Date(now.Year(), time.October, 11, 22, 14, 15, 123*1000*1000, time.UTC),
+			expectedSynced:    false,
+			expectedFacility:  "LINK",
+			expectedSeverity:  3,
+			expectedMnemonic:  "UPDOWN",
+			expectedContent:   "Interface GigabitEthernet0/1, changed state to up",
+		},
+		{
+			description:       "no sequence, zone",
+			input:             "<189>Oct  1 22:14:15 UTC: %SYS-5-CONFIG_I: Configured from console by admin",
+			expectedSequence:  nil,
+			expectedTimestamp: time.Date(now.Year(), time.October, 1, 22, 14, 15, 0, time.UTC),
+			expectedSynced:    true,
+			expectedFacility:  "SYS",
+			expectedSeverity:  5,
+			expectedMnemonic:  "CONFIG_I",
+			expectedContent:   "Configured from console by admin",
+		},
+		{
+			description:       "year",
+			input:             "<187>12: .Oct 11 2003 22:14:15: %LINEPROTO-5-UPDOWN: Line protocol changed",
+			expectedSequence:  12,
+			expectedTimestamp: time.Date(2003, time.October, 11, 22, 14, 15, 0, time.UTC),
+			expectedSynced:    false,
+			expectedFacility:  "LINEPROTO",
+			expectedSeverity:  5,
+			expectedMnemonic:  "UPDOWN",
+			expectedContent:   "Line protocol changed",
+		},
+	}
+
+	for _, tc := range testCases {
+		require.True(t, IsCiscoIOS([]byte(tc.input)), tc.description)
+
+		p := NewCiscoParser([]byte(tc.input))
+
+		err := p.Parse()
+		require.Nil(t, err, tc.description)
+
+		obtained := p.Dump()
+		require.Equal(t, tc.expectedSequence, obtained["sequence"], tc.description)
+		require.Equal(t, tc.expectedTimestamp, obtained["timestamp"], tc.description)
+		require.Equal(t, tc.expectedSynced, obtained["timestamp_synced"], tc.description)
+		require.Equal(t, tc.expectedFacility, obtained["cisco_facility"], tc.description)
+		require.Equal(t, tc.expectedSeverity, obtained["cisco_severity"], tc.description)
+		require.Equal(t, tc.expectedMnemonic, obtained["cisco_mnemonic"], tc.description)
+		require.Equal(t, tc.expectedContent, obtained["content"], tc.description)
+	}
+}
+
+func TestCiscoParserWithoutTimestamp(t *testing.T) {
+	input := "<189>145: %LINK-3-UPDOWN: Interface Gi0/1, changed state to up"
+	require.True(t, IsCiscoIOS([]byte(input)))
+
+	p := NewCiscoParser([]byte(input))
+	require.Nil(t, p.Parse())
+
+	obtained := p.Dump()
+	require.Equal(t, 145, obtained["sequence"])
+	require.Equal(t, time.Time{}, obtained["timestamp"])
+	require.NotContains(t, obtained, "timestamp_synced")
+	require.Equal(t, "UPDOWN", obtained["cisco_mnemonic"])
+	require.Equal(t, "Interface Gi0/1, changed state to up", obtained["content"])
+
+	p = NewCiscoParser([]byte("<189>%LINK-3-UPDOWN: up"))
+	require.Nil(t, p.Parse())
+	require.Nil(t, p.Dump()["sequence"])
+	require.Equal(t, "up", p.Dump()["content"])
+}
+
+func TestCiscoParserRelayed(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		description       string
+		input             string
+		expectedSequence  interface{}
+		expectedTimestamp time.Time
+		expectedSynced    interface{}
+	}{
+		{
+			description:       "sequence and timestamp",
+			input:             "<189>Oct 11 22:14:15 router1 145: *Oct 11 22:14:16.123: %LINK-3-UPDOWN: up",
+			expectedSequence:  145,
+			expectedTimestamp: time.Date(now.Year(), time.October, 11, 22, 14, 16, 123*1000*1000, time.UTC),
+			expectedSynced:    false,
+		},
+		{
+			description:       "sequence only",
+			input:             "<189>Oct 11 22:14:15 router1 145: %LINK-3-UPDOWN: up",
+			expectedSequence:  145,
+			expectedTimestamp: time.Date(now.Year(), time.October, 11, 22, 14, 15, 0, time.UTC),
+			expectedSynced:    nil,
+		},
+		{
+			description:       "code only",
+			input:             "<189>Oct 11 22:14:15 router1 %LINK-3-UPDOWN: up",
+			expectedSequence:  nil,
+			expectedTimestamp: time.Date(now.Year(), time.October, 11, 22, 14, 15, 0, time.UTC),
+			expectedSynced:    nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		require.True(t, IsCiscoIOS([]byte(tc.input)), tc.description)
+
+		p := NewCiscoParser([]byte(tc.input))
+		require.Nil(t, p.Parse(), tc.description)
+
+		obtained := p.Dump()
+		require.Equal(t, "router1", obtained["hostname"], tc.description)
+		require.Equal(t, tc.expectedSequence, obtained["sequence"], tc.description)
+		require.Equal(t, tc.expectedTimestamp, obtained["timestamp"], tc.description)
+		require.Equal(t, tc.expectedSynced, obtained["timestamp_synced"], tc.description)
+		require.Equal(t, "LINK-3-UPDOWN", obtained["tag"], tc.description)
+		require.Equal(t, "up", obtained["content"], tc.description)
+	}
+
+	// WithHostname() takes precedence
+	p := NewCiscoParser([]byte(testCases[0].input))
+	p.WithHostname("core1")
+	require.Nil(t, p.Parse())
+	require.Equal(t, "core1", p.Dump()["hostname"])
+}
+
+func TestCiscoParserDump(t *testing.T) {
+	p := NewCiscoParser(
+		[]byte("<189>145: *Oct 11 22:14:15.123: %LINK-3-UPDOWN: Interface up"),
+	)
+	p.WithHostname("router1")
+
+	err := p.Parse()
+	require.Nil(t, err)
+
+	obtained := p.Dump()
+	require.Equal(t, 189, obtained["priority"])
+	require.Equal(t, 23, obtained["facility"])
+	require.Equal(t, 5, obtained["severity"])
+	require.Equal(t, "router1", obtained["hostname"])
+	require.Equal(t, "LINK-3-UPDOWN", obtained["tag"])
+	require.NotContains(t, obtained, "parser")
+}
+
+func TestCiscoParserWithDiagnostics(t *testing.T) {
+	buff := []byte(
+		"<189>145: *Oct 11 22:14:15.123: %LINK-3-UPDOWN: Interface up",
+	)
+
+	p := NewCiscoParser(buff)
+	p.WithDiagnostics()
+
+	err := p.Parse()
+	require.Nil(t, err)
+
+	obtained := p.Dump()
+	require.Equal(t, CISCO_IOS_NAME, obtained["parser"])
+	require.IsType(t, time.Duration(0), obtained["parse_duration"])
+}
+
+func TestCiscoParserErrors(t *testing.T) {
+	testCases := []struct {
+		description string
+		input       string
+		expectedErr error
+	}{
+		{
+			description: "no priority",
+			input:       "145: *Oct 11 22:14:15.123: %LINK-3-UPDOWN: up",
+			expectedErr: parsercommon.ErrPriorityNoStart,
+		},
+		{
+			description: "invalid timestamp",
+			input:       "<189>145: *Foo 11 22:14:15.123: %LINK-3-UPDOWN: up",
+			expectedErr: parsercommon.ErrTimestampUnknownFormat,
+		},
+		{
+			description: "no mnemonic",
+			input:       "<189>145: *Oct 11 22:14:15.123: LINK-3-UPDOWN: up",
+			expectedErr: ErrInvalidMnemonic,
+		},
+		{
+			description: "invalid mnemonic",
+			input:       "<189>145: *Oct 11 22:14:15.123: %LINK-UPDOWN: up",
+			expectedErr: ErrInvalidMnemonic,
+		},
+	}
+
+	for _, tc := range testCases {
+		p := NewCiscoParser([]byte(tc.input))
+
+		err := p.Parse()
+		require.ErrorIs(t, err, tc.expectedErr, tc.description)
+	}
+}
+
+func TestIsCiscoIOS(t *testing.T) {
+	require.True(t, IsCiscoIOS([]byte("<189>145: *Oct 11 22:14:15.123: %LINK-3-UPDOWN: up")))
+	require.False(t, IsCiscoIOS([]byte("<34>Oct 11 22:14:15 mymachine su: 'su root' failed")))
+	require.False(t, IsCiscoIOS([]byte("<34>Oct 11 22:14:15 mymachine su: %d percent")))
+	require.False(t, IsCiscoIOS([]byte("<166>Oct 11 2003 22:14:15: %ASA-6-302013: Built outbound TCP connection")))
+	require.False(t, IsCiscoIOS([]byte("<166>Oct 11 2003 22:14:15: %FTD-6-430003: EventPriority: Low")))
+	require.False(t, IsCiscoIOS([]byte("<13>Oct 11 22:14:15 mymachine app: %LINK-3-UPDOWN: up")))
+	require.False(t, IsCiscoIOS([]byte("<189>145: *Foo 11 22:14:15.123: %LINK-3-UPDOWN: up")))
+	require.False(t, IsCiscoIOS(nil))
+}
+
+func TestCiscoIOSDialect(t *testing.T) {
+	r := syslogparser.NewRegistry()
+	require.Nil(t, r.Register(CiscoIOS))
+
+	p, name, err := r.NewAutoParser(
+		[]byte("<189>145: *Oct 11 22:14:15.123: %LINK-3-UPDOWN: up"),
+	)
+	require.Nil(t, err)
+	require.Equal(t, CISCO_IOS_NAME, name)
+	require.Nil(t, p.Parse())
+	require.Equal(t, "UPDOWN", p.Dump()["cisco_mnemonic"])
+}
@@ -0,0 +1,102 @@
+// Package dialect provides parsers of vendor specific syslog formats, to be
+// registered in a syslogparser.Registry:
+//
+//	r := syslogparser.NewRegistry()
+//	err := r.Register(dialect.CiscoIOS)
+package dialect
+
+import (
+	"time"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+	"github.com/jeromer/syslogparser/rfc3164"
+)
+
+const (
+	// tag given to the RFC3164 parser by parseHeader()
+	HEADER_TAG = "-"
+)
+
+// Name of the parser ("parser") and time spent parsing ("parse_duration", a
+// time.Duration) reported by Dump() once WithDiagnostics() is called, as the
+// RFC parsers do
+type diagnostics struct {
+	enabled  bool
+	duration time.Duration
+}
+
+func (d *diagnostics) parse(parse func() error) error {
+	if !d.enabled {
+		return parse()
+	}
+
+	start := time.Now()
+	err := parse()
+	d.duration = time.Since(start)
+
+	return err
+}
+
+func (d *diagnostics) dump(parts parsercommon.LogParts, name string) {
+	if d.enabled {
+		parts["parser"] = name
+		parts["parse_duration"] = d.duration
+	}
+}
+
+// Same as rfc3164, timestamps without year get the current one
+func fixYear(ts time.Time) time.Time {
+	if ts.Year() != 0 {
+		return ts
+	}
+
+	return time.Date(
+		time.Now().Year(), ts.Month(), ts.Day(),
+		ts.Hour(), ts.Minute(), ts.Second(), ts.Nanosecond(),
+		ts.Location(),
+	)
+}
+
+// Parses the RFC3164 header of a vendor message, the part preceding its vendor
+// specific prefix: "<166>", "<166>Oct 11 22:14:15 fw01" or
+// "<166>Oct 11 2003 22:14:15". Timestamps are parsed with each of formats
+// in turn, an empty one standing for the RFC3164 formats.
+func parseHeader(buff []byte, l *time.Location, formats ...string) (parsercommon.LogParts, error) {
+	c := parsercommon.NewCursor(buff, len(buff))
+
+	pri, err := c.ParsePriority()
+	if err != nil {
+		return nil, c.Locate(err, 0, "priority")
+	}
+
+	if c.EOF() {
+		return parsercommon.LogParts{
+			"priority":  pri.P,
+			"facility":  pri.F.Value,
+			"severity":  pri.S.Value,
+			"version":   parsercommon.NO_VERSION,
+			"timestamp": time.Time{},
+			"hostname":  "",
+		}, nil
+	}
+
+	var rp *rfc3164.Parser
+
+	for _, format := range formats {
+		rp = rfc3164.NewParser(buff)
+		rp.WithLocation(l)
+		rp.WithTimestampFormat(format)
+		rp.WithTag(HEADER_TAG)
+
+		err = rp.Parse()
+		if err == nil {
+			break
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return rp.Dump(), nil
+}
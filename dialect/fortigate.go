@@ -0,0 +1,88 @@
+package dialect
+
+import (
+	"strings"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+)
+
+const (
+	// key of the nested map of fields returned by ParseFortiGate()
+	FORTIGATE_KEY = "fortigate"
+)
+
+var (
+	ErrInvalidKeyValue   = &parsercommon.ParserError{ErrorString: "Invalid key=value pair"}
+	ErrUnterminatedQuote = &parsercommon.ParserError{ErrorString: "Unterminated quoted value"}
+)
+
+// Content parser of FortiGate logs, to be given to WithPayloadParser():
+//
+//	p := rfc3164.NewParser(b)
+//	p.WithPayloadParser(dialect.FortiGate, parsercommon.HEADER_WINS)
+var FortiGate = parsercommon.PayloadParserFunc(ParseFortiGate)
+
+// Parses space separated key=value pairs, values being optionally double
+// quoted:
+// date=2019-05-10 time=11:50:48 devname="FG100D" srcip=10.0.0.1 msg="a b"
+// Fields are returned as a map[string]string under FORTIGATE_KEY.
+func ParseFortiGate(payload string) (parsercommon.LogParts, error) {
+	fields := map[string]string{}
+
+	s := strings.TrimSpace(payload)
+
+	for len(s) > 0 {
+		eq := strings.IndexByte(s, '=')
+		if eq < 1 || strings.IndexByte(s[:eq], ' ') >= 0 {
+			return nil, ErrInvalidKeyValue
+		}
+
+		key := s[:eq]
+		s = s[eq+1:]
+
+		var value string
+
+		if strings.HasPrefix(s, `"`) {
+			end := closingQuote(s)
+			if end < 0 {
+				return nil, ErrUnterminatedQuote
+			}
+
+			value = strings.Replace(s[1:end], `\"`, `"`, -1)
+			s = s[end+1:]
+
+			if len(s) > 0 && s[0] != ' ' {
+				return nil, ErrInvalidKeyValue
+			}
+		} else {
+			end := strings.IndexByte(s, ' ')
+			if end < 0 {
+				end = len(s)
+			}
+
+			value = s[:end]
+			s = s[end:]
+		}
+
+		fields[key] = value
+		s = strings.TrimLeft(s, " ")
+	}
+
+	return parsercommon.LogParts{
+		FORTIGATE_KEY: fields,
+	}, nil
+}
+
+// Index of the quote closing the value starting s, skipping \" escapes, or -1
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,117 @@
+package dialect
+
+import (
+	"testing"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+	"github.com/jeromer/syslogparser/rfc3164"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFortiGate(t *testing.T) {
+	testCases := []struct {
+		description    string
+		input          string
+		expectedFields map[string]string
+	}{
+		{
+			description: "plain values",
+			input:       "date=2019-05-10 time=11:50:48 srcip=10.0.0.1 srcport=51234",
+			expectedFields: map[string]string{
+				"date":    "2019-05-10",
+				"time":    "11:50:48",
+				"srcip":   "10.0.0.1",
+				"srcport": "51234",
+			},
+		},
+		{
+			description: "quoted values",
+			input:       `devname="FG100D" msg="User \"admin\" logged in" action=login`,
+			expectedFields: map[string]string{
+				"devname": "FG100D",
+				"msg":     `User "admin" logged in`,
+				"action":  "login",
+			},
+		},
+		{
+			description: "empty values and extra spaces",
+			input:       `  vd="" user=  status=success  `,
+			expectedFields: map[string]string{
+				"vd":     "",
+				"user":   "",
+				"status": "success",
+			},
+		},
+		{
+			description:    "empty",
+			input:          "",
+			expectedFields: map[string]string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		obtained, err := ParseFortiGate(tc.input)
+		require.Nil(t, err, tc.description)
+		require.Equal(
+			t,
+			parsercommon.LogParts{FORTIGATE_KEY: tc.expectedFields},
+			obtained,
+			tc.description,
+		)
+	}
+}
+
+func TestParseFortiGateErrors(t *testing.T) {
+	testCases := []struct {
+		description string
+		input       string
+		expectedErr error
+	}{
+		{
+			description: "no value",
+			input:       "date=2019-05-10 foo",
+			expectedErr: ErrInvalidKeyValue,
+		},
+		{
+			description: "no key",
+			input:       "=foo",
+			expectedErr: ErrInvalidKeyValue,
+		},
+		{
+			description: "unterminated quote",
+			input:       `msg="foo bar`,
+			expectedErr: ErrUnterminatedQuote,
+		},
+		{
+			description: "garbage after quote",
+			input:       `msg="foo"bar`,
+			expectedErr: ErrInvalidKeyValue,
+		},
+	}
+
+	for _, tc := range testCases {
+		_, err := ParseFortiGate(tc.input)
+		require.ErrorIs(t, err, tc.expectedErr, tc.description)
+	}
+}
+
+func TestFortiGatePayloadParser(t *testing.T) {
+	p := rfc3164.NewParser(
+		[]byte(`<189>Oct 11 22:14:15 fw01 fortigate: date=2019-05-10 devname="FG100D" srcip=10.0.0.1`),
+	)
+	p.WithPayloadParser(FortiGate, parsercommon.HEADER_WINS)
+
+	err := p.Parse()
+	require.Nil(t, err)
+
+	obtained := p.Dump()
+	require.Equal(
+		t,
+		map[string]string{
+			"date":    "2019-05-10",
+			"devname": "FG100D",
+			"srcip":   "10.0.0.1",
+		},
+		obtained[FORTIGATE_KEY],
+	)
+}
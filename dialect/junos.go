@@ -0,0 +1,93 @@
+package dialect
+
+import (
+	"strings"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+	"github.com/jeromer/syslogparser/rfc5424"
+)
+
+const (
+	// key of the nested map of fields returned by ParseJunos()
+	JUNOS_KEY = "junos"
+
+	// SD-ID of Junos elements, followed by the product OID, ie.
+	// "junos@2636.1.1.1.2.18"
+	JUNOS_SD_ID = "junos@2636"
+)
+
+var (
+	ErrNoJunos = &parsercommon.ParserError{ErrorString: "No Junos structured data found"}
+)
+
+// Content parser of RFC3164 messages sent by Junos devices in structured
+// mode, to be given to WithPayloadParser():
+// RT_FLOW_SESSION_CREATE [junos@2636.1.1.1.2.18 source-address="10.0.0.1" ...]
+var Junos = parsercommon.PayloadParserFunc(ParseJunos)
+
+// Decodes the first junos@2636 element found in s, RFC5424
+// STRUCTURED-DATA or RFC3164 content, and returns its parameters under
+// JUNOS_KEY as a map[string]string. The SD-ID is reported as "sd_id".
+func ParseJunos(s string) (parsercommon.LogParts, error) {
+	start := strings.Index(s, "["+JUNOS_SD_ID)
+	if start < 0 {
+		return nil, ErrNoJunos
+	}
+
+	end := sdElementEnd(s[start:])
+	if end < 0 {
+		return nil, rfc5424.ErrInvalidStructuredData
+	}
+
+	elements, err := rfc5424.ParseStructuredData(s[start : start+end+1])
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]string{
+		"sd_id": elements[0].ID,
+	}
+
+	for _, p := range elements[0].Params {
+		fields[p.Name] = p.Value
+	}
+
+	return parsercommon.LogParts{
+		JUNOS_KEY: fields,
+	}, nil
+}
+
+// Decodes the Junos element of parts returned by Dump(): the
+// "structured_data" of RFC5424 messages or the "content" of RFC3164 ones
+func DecodeJunos(parts parsercommon.LogParts) (parsercommon.LogParts, error) {
+	if sd, ok := parts["structured_data"].(string); ok {
+		return ParseJunos(sd)
+	}
+
+	content, _ := parts["content"].(string)
+
+	return ParseJunos(content)
+}
+
+// Index of the ']' closing the element starting s, quoted values skipped, or
+// -1
+func sdElementEnd(s string) int {
+	quoted := false
+
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if quoted {
+				i++
+			}
+		case '"':
+			quoted = !quoted
+		case ']':
+			if !quoted {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,120 @@
+package dialect
+
+import (
+	"testing"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+	"github.com/jeromer/syslogparser/rfc3164"
+	"github.com/jeromer/syslogparser/rfc5424"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseJunos(t *testing.T) {
+	testCases := []struct {
+		description    string
+		input          string
+		expectedFields map[string]string
+	}{
+		{
+			description: "structured data",
+			input:       `[junos@2636.1.1.1.2.18 source-address="10.0.0.1" source-port="51234" destination-address="198.51.100.7" destination-port="443"][meta@1 x="y"]`,
+			expectedFields: map[string]string{
+				"sd_id":               "junos@2636.1.1.1.2.18",
+				"source-address":      "10.0.0.1",
+				"source-port":         "51234",
+				"destination-address": "198.51.100.7",
+				"destination-port":    "443",
+			},
+		},
+		{
+			description: "rfc3164 content",
+			input:       `RT_FLOW_SESSION_CREATE [junos@2636.1.1.1.2.40 source-address="10.0.0.1" policy-name="allow \"web\" ]"] session created`,
+			expectedFields: map[string]string{
+				"sd_id":          "junos@2636.1.1.1.2.40",
+				"source-address": "10.0.0.1",
+				"policy-name":    `allow "web" ]`,
+			},
+		},
+		{
+			description: "other element first",
+			input:       `[origin ip="10.0.0.1"][junos@2636.1.1.1.2.18 username="bob"]`,
+			expectedFields: map[string]string{
+				"sd_id":    "junos@2636.1.1.1.2.18",
+				"username": "bob",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		obtained, err := ParseJunos(tc.input)
+		require.Nil(t, err, tc.description)
+		require.Equal(
+			t,
+			parsercommon.LogParts{JUNOS_KEY: tc.expectedFields},
+			obtained,
+			tc.description,
+		)
+	}
+}
+
+func TestParseJunosErrors(t *testing.T) {
+	testCases := []struct {
+		description string
+		input       string
+		expectedErr error
+	}{
+		{
+			description: "no element",
+			input:       `[exampleSDID@32473 iut="3"]`,
+			expectedErr: ErrNoJunos,
+		},
+		{
+			description: "unterminated element",
+			input:       `RT_FLOW [junos@2636.1.1.1.2.18 source-address="10.0.0.1"`,
+			expectedErr: rfc5424.ErrInvalidStructuredData,
+		},
+		{
+			description: "invalid param",
+			input:       `RT_FLOW [junos@2636.1.1.1.2.18 source-address=10.0.0.1]`,
+			expectedErr: rfc5424.ErrInvalidStructuredData,
+		},
+	}
+
+	for _, tc := range testCases {
+		_, err := ParseJunos(tc.input)
+		require.ErrorIs(t, err, tc.expectedErr, tc.description)
+	}
+}
+
+func TestDecodeJunos(t *testing.T) {
+	p5 := rfc5424.NewParser(
+		[]byte(`<14>1 2003-10-11T22:14:15.003Z fw01 RT_FLOW - RT_FLOW_SESSION_CREATE [junos@2636.1.1.1.2.18 source-address="10.0.0.1"] session created`),
+	)
+	require.Nil(t, p5.Parse())
+
+	obtained, err := DecodeJunos(p5.Dump())
+	require.Nil(t, err)
+	require.Equal(t, "10.0.0.1", obtained[JUNOS_KEY].(map[string]string)["source-address"])
+
+	p3 := rfc3164.NewParser(
+		[]byte(`<14>Oct 11 22:14:15 fw01 RT_FLOW: RT_FLOW_SESSION_CREATE [junos@2636.1.1.1.2.18 source-address="10.0.0.2"]`),
+	)
+	require.Nil(t, p3.Parse())
+
+	obtained, err = DecodeJunos(p3.Dump())
+	require.Nil(t, err)
+	require.Equal(t, "10.0.0.2", obtained[JUNOS_KEY].(map[string]string)["source-address"])
+}
+
+func TestJunosPayloadParser(t *testing.T) {
+	p := rfc3164.NewParser(
+		[]byte(`<14>Oct 11 22:14:15 fw01 RT_FLOW: RT_FLOW_SESSION_CREATE [junos@2636.1.1.1.2.18 source-address="10.0.0.1" source-port="51234"]`),
+	)
+	p.WithPayloadParser(Junos, parsercommon.HEADER_WINS)
+
+	err := p.Parse()
+	require.Nil(t, err)
+
+	fields := p.Dump()[JUNOS_KEY].(map[string]string)
+	require.Equal(t, "51234", fields["source-port"])
+}
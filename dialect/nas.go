@@ -0,0 +1,174 @@
+package dialect
+
+import (
+	"strings"
+	"time"
+
+	"github.com/jeromer/syslogparser"
+	"github.com/jeromer/syslogparser/parsercommon"
+	"github.com/jeromer/syslogparser/rfc3164"
+)
+
+const (
+	// reported as "parser" when diagnostics are enabled
+	NAS_NAME = "nas"
+
+	NAS_CATEGORY_CONNECTION = "Connection"
+	NAS_CATEGORY_SYSTEM     = "System"
+	NAS_CATEGORY_BACKUP     = "Backup"
+)
+
+var (
+	ErrUnknownNASCategory = &parsercommon.ParserError{ErrorString: "Unknown NAS event category"}
+)
+
+// Tried in turn, an empty format standing for the RFC3164 ones
+var nasTimestampFormats = []string{
+	"",
+	"2006/01/02 15:04:05",
+	"2006-01-02 15:04:05",
+}
+
+// Categories by bracketless tag, as sent by Synology DSM, or by QNAP
+// content prefix
+var nasCategories = map[string]string{
+	"Connection":  NAS_CATEGORY_CONNECTION,
+	"System":      NAS_CATEGORY_SYSTEM,
+	"Backup":      NAS_CATEGORY_BACKUP,
+	"HyperBackup": NAS_CATEGORY_BACKUP,
+	"conn log":    NAS_CATEGORY_CONNECTION,
+	"event log":   NAS_CATEGORY_SYSTEM,
+}
+
+// Synology DSM and QNAP messages:
+// <14>2019/05/10 11:50:48 NAS01 Connection: User [admin] from [10.0.0.1] signed in
+// <14>Oct 11 22:14:15 NAS02 qlogd[1234]: conn log: Users: admin, Source IP: 10.0.0.1
+var NAS = syslogparser.Dialect{
+	Name:   NAS_NAME,
+	Detect: IsNAS,
+	New: func(buff []byte) syslogparser.LogParser {
+		return NewNASParser(buff)
+	},
+}
+
+// Parses messages of Synology DSM and QNAP NAS appliances, whose timestamps
+// may be "2019/05/10 11:50:48" or "2019-05-10 11:50:48", and reports their
+// event category, one of the NAS_CATEGORY_* constants, as "category".
+type NASParser struct {
+	buff     []byte
+	location *time.Location
+	hostname string
+	diag     diagnostics
+
+	parts    parsercommon.LogParts
+	category string
+}
+
+func NewNASParser(buff []byte) *NASParser {
+	return &NASParser{
+		buff:     buff,
+		location: time.UTC,
+	}
+}
+
+func (p *NASParser) WithLocation(l *time.Location) {
+	p.location = l
+}
+
+func (p *NASParser) WithHostname(h string) {
+	p.hostname = h
+}
+
+// Noop, every known NAS timestamp format is tried
+func (p *NASParser) WithTimestampFormat(s string) {}
+
+// Noop, the category is given by the tag
+func (p *NASParser) WithTag(t string) {}
+
+// Adds the name of the parser ("parser") and the time spent parsing
+// ("parse_duration", a time.Duration) to Dump()
+func (p *NASParser) WithDiagnostics() {
+	p.diag.enabled = true
+}
+
+func (p *NASParser) Parse() error {
+	return p.diag.parse(p.parse)
+}
+
+func (p *NASParser) parse() error {
+	var rp *rfc3164.Parser
+	var err error
+
+	for _, format := range nasTimestampFormats {
+		rp = rfc3164.NewParser(p.buff)
+		rp.WithLocation(p.location)
+		rp.WithTimestampFormat(format)
+
+		if p.hostname != "" {
+			rp.WithHostname(p.hostname)
+		}
+
+		err = rp.Parse()
+		if err == nil {
+			break
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	p.parts = rp.Dump()
+
+	tag, _ := p.parts["tag"].(string)
+	content, _ := p.parts["content"].(string)
+
+	category, content, ok := nasCategory(tag, content)
+	if !ok {
+		return ErrUnknownNASCategory
+	}
+
+	p.category = category
+	p.parts["content"] = content
+
+	return nil
+}
+
+func (p *NASParser) Dump() parsercommon.LogParts {
+	parts := parsercommon.LogParts{}
+
+	for k, v := range p.parts {
+		parts[k] = v
+	}
+
+	parts["category"] = p.category
+
+	p.diag.dump(parts, NAS_NAME)
+
+	return parts
+}
+
+// Category given by the tag ("Connection") or by the content prefix
+// ("conn log: ..."), in which case it is removed from the content
+func nasCategory(tag string, content string) (string, string, bool) {
+	if c, ok := nasCategories[tag]; ok {
+		return c, content, true
+	}
+
+	end := strings.Index(content, ": ")
+	if end < 0 {
+		return "", content, false
+	}
+
+	c, ok := nasCategories[content[:end]]
+	if !ok {
+		return "", content, false
+	}
+
+	return c, content[end+2:], true
+}
+
+// Returns true when buff is a NAS message of a known category
+func IsNAS(buff []byte) bool {
+	return NewNASParser(buff).Parse() == nil
+}
@@ -0,0 +1,137 @@
+package dialect
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jeromer/syslogparser"
+	"github.com/jeromer/syslogparser/parsercommon"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNASParser(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		description       string
+		input             string
+		expectedTimestamp time.Time
+		expectedHostname  string
+		expectedTag       string
+		expectedCategory  string
+		expectedContent   string
+	}{
+		{
+			description:       "synology slashed timestamp",
+			input:             "<14>2019/05/10 11:50:48 NAS01 Connection: User [admin] from [10.0.0.1] signed in to [DSM] successfully.",
+			expectedTimestamp: time.Date(2019, time.May, 10, 11, 50, 48, 0, time.UTC),
+			expectedHostname:  "NAS01",
+			expectedTag:       "Connection",
+			expectedCategory:  NAS_CATEGORY_CONNECTION,
+			expectedContent:   "User [admin] from [10.0.0.1] signed in to [DSM] successfully.",
+		},
+		{
+			description:       "synology rfc3164 timestamp",
+			input:             "<14>Oct 11 22:14:15 NAS01 HyperBackup: Backup task [Nightly] completed.",
+			expectedTimestamp: time.Date(now.Year(), time.October, 11, 22, 14, 15, 0, time.UTC),
+			expectedHostname:  "NAS01",
+			expectedTag:       "HyperBackup",
+			expectedCategory:  NAS_CATEGORY_BACKUP,
+			expectedContent:   "Backup task [Nightly] completed.",
+		},
+		{
+			description:       "qnap dashed timestamp",
+			input:             "<14>2019-05-10 11:50:48 NAS02 qlogd[1234]: event log: Users: System, Source IP: 127.0.0.1, Application: Storage, Content: Volume full",
+			expectedTimestamp: time.Date(2019, time.May, 10, 11, 50, 48, 0, time.UTC),
+			expectedHostname:  "NAS02",
+			expectedTag:       "qlogd",
+			expectedCategory:  NAS_CATEGORY_SYSTEM,
+			expectedContent:   "Users: System, Source IP: 127.0.0.1, Application: Storage, Content: Volume full",
+		},
+		{
+			description:       "qnap connection",
+			input:             "<14>Oct 11 22:14:15 NAS02 qlogd[1234]: conn log: Users: admin, Source IP: 10.0.0.1, Action: Login OK",
+			expectedTimestamp: time.Date(now.Year(), time.October, 11, 22, 14, 15, 0, time.UTC),
+			expectedHostname:  "NAS02",
+			expectedTag:       "qlogd",
+			expectedCategory:  NAS_CATEGORY_CONNECTION,
+			expectedContent:   "Users: admin, Source IP: 10.0.0.1, Action: Login OK",
+		},
+	}
+
+	for _, tc := range testCases {
+		p := NewNASParser([]byte(tc.input))
+
+		err := p.Parse()
+		require.Nil(t, err, tc.description)
+
+		obtained := p.Dump()
+		require.Equal(t, tc.expectedTimestamp, obtained["timestamp"], tc.description)
+		require.Equal(t, tc.expectedHostname, obtained["hostname"], tc.description)
+		require.Equal(t, tc.expectedTag, obtained["tag"], tc.description)
+		require.Equal(t, tc.expectedCategory, obtained["category"], tc.description)
+		require.Equal(t, tc.expectedContent, obtained["content"], tc.description)
+		require.NotContains(t, obtained, "parser", tc.description)
+	}
+}
+
+func TestNASParserWithDiagnostics(t *testing.T) {
+	buff := []byte(
+		"<14>2019/05/10 11:50:48 NAS01 Connection: User [admin] from [10.0.0.1] signed in to [DSM] successfully.",
+	)
+
+	p := NewNASParser(buff)
+	p.WithDiagnostics()
+
+	err := p.Parse()
+	require.Nil(t, err)
+
+	obtained := p.Dump()
+	require.Equal(t, NAS_NAME, obtained["parser"])
+	require.IsType(t, time.Duration(0), obtained["parse_duration"])
+}
+
+func TestNASParserErrors(t *testing.T) {
+	testCases := []struct {
+		description string
+		input       string
+		expectedErr error
+	}{
+		{
+			description: "invalid timestamp",
+			input:       "<14>2019.05.10 11:50:48 NAS01 Connection: User [admin] signed in",
+			expectedErr: parsercommon.ErrTimestampUnknownFormat,
+		},
+		{
+			description: "unknown category",
+			input:       "<34>Oct 11 22:14:15 mymachine su: 'su root' failed",
+			expectedErr: ErrUnknownNASCategory,
+		},
+	}
+
+	for _, tc := range testCases {
+		p := NewNASParser([]byte(tc.input))
+
+		err := p.Parse()
+		require.ErrorIs(t, err, tc.expectedErr, tc.description)
+	}
+}
+
+func TestIsNAS(t *testing.T) {
+	require.True(t, IsNAS([]byte("<14>2019/05/10 11:50:48 NAS01 System: System started")))
+	require.False(t, IsNAS([]byte("<34>Oct 11 22:14:15 mymachine su: 'su root' failed")))
+	require.False(t, IsNAS(nil))
+}
+
+func TestNASDialect(t *testing.T) {
+	r := syslogparser.NewRegistry()
+	require.Nil(t, r.Register(NAS))
+
+	p, name, err := r.NewAutoParser(
+		[]byte("<14>2019/05/10 11:50:48 NAS01 System: System started"),
+	)
+	require.Nil(t, err)
+	require.Equal(t, NAS_NAME, name)
+	require.Nil(t, p.Parse())
+	require.Equal(t, NAS_CATEGORY_SYSTEM, p.Dump()["category"])
+}
@@ -0,0 +1,110 @@
+package dialect
+
+import (
+	"encoding/csv"
+	"strings"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+)
+
+const (
+	// key of the nested map of fields returned by ParsePANOS()
+	PANOS_KEY = "panos"
+
+	// column giving the log type, ie. "TRAFFIC"
+	PANOS_TYPE_COLUMN = 3
+)
+
+var (
+	ErrInvalidCSV          = &parsercommon.ParserError{ErrorString: "Invalid CSV payload"}
+	ErrUnknownPANOSLogType = &parsercommon.ParserError{ErrorString: "Unknown PAN-OS log type"}
+)
+
+// Content parser of PAN-OS logs, to be given to WithPayloadParser(). PAN-OS
+// messages have no tag, set one so the first column is kept in the content:
+//
+//	p := rfc3164.NewParser(b)
+//	p.WithTag("panos")
+//	p.WithPayloadParser(dialect.PANOS, parsercommon.HEADER_WINS)
+var PANOS = parsercommon.PayloadParserFunc(ParsePANOS)
+
+// Columns shared by every log type, empty names are "FUTURE_USE" columns
+// which are skipped
+var panosHeader = []string{
+	"", "receive_time", "serial", "type", "subtype", "", "generated_time",
+}
+
+var panosSession = []string{
+	"src", "dst", "natsrc", "natdst", "rule", "srcuser", "dstuser", "app",
+	"vsys", "from", "to", "inbound_if", "outbound_if", "logset", "",
+	"sessionid", "repeatcnt", "sport", "dport", "natsport", "natdport",
+	"flags", "proto", "action",
+}
+
+// Columns following panosHeader, by log type, as documented for PAN-OS 8+
+var panosColumns = map[string][]string{
+	"TRAFFIC": concat(panosSession, []string{
+		"bytes", "bytes_sent", "bytes_received", "packets", "start",
+		"elapsed", "category", "", "seqno", "actionflags", "srcloc",
+		"dstloc", "", "pkts_sent", "pkts_received", "session_end_reason",
+	}),
+	"THREAT": concat(panosSession, []string{
+		"misc", "threatid", "category", "severity", "direction", "seqno",
+		"actionflags", "srcloc", "dstloc", "", "contenttype", "pcap_id",
+		"filedigest", "cloud", "url_idx", "user_agent", "filetype", "xff",
+		"referer", "sender", "subject", "recipient", "reportid",
+	}),
+	"SYSTEM": {
+		"vsys", "eventid", "object", "", "", "module", "severity", "opaque",
+		"seqno", "actionflags",
+	},
+}
+
+// Parses the comma separated payload of PAN-OS TRAFFIC, THREAT and SYSTEM
+// logs and names each column after the log type:
+// 1,2012/04/10 04:39:56,001606000117,TRAFFIC,end,1,2012/04/10 04:39:55,...
+// Fields are returned as a map[string]string under PANOS_KEY. Columns added
+// by newer PAN-OS versions are ignored.
+func ParsePANOS(payload string) (parsercommon.LogParts, error) {
+	r := csv.NewReader(strings.NewReader(payload))
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+
+	record, err := r.Read()
+	if err != nil {
+		return nil, parsercommon.Wrap(ErrInvalidCSV, err)
+	}
+
+	if len(record) <= PANOS_TYPE_COLUMN {
+		return nil, ErrInvalidCSV
+	}
+
+	columns, ok := panosColumns[record[PANOS_TYPE_COLUMN]]
+	if !ok {
+		return nil, ErrUnknownPANOSLogType
+	}
+
+	names := concat(panosHeader, columns)
+	fields := make(map[string]string, len(names))
+
+	for i, v := range record {
+		if i >= len(names) {
+			break
+		}
+
+		if names[i] != "" {
+			fields[names[i]] = v
+		}
+	}
+
+	return parsercommon.LogParts{
+		PANOS_KEY: fields,
+	}, nil
+}
+
+func concat(a []string, b []string) []string {
+	s := make([]string, 0, len(a)+len(b))
+	s = append(s, a...)
+
+	return append(s, b...)
+}
@@ -0,0 +1,152 @@
+package dialect
+
+import (
+	"testing"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+	"github.com/jeromer/syslogparser/rfc3164"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePANOS(t *testing.T) {
+	testCases := []struct {
+		description    string
+		input          string
+		expectedFields map[string]string
+	}{
+		{
+			description: "traffic",
+			input:       "1,2012/04/10 04:39:56,001606000117,TRAFFIC,end,1,2012/04/10 04:39:55,10.0.0.1,198.51.100.7,0.0.0.0,0.0.0.0,allow-web,,,web-browsing,vsys1,trust,untrust,ethernet1/1,ethernet1/2,fwd,2012/04/10 04:39:55,12345,1,51234,443,0,0,0x19,tcp,allow,1024,512,512,10,2012/04/10 04:39:45,10,any,0,42,0x0,10.0.0.0-10.255.255.255,US,0,5,5,tcp-fin",
+			expectedFields: map[string]string{
+				"receive_time":       "2012/04/10 04:39:56",
+				"serial":             "001606000117",
+				"type":               "TRAFFIC",
+				"subtype":            "end",
+				"generated_time":     "2012/04/10 04:39:55",
+				"src":                "10.0.0.1",
+				"dst":                "198.51.100.7",
+				"natsrc":             "0.0.0.0",
+				"natdst":             "0.0.0.0",
+				"rule":               "allow-web",
+				"srcuser":            "",
+				"dstuser":            "",
+				"app":                "web-browsing",
+				"vsys":               "vsys1",
+				"from":               "trust",
+				"to":                 "untrust",
+				"inbound_if":         "ethernet1/1",
+				"outbound_if":        "ethernet1/2",
+				"logset":             "fwd",
+				"sessionid":          "12345",
+				"repeatcnt":          "1",
+				"sport":              "51234",
+				"dport":              "443",
+				"natsport":           "0",
+				"natdport":           "0",
+				"flags":              "0x19",
+				"proto":              "tcp",
+				"action":             "allow",
+				"bytes":              "1024",
+				"bytes_sent":         "512",
+				"bytes_received":     "512",
+				"packets":            "10",
+				"start":              "2012/04/10 04:39:45",
+				"elapsed":            "10",
+				"category":           "any",
+				"seqno":              "42",
+				"actionflags":        "0x0",
+				"srcloc":             "10.0.0.0-10.255.255.255",
+				"dstloc":             "US",
+				"pkts_sent":          "5",
+				"pkts_received":      "5",
+				"session_end_reason": "tcp-fin",
+			},
+		},
+		{
+			description: "system, quoted column and extra columns",
+			input:       `1,2012/04/10 04:39:56,001606000117,SYSTEM,general,0,2012/04/10 04:39:55,,general,,0,0,general,informational,"User admin logged in, from 10.0.0.1",42,0x0,extra,columns`,
+			expectedFields: map[string]string{
+				"receive_time":   "2012/04/10 04:39:56",
+				"serial":         "001606000117",
+				"type":           "SYSTEM",
+				"subtype":        "general",
+				"generated_time": "2012/04/10 04:39:55",
+				"vsys":           "",
+				"eventid":        "general",
+				"object":         "",
+				"module":         "general",
+				"severity":       "informational",
+				"opaque":         "User admin logged in, from 10.0.0.1",
+				"seqno":          "42",
+				"actionflags":    "0x0",
+			},
+		},
+		{
+			description: "truncated threat",
+			input:       "1,2012/04/10 04:39:56,001606000117,THREAT,url,1,2012/04/10 04:39:55,10.0.0.1",
+			expectedFields: map[string]string{
+				"receive_time":   "2012/04/10 04:39:56",
+				"serial":         "001606000117",
+				"type":           "THREAT",
+				"subtype":        "url",
+				"generated_time": "2012/04/10 04:39:55",
+				"src":            "10.0.0.1",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		obtained, err := ParsePANOS(tc.input)
+		require.Nil(t, err, tc.description)
+		require.Equal(
+			t,
+			parsercommon.LogParts{PANOS_KEY: tc.expectedFields},
+			obtained,
+			tc.description,
+		)
+	}
+}
+
+func TestParsePANOSErrors(t *testing.T) {
+	testCases := []struct {
+		description string
+		input       string
+		expectedErr error
+	}{
+		{
+			description: "empty",
+			input:       "",
+			expectedErr: ErrInvalidCSV,
+		},
+		{
+			description: "no type",
+			input:       "1,2012/04/10 04:39:56,001606000117",
+			expectedErr: ErrInvalidCSV,
+		},
+		{
+			description: "unknown type",
+			input:       "1,2012/04/10 04:39:56,001606000117,CONFIG,0,0",
+			expectedErr: ErrUnknownPANOSLogType,
+		},
+	}
+
+	for _, tc := range testCases {
+		_, err := ParsePANOS(tc.input)
+		require.ErrorIs(t, err, tc.expectedErr, tc.description)
+	}
+}
+
+func TestPANOSPayloadParser(t *testing.T) {
+	p := rfc3164.NewParser(
+		[]byte("<14>Apr 10 04:39:56 pa-fw 1,2012/04/10 04:39:56,001606000117,SYSTEM,general,0,2012/04/10 04:39:55,,general,,0,0,general,informational,Commit succeeded,42,0x0"),
+	)
+	p.WithTag("panos")
+	p.WithPayloadParser(PANOS, parsercommon.HEADER_WINS)
+
+	err := p.Parse()
+	require.Nil(t, err)
+
+	fields := p.Dump()[PANOS_KEY].(map[string]string)
+	require.Equal(t, "Commit succeeded", fields["opaque"])
+	require.Equal(t, "informational", fields["severity"])
+}
@@ -0,0 +1,192 @@
+package dialect
+
+import (
+	"strings"
+	"time"
+
+	"github.com/jeromer/syslogparser"
+	"github.com/jeromer/syslogparser/parsercommon"
+	"github.com/jeromer/syslogparser/rfc3164"
+)
+
+const (
+	// reported as "parser" when diagnostics are enabled
+	ROUTEROS_NAME = "routeros"
+
+	// "firewall,info"
+	TOPIC_SEPARATOR = ","
+)
+
+var (
+	ErrInvalidTopics = &parsercommon.ParserError{ErrorString: "Invalid RouterOS topics"}
+)
+
+// Topics giving the level of RouterOS messages, one of them is always sent
+var routerOSLevels = map[string]bool{
+	"debug":    true,
+	"info":     true,
+	"warning":  true,
+	"error":    true,
+	"critical": true,
+}
+
+// Mikrotik RouterOS messages:
+// <30>Oct 11 22:14:15 MikroTik system,info,account user admin logged in
+var RouterOS = syslogparser.Dialect{
+	Name:   ROUTEROS_NAME,
+	Detect: IsRouterOS,
+	New: func(buff []byte) syslogparser.LogParser {
+		return NewRouterOSParser(buff)
+	},
+}
+
+// Parses Mikrotik RouterOS messages, whose tag is a comma separated list of
+// topics, with or without hostname. Topics are reported as "topics", a
+// []string, and as "tag" as sent.
+type RouterOSParser struct {
+	buff     []byte
+	location *time.Location
+	hostname string
+	diag     diagnostics
+
+	header  parsercommon.LogParts
+	topics  []string
+	tag     string
+	content string
+}
+
+func NewRouterOSParser(buff []byte) *RouterOSParser {
+	return &RouterOSParser{
+		buff:     buff,
+		location: time.UTC,
+	}
+}
+
+func (p *RouterOSParser) WithLocation(l *time.Location) {
+	p.location = l
+}
+
+func (p *RouterOSParser) WithHostname(h string) {
+	p.hostname = h
+}
+
+// Noop, RouterOS sends RFC3164 timestamps
+func (p *RouterOSParser) WithTimestampFormat(s string) {}
+
+// Noop, the tag of RouterOS messages is their topics
+func (p *RouterOSParser) WithTag(t string) {}
+
+// Adds the name of the parser ("parser") and the time spent parsing
+// ("parse_duration", a time.Duration) to Dump()
+func (p *RouterOSParser) WithDiagnostics() {
+	p.diag.enabled = true
+}
+
+func (p *RouterOSParser) Parse() error {
+	return p.diag.parse(p.parse)
+}
+
+func (p *RouterOSParser) parse() error {
+	rp := rfc3164.NewParser(p.buff)
+	rp.WithLocation(p.location)
+
+	// XXX : the tag is parsed here, the content starts after the hostname
+	rp.WithTag(ROUTEROS_NAME)
+
+	err := rp.Parse()
+	if err != nil {
+		return err
+	}
+
+	p.header = rp.Dump()
+
+	hostname, _ := p.header["hostname"].(string)
+	content, _ := p.header["content"].(string)
+
+	// "<30>Oct 11 22:14:15 firewall,info input: ...", the hostname is the tag
+	if strings.Contains(hostname, TOPIC_SEPARATOR) {
+		content = hostname + " " + content
+		p.header["hostname"] = ""
+	}
+
+	tag := content
+	p.content = ""
+
+	if end := strings.IndexByte(content, ' '); end >= 0 {
+		tag = content[:end]
+		p.content = content[end+1:]
+	}
+
+	topics, ok := parseTopics(tag)
+	if !ok {
+		return ErrInvalidTopics
+	}
+
+	p.tag = tag
+	p.topics = topics
+
+	return nil
+}
+
+func (p *RouterOSParser) Dump() parsercommon.LogParts {
+	parts := parsercommon.LogParts{}
+
+	for k, v := range p.header {
+		parts[k] = v
+	}
+
+	if p.hostname != "" {
+		parts["hostname"] = p.hostname
+	}
+
+	parts["tag"] = p.tag
+	parts["topics"] = p.topics
+	parts["content"] = p.content
+
+	p.diag.dump(parts, ROUTEROS_NAME)
+
+	return parts
+}
+
+// "system,info,account" => ["system", "info", "account"], one of the topics
+// being a level
+func parseTopics(s string) ([]string, bool) {
+	topics := strings.Split(s, TOPIC_SEPARATOR)
+	if len(topics) < 2 {
+		return nil, false
+	}
+
+	level := false
+
+	for _, t := range topics {
+		if !isTopic(t) {
+			return nil, false
+		}
+
+		if routerOSLevels[t] {
+			level = true
+		}
+	}
+
+	return topics, level
+}
+
+func isTopic(t string) bool {
+	if t == "" {
+		return false
+	}
+
+	for _, c := range t {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Returns true when buff is an RFC3164 message whose tag is a list of
+// RouterOS topics
+func IsRouterOS(buff []byte) bool {
+	return NewRouterOSParser(buff).Parse() == nil
+}
@@ -0,0 +1,150 @@
+package dialect
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jeromer/syslogparser"
+	"github.com/jeromer/syslogparser/parsercommon"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouterOSParser(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		description      string
+		input            string
+		expectedHostname string
+		expectedTag      string
+		expectedTopics   []string
+		expectedContent  string
+	}{
+		{
+			description:      "hostname",
+			input:            "<30>Oct 11 22:14:15 MikroTik system,info,account user admin logged in from 10.0.0.1 via ssh",
+			expectedHostname: "MikroTik",
+			expectedTag:      "system,info,account",
+			expectedTopics:   []string{"system", "info", "account"},
+			expectedContent:  "user admin logged in from 10.0.0.1 via ssh",
+		},
+		{
+			description:      "no hostname",
+			input:            "<30>Oct 11 22:14:15 firewall,info input: in:ether1 out:(none), proto TCP (SYN), 10.0.0.1:51234->192.168.88.1:22",
+			expectedHostname: "",
+			expectedTag:      "firewall,info",
+			expectedTopics:   []string{"firewall", "info"},
+			expectedContent:  "input: in:ether1 out:(none), proto TCP (SYN), 10.0.0.1:51234->192.168.88.1:22",
+		},
+		{
+			description:      "no content",
+			input:            "<28>Oct 11 22:14:15 MikroTik interface,warning",
+			expectedHostname: "MikroTik",
+			expectedTag:      "interface,warning",
+			expectedTopics:   []string{"interface", "warning"},
+			expectedContent:  "",
+		},
+	}
+
+	for _, tc := range testCases {
+		p := NewRouterOSParser([]byte(tc.input))
+
+		err := p.Parse()
+		require.Nil(t, err, tc.description)
+
+		obtained := p.Dump()
+		require.Equal(
+			t,
+			time.Date(now.Year(), time.October, 11, 22, 14, 15, 0, time.UTC),
+			obtained["timestamp"],
+			tc.description,
+		)
+		require.Equal(t, tc.expectedHostname, obtained["hostname"], tc.description)
+		require.Equal(t, tc.expectedTag, obtained["tag"], tc.description)
+		require.Equal(t, tc.expectedTopics, obtained["topics"], tc.description)
+		require.Equal(t, tc.expectedContent, obtained["content"], tc.description)
+		require.NotContains(t, obtained, "parser", tc.description)
+	}
+}
+
+func TestRouterOSParserWithDiagnostics(t *testing.T) {
+	buff := []byte(
+		"<30>Oct 11 22:14:15 MikroTik system,info,account user admin logged in from 10.0.0.1 via ssh",
+	)
+
+	p := NewRouterOSParser(buff)
+	p.WithDiagnostics()
+
+	err := p.Parse()
+	require.Nil(t, err)
+
+	obtained := p.Dump()
+	require.Equal(t, ROUTEROS_NAME, obtained["parser"])
+	require.IsType(t, time.Duration(0), obtained["parse_duration"])
+}
+
+func TestRouterOSParserErrors(t *testing.T) {
+	testCases := []struct {
+		description string
+		input       string
+		expectedErr error
+	}{
+		{
+			description: "invalid timestamp",
+			input:       "<30>Foo 11 22:14:15 MikroTik system,info user admin logged in",
+			expectedErr: parsercommon.ErrTimestampUnknownFormat,
+		},
+		{
+			description: "no topics",
+			input:       "<30>Oct 11 22:14:15 mymachine su: 'su root' failed",
+			expectedErr: ErrInvalidTopics,
+		},
+		{
+			description: "no level",
+			input:       "<30>Oct 11 22:14:15 MikroTik system,account user admin logged in",
+			expectedErr: ErrInvalidTopics,
+		},
+		{
+			description: "empty topic",
+			input:       "<30>Oct 11 22:14:15 MikroTik system,,info user admin logged in",
+			expectedErr: ErrInvalidTopics,
+		},
+	}
+
+	for _, tc := range testCases {
+		p := NewRouterOSParser([]byte(tc.input))
+
+		err := p.Parse()
+		require.ErrorIs(t, err, tc.expectedErr, tc.description)
+	}
+}
+
+func TestRouterOSParserWithHostname(t *testing.T) {
+	p := NewRouterOSParser(
+		[]byte("<30>Oct 11 22:14:15 firewall,info input: in:ether1"),
+	)
+	p.WithHostname("router1")
+
+	err := p.Parse()
+	require.Nil(t, err)
+	require.Equal(t, "router1", p.Dump()["hostname"])
+}
+
+func TestIsRouterOS(t *testing.T) {
+	require.True(t, IsRouterOS([]byte("<30>Oct 11 22:14:15 firewall,info input: in:ether1")))
+	require.False(t, IsRouterOS([]byte("<34>Oct 11 22:14:15 mymachine su: 'su root' failed")))
+	require.False(t, IsRouterOS(nil))
+}
+
+func TestRouterOSDialect(t *testing.T) {
+	r := syslogparser.NewRegistry()
+	require.Nil(t, r.Register(RouterOS))
+
+	p, name, err := r.NewAutoParser(
+		[]byte("<30>Oct 11 22:14:15 MikroTik system,info user admin logged in"),
+	)
+	require.Nil(t, err)
+	require.Equal(t, ROUTEROS_NAME, name)
+	require.Nil(t, p.Parse())
+	require.Equal(t, []string{"system", "info"}, p.Dump()["topics"])
+}
@@ -0,0 +1,93 @@
+package dialect
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+)
+
+const (
+	// key of the nested map of fields returned by ParseSnare()
+	SNARE_KEY = "snare"
+
+	// first field of Snare payloads
+	SNARE_MARKER = "MSWinEventLog"
+
+	// "Tue Oct 11 22:14:15 2003"
+	SNARE_DATE_FORMAT = "Mon Jan _2 15:04:05 2006"
+
+	// marker to expanded string, the trailing counter being optional
+	SNARE_FIELDS = 14
+)
+
+var (
+	ErrNoSnare           = &parsercommon.ParserError{ErrorString: "No Snare payload found"}
+	ErrInvalidSnareEvent = &parsercommon.ParserError{ErrorString: "Invalid Snare event"}
+)
+
+// Content parser of Windows events sent by the Snare agent, to be given to
+// WithPayloadParser(). Fields are tab separated so the RFC3164 parser would
+// take the beginning of the payload as tag, set one:
+//
+//	p := rfc3164.NewParser(b)
+//	p.WithTag("snare")
+//	p.WithPayloadParser(dialect.Snare, parsercommon.HEADER_WINS)
+var Snare = parsercommon.PayloadParserFunc(ParseSnare)
+
+// Parses tab separated Snare payloads:
+// MSWinEventLog	1	Security	42	Tue Oct 11 22:14:15 2003	4625	Microsoft-Windows-Security-Auditing	...
+// Fields are returned under SNARE_KEY as a map holding "criticality",
+// "counter" and "event_id", ints, "time", a time.Time in UTC, "channel",
+// "source", "user", "sid_type", "event_type", "computer", "category",
+// "data" and "message".
+func ParseSnare(payload string) (parsercommon.LogParts, error) {
+	start := strings.Index(payload, SNARE_MARKER)
+	if start < 0 {
+		return nil, ErrNoSnare
+	}
+
+	f := strings.Split(strings.TrimRight(payload[start:], "\r\n"), "\t")
+	if len(f) < SNARE_FIELDS {
+		return nil, ErrInvalidSnareEvent
+	}
+
+	criticality, err := strconv.Atoi(f[1])
+	if err != nil {
+		return nil, parsercommon.Wrap(ErrInvalidSnareEvent, err)
+	}
+
+	counter, err := strconv.Atoi(f[3])
+	if err != nil {
+		return nil, parsercommon.Wrap(ErrInvalidSnareEvent, err)
+	}
+
+	ts, err := time.Parse(SNARE_DATE_FORMAT, f[4])
+	if err != nil {
+		return nil, parsercommon.Wrap(ErrInvalidSnareEvent, err)
+	}
+
+	eventId, err := strconv.Atoi(f[5])
+	if err != nil {
+		return nil, parsercommon.Wrap(ErrInvalidSnareEvent, err)
+	}
+
+	return parsercommon.LogParts{
+		SNARE_KEY: map[string]interface{}{
+			"criticality": criticality,
+			"channel":     f[2],
+			"counter":     counter,
+			"time":        ts,
+			"event_id":    eventId,
+			"source":      f[6],
+			"user":        f[7],
+			"sid_type":    f[8],
+			"event_type":  f[9],
+			"computer":    f[10],
+			"category":    f[11],
+			"data":        f[12],
+			"message":     strings.TrimSpace(f[13]),
+		},
+	}, nil
+}
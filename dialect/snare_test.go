@@ -0,0 +1,141 @@
+package dialect
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+	"github.com/jeromer/syslogparser/rfc3164"
+	"github.com/stretchr/testify/require"
+)
+
+func snarePayload(fields ...string) string {
+	return strings.Join(fields, "\t")
+}
+
+func TestParseSnare(t *testing.T) {
+	testCases := []struct {
+		description    string
+		input          string
+		expectedFields map[string]interface{}
+	}{
+		{
+			description: "failed logon",
+			input: snarePayload(
+				"MSWinEventLog", "1", "Security", "42", "Tue Oct 11 22:14:15 2003", "4625",
+				"Microsoft-Windows-Security-Auditing", "CORP\\bob", "N/A", "Failure Audit",
+				"DC01", "Logon", "", "An account failed to log on.  ", "17",
+			),
+			expectedFields: map[string]interface{}{
+				"criticality": 1,
+				"channel":     "Security",
+				"counter":     42,
+				"time":        time.Date(2003, time.October, 11, 22, 14, 15, 0, time.UTC),
+				"event_id":    4625,
+				"source":      "Microsoft-Windows-Security-Auditing",
+				"user":        "CORP\\bob",
+				"sid_type":    "N/A",
+				"event_type":  "Failure Audit",
+				"computer":    "DC01",
+				"category":    "Logon",
+				"data":        "",
+				"message":     "An account failed to log on.",
+			},
+		},
+		{
+			description: "no counter, text before marker",
+			input: "DC01\t" + snarePayload(
+				"MSWinEventLog", "0", "System", "7", "Wed Oct  1 08:00:00 2003", "7036",
+				"Service Control Manager", "N/A", "N/A", "Information", "DC01", "None",
+				"", "The Print Spooler service entered the running state.",
+			),
+			expectedFields: map[string]interface{}{
+				"criticality": 0,
+				"channel":     "System",
+				"counter":     7,
+				"time":        time.Date(2003, time.October, 1, 8, 0, 0, 0, time.UTC),
+				"event_id":    7036,
+				"source":      "Service Control Manager",
+				"user":        "N/A",
+				"sid_type":    "N/A",
+				"event_type":  "Information",
+				"computer":    "DC01",
+				"category":    "None",
+				"data":        "",
+				"message":     "The Print Spooler service entered the running state.",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		obtained, err := ParseSnare(tc.input)
+		require.Nil(t, err, tc.description)
+		require.Equal(
+			t,
+			parsercommon.LogParts{SNARE_KEY: tc.expectedFields},
+			obtained,
+			tc.description,
+		)
+	}
+}
+
+func TestParseSnareErrors(t *testing.T) {
+	testCases := []struct {
+		description string
+		input       string
+		expectedErr error
+	}{
+		{
+			description: "no marker",
+			input:       "'su root' failed for lonvick on /dev/pts/8",
+			expectedErr: ErrNoSnare,
+		},
+		{
+			description: "truncated",
+			input:       snarePayload("MSWinEventLog", "1", "Security", "42"),
+			expectedErr: ErrInvalidSnareEvent,
+		},
+		{
+			description: "invalid event ID",
+			input: snarePayload(
+				"MSWinEventLog", "1", "Security", "42", "Tue Oct 11 22:14:15 2003", "x",
+				"Source", "N/A", "N/A", "Information", "DC01", "None", "", "message",
+			),
+			expectedErr: ErrInvalidSnareEvent,
+		},
+		{
+			description: "invalid date",
+			input: snarePayload(
+				"MSWinEventLog", "1", "Security", "42", "2003-10-11 22:14:15", "4625",
+				"Source", "N/A", "N/A", "Information", "DC01", "None", "", "message",
+			),
+			expectedErr: ErrInvalidSnareEvent,
+		},
+	}
+
+	for _, tc := range testCases {
+		_, err := ParseSnare(tc.input)
+		require.ErrorIs(t, err, tc.expectedErr, tc.description)
+	}
+}
+
+func TestSnarePayloadParser(t *testing.T) {
+	p := rfc3164.NewParser(
+		[]byte("<13>Oct 11 22:14:15 DC01 " + snarePayload(
+			"MSWinEventLog", "1", "Security", "42", "Tue Oct 11 22:14:15 2003", "4624",
+			"Microsoft-Windows-Security-Auditing", "CORP\\bob", "N/A", "Success Audit",
+			"DC01", "Logon", "", "An account was successfully logged on.", "18",
+		)),
+	)
+	p.WithTag("snare")
+	p.WithPayloadParser(Snare, parsercommon.HEADER_WINS)
+
+	err := p.Parse()
+	require.Nil(t, err)
+
+	event := p.Dump()[SNARE_KEY].(map[string]interface{})
+	require.Equal(t, 4624, event["event_id"])
+	require.Equal(t, "Security", event["channel"])
+	require.Equal(t, "CORP\\bob", event["user"])
+}
@@ -0,0 +1,181 @@
+package dialect
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jeromer/syslogparser"
+	"github.com/jeromer/syslogparser/parsercommon"
+)
+
+const (
+	// reported as "parser" when diagnostics are enabled
+	UNIFI_NAME = "unifi"
+)
+
+var (
+	ErrInvalidUniFiPrefix = &parsercommon.ParserError{ErrorString: "Invalid UniFi device prefix"}
+)
+
+// "OfficeAP 802aa8a1b2c3,UAP-AC-Pro-Gen2-4.3.20.11298: ", the version being
+// what follows the last dash of the model
+var unifiPrefix = regexp.MustCompile(
+	`([^\s>]+) ([0-9A-Fa-f]{12}),(\S+)-(v?[0-9][0-9.+a-z]*): `,
+)
+
+// Ubiquiti UniFi messages, the timestamp being often missing:
+// <13>OfficeAP 802aa8a1b2c3,UAP-AC-Pro-Gen2-4.3.20.11298: hostapd: ath0: ...
+var UniFi = syslogparser.Dialect{
+	Name:   UNIFI_NAME,
+	Detect: IsUniFi,
+	New: func(buff []byte) syslogparser.LogParser {
+		return NewUniFiParser(buff)
+	},
+}
+
+// Parses Ubiquiti UniFi messages, with or without timestamp, whose
+// "DEVNAME MAC,MODEL-VER:" prefix gives "device_name", also reported as
+// "hostname", "mac", as in "80:2a:a8:a1:b2:c3", "model" and
+// "firmware_version". The tag and pid following it are parsed as RFC3164 ones.
+type UniFiParser struct {
+	buff     []byte
+	location *time.Location
+	hostname string
+	diag     diagnostics
+
+	header     parsercommon.LogParts
+	deviceName string
+	mac        string
+	model      string
+	version    string
+	tag        string
+	pid        string
+	content    string
+}
+
+func NewUniFiParser(buff []byte) *UniFiParser {
+	return &UniFiParser{
+		buff:     buff,
+		location: time.UTC,
+	}
+}
+
+func (p *UniFiParser) WithLocation(l *time.Location) {
+	p.location = l
+}
+
+func (p *UniFiParser) WithHostname(h string) {
+	p.hostname = h
+}
+
+// Noop, UniFi devices send RFC3164 timestamps, if any
+func (p *UniFiParser) WithTimestampFormat(s string) {}
+
+func (p *UniFiParser) WithTag(t string) {
+	p.tag = t
+}
+
+// Adds the name of the parser ("parser") and the time spent parsing
+// ("parse_duration", a time.Duration) to Dump()
+func (p *UniFiParser) WithDiagnostics() {
+	p.diag.enabled = true
+}
+
+func (p *UniFiParser) Parse() error {
+	return p.diag.parse(p.parse)
+}
+
+func (p *UniFiParser) parse() error {
+	m := unifiPrefix.FindSubmatchIndex(p.buff)
+	if m == nil {
+		return ErrInvalidUniFiPrefix
+	}
+
+	hdr, err := parseHeader(
+		[]byte(strings.TrimRight(string(p.buff[:m[0]]), " ")), p.location, "",
+	)
+	if err != nil {
+		return err
+	}
+
+	p.header = hdr
+	p.deviceName = string(p.buff[m[2]:m[3]])
+	p.mac = formatMAC(string(p.buff[m[4]:m[5]]))
+	p.model = string(p.buff[m[6]:m[7]])
+	p.version = string(p.buff[m[8]:m[9]])
+
+	tag, pid, content := splitTag(string(p.buff[m[1]:]))
+	if p.tag == "" {
+		p.tag = tag
+		p.pid = pid
+	}
+
+	p.content = content
+
+	return nil
+}
+
+func (p *UniFiParser) Dump() parsercommon.LogParts {
+	parts := parsercommon.LogParts{}
+
+	for k, v := range p.header {
+		parts[k] = v
+	}
+
+	parts["hostname"] = p.deviceName
+	if p.hostname != "" {
+		parts["hostname"] = p.hostname
+	}
+
+	parts["device_name"] = p.deviceName
+	parts["mac"] = p.mac
+	parts["model"] = p.model
+	parts["firmware_version"] = p.version
+	parts["tag"] = p.tag
+	parts["pid"] = p.pid
+	parts["content"] = p.content
+
+	p.diag.dump(parts, UNIFI_NAME)
+
+	return parts
+}
+
+// "802aa8a1b2c3" => "80:2a:a8:a1:b2:c3"
+func formatMAC(s string) string {
+	s = strings.ToLower(s)
+
+	var b strings.Builder
+
+	for i := 0; i < len(s); i += 2 {
+		if i > 0 {
+			b.WriteByte(':')
+		}
+
+		b.WriteString(s[i : i+2])
+	}
+
+	return b.String()
+}
+
+// "hostapd[42]: ath0: ..." => "hostapd", "42", "ath0: ...". Content without
+// tag is returned as is.
+func splitTag(s string) (string, string, string) {
+	end := strings.Index(s, ": ")
+	if end <= 0 || strings.IndexByte(s[:end], ' ') >= 0 {
+		return "", "", s
+	}
+
+	tag, pid := s[:end], ""
+
+	if i := strings.IndexByte(tag, '['); i > 0 && strings.HasSuffix(tag, "]") {
+		tag, pid = tag[:i], tag[i+1:len(tag)-1]
+	}
+
+	return tag, pid, s[end+2:]
+}
+
+// Returns true when buff has PRI and a "DEVNAME MAC,MODEL-VER:" prefix
+func IsUniFi(buff []byte) bool {
+	return len(buff) > 0 && buff[0] == '<' && unifiPrefix.Match(buff)
+}
@@ -0,0 +1,165 @@
+package dialect
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jeromer/syslogparser"
+	"github.com/jeromer/syslogparser/parsercommon"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUniFiParser(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		description       string
+		input             string
+		expectedTimestamp time.Time
+		expectedDevice    string
+		expectedMAC       string
+		expectedModel     string
+		expectedVersion   string
+		expectedTag       string
+		expectedPid       string
+		expectedContent   string
+	}{
+		{
+			description:       "no timestamp",
+			input:             "<13>OfficeAP 802AA8A1B2C3,UAP-AC-Pro-Gen2-4.3.20.11298: hostapd: ath0: STA 11:22:33:44:55:66 IEEE 802.11: associated",
+			expectedTimestamp: time.Time{},
+			expectedDevice:    "OfficeAP",
+			expectedMAC:       "80:2a:a8:a1:b2:c3",
+			expectedModel:     "UAP-AC-Pro-Gen2",
+			expectedVersion:   "4.3.20.11298",
+			expectedTag:       "hostapd",
+			expectedPid:       "",
+			expectedContent:   "ath0: STA 11:22:33:44:55:66 IEEE 802.11: associated",
+		},
+		{
+			description:       "timestamp and pid",
+			input:             "<30>Oct 11 22:14:15 Switch-1 f09fc2a1b2c3,US-8-60W-v4.0.66.10832: mcad[1234]: ace_reporter.reporter_fail(): Unknown[11] (http://unifi:8080/inform)",
+			expectedTimestamp: time.Date(now.Year(), time.October, 11, 22, 14, 15, 0, time.UTC),
+			expectedDevice:    "Switch-1",
+			expectedMAC:       "f0:9f:c2:a1:b2:c3",
+			expectedModel:     "US-8-60W",
+			expectedVersion:   "v4.0.66.10832",
+			expectedTag:       "mcad",
+			expectedPid:       "1234",
+			expectedContent:   "ace_reporter.reporter_fail(): Unknown[11] (http://unifi:8080/inform)",
+		},
+		{
+			description:       "no tag",
+			input:             "<13>OfficeAP 802aa8a1b2c3,U7PG2-4.0.80.10875: kernel rebooting now",
+			expectedTimestamp: time.Time{},
+			expectedDevice:    "OfficeAP",
+			expectedMAC:       "80:2a:a8:a1:b2:c3",
+			expectedModel:     "U7PG2",
+			expectedVersion:   "4.0.80.10875",
+			expectedTag:       "",
+			expectedPid:       "",
+			expectedContent:   "kernel rebooting now",
+		},
+	}
+
+	for _, tc := range testCases {
+		p := NewUniFiParser([]byte(tc.input))
+
+		err := p.Parse()
+		require.Nil(t, err, tc.description)
+
+		obtained := p.Dump()
+		require.Equal(t, tc.expectedTimestamp, obtained["timestamp"], tc.description)
+		require.Equal(t, tc.expectedDevice, obtained["hostname"], tc.description)
+		require.Equal(t, tc.expectedDevice, obtained["device_name"], tc.description)
+		require.Equal(t, tc.expectedMAC, obtained["mac"], tc.description)
+		require.Equal(t, tc.expectedModel, obtained["model"], tc.description)
+		require.Equal(t, tc.expectedVersion, obtained["firmware_version"], tc.description)
+		require.Equal(t, tc.expectedTag, obtained["tag"], tc.description)
+		require.Equal(t, tc.expectedPid, obtained["pid"], tc.description)
+		require.Equal(t, tc.expectedContent, obtained["content"], tc.description)
+		require.NotContains(t, obtained, "parser", tc.description)
+	}
+}
+
+func TestUniFiParserWithDiagnostics(t *testing.T) {
+	buff := []byte(
+		"<13>OfficeAP 802AA8A1B2C3,UAP-AC-Pro-Gen2-4.3.20.11298: hostapd: ath0: STA 11:22:33:44:55:66 IEEE 802.11: associated",
+	)
+
+	p := NewUniFiParser(buff)
+	p.WithDiagnostics()
+
+	err := p.Parse()
+	require.Nil(t, err)
+
+	obtained := p.Dump()
+	require.Equal(t, UNIFI_NAME, obtained["parser"])
+	require.IsType(t, time.Duration(0), obtained["parse_duration"])
+}
+
+func TestUniFiParserErrors(t *testing.T) {
+	testCases := []struct {
+		description string
+		input       string
+		expectedErr error
+	}{
+		{
+			description: "no priority",
+			input:       "Oct 11 22:14:15 OfficeAP 802aa8a1b2c3,U7PG2-4.0.80.10875: hostapd: ath0",
+			expectedErr: parsercommon.ErrPriorityNoStart,
+		},
+		{
+			description: "no prefix",
+			input:       "<34>Oct 11 22:14:15 mymachine su: 'su root' failed",
+			expectedErr: ErrInvalidUniFiPrefix,
+		},
+		{
+			description: "invalid MAC",
+			input:       "<13>OfficeAP 802aa8a1b2,U7PG2-4.0.80.10875: hostapd: ath0",
+			expectedErr: ErrInvalidUniFiPrefix,
+		},
+	}
+
+	for _, tc := range testCases {
+		p := NewUniFiParser([]byte(tc.input))
+
+		err := p.Parse()
+		require.ErrorIs(t, err, tc.expectedErr, tc.description)
+	}
+}
+
+func TestUniFiParserWithHostname(t *testing.T) {
+	p := NewUniFiParser(
+		[]byte("<13>OfficeAP 802aa8a1b2c3,U7PG2-4.0.80.10875: hostapd: ath0"),
+	)
+	p.WithHostname("10.0.0.5")
+	p.WithTag("unifi")
+
+	err := p.Parse()
+	require.Nil(t, err)
+
+	obtained := p.Dump()
+	require.Equal(t, "10.0.0.5", obtained["hostname"])
+	require.Equal(t, "OfficeAP", obtained["device_name"])
+	require.Equal(t, "unifi", obtained["tag"])
+}
+
+func TestIsUniFi(t *testing.T) {
+	require.True(t, IsUniFi([]byte("<13>OfficeAP 802aa8a1b2c3,U7PG2-4.0.80.10875: hostapd: ath0")))
+	require.False(t, IsUniFi([]byte("<34>Oct 11 22:14:15 mymachine su: 'su root' failed")))
+	require.False(t, IsUniFi(nil))
+}
+
+func TestUniFiDialect(t *testing.T) {
+	r := syslogparser.NewRegistry()
+	require.Nil(t, r.Register(UniFi))
+
+	p, name, err := r.NewAutoParser(
+		[]byte("<13>OfficeAP 802aa8a1b2c3,U7PG2-4.0.80.10875: hostapd: ath0"),
+	)
+	require.Nil(t, err)
+	require.Equal(t, UNIFI_NAME, name)
+	require.Nil(t, p.Parse())
+	require.Equal(t, "U7PG2", p.Dump()["model"])
+}
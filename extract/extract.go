@@ -0,0 +1,177 @@
+// Package extract adds structured fields to parsed messages by matching their
+// content against patterns registered by tag (or APP-NAME), grok-like:
+//
+//	e := extract.NewExtractor()
+//
+//	p, err := extract.NewTemplatePattern(
+//		"sshd_failed", "Failed %{WORD:method} for %{NOTSPACE:user} from %{IP:src_ip} port %{INT:src_port}",
+//	)
+//	err = e.Register("sshd", p)
+//
+//	e.Apply(parts, parsercommon.HEADER_WINS)
+package extract
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"sync"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+)
+
+const (
+	// patterns registered under ANY_KEY are tried on every message, after
+	// the ones registered under its tag
+	ANY_KEY = ""
+
+	// name of the matching pattern in the parts returned by Extract()
+	PATTERN_KEY = "pattern"
+)
+
+var (
+	ErrInvalidPattern = errors.New("Invalid pattern")
+	ErrUnknownPattern = errors.New("Unknown template pattern")
+)
+
+// Named regular expression whose named sub-expressions are the extracted
+// fields
+type Pattern struct {
+	Name string
+
+	re    *regexp.Regexp
+	types map[string]string
+}
+
+// Builds a pattern from a regular expression, ie.
+// `user (?P<user>\S+) logged in`. Errors of regexp.Compile() are returned as
+// is, ErrInvalidPattern when expr has no named sub-expression.
+func NewRegexpPattern(name string, expr string) (*Pattern, error) {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPattern(name, re, nil)
+}
+
+func newPattern(name string, re *regexp.Regexp, types map[string]string) (*Pattern, error) {
+	named := false
+	for _, n := range re.SubexpNames() {
+		named = named || n != ""
+	}
+
+	if name == "" || !named {
+		return nil, ErrInvalidPattern
+	}
+
+	return &Pattern{Name: name, re: re, types: types}, nil
+}
+
+// Returns the fields of s, nil when s does not match
+func (p *Pattern) Match(s string) parsercommon.LogParts {
+	m := p.re.FindStringSubmatch(s)
+	if m == nil {
+		return nil
+	}
+
+	parts := parsercommon.LogParts{}
+
+	for i, name := range p.re.SubexpNames() {
+		if name == "" {
+			continue
+		}
+
+		parts[name] = convert(m[i], p.types[name])
+	}
+
+	return parts
+}
+
+func convert(s string, typ string) interface{} {
+	switch typ {
+	case "INT":
+		if n, err := strconv.Atoi(s); err == nil {
+			return n
+		}
+	case "NUMBER":
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	}
+
+	return s
+}
+
+// Patterns by tag. An Extractor is safe for concurrent use, patterns are
+// tried in registration order.
+type Extractor struct {
+	mu       sync.RWMutex
+	patterns map[string][]*Pattern
+}
+
+func NewExtractor() *Extractor {
+	return &Extractor{
+		patterns: map[string][]*Pattern{},
+	}
+}
+
+// Registers p for messages whose tag, or APP-NAME, is key
+func (e *Extractor) Register(key string, p *Pattern) error {
+	if p == nil || p.re == nil {
+		return ErrInvalidPattern
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.patterns[key] = append(e.patterns[key], p)
+
+	return nil
+}
+
+// Returns the fields extracted from the content (or message) of parts by the
+// first matching pattern, and its name as PATTERN_KEY, or nil when none
+// matches
+func (e *Extractor) Extract(parts parsercommon.LogParts) parsercommon.LogParts {
+	key, _ := parts["tag"].(string)
+	if key == "" {
+		key, _ = parts["app_name"].(string)
+	}
+
+	msg, ok := parts["content"].(string)
+	if !ok {
+		msg, _ = parts["message"].(string)
+	}
+
+	var patterns []*Pattern
+
+	e.mu.RLock()
+	if key != ANY_KEY {
+		patterns = append(patterns, e.patterns[key]...)
+	}
+	patterns = append(patterns, e.patterns[ANY_KEY]...)
+	e.mu.RUnlock()
+
+	for _, p := range patterns {
+		if fields := p.Match(msg); fields != nil {
+			fields[PATTERN_KEY] = p.Name
+			return fields
+		}
+	}
+
+	return nil
+}
+
+// Merges the result of Extract() into parts following policy. Returns false
+// when no pattern matched.
+func (e *Extractor) Apply(parts parsercommon.LogParts, policy parsercommon.KeyPolicy) bool {
+	fields := e.Extract(parts)
+	if fields == nil {
+		return false
+	}
+
+	parsercommon.Merge(parts, fields, policy)
+
+	return true
+}
@@ -0,0 +1,141 @@
+package extract
+
+import (
+	"regexp/syntax"
+	"testing"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+	"github.com/jeromer/syslogparser/rfc3164"
+	"github.com/jeromer/syslogparser/rfc5424"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRegexpPattern(t *testing.T) {
+	p, err := NewRegexpPattern("login", `user (?P<user>\S+) logged in from (?P<src_ip>\S+)`)
+	require.Nil(t, err)
+	require.Equal(t, "login", p.Name)
+
+	require.Equal(
+		t,
+		parsercommon.LogParts{"user": "bob", "src_ip": "10.0.0.1"},
+		p.Match("session: user bob logged in from 10.0.0.1"),
+	)
+	require.Nil(t, p.Match("user bob logged out"))
+}
+
+func TestNewRegexpPatternErrors(t *testing.T) {
+	_, err := NewRegexpPattern("login", `user (?P<user>\S+`)
+	require.IsType(t, &syntax.Error{}, err)
+
+	_, err = NewRegexpPattern("login", `user (\S+) logged in`)
+	require.Equal(t, ErrInvalidPattern, err)
+
+	_, err = NewRegexpPattern("", `user (?P<user>\S+) logged in`)
+	require.Equal(t, ErrInvalidPattern, err)
+}
+
+func TestExtractor(t *testing.T) {
+	e := NewExtractor()
+
+	failed, err := NewRegexpPattern("sshd_failed", `^Failed (?P<method>\S+) for (?P<user>\S+)`)
+	require.Nil(t, err)
+	require.Nil(t, e.Register("sshd", failed))
+
+	accepted, err := NewRegexpPattern("sshd_accepted", `^Accepted (?P<method>\S+) for (?P<user>\S+)`)
+	require.Nil(t, err)
+	require.Nil(t, e.Register("sshd", accepted))
+
+	anyIP, err := NewRegexpPattern("any_ip", `(?P<ip>\d+\.\d+\.\d+\.\d+)`)
+	require.Nil(t, err)
+	require.Nil(t, e.Register(ANY_KEY, anyIP))
+
+	testCases := []struct {
+		description    string
+		parts          parsercommon.LogParts
+		expectedFields parsercommon.LogParts
+	}{
+		{
+			description: "tag, first pattern",
+			parts: parsercommon.LogParts{
+				"tag":     "sshd",
+				"content": "Failed password for root from 10.0.0.1 port 22 ssh2",
+			},
+			expectedFields: parsercommon.LogParts{
+				"pattern": "sshd_failed",
+				"method":  "password",
+				"user":    "root",
+			},
+		},
+		{
+			description: "app name, second pattern",
+			parts: parsercommon.LogParts{
+				"app_name": "sshd",
+				"message":  "Accepted publickey for bob from 10.0.0.1 port 22 ssh2",
+			},
+			expectedFields: parsercommon.LogParts{
+				"pattern": "sshd_accepted",
+				"method":  "publickey",
+				"user":    "bob",
+			},
+		},
+		{
+			description: "any key",
+			parts: parsercommon.LogParts{
+				"tag":     "myapp",
+				"content": "connection from 10.0.0.1",
+			},
+			expectedFields: parsercommon.LogParts{
+				"pattern": "any_ip",
+				"ip":      "10.0.0.1",
+			},
+		},
+		{
+			description: "no match",
+			parts: parsercommon.LogParts{
+				"tag":     "sshd",
+				"content": "Connection closed by authenticating user root",
+			},
+			expectedFields: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.expectedFields, e.Extract(tc.parts), tc.description)
+	}
+}
+
+func TestExtractorRegisterErrors(t *testing.T) {
+	e := NewExtractor()
+	require.Equal(t, ErrInvalidPattern, e.Register("sshd", nil))
+	require.Equal(t, ErrInvalidPattern, e.Register("sshd", &Pattern{Name: "empty"}))
+}
+
+func TestExtractorApply(t *testing.T) {
+	e := NewExtractor()
+
+	tmpl, err := NewTemplatePattern(
+		"sshd_failed",
+		"Failed %{WORD:method} for %{NOTSPACE:user} from %{IP:src_ip} port %{INT:src_port}",
+	)
+	require.Nil(t, err)
+	require.Nil(t, e.Register("sshd", tmpl))
+
+	p := rfc3164.NewParser(
+		[]byte("<38>Oct 11 22:14:15 mymachine sshd[4321]: Failed password for root from 198.51.100.7 port 40000 ssh2"),
+	)
+	require.Nil(t, p.Parse())
+
+	parts := p.Dump()
+	require.True(t, e.Apply(parts, parsercommon.HEADER_WINS))
+	require.Equal(t, "mymachine", parts["hostname"])
+	require.Equal(t, "root", parts["user"])
+	require.Equal(t, "198.51.100.7", parts["src_ip"])
+	require.Equal(t, 40000, parts["src_port"])
+	require.Equal(t, "sshd_failed", parts[PATTERN_KEY])
+
+	p5 := rfc5424.NewParser(
+		[]byte("<38>1 2003-10-11T22:14:15.003Z mymachine myapp - - - started"),
+	)
+	require.Nil(t, p5.Parse())
+	require.False(t, e.Apply(p5.Dump(), parsercommon.HEADER_WINS))
+}
@@ -0,0 +1,65 @@
+package extract
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Patterns available in templates as %{NAME} or %{NAME:field}. INT fields
+// are extracted as int, NUMBER ones as float64, others as string.
+var TEMPLATE_PATTERNS = map[string]string{
+	"WORD":         `\w+`,
+	"NOTSPACE":     `\S+`,
+	"DATA":         `.*?`,
+	"GREEDYDATA":   `.*`,
+	"INT":          `[+-]?\d+`,
+	"NUMBER":       `[+-]?\d+(?:\.\d+)?`,
+	"IP":           `(?:\d{1,3}(?:\.\d{1,3}){3}|[0-9A-Fa-f]*:[0-9A-Fa-f:.]+)`,
+	"HOSTNAME":     `[0-9A-Za-z](?:[0-9A-Za-z.-]*[0-9A-Za-z])?`,
+	"QUOTEDSTRING": `"(?:[^"\\]|\\.)*"`,
+}
+
+// "%{IP:src_ip}"
+var templateRef = regexp.MustCompile(`%\{([A-Z]+)(?::([A-Za-z_][A-Za-z0-9_]*))?\}`)
+
+// Builds a pattern from a template, text holding %{NAME:field} references to
+// TEMPLATE_PATTERNS, ie. "Failed %{WORD:method} for %{NOTSPACE:user}". The
+// rest of the template is matched literally. ErrUnknownPattern is returned
+// for unknown names.
+func NewTemplatePattern(name string, template string) (*Pattern, error) {
+	var b strings.Builder
+
+	types := map[string]string{}
+	from := 0
+
+	for _, m := range templateRef.FindAllStringSubmatchIndex(template, -1) {
+		b.WriteString(regexp.QuoteMeta(template[from:m[0]]))
+		from = m[1]
+
+		ref := template[m[2]:m[3]]
+
+		expr, ok := TEMPLATE_PATTERNS[ref]
+		if !ok {
+			return nil, ErrUnknownPattern
+		}
+
+		if m[4] < 0 {
+			b.WriteString("(?:" + expr + ")")
+			continue
+		}
+
+		field := template[m[4]:m[5]]
+		types[field] = ref
+
+		b.WriteString("(?P<" + field + ">" + expr + ")")
+	}
+
+	b.WriteString(regexp.QuoteMeta(template[from:]))
+
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return newPattern(name, re, types)
+}
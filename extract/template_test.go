@@ -0,0 +1,66 @@
+package extract
+
+import (
+	"testing"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTemplatePattern(t *testing.T) {
+	testCases := []struct {
+		description    string
+		template       string
+		input          string
+		expectedFields parsercommon.LogParts
+	}{
+		{
+			description: "typed fields",
+			template:    "took %{NUMBER:duration}s, %{INT:retries} retries",
+			input:       "request took 1.25s, 3 retries",
+			expectedFields: parsercommon.LogParts{
+				"duration": 1.25,
+				"retries":  3,
+			},
+		},
+		{
+			description: "literal text is escaped",
+			template:    "[%{WORD:level}] (%{HOSTNAME:host}) %{GREEDYDATA:msg}",
+			input:       "[warn] (db1.example.com) disk 91% full",
+			expectedFields: parsercommon.LogParts{
+				"level": "warn",
+				"host":  "db1.example.com",
+				"msg":   "disk 91% full",
+			},
+		},
+		{
+			description: "unnamed reference, ipv6, quoted string",
+			template:    "%{WORD} %{IP:ip} said %{QUOTEDSTRING:quote}",
+			input:       "client 2001:db8::1 said \"hello \\\"world\\\"\"",
+			expectedFields: parsercommon.LogParts{
+				"ip":    "2001:db8::1",
+				"quote": "\"hello \\\"world\\\"\"",
+			},
+		},
+		{
+			description:    "no match",
+			template:       "took %{NUMBER:duration}s",
+			input:          "took forever",
+			expectedFields: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		p, err := NewTemplatePattern("test", tc.template)
+		require.Nil(t, err, tc.description)
+		require.Equal(t, tc.expectedFields, p.Match(tc.input), tc.description)
+	}
+}
+
+func TestNewTemplatePatternErrors(t *testing.T) {
+	_, err := NewTemplatePattern("test", "took %{DURATION:duration}")
+	require.Equal(t, ErrUnknownPattern, err)
+
+	_, err = NewTemplatePattern("test", "took %{NUMBER}s")
+	require.Equal(t, ErrInvalidPattern, err)
+}
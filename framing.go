@@ -0,0 +1,59 @@
+package syslogparser
+
+import (
+	"github.com/jeromer/syslogparser/parsercommon"
+)
+
+const (
+	// https://tools.ietf.org/html/rfc6587#section-3.4.1
+	// MSG-LEN is at most 10 digits long
+	MAX_MSG_LEN_DIGITS = 10
+)
+
+// Returns the offset of the syslog message in a raw TCP frame payload,
+// skipping leading spaces, tabs, CR and LF and the octet count prefix of
+// RFC6587, as in "123 <34>Oct 11 ...".
+// XXX : "1 2003-10-11T22:14:15.003Z ..." may be an RFC5424 message without
+// XXX : PRI, the octet count is only skipped when followed by "<" or when
+// XXX : it matches the length of the message.
+func Unframe(buff []byte) int {
+	i := 0
+
+	for i < len(buff) && isFramingSpace(buff[i]) {
+		i++
+	}
+
+	j := i
+	l := 0
+
+	for j < len(buff) && j-i < MAX_MSG_LEN_DIGITS && parsercommon.IsDigit(buff[j]) {
+		l = (l * 10) + int(buff[j]-'0')
+		j++
+	}
+
+	if j == i || j >= len(buff) || buff[j] != ' ' {
+		return i
+	}
+
+	j++
+
+	if (j < len(buff) && buff[j] == '<') || l == len(buff)-j {
+		return j
+	}
+
+	return i
+}
+
+// Same as DetectRFC() on a raw TCP frame payload. Returns the offset of the
+// message in buff as well, see Unframe(). Parse buff[offset:].
+func DetectFramedRFC(buff []byte) (RFC, int, error) {
+	offset := Unframe(buff)
+
+	rfc, err := DetectRFC(buff[offset:])
+
+	return rfc, offset, err
+}
+
+func isFramingSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\r' || b == '\n'
+}
@@ -0,0 +1,85 @@
+package syslogparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnframe(t *testing.T) {
+	testCases := []struct {
+		description string
+		input       string
+		expected    string
+	}{
+		{
+			description: "no framing",
+			input:       "<34>Oct 11 22:14:15 mymachine su: msg",
+			expected:    "<34>Oct 11 22:14:15 mymachine su: msg",
+		},
+		{
+			description: "octet count",
+			input:       "37 <34>Oct 11 22:14:15 mymachine su: msg",
+			expected:    "<34>Oct 11 22:14:15 mymachine su: msg",
+		},
+		{
+			description: "wrong octet count followed by priority",
+			input:       "12 <34>Oct 11 22:14:15 mymachine su: msg",
+			expected:    "<34>Oct 11 22:14:15 mymachine su: msg",
+		},
+		{
+			description: "leading whitespaces",
+			input:       "\r\n \t<34>Oct 11 22:14:15 mymachine su: msg",
+			expected:    "<34>Oct 11 22:14:15 mymachine su: msg",
+		},
+		{
+			description: "leading whitespaces and octet count",
+			input:       "\n37 <34>Oct 11 22:14:15 mymachine su: msg",
+			expected:    "<34>Oct 11 22:14:15 mymachine su: msg",
+		},
+		{
+			description: "octet count without priority",
+			input:       "52 1 2003-10-11T22:14:15.003Z mymachine su - ID47 - msg",
+			expected:    "1 2003-10-11T22:14:15.003Z mymachine su - ID47 - msg",
+		},
+		{
+			description: "version without priority",
+			input:       "1 2003-10-11T22:14:15.003Z mymachine su - ID47 - msg",
+			expected:    "1 2003-10-11T22:14:15.003Z mymachine su - ID47 - msg",
+		},
+		{
+			description: "digits only",
+			input:       "\r\n123",
+			expected:    "123",
+		},
+		{
+			description: "empty",
+			input:       "",
+			expected:    "",
+		},
+	}
+
+	for _, tc := range testCases {
+		buff := []byte(tc.input)
+
+		require.Equal(
+			t, tc.expected, string(buff[Unframe(buff):]), tc.description,
+		)
+	}
+}
+
+func TestDetectFramedRFC(t *testing.T) {
+	buff := []byte("37 <34>Oct 11 22:14:15 mymachine su: msg")
+
+	rfc, offset, err := DetectFramedRFC(buff)
+	require.Nil(t, err)
+	require.Equal(t, RFC(RFC_3164), rfc)
+	require.Equal(t, 3, offset)
+
+	buff = []byte("  \r\n")
+
+	rfc, offset, err = DetectFramedRFC(buff)
+	require.Equal(t, ErrBufferTooShort, err)
+	require.Equal(t, RFC(RFC_UNKNOWN), rfc)
+	require.Equal(t, 4, offset)
+}
@@ -0,0 +1,137 @@
+// Package leef parses IBM QRadar Log Event Extended Format payloads carried
+// in syslog messages:
+//
+//	LEEF:1.0|Vendor|Product|1.0|Login|src=10.0.0.1<tab>usrName=bob
+//	LEEF:2.0|Vendor|Product|1.0|Login|^|src=10.0.0.1^usrName=bob
+//
+// Payload is a parsercommon.PayloadParser to be given to WithPayloadParser().
+package leef
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+)
+
+const (
+	// start of LEEF payloads, followed by the version
+	LEEF_PREFIX = "LEEF:"
+
+	// key of the nested map returned by ParsePayload()
+	LEEF_KEY = "leef"
+
+	VERSION_1 = "1.0"
+	VERSION_2 = "2.0"
+
+	// separates attributes of LEEF 1.0 payloads, and of LEEF 2.0 ones which
+	// do not set a delimiter
+	DEFAULT_DELIMITER = '\t'
+
+	// version, vendor, product, product version, event ID
+	HEADER_FIELDS = 5
+)
+
+var (
+	ErrNoLEEF           = &parsercommon.ParserError{ErrorString: "No LEEF prefix found"}
+	ErrInvalidHeader    = &parsercommon.ParserError{ErrorString: "Invalid LEEF header"}
+	ErrInvalidVersion   = &parsercommon.ParserError{ErrorString: "Invalid LEEF version"}
+	ErrInvalidDelimiter = &parsercommon.ParserError{ErrorString: "Invalid LEEF delimiter"}
+)
+
+var Payload = parsercommon.PayloadParserFunc(ParsePayload)
+
+// Returns true when s holds a LEEF payload
+func IsLEEF(s string) bool {
+	return strings.Contains(s, LEEF_PREFIX)
+}
+
+// Parses the LEEF payload found in s, ie. the content of an RFC3164 message,
+// and returns it under LEEF_KEY as a map holding "version", "vendor",
+// "product", "product_version", "event_id", "delimiter", a string, and
+// "attributes", a map[string]string. Text before LEEF_PREFIX is ignored.
+func ParsePayload(s string) (parsercommon.LogParts, error) {
+	start := strings.Index(s, LEEF_PREFIX)
+	if start < 0 {
+		return nil, ErrNoLEEF
+	}
+
+	fields := strings.SplitN(s[start+len(LEEF_PREFIX):], "|", HEADER_FIELDS+1)
+	if len(fields) <= HEADER_FIELDS {
+		return nil, ErrInvalidHeader
+	}
+
+	attrs := fields[HEADER_FIELDS]
+	delimiter := byte(DEFAULT_DELIMITER)
+
+	switch fields[0] {
+	case VERSION_1:
+	case VERSION_2:
+		d, rest, err := parseDelimiter(attrs)
+		if err != nil {
+			return nil, err
+		}
+
+		delimiter, attrs = d, rest
+	default:
+		return nil, ErrInvalidVersion
+	}
+
+	return parsercommon.LogParts{
+		LEEF_KEY: map[string]interface{}{
+			"version":         fields[0],
+			"vendor":          fields[1],
+			"product":         fields[2],
+			"product_version": fields[3],
+			"event_id":        fields[4],
+			"delimiter":       string(delimiter),
+			"attributes":      parseAttributes(attrs, delimiter),
+		},
+	}, nil
+}
+
+// LEEF 2.0 delimiter field: a character ("^") or its code ("0x5E" or "x5E"),
+// followed by a pipe. Payloads without delimiter field use DEFAULT_DELIMITER.
+func parseDelimiter(s string) (byte, string, error) {
+	end := strings.IndexByte(s, '|')
+	if end < 0 || strings.IndexByte(s[:end], '=') >= 0 {
+		return DEFAULT_DELIMITER, s, nil
+	}
+
+	spec, rest := s[:end], s[end+1:]
+
+	switch {
+	case len(spec) == 0:
+		return DEFAULT_DELIMITER, rest, nil
+	case len(spec) == 1:
+		return spec[0], rest, nil
+	}
+
+	hex := strings.TrimPrefix(strings.TrimPrefix(strings.ToLower(spec), "0"), "x")
+	if len(hex) == len(spec) {
+		return 0, "", ErrInvalidDelimiter
+	}
+
+	n, err := strconv.ParseUint(hex, 16, 8)
+	if err != nil {
+		return 0, "", parsercommon.Wrap(ErrInvalidDelimiter, err)
+	}
+
+	return byte(n), rest, nil
+}
+
+// "src=10.0.0.1\tusrName=bob", pairs without '=' are ignored
+func parseAttributes(s string, delimiter byte) map[string]string {
+	attrs := map[string]string{}
+
+	for _, pair := range strings.Split(s, string(delimiter)) {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			continue
+		}
+
+		attrs[kv[0]] = kv[1]
+	}
+
+	return attrs
+}
@@ -0,0 +1,170 @@
+package leef
+
+import (
+	"testing"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+	"github.com/jeromer/syslogparser/rfc3164"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePayload(t *testing.T) {
+	testCases := []struct {
+		description        string
+		input              string
+		expectedVersion    string
+		expectedEventId    string
+		expectedDelimiter  string
+		expectedAttributes map[string]string
+	}{
+		{
+			description:       "leef 1.0",
+			input:             "LEEF:1.0|Microsoft|MSExchange|4.0 SP1|15345|src=192.0.2.0\tdst=172.50.123.1\tsev=5\tcat=anomaly\tmsg=there are spaces in this message",
+			expectedVersion:   VERSION_1,
+			expectedEventId:   "15345",
+			expectedDelimiter: "\t",
+			expectedAttributes: map[string]string{
+				"src": "192.0.2.0",
+				"dst": "172.50.123.1",
+				"sev": "5",
+				"cat": "anomaly",
+				"msg": "there are spaces in this message",
+			},
+		},
+		{
+			description:       "leef 2.0, character delimiter",
+			input:             "LEEF:2.0|Lancope|StealthWatch|1.0|41|^|src=10.0.1.8^dst=10.0.0.5^sev=5^srcPort=81^dstPort=21",
+			expectedVersion:   VERSION_2,
+			expectedEventId:   "41",
+			expectedDelimiter: "^",
+			expectedAttributes: map[string]string{
+				"src":     "10.0.1.8",
+				"dst":     "10.0.0.5",
+				"sev":     "5",
+				"srcPort": "81",
+				"dstPort": "21",
+			},
+		},
+		{
+			description:       "leef 2.0, hex delimiter",
+			input:             "LEEF:2.0|Lancope|StealthWatch|1.0|41|0x7C|src=10.0.1.8|dst=10.0.0.5",
+			expectedVersion:   VERSION_2,
+			expectedEventId:   "41",
+			expectedDelimiter: "|",
+			expectedAttributes: map[string]string{
+				"src": "10.0.1.8",
+				"dst": "10.0.0.5",
+			},
+		},
+		{
+			description:       "leef 2.0, short hex delimiter",
+			input:             "LEEF:2.0|Lancope|StealthWatch|1.0|41|x5E|src=10.0.1.8^dst=10.0.0.5",
+			expectedVersion:   VERSION_2,
+			expectedEventId:   "41",
+			expectedDelimiter: "^",
+			expectedAttributes: map[string]string{
+				"src": "10.0.1.8",
+				"dst": "10.0.0.5",
+			},
+		},
+		{
+			description:       "leef 2.0, no delimiter",
+			input:             "LEEF:2.0|Lancope|StealthWatch|1.0|41|src=10.0.1.8\tdst=10.0.0.5",
+			expectedVersion:   VERSION_2,
+			expectedEventId:   "41",
+			expectedDelimiter: "\t",
+			expectedAttributes: map[string]string{
+				"src": "10.0.1.8",
+				"dst": "10.0.0.5",
+			},
+		},
+		{
+			description:        "no attributes, text before prefix",
+			input:              "host01 LEEF:1.0|Vendor|Product|1.0|Login|",
+			expectedVersion:    VERSION_1,
+			expectedEventId:    "Login",
+			expectedDelimiter:  "\t",
+			expectedAttributes: map[string]string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		obtained, err := ParsePayload(tc.input)
+		require.Nil(t, err, tc.description)
+
+		event := obtained[LEEF_KEY].(map[string]interface{})
+		require.Equal(t, tc.expectedVersion, event["version"], tc.description)
+		require.Equal(t, tc.expectedEventId, event["event_id"], tc.description)
+		require.Equal(t, tc.expectedDelimiter, event["delimiter"], tc.description)
+		require.Equal(t, tc.expectedAttributes, event["attributes"], tc.description)
+	}
+}
+
+func TestParsePayloadHeader(t *testing.T) {
+	obtained, err := ParsePayload("LEEF:1.0|Microsoft|MSExchange|4.0 SP1|15345|src=192.0.2.0")
+	require.Nil(t, err)
+
+	event := obtained[LEEF_KEY].(map[string]interface{})
+	require.Equal(t, "Microsoft", event["vendor"])
+	require.Equal(t, "MSExchange", event["product"])
+	require.Equal(t, "4.0 SP1", event["product_version"])
+}
+
+func TestParsePayloadErrors(t *testing.T) {
+	testCases := []struct {
+		description string
+		input       string
+		expectedErr error
+	}{
+		{
+			description: "no prefix",
+			input:       "'su root' failed for lonvick on /dev/pts/8",
+			expectedErr: ErrNoLEEF,
+		},
+		{
+			description: "truncated header",
+			input:       "LEEF:1.0|Vendor|Product|1.0|Login",
+			expectedErr: ErrInvalidHeader,
+		},
+		{
+			description: "invalid version",
+			input:       "LEEF:3.0|Vendor|Product|1.0|Login|",
+			expectedErr: ErrInvalidVersion,
+		},
+		{
+			description: "invalid delimiter",
+			input:       "LEEF:2.0|Vendor|Product|1.0|Login|^^|src=10.0.0.1",
+			expectedErr: ErrInvalidDelimiter,
+		},
+		{
+			description: "invalid hex delimiter",
+			input:       "LEEF:2.0|Vendor|Product|1.0|Login|0xZZ|src=10.0.0.1",
+			expectedErr: ErrInvalidDelimiter,
+		},
+	}
+
+	for _, tc := range testCases {
+		_, err := ParsePayload(tc.input)
+		require.ErrorIs(t, err, tc.expectedErr, tc.description)
+	}
+}
+
+func TestIsLEEF(t *testing.T) {
+	require.True(t, IsLEEF("LEEF:1.0|Vendor|Product|1.0|Login|"))
+	require.False(t, IsLEEF("'su root' failed for lonvick on /dev/pts/8"))
+}
+
+func TestPayloadParser(t *testing.T) {
+	p := rfc3164.NewParser(
+		[]byte("<134>Oct 11 22:14:15 fw01 LEEF:2.0|Vendor|Product|1.0|Login|^|usrName=bob^src=10.0.0.1"),
+	)
+	p.WithTag("leef")
+	p.WithPayloadParser(Payload, parsercommon.HEADER_WINS)
+
+	err := p.Parse()
+	require.Nil(t, err)
+
+	event := p.Dump()[LEEF_KEY].(map[string]interface{})
+	require.Equal(t, "Login", event["event_id"])
+	require.Equal(t, map[string]string{"usrName": "bob", "src": "10.0.0.1"}, event["attributes"])
+}
@@ -0,0 +1,91 @@
+package syslogparser
+
+import (
+	"context"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+)
+
+// Representation of the messages returned by ParseBatch()
+type Output uint8
+
+const (
+	// one LogParts map per message, as returned by Dump()
+	OUTPUT_MAP Output = iota
+
+	// one Message struct per message
+	OUTPUT_STRUCT
+
+	// one slice per field, see Columns
+	OUTPUT_COLUMNAR
+)
+
+type Message = parsercommon.Message
+
+type Columns = parsercommon.Columns
+
+// Messages parsed by ParseBatch(). Only the field matching Output is set,
+// holding one entry per parsed buffer. Entries of messages which could not be
+// parsed are left empty and the error is set in Errs.
+type Batch struct {
+	Output   Output
+	Parts    []LogParts
+	Messages []Message
+	Columns  *Columns
+	Errs     []error
+}
+
+// Same as ParseMany() with messages returned in the given output. Unknown
+// outputs are considered as OUTPUT_MAP.
+func ParseBatch(ctx context.Context, buffs [][]byte, concurrency int, output Output) *Batch {
+	b := &Batch{
+		Output: output,
+		Errs:   make([]error, len(buffs)),
+	}
+
+	var fn func(w *batchWorker, i int)
+
+	switch output {
+	case OUTPUT_STRUCT:
+		b.Messages = make([]Message, len(buffs))
+
+		fn = func(w *batchWorker, i int) {
+			p, err := w.parser(buffs[i])
+			if err != nil {
+				b.Errs[i] = err
+				return
+			}
+
+			p.DumpMessage(&b.Messages[i])
+		}
+	case OUTPUT_COLUMNAR:
+		b.Columns = parsercommon.NewColumns(len(buffs))
+
+		fn = func(w *batchWorker, i int) {
+			p, err := w.parser(buffs[i])
+			if err != nil {
+				b.Errs[i] = err
+				return
+			}
+
+			var m Message
+			p.DumpMessage(&m)
+			b.Columns.Set(i, &m)
+		}
+	default:
+		b.Output = OUTPUT_MAP
+		b.Parts = make([]LogParts, len(buffs))
+
+		fn = func(w *batchWorker, i int) {
+			b.Parts[i], b.Errs[i] = w.parse(buffs[i])
+		}
+	}
+
+	i := runBatch(ctx, len(buffs), concurrency, fn)
+
+	for ; i < len(buffs); i++ {
+		b.Errs[i] = ctx.Err()
+	}
+
+	return b
+}
@@ -0,0 +1,118 @@
+package syslogparser
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBatch(t *testing.T) {
+	buffs := [][]byte{
+		[]byte("<34>Oct 11 22:14:15 mymachine su: 'su root' failed for lonvick on /dev/pts/8"),
+		[]byte("<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 - An application event log entry..."),
+		[]byte("<34>1 2003-13-11T22:14:15.003Z mymachine su - ID47 - message"),
+	}
+
+	expected, expectedErrs := ParseMany(context.Background(), buffs, 1)
+
+	b := ParseBatch(context.Background(), buffs, 2, OUTPUT_MAP)
+	require.Equal(t, OUTPUT_MAP, b.Output)
+	require.Equal(t, expectedErrs, b.Errs)
+	require.Nil(t, b.Messages)
+	require.Nil(t, b.Columns)
+	require.Equal(t, expected, b.Parts)
+
+	b = ParseBatch(context.Background(), buffs, 2, OUTPUT_STRUCT)
+	require.Equal(t, OUTPUT_STRUCT, b.Output)
+	require.Equal(t, expectedErrs, b.Errs)
+	require.Nil(t, b.Parts)
+	require.Len(t, b.Messages, len(buffs))
+	require.Equal(t, expected[0], b.Messages[0].Parts())
+	require.Equal(t, expected[1], b.Messages[1].Parts())
+	require.Equal(t, Message{}, b.Messages[2])
+
+	b = ParseBatch(context.Background(), buffs, 2, OUTPUT_COLUMNAR)
+	require.Equal(t, OUTPUT_COLUMNAR, b.Output)
+	require.Equal(t, expectedErrs, b.Errs)
+	require.Equal(t, 3, b.Columns.Len())
+	require.Equal(t, []string{"mymachine", "mymachine.example.com", ""}, b.Columns.Hostname)
+	require.Equal(t, []int{34, 165, 0}, b.Columns.Priority)
+
+	b = ParseBatch(context.Background(), buffs, 2, Output(42))
+	require.Equal(t, OUTPUT_MAP, b.Output)
+	require.Len(t, b.Parts, len(buffs))
+}
+
+func TestParseBatchCanceled(t *testing.T) {
+	buffs := [][]byte{
+		[]byte("<34>Oct 11 22:14:15 mymachine su: 'su root' failed for lonvick on /dev/pts/8"),
+		[]byte("<34>Oct 11 22:14:15 mymachine su: 'su root' failed for lonvick on /dev/pts/8"),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	b := ParseBatch(ctx, buffs, 1, OUTPUT_COLUMNAR)
+
+	for i := range buffs {
+		if b.Errs[i] == nil {
+			require.Equal(t, "su", b.Columns.Tag[i])
+			continue
+		}
+
+		require.Equal(t, context.Canceled, b.Errs[i])
+	}
+}
+
+// Set SYSLOGPARSER_CORPUS to a file holding one message per line to
+// benchmark outputs against your own messages:
+// SYSLOGPARSER_CORPUS=/var/log/messages go test -run XXX -bench Output
+func BenchmarkOutput(b *testing.B) {
+	buffs := benchmarkCorpus(b)
+
+	outputs := []struct {
+		name   string
+		output Output
+	}{
+		{"map", OUTPUT_MAP},
+		{"struct", OUTPUT_STRUCT},
+		{"columnar", OUTPUT_COLUMNAR},
+	}
+
+	for _, o := range outputs {
+		b.Run(o.name, func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				ParseBatch(context.Background(), buffs, 1, o.output)
+			}
+		})
+	}
+}
+
+func benchmarkCorpus(b *testing.B) [][]byte {
+	name := os.Getenv("SYSLOGPARSER_CORPUS")
+	if name == "" {
+		buffs := make([][]byte, 0, 1000)
+		for i := 0; i < cap(buffs)/2; i++ {
+			buffs = append(
+				buffs,
+				[]byte("<34>Oct 11 22:14:15 mymachine su: 'su root' failed for lonvick on /dev/pts/8"),
+				[]byte(`<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 [exampleSDID@32473 iut="3"] An application event log entry...`),
+			)
+		}
+
+		return buffs
+	}
+
+	data, err := ioutil.ReadFile(name)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	return bytes.Split(bytes.TrimRight(data, "\n"), []byte("\n"))
+}
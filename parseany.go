@@ -0,0 +1,93 @@
+package syslogparser
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+	"github.com/jeromer/syslogparser/rfc3164"
+	"github.com/jeromer/syslogparser/rfc5424"
+)
+
+// Format of the message returned by ParseAny()
+type Format uint8
+
+const (
+	// not parsed, see ParseAny()
+	FORMAT_RAW Format = iota
+	FORMAT_RFC3164
+	FORMAT_RFC5424
+)
+
+func (f Format) String() string {
+	switch f {
+	case FORMAT_RFC3164:
+		return "rfc3164"
+	case FORMAT_RFC5424:
+		return "rfc5424"
+	}
+
+	return "raw"
+}
+
+// Parses any message, framed or not, and always returns usable parts.
+// The detected RFC is tried first, then the other one, both parsers being
+// as lenient as possible. When both fail the message is returned as is with
+// FORMAT_RAW: "message" holds it, "received_at" the time ParseAny() was
+// called and the priority is parsercommon.DEFAULT_PRIORITY. The returned
+// error then tells why detection or parsing failed.
+func ParseAny(buff []byte) (LogParts, Format, error) {
+	msg := buff[Unframe(buff):]
+
+	rfc, err := DetectRFC(msg)
+	if err != nil {
+		return rawParts(msg), FORMAT_RAW, err
+	}
+
+	formats := []Format{FORMAT_RFC3164, FORMAT_RFC5424}
+	if rfc == RFC_5424 {
+		formats = []Format{FORMAT_RFC5424, FORMAT_RFC3164}
+	}
+
+	for i, f := range formats {
+		p := newLenientParser(f, msg)
+
+		perr := p.Parse()
+		if perr == nil {
+			return p.Dump(), f, nil
+		}
+
+		if i == 0 {
+			err = perr
+		}
+	}
+
+	return rawParts(msg), FORMAT_RAW, err
+}
+
+func newLenientParser(f Format, buff []byte) LogParser {
+	if f == FORMAT_RFC5424 {
+		p := rfc5424.NewParser(buff)
+		p.WithLenient()
+
+		return p
+	}
+
+	p := rfc3164.NewParser(buff)
+	p.WithLenient()
+
+	return p
+}
+
+func rawParts(buff []byte) LogParts {
+	pri := parsercommon.NewPriority(parsercommon.DEFAULT_PRIORITY)
+
+	return LogParts{
+		"priority":    pri.P,
+		"facility":    pri.F.Value,
+		"severity":    pri.S.Value,
+		"version":     parsercommon.NO_VERSION,
+		"message":     string(bytes.TrimRight(buff, " \r\n")),
+		"received_at": time.Now(),
+	}
+}
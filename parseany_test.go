@@ -0,0 +1,101 @@
+package syslogparser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAny(t *testing.T) {
+	testCases := []struct {
+		description    string
+		input          string
+		expectedFormat Format
+		expectedKey    string
+		expectedValue  interface{}
+		expectedErr    error
+	}{
+		{
+			description:    "rfc3164",
+			input:          "<34>Oct 11 22:14:15 mymachine su: 'su root' failed",
+			expectedFormat: FORMAT_RFC3164,
+			expectedKey:    "hostname",
+			expectedValue:  "mymachine",
+		},
+		{
+			description:    "rfc5424",
+			input:          "<165>1 2003-10-11T22:14:15.003Z mymachine su - ID47 - msg",
+			expectedFormat: FORMAT_RFC5424,
+			expectedKey:    "msg_id",
+			expectedValue:  "ID47",
+		},
+		{
+			description:    "framed rfc3164 without priority",
+			input:          "46 Oct 11 22:14:15 mymachine su: 'su root' failed",
+			expectedFormat: FORMAT_RFC3164,
+			expectedKey:    "priority",
+			expectedValue:  parsercommon.DEFAULT_PRIORITY,
+		},
+		{
+			description:    "lenient priority",
+			input:          "<999>Oct 11 22:14:15 mymachine su: 'su root' failed",
+			expectedFormat: FORMAT_RFC3164,
+			expectedKey:    "priority",
+			expectedValue:  999,
+		},
+		{
+			description:    "swapped header",
+			input:          "<34>mymachine Oct 11 22:14:15 su: 'su root' failed",
+			expectedFormat: FORMAT_RFC3164,
+			expectedKey:    "hostname",
+			expectedValue:  "mymachine",
+		},
+		{
+			description:    "invalid timestamp, recovered as rfc3164 without timestamp",
+			input:          "<165>1 2003-13-11T22:14:15.003Z mymachine su - ID47 - msg\n",
+			expectedFormat: FORMAT_RFC3164,
+			expectedKey:    "content",
+			expectedValue:  "mymachine su - ID47 - msg",
+		},
+		{
+			description:    "garbage",
+			input:          "hello world",
+			expectedFormat: FORMAT_RAW,
+			expectedKey:    "message",
+			expectedValue:  "hello world",
+			expectedErr:    ErrUnknownRFC,
+		},
+		{
+			description:    "empty",
+			input:          "",
+			expectedFormat: FORMAT_RAW,
+			expectedKey:    "message",
+			expectedValue:  "",
+			expectedErr:    ErrBufferTooShort,
+		},
+	}
+
+	for _, tc := range testCases {
+		parts, format, err := ParseAny([]byte(tc.input))
+
+		require.Equal(t, tc.expectedFormat, format, tc.description)
+		require.Equal(t, tc.expectedValue, parts[tc.expectedKey], tc.description)
+
+		if tc.expectedErr == nil {
+			require.Nil(t, err, tc.description)
+			continue
+		}
+
+		require.ErrorIs(t, err, tc.expectedErr, tc.description)
+		require.Equal(t, parsercommon.DEFAULT_PRIORITY, parts["priority"], tc.description)
+		require.IsType(t, time.Time{}, parts["received_at"], tc.description)
+	}
+}
+
+func TestFormatString(t *testing.T) {
+	require.Equal(t, "raw", FORMAT_RAW.String())
+	require.Equal(t, "rfc3164", FORMAT_RFC3164.String())
+	require.Equal(t, "rfc5424", FORMAT_RFC5424.String())
+}
@@ -0,0 +1,45 @@
+package parsercommon
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+const (
+	// Lumberjack / CEE cookie preceding the JSON of structured messages
+	CEE_COOKIE = "@cee:"
+
+	// key of the parsed JSON in Dump()
+	CEE_KEY = "cee"
+)
+
+var (
+	ErrInvalidCEE = &ParserError{ErrorString: "Invalid CEE JSON"}
+)
+
+// Returns true when msg starts with CEE_COOKIE, leading spaces ignored
+func HasCEECookie(msg string) bool {
+	return strings.HasPrefix(strings.TrimLeft(msg, " "), CEE_COOKIE)
+}
+
+// Parses the JSON object following CEE_COOKIE in msg, as in
+// `@cee: {"event":"login","user":"bob"}`, the way rsyslog's mmjsonparse
+// does. ok is false when msg has no cookie.
+func ParseCEE(msg string) (fields map[string]interface{}, ok bool, err error) {
+	if !HasCEECookie(msg) {
+		return nil, false, nil
+	}
+
+	s := strings.TrimLeft(msg, " ")[len(CEE_COOKIE):]
+
+	err = json.Unmarshal([]byte(s), &fields)
+	if err != nil {
+		return nil, true, Wrap(ErrInvalidCEE, err)
+	}
+
+	if fields == nil {
+		return nil, true, ErrInvalidCEE
+	}
+
+	return fields, true, nil
+}
@@ -0,0 +1,77 @@
+package parsercommon
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCEE(t *testing.T) {
+	testCases := []struct {
+		description    string
+		input          string
+		expectedFields map[string]interface{}
+		expectedOk     bool
+	}{
+		{
+			description: "cookie",
+			input:       `@cee:{"event":"login","user":"bob","attempts":3}`,
+			expectedFields: map[string]interface{}{
+				"event":    "login",
+				"user":     "bob",
+				"attempts": float64(3),
+			},
+			expectedOk: true,
+		},
+		{
+			description: "spaces and nested object",
+			input:       ` @cee: {"src":{"ip":"10.0.0.1","port":22}} `,
+			expectedFields: map[string]interface{}{
+				"src": map[string]interface{}{
+					"ip":   "10.0.0.1",
+					"port": float64(22),
+				},
+			},
+			expectedOk: true,
+		},
+		{
+			description:    "no cookie",
+			input:          `{"event":"login"}`,
+			expectedFields: nil,
+			expectedOk:     false,
+		},
+		{
+			description:    "cookie not at start",
+			input:          `user bob @cee:{"event":"login"}`,
+			expectedFields: nil,
+			expectedOk:     false,
+		},
+	}
+
+	for _, tc := range testCases {
+		fields, ok, err := ParseCEE(tc.input)
+		require.Nil(t, err, tc.description)
+		require.Equal(t, tc.expectedOk, ok, tc.description)
+		require.Equal(t, tc.expectedFields, fields, tc.description)
+	}
+}
+
+func TestParseCEEErrors(t *testing.T) {
+	for _, input := range []string{
+		`@cee:`,
+		`@cee:{"event":`,
+		`@cee:["login"]`,
+		`@cee:null`,
+	} {
+		_, ok, err := ParseCEE(input)
+		require.True(t, ok, input)
+		require.ErrorIs(t, err, ErrInvalidCEE, input)
+	}
+}
+
+func TestHasCEECookie(t *testing.T) {
+	require.True(t, HasCEECookie(`@cee:{}`))
+	require.True(t, HasCEECookie(`  @cee: {}`))
+	require.False(t, HasCEECookie(`cee:{}`))
+	require.False(t, HasCEECookie(""))
+}
@@ -0,0 +1,147 @@
+package parsercommon
+
+// Cursor scans the first l bytes of a buffer. Its methods never read nor
+// move past the limit so parsers built on it can not index out of range,
+// even on truncated messages.
+type Cursor struct {
+	buff []byte
+	pos  int
+	l    int
+}
+
+// Creates a cursor over the first l bytes of buff, l is capped to len(buff)
+func NewCursor(buff []byte, l int) Cursor {
+	if l > len(buff) {
+		l = len(buff)
+	}
+
+	if l < 0 {
+		l = 0
+	}
+
+	return Cursor{
+		buff: buff,
+		l:    l,
+	}
+}
+
+// Position of the next byte to be scanned
+func (c *Cursor) Pos() int {
+	return c.pos
+}
+
+// Moves to pos, capped to [0, Len()]
+func (c *Cursor) SetPos(pos int) {
+	switch {
+	case pos < 0:
+		c.pos = 0
+	case pos > c.l:
+		c.pos = c.l
+	default:
+		c.pos = pos
+	}
+}
+
+// Returns the whole buffer given to NewCursor(), bytes past Len() included
+func (c *Cursor) Buffer() []byte {
+	return c.buff
+}
+
+// Number of bytes which can be scanned
+func (c *Cursor) Len() int {
+	return c.l
+}
+
+// Returns true once every byte has been scanned
+func (c *Cursor) EOF() bool {
+	return c.pos >= c.l
+}
+
+// Returns the byte at the current position without moving
+func (c *Cursor) Peek() (byte, bool) {
+	return c.At(c.pos)
+}
+
+// Returns the byte at position i without moving
+func (c *Cursor) At(i int) (byte, bool) {
+	if i < 0 || i >= c.l {
+		return 0, false
+	}
+
+	return c.buff[i], true
+}
+
+// Moves n bytes forward, or to Len() if fewer bytes are left in which
+// case false is returned
+func (c *Cursor) Advance(n int) bool {
+	if c.pos+n > c.l {
+		c.pos = c.l
+		return false
+	}
+
+	c.pos += n
+
+	return true
+}
+
+// Moves past the current byte if it is b
+func (c *Cursor) Expect(b byte) bool {
+	if c.pos >= c.l || c.buff[c.pos] != b {
+		return false
+	}
+
+	c.pos++
+
+	return true
+}
+
+// Returns bytes [from, to), both capped to [0, Len()].
+// The returned slice shares the memory of the buffer.
+func (c *Cursor) Slice(from int, to int) []byte {
+	if from < 0 {
+		from = 0
+	}
+
+	if to > c.l {
+		to = c.l
+	}
+
+	if from > to {
+		from = to
+	}
+
+	return c.buff[from:to]
+}
+
+// Returns the bytes left to scan, without moving
+func (c *Cursor) Rest() []byte {
+	return c.Slice(c.pos, c.l)
+}
+
+// Excludes the trailing LF, CR and NUL bytes messages received over TCP often
+// end with from the bytes which can be scanned
+func (c *Cursor) TrimTrailer() {
+	for c.l > 0 && isTrailer(c.buff[c.l-1]) {
+		c.l--
+	}
+
+	if c.pos > c.l {
+		c.pos = c.l
+	}
+}
+
+// Returns true when the buffer holds more than Len() bytes, trailing LF, CR
+// and NUL bytes excepted, ie. when a message was cut at the length limit
+func (c *Cursor) Truncated() bool {
+	for i := c.l; i < len(c.buff); i++ {
+		if !isTrailer(c.buff[i]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isTrailer(b byte) bool {
+	return b == '\n' || b == '\r' || b == 0
+}
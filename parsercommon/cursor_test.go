@@ -0,0 +1,199 @@
+package parsercommon
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCursor(t *testing.T) {
+	buff := []byte("abc")
+
+	c := NewCursor(buff, 10)
+	require.Equal(t, 3, c.Len())
+	require.Equal(t, 0, c.Pos())
+
+	c = NewCursor(buff, 2)
+	require.Equal(t, 2, c.Len())
+	require.Equal(t, []byte("ab"), c.Rest())
+
+	c = NewCursor(buff, -1)
+	require.Equal(t, 0, c.Len())
+	require.True(t, c.EOF())
+}
+
+func TestCursorPeekAt(t *testing.T) {
+	c := NewCursor([]byte("abc"), 2)
+
+	b, ok := c.Peek()
+	require.True(t, ok)
+	require.Equal(t, byte('a'), b)
+
+	b, ok = c.At(1)
+	require.True(t, ok)
+	require.Equal(t, byte('b'), b)
+
+	// beyond the limit
+	_, ok = c.At(2)
+	require.False(t, ok)
+
+	_, ok = c.At(-1)
+	require.False(t, ok)
+
+	c.SetPos(2)
+	_, ok = c.Peek()
+	require.False(t, ok)
+}
+
+func TestCursorAdvance(t *testing.T) {
+	c := NewCursor([]byte("abcd"), 4)
+
+	require.True(t, c.Advance(3))
+	require.Equal(t, 3, c.Pos())
+	require.False(t, c.EOF())
+
+	require.False(t, c.Advance(2))
+	require.Equal(t, 4, c.Pos())
+	require.True(t, c.EOF())
+
+	require.False(t, c.Advance(1))
+	require.Equal(t, 4, c.Pos())
+}
+
+func TestCursorExpect(t *testing.T) {
+	c := NewCursor([]byte("a "), 2)
+
+	require.False(t, c.Expect(' '))
+	require.Equal(t, 0, c.Pos())
+
+	require.True(t, c.Expect('a'))
+	require.True(t, c.Expect(' '))
+	require.Equal(t, 2, c.Pos())
+
+	require.False(t, c.Expect(' '))
+	require.Equal(t, 2, c.Pos())
+}
+
+func TestCursorSetPos(t *testing.T) {
+	c := NewCursor([]byte("abc"), 3)
+
+	c.SetPos(2)
+	require.Equal(t, 2, c.Pos())
+
+	c.SetPos(10)
+	require.Equal(t, 3, c.Pos())
+
+	c.SetPos(-1)
+	require.Equal(t, 0, c.Pos())
+}
+
+func TestCursorSlice(t *testing.T) {
+	c := NewCursor([]byte("abcdef"), 4)
+
+	testCases := []struct {
+		description string
+		from        int
+		to          int
+		expected    string
+	}{
+		{"within limit", 1, 3, "bc"},
+		{"to beyond limit", 2, 6, "cd"},
+		{"from beyond limit", 5, 6, ""},
+		{"negative from", -1, 2, "ab"},
+		{"from after to", 3, 1, ""},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(
+			t, tc.expected, string(c.Slice(tc.from, tc.to)), tc.description,
+		)
+	}
+
+	c.SetPos(1)
+	require.Equal(t, []byte("bcd"), c.Rest())
+	require.Equal(t, []byte("abcdef"), c.Buffer())
+}
+
+func TestCursorTrimTrailer(t *testing.T) {
+	testCases := []struct {
+		description string
+		input       string
+		expected    string
+	}{
+		{"LF", "abc\n", "abc"},
+		{"CRLF", "abc\r\n", "abc"},
+		{"NUL", "abc\x00", "abc"},
+		{"mixed", "abc\r\n\x00\n", "abc"},
+		{"inner bytes kept", "a\nb\x00c", "a\nb\x00c"},
+		{"only trailer", "\r\n", ""},
+		{"none", "abc", "abc"},
+	}
+
+	for _, tc := range testCases {
+		c := NewCursor([]byte(tc.input), len(tc.input))
+		c.SetPos(len(tc.input))
+		c.TrimTrailer()
+
+		require.Equal(t, len(tc.expected), c.Len(), tc.description)
+		require.Equal(t, len(tc.expected), c.Pos(), tc.description)
+
+		c.SetPos(0)
+		require.Equal(t, tc.expected, string(c.Rest()), tc.description)
+	}
+}
+
+func TestCursorTruncated(t *testing.T) {
+	testCases := []struct {
+		description string
+		input       string
+		l           int
+		expected    bool
+	}{
+		{"within limit", "abc", 3, false},
+		{"cut", "abcdef", 3, true},
+		{"trailer beyond limit", "abc\r\n\x00", 3, false},
+		{"trailer then data", "abc\ndef", 3, true},
+	}
+
+	for _, tc := range testCases {
+		c := NewCursor([]byte(tc.input), tc.l)
+		require.Equal(t, tc.expected, c.Truncated(), tc.description)
+	}
+}
+
+func TestCursorParsePriorityNotAtStart(t *testing.T) {
+	c := NewCursor([]byte("xx<34>"), 6)
+	c.SetPos(2)
+
+	pri, err := c.ParsePriority()
+	require.Nil(t, err)
+	require.Equal(t, NewPriority(34), pri)
+	require.Equal(t, 6, c.Pos())
+}
+
+func TestCursorScanHostname(t *testing.T) {
+	c := NewCursor([]byte("host rest"), 9)
+
+	require.Equal(t, []byte("host"), c.ScanHostname())
+	require.Equal(t, 4, c.Pos())
+
+	c = NewCursor([]byte("host"), 4)
+
+	require.Equal(t, []byte("host"), c.ScanHostname())
+	require.True(t, c.EOF())
+}
+
+func TestCursorParsePriorityValue(t *testing.T) {
+	c := NewCursor([]byte("<34>Oct"), 7)
+
+	pri, err := c.ParsePriorityValue()
+	require.Nil(t, err)
+	require.Equal(t, *NewPriority(34), pri)
+	require.Equal(t, 4, c.Pos())
+
+	c = NewCursor([]byte("<999>Oct"), 8)
+
+	_, err = c.ParsePriorityValue()
+	require.Equal(t, ErrPriorityInvalid, err)
+	require.Equal(t, 0, c.Pos())
+}
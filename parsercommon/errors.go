@@ -0,0 +1,117 @@
+package parsercommon
+
+import (
+	"strconv"
+)
+
+const (
+	// bytes of the input reported by ParserError.Snippet()
+	SNIPPET_LEN = 16
+)
+
+// Returns an error reported as e by errors.Is() and whose Unwrap() returns
+// cause, ie. the strconv or time error which made parsing fail
+func Wrap(e *ParserError, cause error) error {
+	return &ParserError{
+		ErrorString: e.ErrorString,
+		err:         e,
+		cause:       cause,
+	}
+}
+
+// Returns a copy of err telling field starts at offset in buff, where
+// parsing failed. Errors which are not a *ParserError, or already located,
+// are returned as is.
+// errors.Is() reports the returned error as err.
+func Locate(err error, buff []byte, offset int, field string) error {
+	pe, ok := err.(*ParserError)
+	if !ok || pe.located {
+		return err
+	}
+
+	if offset < 0 {
+		offset = 0
+	}
+
+	if offset > len(buff) {
+		offset = len(buff)
+	}
+
+	end := offset + SNIPPET_LEN
+	if end > len(buff) {
+		end = len(buff)
+	}
+
+	return &ParserError{
+		ErrorString: pe.ErrorString,
+		err:         pe.sentinel(),
+		cause:       pe.cause,
+		located:     true,
+		offset:      offset,
+		field:       field,
+		snippet:     string(buff[offset:end]),
+	}
+}
+
+// Same as Locate() in the buffer of the cursor
+func (c *Cursor) Locate(err error, offset int, field string) error {
+	return Locate(err, c.buff[:c.l], offset, field)
+}
+
+// Offset in the parsed buffer of the field which could not be parsed or -1
+// when unknown
+func (err *ParserError) Offset() int {
+	if !err.located {
+		return -1
+	}
+
+	return err.offset
+}
+
+// Name of the field which could not be parsed (ie. "timestamp"), as returned
+// by Dump(), or an empty string when unknown
+func (err *ParserError) Field() string {
+	return err.field
+}
+
+// Up to SNIPPET_LEN bytes of the input starting at Offset()
+func (err *ParserError) Snippet() string {
+	return err.snippet
+}
+
+// Returns the error, offset, field, snippet and cause when known:
+// Invalid month in timestamp at offset 7 (timestamp): "2003-13-11T22:14:"
+func (err *ParserError) Detail() string {
+	s := err.ErrorString
+
+	if err.located {
+		s += " at offset " + strconv.Itoa(err.offset) +
+			" (" + err.field + "): " + strconv.Quote(err.snippet)
+	}
+
+	if err.cause != nil {
+		s += ": " + err.cause.Error()
+	}
+
+	return s
+}
+
+// Reports errors returned by Wrap() or Locate() as the exported error, ie.
+// ErrPriorityInvalid, they were built from
+func (err *ParserError) Is(target error) bool {
+	return err.err != nil && target == error(err.err)
+}
+
+// Returns the underlying error given to Wrap(), if any
+func (err *ParserError) Unwrap() error {
+	return err.cause
+}
+
+// Exported error err was built from, err itself for exported errors
+func (err *ParserError) sentinel() *ParserError {
+	if err.err != nil {
+		return err.err
+	}
+
+	return err
+}
@@ -0,0 +1,92 @@
+package parsercommon
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocate(t *testing.T) {
+	buff := []byte("<34>1 2003-13-11T22:14:15.003Z mymachine su - ID47 - message")
+
+	err := Locate(ErrPriorityInvalid, buff, 6, "timestamp")
+
+	pe, ok := err.(*ParserError)
+	require.True(t, ok)
+	require.Equal(t, ErrPriorityInvalid.Error(), pe.Error())
+	require.Equal(t, 6, pe.Offset())
+	require.Equal(t, "timestamp", pe.Field())
+	require.Equal(t, "2003-13-11T22:14", pe.Snippet())
+	require.Equal(
+		t,
+		`Priority out of range at offset 6 (timestamp): "2003-13-11T22:14"`,
+		pe.Detail(),
+	)
+
+	require.True(t, errors.Is(err, ErrPriorityInvalid))
+	require.False(t, errors.Is(err, ErrPriorityNoEnd))
+	require.Nil(t, errors.Unwrap(err))
+
+	// already located
+	require.True(t, err == Locate(err, buff, 0, "priority"))
+
+	// snippet is truncated at the end of the buffer
+	err = Locate(ErrEOL, buff, len(buff)-3, "message")
+	require.Equal(t, "age", err.(*ParserError).Snippet())
+
+	err = Locate(ErrEOL, buff, len(buff)+3, "message")
+	require.Equal(t, len(buff), err.(*ParserError).Offset())
+	require.Equal(t, "", err.(*ParserError).Snippet())
+
+	other := errors.New("Other")
+	require.Equal(t, other, Locate(other, buff, 0, "priority"))
+}
+
+func TestWrap(t *testing.T) {
+	_, cause := strconv.Atoi("20a3")
+
+	err := Wrap(ErrTimestampUnknownFormat, cause)
+
+	require.Equal(t, ErrTimestampUnknownFormat.Error(), err.Error())
+	require.True(t, errors.Is(err, ErrTimestampUnknownFormat))
+	require.True(t, errors.Is(err, strconv.ErrSyntax))
+	require.False(t, errors.Is(err, ErrEOL))
+
+	var numErr *strconv.NumError
+	require.True(t, errors.As(err, &numErr))
+	require.Equal(t, "20a3", numErr.Num)
+
+	var pe *ParserError
+	require.True(t, errors.As(err, &pe))
+	require.Equal(t, -1, pe.Offset())
+
+	located := Locate(err, []byte("<34>20a3"), 4, "timestamp")
+
+	require.True(t, errors.Is(located, ErrTimestampUnknownFormat))
+	require.True(t, errors.Is(located, strconv.ErrSyntax))
+	require.Equal(t, 4, located.(*ParserError).Offset())
+	require.Equal(
+		t,
+		`Timestamp format unknown at offset 4 (timestamp): "20a3": `+cause.Error(),
+		located.(*ParserError).Detail(),
+	)
+}
+
+func TestParserErrorNotLocated(t *testing.T) {
+	require.Equal(t, -1, ErrEOL.Offset())
+	require.Equal(t, "", ErrEOL.Field())
+	require.Equal(t, "", ErrEOL.Snippet())
+	require.Equal(t, ErrEOL.Error(), ErrEOL.Detail())
+	require.Nil(t, ErrEOL.Unwrap())
+	require.False(t, ErrEOL.Is(ErrEOL))
+	require.True(t, errors.Is(ErrEOL, ErrEOL))
+}
+
+func TestCursorLocate(t *testing.T) {
+	c := NewCursor([]byte("<34>Oct 11"), 7)
+
+	err := c.Locate(ErrTimestampUnknownFormat, 4, "timestamp")
+	require.Equal(t, "Oct", err.(*ParserError).Snippet())
+}
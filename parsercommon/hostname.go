@@ -0,0 +1,108 @@
+package parsercommon
+
+import (
+	"bytes"
+	"net"
+	"strings"
+)
+
+const (
+	// https://tools.ietf.org/html/rfc1123#section-2.1
+	MAX_LABEL_LEN    = 63
+	MAX_HOSTNAME_LEN = 255
+)
+
+// Kinds of hostnames returned by HostnameType()
+const (
+	HOSTNAME_TYPE_IP4  = "ip4"
+	HOSTNAME_TYPE_IP6  = "ip6"
+	HOSTNAME_TYPE_FQDN = "fqdn"
+	HOSTNAME_TYPE_NAME = "name"
+)
+
+// Returns true when h is an RFC 1123 host name, ie. "mymachine.example.com",
+// or an IPv4 or IPv6 address, bracketed or not and with a zone or not. Labels are made of letters,
+// digits and hyphens, neither start nor end with a hyphen and are at most
+// MAX_LABEL_LEN long. A trailing dot is accepted.
+func IsValidHostname(h []byte) bool {
+	if len(h) == 0 || len(h) > MAX_HOSTNAME_LEN {
+		return false
+	}
+
+	if net.ParseIP(string(ipLiteral(h))) != nil {
+		return true
+	}
+
+	if h[len(h)-1] == '.' {
+		h = h[:len(h)-1]
+	}
+
+	start := 0
+
+	for i := 0; i <= len(h); i++ {
+		if i < len(h) && h[i] != '.' {
+			if !isHostnameByte(h[i]) {
+				return false
+			}
+
+			continue
+		}
+
+		label := h[start:i]
+
+		if len(label) == 0 || len(label) > MAX_LABEL_LEN {
+			return false
+		}
+
+		if label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+
+		start = i + 1
+	}
+
+	return true
+}
+
+func isHostnameByte(c byte) bool {
+	return IsDigit(c) || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '-'
+}
+
+// Tells whether h is an IPv4 address, an IPv6 one (as in "[2001:db8::1]" or
+// "fe80::1%eth0"), a dotted name or a single label one. An empty string is
+// returned for empty hostnames and NILVALUE.
+func HostnameType(h string) string {
+	if h == "" || IsNilString(h) {
+		return ""
+	}
+
+	b := ipLiteral([]byte(h))
+
+	if ip := net.ParseIP(string(b)); ip != nil {
+		if ip.To4() != nil && !strings.Contains(h, ":") {
+			return HOSTNAME_TYPE_IP4
+		}
+
+		return HOSTNAME_TYPE_IP6
+	}
+
+	if strings.Contains(h, ".") {
+		return HOSTNAME_TYPE_FQDN
+	}
+
+	return HOSTNAME_TYPE_NAME
+}
+
+// "[2001:db8::1]" or "fe80::1%eth0" => "2001:db8::1" or "fe80::1", other
+// values are returned as is
+func ipLiteral(h []byte) []byte {
+	if len(h) > 2 && h[0] == '[' && h[len(h)-1] == ']' {
+		h = h[1 : len(h)-1]
+	}
+
+	if i := bytes.IndexByte(h, '%'); i > 0 && bytes.IndexByte(h, ':') >= 0 {
+		h = h[:i]
+	}
+
+	return h
+}
@@ -0,0 +1,68 @@
+package parsercommon
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsValidHostname(t *testing.T) {
+	testCases := []struct {
+		description string
+		input       string
+		expected    bool
+	}{
+		{"name", "mymachine", true},
+		{"FQDN", "mymachine.example.com", true},
+		{"trailing dot", "mymachine.example.com.", true},
+		{"digits and hyphens", "host-01", true},
+		{"label starting with a digit", "1host.example.com", true},
+		{"IPv4", "192.0.2.1", true},
+		{"IPv6", "2001:db8::1", true},
+		{"bracketed IPv6", "[2001:db8::1]", true},
+		{"IPv6 with zone", "fe80::1%eth0", true},
+		{"percent sign", "my%machine", false},
+		{"bracketed name", "[mymachine]", false},
+		{"longest label", strings.Repeat("a", MAX_LABEL_LEN), true},
+		{"empty", "", false},
+		{"colon", "su:", false},
+		{"bracket", "sshd[42]:", false},
+		{"control character", "my\x01machine", false},
+		{"UTF-8", "m\xC3\xA9chine", false},
+		{"underscore", "my_machine", false},
+		{"label starting with a hyphen", "-host", false},
+		{"label ending with a hyphen", "host-.example.com", false},
+		{"empty label", "mymachine..com", false},
+		{"leading dot", ".mymachine", false},
+		{"label too long", strings.Repeat("a", MAX_LABEL_LEN+1), false},
+		{"too long", strings.Repeat("a.", 128), false},
+		{"NILVALUE", "-", false},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.expected, IsValidHostname([]byte(tc.input)), tc.description)
+	}
+}
+
+func TestHostnameType(t *testing.T) {
+	testCases := []struct {
+		description string
+		input       string
+		expected    string
+	}{
+		{"empty", "", ""},
+		{"NILVALUE", "-", ""},
+		{"IPv4", "192.0.2.1", HOSTNAME_TYPE_IP4},
+		{"IPv6", "2001:db8::1", HOSTNAME_TYPE_IP6},
+		{"bracketed IPv6", "[2001:db8::1]", HOSTNAME_TYPE_IP6},
+		{"IPv6 with zone", "fe80::1%eth0", HOSTNAME_TYPE_IP6},
+		{"IPv4-mapped IPv6", "::ffff:192.0.2.1", HOSTNAME_TYPE_IP6},
+		{"FQDN", "mymachine.example.com", HOSTNAME_TYPE_FQDN},
+		{"name", "mymachine", HOSTNAME_TYPE_NAME},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.expected, HostnameType(tc.input), tc.description)
+	}
+}
@@ -0,0 +1,132 @@
+package parsercommon
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// Keys of the JSON schema, in the order they are encoded. Keys which are
+// not part of the schema are encoded afterwards, sorted.
+//
+//	priority, facility, severity, version   numbers
+//	facility_name, severity_name            strings, when enabled
+//	timestamp                               RFC3339 string, null when unknown
+//	hostname, app_name, proc_id, msg_id     strings
+//	tag, pid, content                       strings (RFC 3164)
+//	structured_data, message                strings (RFC 5424)
+var SCHEMA_KEYS = []string{
+	"priority",
+	"facility",
+	"severity",
+	"facility_name",
+	"severity_name",
+	"version",
+	"timestamp",
+	"hostname",
+	"app_name",
+	"proc_id",
+	"msg_id",
+	"tag",
+	"pid",
+	"content",
+	"structured_data",
+	"message",
+}
+
+var schemaKeys = func() map[string]bool {
+	m := make(map[string]bool, len(SCHEMA_KEYS))
+	for _, k := range SCHEMA_KEYS {
+		m[k] = true
+	}
+
+	return m
+}()
+
+// Returns the keys of parts, keys listed in SCHEMA_KEYS first, in that
+// order, followed by the other keys sorted
+func (lp LogParts) Keys() []string {
+	keys := make([]string, 0, len(lp))
+
+	for _, k := range SCHEMA_KEYS {
+		if _, ok := lp[k]; ok {
+			keys = append(keys, k)
+		}
+	}
+
+	n := len(keys)
+	for k := range lp {
+		if !schemaKeys[k] {
+			keys = append(keys, k)
+		}
+	}
+
+	sort.Strings(keys[n:])
+
+	return keys
+}
+
+// Encodes parts following SCHEMA_KEYS so the output does not depend on
+// the parser nor on the map iteration order
+func (lp LogParts) MarshalJSON() ([]byte, error) {
+	if lp == nil {
+		return []byte("null"), nil
+	}
+
+	keys := lp.Keys()
+
+	var buff bytes.Buffer
+
+	buff.WriteByte('{')
+
+	for i, k := range keys {
+		if i > 0 {
+			buff.WriteByte(',')
+		}
+
+		err := writeJSON(&buff, k)
+		if err != nil {
+			return nil, err
+		}
+
+		buff.WriteByte(':')
+
+		err = writeJSON(&buff, jsonValue(lp[k]))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	buff.WriteByte('}')
+
+	return buff.Bytes(), nil
+}
+
+func jsonValue(v interface{}) interface{} {
+	t, ok := v.(time.Time)
+	if !ok {
+		return v
+	}
+
+	if t.IsZero() {
+		return nil
+	}
+
+	return t.Format(time.RFC3339Nano)
+}
+
+func writeJSON(buff *bytes.Buffer, v interface{}) error {
+	enc := json.NewEncoder(buff)
+	enc.SetEscapeHTML(false)
+
+	err := enc.Encode(v)
+	if err != nil {
+		return err
+	}
+
+	// Encode terminates every value with a LF
+	buff.Truncate(buff.Len() - 1)
+
+	return nil
+}
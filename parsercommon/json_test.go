@@ -0,0 +1,113 @@
+package parsercommon
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogPartsMarshalJSON(t *testing.T) {
+	testCases := []struct {
+		description string
+		input       LogParts
+		expected    string
+	}{
+		{
+			description: "nil",
+			input:       nil,
+			expected:    `null`,
+		},
+		{
+			description: "empty",
+			input:       LogParts{},
+			expected:    `{}`,
+		},
+		{
+			description: "schema order",
+			input: LogParts{
+				"tag":       "sshd",
+				"content":   "<b>&",
+				"hostname":  "mymachine",
+				"severity":  2,
+				"facility":  4,
+				"priority":  34,
+				"version":   NO_VERSION,
+				"timestamp": time.Date(2003, time.October, 11, 22, 14, 15, 3000000, time.FixedZone("", -7*3600)),
+			},
+			expected: `{"priority":34,"facility":4,"severity":2,"version":-1,` +
+				`"timestamp":"2003-10-11T22:14:15.003-07:00","hostname":"mymachine",` +
+				`"tag":"sshd","content":"<b>&"}`,
+		},
+		{
+			description: "extra keys sorted after schema keys",
+			input: LogParts{
+				"zzz":      1,
+				"aaa":      []string{"a"},
+				"hostname": "mymachine",
+			},
+			expected: `{"hostname":"mymachine","aaa":["a"],"zzz":1}`,
+		},
+		{
+			description: "zero timestamp",
+			input: LogParts{
+				"timestamp": time.Time{},
+			},
+			expected: `{"timestamp":null}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		obtained, err := tc.input.MarshalJSON()
+
+		require.Nil(t, err, tc.description)
+		require.Equal(t, tc.expected, string(obtained), tc.description)
+		require.True(t, json.Valid(obtained), tc.description)
+	}
+}
+
+func TestLogPartsMarshalJSONStable(t *testing.T) {
+	parts := LogParts{}
+	for _, k := range []string{"a", "b", "c", "d", "e", "f", "hostname", "message"} {
+		parts[k] = k
+	}
+
+	expected, err := json.Marshal(parts)
+	require.Nil(t, err)
+
+	for i := 0; i < 100; i++ {
+		obtained, err := json.Marshal(parts)
+
+		require.Nil(t, err)
+		require.Equal(t, expected, obtained)
+	}
+}
+
+func TestLogPartsMarshalJSONError(t *testing.T) {
+	_, err := LogParts{"foo": make(chan int)}.MarshalJSON()
+	require.NotNil(t, err)
+}
+
+func BenchmarkLogPartsMarshalJSON(b *testing.B) {
+	parts := LogParts{
+		"priority":        165,
+		"facility":        20,
+		"severity":        5,
+		"version":         1,
+		"timestamp":       time.Date(2003, time.October, 11, 22, 14, 15, 3000000, time.UTC),
+		"hostname":        "mymachine.example.com",
+		"app_name":        "evntslog",
+		"proc_id":         "-",
+		"msg_id":          "ID47",
+		"structured_data": `[exampleSDID@32473 iut="3" eventSource="Application" eventID="1011"]`,
+		"message":         "An application event log entry...",
+	}
+
+	for i := 0; i < b.N; i++ {
+		_, err := parts.MarshalJSON()
+		if err != nil {
+			panic(err)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package parsercommon
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+const (
+	// key of the parsed JSON body in Dump()
+	JSON_KEY = "json"
+
+	// limits used by WithJSON() when given 0
+	JSON_MAX_DEPTH = 32
+	JSON_MAX_SIZE  = 64 * 1024
+)
+
+var (
+	ErrJSONTooDeep  = &ParserError{ErrorString: "JSON body nested too deeply"}
+	ErrJSONTooLarge = &ParserError{ErrorString: "JSON body too large"}
+)
+
+// Unmarshals msg when it is a JSON object, as logged by containers, leading
+// spaces ignored. ok is false when msg is not valid JSON. Bodies of more than
+// maxSize bytes or nesting objects and arrays deeper than maxDepth are
+// rejected with ErrJSONTooLarge or ErrJSONTooDeep, 0 meaning JSON_MAX_SIZE
+// and JSON_MAX_DEPTH.
+func ParseJSONBody(msg string, maxDepth int, maxSize int) (fields map[string]interface{}, ok bool, err error) {
+	s := strings.TrimLeft(msg, " ")
+	if !strings.HasPrefix(s, "{") {
+		return nil, false, nil
+	}
+
+	if maxDepth <= 0 {
+		maxDepth = JSON_MAX_DEPTH
+	}
+
+	if maxSize <= 0 {
+		maxSize = JSON_MAX_SIZE
+	}
+
+	if len(s) > maxSize {
+		return nil, true, ErrJSONTooLarge
+	}
+
+	if !json.Valid([]byte(s)) {
+		return nil, false, nil
+	}
+
+	if jsonDepth(s) > maxDepth {
+		return nil, true, ErrJSONTooDeep
+	}
+
+	err = json.Unmarshal([]byte(s), &fields)
+	if err != nil {
+		return nil, false, nil
+	}
+
+	return fields, true, nil
+}
+
+// Deepest nesting of objects and arrays in the valid JSON s
+func jsonDepth(s string) int {
+	depth, max := 0, 0
+	inString := false
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		if inString {
+			switch c {
+			case '\\':
+				i++
+			case '"':
+				inString = false
+			}
+
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			depth++
+			if depth > max {
+				max = depth
+			}
+		case '}', ']':
+			depth--
+		}
+	}
+
+	return max
+}
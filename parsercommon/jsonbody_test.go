@@ -0,0 +1,104 @@
+package parsercommon
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseJSONBody(t *testing.T) {
+	testCases := []struct {
+		description    string
+		input          string
+		expectedFields map[string]interface{}
+		expectedOk     bool
+	}{
+		{
+			description: "object",
+			input:       `{"log":"started","stream":"stdout","attrs":{"tag":"web"}}`,
+			expectedFields: map[string]interface{}{
+				"log":    "started",
+				"stream": "stdout",
+				"attrs": map[string]interface{}{
+					"tag": "web",
+				},
+			},
+			expectedOk: true,
+		},
+		{
+			description: "leading spaces, brackets in strings",
+			input:       ` {"msg":"[{not nested}]"}`,
+			expectedFields: map[string]interface{}{
+				"msg": "[{not nested}]",
+			},
+			expectedOk: true,
+		},
+		{
+			description:    "not json",
+			input:          "{foo} bar",
+			expectedFields: nil,
+			expectedOk:     false,
+		},
+		{
+			description:    "array",
+			input:          `["foo"]`,
+			expectedFields: nil,
+			expectedOk:     false,
+		},
+		{
+			description:    "text",
+			input:          "'su root' failed for lonvick on /dev/pts/8",
+			expectedFields: nil,
+			expectedOk:     false,
+		},
+	}
+
+	for _, tc := range testCases {
+		fields, ok, err := ParseJSONBody(tc.input, 0, 0)
+		require.Nil(t, err, tc.description)
+		require.Equal(t, tc.expectedOk, ok, tc.description)
+		require.Equal(t, tc.expectedFields, fields, tc.description)
+	}
+}
+
+func TestParseJSONBodyLimits(t *testing.T) {
+	testCases := []struct {
+		description string
+		input       string
+		maxDepth    int
+		maxSize     int
+		expectedErr error
+	}{
+		{
+			description: "within limits",
+			input:       `{"a":{"b":[1]}}`,
+			maxDepth:    3,
+			maxSize:     15,
+			expectedErr: nil,
+		},
+		{
+			description: "too deep",
+			input:       `{"a":{"b":[1]}}`,
+			maxDepth:    2,
+			expectedErr: ErrJSONTooDeep,
+		},
+		{
+			description: "too large",
+			input:       `{"a":{"b":[1]}}`,
+			maxSize:     14,
+			expectedErr: ErrJSONTooLarge,
+		},
+		{
+			description: "default depth",
+			input:       `{"a":` + strings.Repeat("[", JSON_MAX_DEPTH) + strings.Repeat("]", JSON_MAX_DEPTH) + `}`,
+			expectedErr: ErrJSONTooDeep,
+		},
+	}
+
+	for _, tc := range testCases {
+		_, ok, err := ParseJSONBody(tc.input, tc.maxDepth, tc.maxSize)
+		require.True(t, ok, tc.description)
+		require.Equal(t, tc.expectedErr, err, tc.description)
+	}
+}
@@ -0,0 +1,60 @@
+package parsercommon
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	// tag, or APP-NAME, of messages logged by the Linux kernel
+	KERNEL_TAG = "kernel"
+)
+
+// Parses the time elapsed since boot printk prefixes kernel messages with, as
+// in "[12345.678901] usb 1-1: new device". Returns the offset and msg without
+// the prefix, ok is false when msg has no such prefix.
+func ParseBootOffset(msg string) (offset time.Duration, rest string, ok bool) {
+	if len(msg) < 2 || msg[0] != '[' {
+		return 0, msg, false
+	}
+
+	end := strings.IndexByte(msg, ']')
+	if end < 0 {
+		return 0, msg, false
+	}
+
+	// XXX : printk pads seconds with spaces, ie. "[    1.234567]"
+	ts := strings.TrimLeft(msg[1:end], " ")
+
+	dot := strings.IndexByte(ts, '.')
+	if dot <= 0 || dot == len(ts)-1 || len(ts)-dot-1 > 9 {
+		return 0, msg, false
+	}
+
+	var sec, frac int64
+
+	for i := 0; i < len(ts); i++ {
+		if i == dot {
+			continue
+		}
+
+		if !IsDigit(ts[i]) {
+			return 0, msg, false
+		}
+
+		d := int64(ts[i] - '0')
+		if i < dot {
+			sec = sec*10 + d
+		} else {
+			frac = frac*10 + d
+		}
+	}
+
+	for i := len(ts) - dot - 1; i < 9; i++ {
+		frac *= 10
+	}
+
+	offset = time.Duration(sec)*time.Second + time.Duration(frac)
+
+	return offset, strings.TrimLeft(msg[end+1:], " "), true
+}
@@ -0,0 +1,73 @@
+package parsercommon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBootOffset(t *testing.T) {
+	testCases := []struct {
+		description    string
+		input          string
+		expectedOffset time.Duration
+		expectedRest   string
+		expectedOk     bool
+	}{
+		{
+			description:    "microseconds",
+			input:          "[12345.678901] usb 1-1: new device",
+			expectedOffset: 12345*time.Second + 678901*time.Microsecond,
+			expectedRest:   "usb 1-1: new device",
+			expectedOk:     true,
+		},
+		{
+			description:    "padded",
+			input:          "[    1.500000] Booting Linux",
+			expectedOffset: 1500 * time.Millisecond,
+			expectedRest:   "Booting Linux",
+			expectedOk:     true,
+		},
+		{
+			description:    "nanoseconds",
+			input:          "[0.000000001]",
+			expectedOffset: time.Nanosecond,
+			expectedRest:   "",
+			expectedOk:     true,
+		},
+		{
+			description:  "no prefix",
+			input:        "usb 1-1: new device",
+			expectedRest: "usb 1-1: new device",
+		},
+		{
+			description:  "no dot",
+			input:        "[12345] usb 1-1: new device",
+			expectedRest: "[12345] usb 1-1: new device",
+		},
+		{
+			description:  "not a number",
+			input:        "[info] usb 1-1: new device",
+			expectedRest: "[info] usb 1-1: new device",
+		},
+		{
+			description:  "too precise",
+			input:        "[1.0000000001] usb",
+			expectedRest: "[1.0000000001] usb",
+		},
+		{
+			description:  "unterminated",
+			input:        "[1.5 usb",
+			expectedRest: "[1.5 usb",
+		},
+	}
+
+	for _, tc := range testCases {
+		offset, rest, ok := ParseBootOffset(tc.input)
+
+		require.Equal(t, tc.expectedOk, ok, tc.description)
+		require.Equal(t, tc.expectedOffset, offset, tc.description)
+		require.Equal(t, tc.expectedRest, rest, tc.description)
+	}
+}
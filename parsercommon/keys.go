@@ -0,0 +1,72 @@
+package parsercommon
+
+import (
+	"strings"
+)
+
+// Renames the keys returned by Dump(), ie. to match the naming of a backend.
+// It MUST NOT map two keys to the same one.
+type KeyMapper func(key string) string
+
+// Elastic Common Schema names
+// https://www.elastic.co/guide/en/ecs/current/ecs-log.html
+var KeysECS = KeysFromMap(map[string]string{
+	"priority":        "log.syslog.priority",
+	"facility":        "log.syslog.facility.code",
+	"facility_name":   "log.syslog.facility.name",
+	"severity":        "log.syslog.severity.code",
+	"severity_name":   "log.syslog.severity.name",
+	"version":         "log.syslog.version",
+	"timestamp":       "@timestamp",
+	"hostname":        "log.syslog.hostname",
+	"app_name":        "log.syslog.appname",
+	"proc_id":         "log.syslog.procid",
+	"msg_id":          "log.syslog.msgid",
+	"tag":             "process.name",
+	"pid":             "process.pid",
+	"content":         "message",
+	"structured_data": "log.syslog.structured_data",
+	"message":         "message",
+})
+
+// Renames keys found in m, others are kept as is
+func KeysFromMap(m map[string]string) KeyMapper {
+	return func(key string) string {
+		if mapped, ok := m[key]; ok {
+			return mapped
+		}
+
+		return key
+	}
+}
+
+// "app_name" => "appName"
+func KeysCamelCase(key string) string {
+	if strings.IndexByte(key, '_') < 0 {
+		return key
+	}
+
+	words := strings.Split(key, "_")
+	for i := 1; i < len(words); i++ {
+		if words[i] != "" {
+			words[i] = strings.ToUpper(words[i][:1]) + words[i][1:]
+		}
+	}
+
+	return strings.Join(words, "")
+}
+
+// Returns a copy of parts whose keys are renamed with m, parts itself when m
+// is nil
+func MapKeys(parts LogParts, m KeyMapper) LogParts {
+	if m == nil {
+		return parts
+	}
+
+	mapped := make(LogParts, len(parts))
+	for k, v := range parts {
+		mapped[m(k)] = v
+	}
+
+	return mapped
+}
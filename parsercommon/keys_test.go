@@ -0,0 +1,60 @@
+package parsercommon
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeysCamelCase(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"hostname", "hostname"},
+		{"app_name", "appName"},
+		{"structured_data", "structuredData"},
+		{"payload_error_", "payloadError"},
+		{"", ""},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.expected, KeysCamelCase(tc.input), tc.input)
+	}
+}
+
+func TestKeysECS(t *testing.T) {
+	require.Equal(t, "@timestamp", KeysECS("timestamp"))
+	require.Equal(t, "log.syslog.appname", KeysECS("app_name"))
+	require.Equal(t, "message", KeysECS("content"))
+	require.Equal(t, "custom", KeysECS("custom"))
+}
+
+func TestMapKeys(t *testing.T) {
+	parts := LogParts{
+		"app_name": "su",
+		"hostname": "mymachine",
+	}
+
+	require.Equal(t, parts, MapKeys(parts, nil))
+
+	require.Equal(
+		t,
+		LogParts{
+			"appName":  "su",
+			"hostname": "mymachine",
+		},
+		MapKeys(parts, KeysCamelCase),
+	)
+
+	require.Equal(
+		t,
+		LogParts{
+			"app":      "su",
+			"hostname": "mymachine",
+		},
+		MapKeys(parts, KeysFromMap(map[string]string{"app_name": "app"})),
+	)
+
+	require.Equal(t, "su", parts["app_name"])
+}
@@ -0,0 +1,21 @@
+//go:build !tinygo && !wasm && !syslogparser_fixedzones
+// +build !tinygo,!wasm,!syslogparser_fixedzones
+
+package parsercommon
+
+import (
+	"time"
+)
+
+// Returns the location for the given name. Names can be any IANA time zone
+// known to the system (ie. "Europe/Paris") or a fixed offset such as
+// "+02:00" or "-0700".
+// Build with the syslogparser_fixedzones tag (implied by tinygo and wasm)
+// to only accept fixed offsets and never depend on tzdata.
+func LoadLocation(name string) (*time.Location, error) {
+	if loc, err := ParseFixedZone(name); err == nil {
+		return loc, nil
+	}
+
+	return time.LoadLocation(name)
+}
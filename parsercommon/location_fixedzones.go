@@ -0,0 +1,19 @@
+//go:build tinygo || wasm || syslogparser_fixedzones
+// +build tinygo wasm syslogparser_fixedzones
+
+package parsercommon
+
+import (
+	"time"
+)
+
+// Returns the location for the given name without relying on tzdata, as
+// under TinyGo and WASM. Only "UTC", "Local" and fixed offsets such as
+// "+02:00" or "-0700" are supported.
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "Local" {
+		return time.Local, nil
+	}
+
+	return ParseFixedZone(name)
+}
@@ -0,0 +1,29 @@
+//go:build tinygo || wasm || syslogparser_fixedzones
+// +build tinygo wasm syslogparser_fixedzones
+
+package parsercommon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadLocation(t *testing.T) {
+	loc, err := LoadLocation("Local")
+	require.Nil(t, err)
+	require.Equal(t, time.Local, loc)
+
+	loc, err = LoadLocation("+02:00")
+	require.Nil(t, err)
+
+	_, offset := time.Date(
+		2003, time.October, 11, 22, 14, 15, 0, loc,
+	).Zone()
+
+	require.Equal(t, 7200, offset)
+
+	_, err = LoadLocation("America/New_York")
+	require.Equal(t, ErrUnknownLocation, err)
+}
@@ -0,0 +1,168 @@
+package parsercommon
+
+import (
+	"time"
+)
+
+// Struct counterpart of LogParts. Filling a Message does not allocate a map
+// nor box values in interfaces, which matters when parsing large volumes.
+// Fields which do not apply to the parsed RFC are left empty.
+type Message struct {
+	Priority int
+	Facility int
+	Severity int
+	Version  int
+
+	Timestamp time.Time
+
+	Hostname string
+	AppName  string
+	ProcId   string
+	MsgId    string
+
+	// RFC 3164
+	Tag     string
+	Pid     string
+	Content string
+
+	// RFC 5424
+	StructuredData string
+	Message        string
+}
+
+// Returns the keys a parser would return from Dump() for the same message
+func (m *Message) Parts() LogParts {
+	parts := LogParts{
+		"priority":  m.Priority,
+		"facility":  m.Facility,
+		"severity":  m.Severity,
+		"version":   m.Version,
+		"timestamp": m.Timestamp,
+		"hostname":  m.Hostname,
+	}
+
+	if m.Version == NO_VERSION {
+		parts["tag"] = m.Tag
+		parts["pid"] = m.Pid
+		parts["content"] = m.Content
+
+		return parts
+	}
+
+	parts["app_name"] = m.AppName
+	parts["proc_id"] = m.ProcId
+	parts["msg_id"] = m.MsgId
+	parts["structured_data"] = m.StructuredData
+	parts["message"] = m.Message
+
+	return parts
+}
+
+// Encodes m as its Parts() so both follow the same JSON schema
+func (m Message) MarshalJSON() ([]byte, error) {
+	return m.Parts().MarshalJSON()
+}
+
+// Column oriented storage of messages: the i-th message is made of the i-th
+// value of every column. Useful to feed columnar formats or to compute
+// aggregates over a single field.
+type Columns struct {
+	Priority []int
+	Facility []int
+	Severity []int
+	Version  []int
+
+	Timestamp []time.Time
+
+	Hostname []string
+	AppName  []string
+	ProcId   []string
+	MsgId    []string
+
+	Tag     []string
+	Pid     []string
+	Content []string
+
+	StructuredData []string
+	Message        []string
+}
+
+// Returns columns holding n zero valued messages, to be filled with Set()
+func NewColumns(n int) *Columns {
+	return &Columns{
+		Priority:       make([]int, n),
+		Facility:       make([]int, n),
+		Severity:       make([]int, n),
+		Version:        make([]int, n),
+		Timestamp:      make([]time.Time, n),
+		Hostname:       make([]string, n),
+		AppName:        make([]string, n),
+		ProcId:         make([]string, n),
+		MsgId:          make([]string, n),
+		Tag:            make([]string, n),
+		Pid:            make([]string, n),
+		Content:        make([]string, n),
+		StructuredData: make([]string, n),
+		Message:        make([]string, n),
+	}
+}
+
+func (c *Columns) Len() int {
+	return len(c.Priority)
+}
+
+// Stores m as the i-th message
+func (c *Columns) Set(i int, m *Message) {
+	c.Priority[i] = m.Priority
+	c.Facility[i] = m.Facility
+	c.Severity[i] = m.Severity
+	c.Version[i] = m.Version
+	c.Timestamp[i] = m.Timestamp
+	c.Hostname[i] = m.Hostname
+	c.AppName[i] = m.AppName
+	c.ProcId[i] = m.ProcId
+	c.MsgId[i] = m.MsgId
+	c.Tag[i] = m.Tag
+	c.Pid[i] = m.Pid
+	c.Content[i] = m.Content
+	c.StructuredData[i] = m.StructuredData
+	c.Message[i] = m.Message
+}
+
+// Adds m after the last message
+func (c *Columns) Append(m *Message) {
+	c.Priority = append(c.Priority, m.Priority)
+	c.Facility = append(c.Facility, m.Facility)
+	c.Severity = append(c.Severity, m.Severity)
+	c.Version = append(c.Version, m.Version)
+	c.Timestamp = append(c.Timestamp, m.Timestamp)
+	c.Hostname = append(c.Hostname, m.Hostname)
+	c.AppName = append(c.AppName, m.AppName)
+	c.ProcId = append(c.ProcId, m.ProcId)
+	c.MsgId = append(c.MsgId, m.MsgId)
+	c.Tag = append(c.Tag, m.Tag)
+	c.Pid = append(c.Pid, m.Pid)
+	c.Content = append(c.Content, m.Content)
+	c.StructuredData = append(c.StructuredData, m.StructuredData)
+	c.Message = append(c.Message, m.Message)
+}
+
+// Returns the i-th message
+func (c *Columns) Row(i int) Message {
+	return Message{
+		Priority:       c.Priority[i],
+		Facility:       c.Facility[i],
+		Severity:       c.Severity[i],
+		Version:        c.Version[i],
+		Timestamp:      c.Timestamp[i],
+		Hostname:       c.Hostname[i],
+		AppName:        c.AppName[i],
+		ProcId:         c.ProcId[i],
+		MsgId:          c.MsgId[i],
+		Tag:            c.Tag[i],
+		Pid:            c.Pid[i],
+		Content:        c.Content[i],
+		StructuredData: c.StructuredData[i],
+		Message:        c.Message[i],
+	}
+}
@@ -0,0 +1,145 @@
+package parsercommon
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageParts(t *testing.T) {
+	ts := time.Date(2003, time.October, 11, 22, 14, 15, 0, time.UTC)
+
+	m := &Message{
+		Priority:  34,
+		Facility:  4,
+		Severity:  2,
+		Version:   NO_VERSION,
+		Timestamp: ts,
+		Hostname:  "mymachine",
+		Tag:       "su",
+		Content:   "'su root' failed",
+	}
+
+	require.Equal(
+		t,
+		LogParts{
+			"priority":  34,
+			"facility":  4,
+			"severity":  2,
+			"version":   NO_VERSION,
+			"timestamp": ts,
+			"hostname":  "mymachine",
+			"tag":       "su",
+			"pid":       "",
+			"content":   "'su root' failed",
+		},
+		m.Parts(),
+	)
+
+	m = &Message{
+		Priority:       165,
+		Facility:       20,
+		Severity:       5,
+		Version:        1,
+		Timestamp:      ts,
+		Hostname:       "mymachine",
+		AppName:        "evntslog",
+		ProcId:         "-",
+		MsgId:          "ID47",
+		StructuredData: "-",
+		Message:        "An application event log entry...",
+	}
+
+	require.Equal(
+		t,
+		LogParts{
+			"priority":        165,
+			"facility":        20,
+			"severity":        5,
+			"version":         1,
+			"timestamp":       ts,
+			"hostname":        "mymachine",
+			"app_name":        "evntslog",
+			"proc_id":         "-",
+			"msg_id":          "ID47",
+			"structured_data": "-",
+			"message":         "An application event log entry...",
+		},
+		m.Parts(),
+	)
+}
+
+func TestMessageMarshalJSON(t *testing.T) {
+	messages := []Message{
+		{
+			Priority:  34,
+			Facility:  4,
+			Severity:  2,
+			Version:   NO_VERSION,
+			Timestamp: time.Date(2003, time.October, 11, 22, 14, 15, 0, time.UTC),
+			Hostname:  "mymachine",
+			Tag:       "su",
+			Content:   "ok",
+		},
+		{
+			Priority: 165,
+			Facility: 20,
+			Severity: 5,
+			Version:  1,
+			Hostname: "mymachine",
+			AppName:  "evntslog",
+			MsgId:    "ID47",
+			Message:  "hello",
+		},
+	}
+
+	for _, m := range messages {
+		expected, err := json.Marshal(m.Parts())
+		require.Nil(t, err)
+
+		obtained, err := json.Marshal(m)
+		require.Nil(t, err)
+		require.Equal(t, string(expected), string(obtained))
+
+		obtained, err = json.Marshal(&m)
+		require.Nil(t, err)
+		require.Equal(t, string(expected), string(obtained))
+	}
+
+	b, err := json.Marshal(messages[1])
+	require.Nil(t, err)
+	require.Contains(t, string(b), `"timestamp":null`)
+	require.Contains(t, string(b), `"app_name":"evntslog"`)
+}
+
+func TestColumns(t *testing.T) {
+	messages := []Message{
+		{Priority: 34, Facility: 4, Severity: 2, Version: NO_VERSION, Hostname: "a", Tag: "su"},
+		{Priority: 165, Facility: 20, Severity: 5, Version: 1, Hostname: "b", AppName: "evntslog"},
+	}
+
+	c := NewColumns(len(messages))
+	require.Equal(t, 2, c.Len())
+	require.Equal(t, Message{}, c.Row(0))
+
+	for i := range messages {
+		c.Set(i, &messages[i])
+	}
+
+	require.Equal(t, []int{34, 165}, c.Priority)
+	require.Equal(t, []string{"a", "b"}, c.Hostname)
+	require.Equal(t, []string{"su", ""}, c.Tag)
+
+	for i := range messages {
+		require.Equal(t, messages[i], c.Row(i))
+	}
+
+	appended := &Columns{}
+	for i := range messages {
+		appended.Append(&messages[i])
+	}
+
+	require.Equal(t, c, appended)
+}
@@ -2,34 +2,58 @@ package parsercommon
 
 import (
 	"fmt"
-	"strconv"
 	"strings"
+	"time"
+	"unsafe"
 )
 
 const (
 	NO_VERSION = -1
+
+	// https://tools.ietf.org/html/rfc5424#section-6.2.1
+	MAX_PRIORITY = 191
+
+	// user.notice, given by relays to messages without PRI
+	// https://tools.ietf.org/html/rfc3164#section-4.3.3
+	DEFAULT_PRIORITY = 13
+
+	// https://tools.ietf.org/html/rfc5424#section-6
+	NILVALUE = '-'
 )
 
 var (
-	ErrEOL     = &ParserError{"End of log line"}
-	ErrNoSpace = &ParserError{"No space found"}
+	ErrEOL     = &ParserError{ErrorString: "End of log line"}
+	ErrNoSpace = &ParserError{ErrorString: "No space found"}
+
+	ErrPriorityNoStart  = &ParserError{ErrorString: "No start char found for priority"}
+	ErrPriorityEmpty    = &ParserError{ErrorString: "Priority field empty"}
+	ErrPriorityNoEnd    = &ParserError{ErrorString: "No end char found for priority"}
+	ErrPriorityTooShort = &ParserError{ErrorString: "Priority field too short"}
+	ErrPriorityTooLong  = &ParserError{ErrorString: "Priority field too long"}
+	ErrPriorityNonDigit = &ParserError{ErrorString: "Non digit found in priority"}
+	ErrPriorityInvalid  = &ParserError{ErrorString: "Priority out of range"}
 
-	ErrPriorityNoStart  = &ParserError{"No start char found for priority"}
-	ErrPriorityEmpty    = &ParserError{"Priority field empty"}
-	ErrPriorityNoEnd    = &ParserError{"No end char found for priority"}
-	ErrPriorityTooShort = &ParserError{"Priority field too short"}
-	ErrPriorityTooLong  = &ParserError{"Priority field too long"}
-	ErrPriorityNonDigit = &ParserError{"Non digit found in priority"}
+	ErrVersionNotFound = &ParserError{ErrorString: "Can not find version"}
 
-	ErrVersionNotFound = &ParserError{"Can not find version"}
+	ErrTimestampUnknownFormat = &ParserError{ErrorString: "Timestamp format unknown"}
 
-	ErrTimestampUnknownFormat = &ParserError{"Timestamp format unknown"}
+	ErrHostnameNotFound = &ParserError{ErrorString: "Hostname not found"}
 
-	ErrHostnameNotFound = &ParserError{"Hostname not found"}
+	ErrUnknownLocation = &ParserError{ErrorString: "Unknown location"}
 )
 
+type LogParts map[string]interface{}
+
 type ParserError struct {
 	ErrorString string
+
+	// set by Wrap() and Locate(), see errors.go
+	err     *ParserError
+	cause   error
+	located bool
+	offset  int
+	field   string
+	snippet string
 }
 
 type Priority struct {
@@ -47,74 +71,195 @@ type Severity struct {
 }
 
 // https://tools.ietf.org/html/rfc3164#section-4.1
-func ParsePriority(buff []byte, cursor *int, l int) (*Priority, error) {
-	if l <= 0 {
-		return nil, ErrPriorityEmpty
+// Values above MAX_PRIORITY are rejected with ErrPriorityInvalid.
+func (c *Cursor) ParsePriority() (*Priority, error) {
+	return c.parsePriority(false)
+}
+
+// Same as ParsePriority() accepting any value made of up to 3 digits, as
+// older versions did, ie. <999>
+func (c *Cursor) ParseLenientPriority() (*Priority, error) {
+	return c.parsePriority(true)
+}
+
+// Same as ParsePriority() returning a value, which does not allocate
+func (c *Cursor) ParsePriorityValue() (Priority, error) {
+	p, err := c.scanPriority(false)
+	if err != nil {
+		return Priority{}, err
 	}
 
-	if buff[*cursor] != '<' {
-		return nil, ErrPriorityNoStart
+	return priority(p), nil
+}
+
+func (c *Cursor) parsePriority(lenient bool) (*Priority, error) {
+	p, err := c.scanPriority(lenient)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPriority(p), nil
+}
+
+func (c *Cursor) scanPriority(lenient bool) (int, error) {
+	if c.EOF() {
+		return 0, ErrPriorityEmpty
+	}
+
+	from := c.pos
+
+	if !c.Expect('<') {
+		return 0, ErrPriorityNoStart
 	}
 
 	i := 1
 	priDigit := 0
 
-	for i < l {
-		if i >= 5 {
-			return nil, ErrPriorityTooLong
+	for {
+		b, ok := c.At(from + i)
+		if !ok {
+			break
 		}
 
-		c := buff[i]
+		if i >= 5 {
+			c.pos = from
+			return 0, ErrPriorityTooLong
+		}
 
-		if c == '>' {
+		if b == '>' {
 			if i == 1 {
-				return nil, ErrPriorityTooShort
+				c.pos = from
+				return 0, ErrPriorityTooShort
 			}
 
-			*cursor = i + 1
+			if !lenient && priDigit > MAX_PRIORITY {
+				c.pos = from
+				return 0, ErrPriorityInvalid
+			}
 
-			return NewPriority(priDigit), nil
-		}
+			c.pos = from + i + 1
 
-		if IsDigit(c) {
-			v, e := strconv.Atoi(string(c))
-			if e != nil {
-				return nil, e
-			}
+			return priDigit, nil
+		}
 
-			priDigit = (priDigit * 10) + v
-		} else {
-			return nil, ErrPriorityNonDigit
+		if !IsDigit(b) {
+			c.pos = from
+			return 0, ErrPriorityNonDigit
 		}
 
+		priDigit = (priDigit * 10) + int(b-'0')
+
 		i++
 	}
 
-	return nil, ErrPriorityNoEnd
+	c.pos = from
+
+	return 0, ErrPriorityNoEnd
 }
 
 // https://tools.ietf.org/html/rfc5424#section-6.2.2
-func ParseVersion(buff []byte, cursor *int, l int) (int, error) {
-	if *cursor >= l {
+func (c *Cursor) ParseVersion() (int, error) {
+	b, ok := c.Peek()
+	if !ok {
 		return NO_VERSION, ErrVersionNotFound
 	}
 
-	c := buff[*cursor]
-	*cursor++
+	c.pos++
 
 	// XXX : not a version, not an error though as RFC 3164 does not support it
-	if !IsDigit(c) {
+	if !IsDigit(b) {
 		return NO_VERSION, nil
 	}
 
-	v, e := strconv.Atoi(string(c))
-	if e != nil {
-		*cursor--
-		return NO_VERSION, e
+	return int(b - '0'), nil
+}
+
+// Parses 2 digits as an int in [min, max], e is returned otherwise
+func (c *Cursor) Parse2Digits(min int, max int, e error) (int, error) {
+	digitLen := 2
+
+	if c.pos+digitLen > c.l {
+		return 0, ErrEOL
+	}
+
+	sub := c.buff[c.pos : c.pos+digitLen]
+
+	c.pos += digitLen
+
+	if !IsDigit(sub[0]) || !IsDigit(sub[1]) {
+		return 0, e
+	}
+
+	i := int(sub[0]-'0')*10 + int(sub[1]-'0')
+
+	if i >= min && i <= max {
+		return i, nil
 	}
 
-	return v, nil
+	return 0, e
+}
+
+// Scans up to the next space, or the end of the buffer. The returned
+// hostname shares the memory of the buffer.
+func (c *Cursor) ScanHostname() []byte {
+	from := c.pos
+
+	for c.pos < c.l && c.buff[c.pos] != ' ' {
+		c.pos++
+	}
 
+	return c.buff[from:c.pos]
+}
+
+// DEPRECATED. Use Cursor.ParsePriority() instead
+func ParsePriority(buff []byte, cursor *int, l int) (*Priority, error) {
+	c := newCursorAt(buff, *cursor, l)
+
+	pri, err := c.ParsePriority()
+	*cursor = c.pos
+
+	return pri, err
+}
+
+// DEPRECATED. Use Cursor.ParseVersion() instead
+func ParseVersion(buff []byte, cursor *int, l int) (int, error) {
+	c := newCursorAt(buff, *cursor, l)
+
+	v, err := c.ParseVersion()
+	*cursor = c.pos
+
+	return v, err
+}
+
+// Returns true if b is NILVALUE, ie. the field has no value
+func IsNilValue(b []byte) bool {
+	return len(b) == 1 && b[0] == NILVALUE
+}
+
+// Same as IsNilValue() for strings, such as the fields returned by Dump()
+func IsNilString(s string) bool {
+	return len(s) == 1 && s[0] == NILVALUE
+}
+
+var months = [...]string{
+	"Jan", "Feb", "Mar", "Apr", "May", "Jun",
+	"Jul", "Aug", "Sep", "Oct", "Nov", "Dec",
+}
+
+// Returns true if b starts with an abbreviated month followed by a space, as
+// RFC3164 timestamps do, ie. "Oct 11 22:14:15"
+func StartsWithMonth(b []byte) bool {
+	if len(b) < 4 || b[3] != ' ' {
+		return false
+	}
+
+	for _, m := range months {
+		if string(b[:3]) == m {
+			return true
+		}
+	}
+
+	return false
 }
 
 func IsDigit(c byte) bool {
@@ -122,10 +267,16 @@ func IsDigit(c byte) bool {
 }
 
 func NewPriority(p int) *Priority {
+	pri := priority(p)
+
+	return &pri
+}
+
+func priority(p int) Priority {
 	// The Priority value is calculated by first multiplying the Facility
 	// number by 8 and then adding the numerical value of the Severity.
 
-	return &Priority{
+	return Priority{
 		P: p,
 		F: Facility{Value: p / 8},
 		S: Severity{Value: p % 8},
@@ -145,44 +296,89 @@ func FindNextSpace(buff []byte, from int, l int) (int, error) {
 	return 0, ErrNoSpace
 }
 
+// DEPRECATED. Use Cursor.Parse2Digits() instead
 func Parse2Digits(buff []byte, cursor *int, l int, min int, max int, e error) (int, error) {
-	digitLen := 2
+	c := newCursorAt(buff, *cursor, l)
 
-	if *cursor+digitLen > l {
-		return 0, ErrEOL
+	i, err := c.Parse2Digits(min, max, e)
+	*cursor = c.pos
+
+	return i, err
+}
+
+// DEPRECATED. Use Cursor.ScanHostname() instead
+func ParseHostname(buff []byte, cursor *int, l int) (string, error) {
+	return string(ScanHostname(buff, cursor, l)), nil
+}
+
+// DEPRECATED. Use Cursor.ScanHostname() instead
+func ScanHostname(buff []byte, cursor *int, l int) []byte {
+	c := newCursorAt(buff, *cursor, l)
+
+	h := c.ScanHostname()
+	*cursor = c.pos
+
+	return h
+}
+
+func newCursorAt(buff []byte, pos int, l int) Cursor {
+	c := NewCursor(buff, l)
+	c.SetPos(pos)
+
+	return c
+}
+
+// Returns a string sharing the memory of b instead of a copy.
+// b MUST NOT be modified as long as the returned string is in use.
+func UnsafeString(b []byte) string {
+	if len(b) == 0 {
+		return ""
 	}
 
-	sub := string(buff[*cursor : *cursor+digitLen])
+	return *(*string)(unsafe.Pointer(&b))
+}
 
-	*cursor += digitLen
+// Returns a fixed zone for "", "UTC", "Z" or a numerical offset
+// formatted as "+hh:mm", "-hh:mm", "+hhmm" or "-hhmm"
+func ParseFixedZone(name string) (*time.Location, error) {
+	switch name {
+	case "", "UTC", "Z":
+		return time.UTC, nil
+	}
 
-	i, err := strconv.Atoi(sub)
-	if err != nil {
-		return 0, e
+	l := len(name)
+	if l != 5 && l != 6 {
+		return nil, ErrUnknownLocation
 	}
 
-	if i >= min && i <= max {
-		return i, nil
+	sign := name[0]
+	if sign != '+' && sign != '-' {
+		return nil, ErrUnknownLocation
 	}
 
-	return 0, e
-}
+	c := NewCursor([]byte(name), l)
+	c.Advance(1)
 
-func ParseHostname(buff []byte, cursor *int, l int) (string, error) {
-	from := *cursor
-	var to int
+	hour, err := c.Parse2Digits(0, 23, ErrUnknownLocation)
+	if err != nil {
+		return nil, err
+	}
 
-	for to = from; to < l; to++ {
-		if buff[to] == ' ' {
-			break
-		}
+	if l == 6 && !c.Expect(':') {
+		return nil, ErrUnknownLocation
 	}
 
-	hostname := buff[from:to]
+	minute, err := c.Parse2Digits(0, 59, ErrUnknownLocation)
+	if err != nil {
+		return nil, err
+	}
 
-	*cursor = to
+	offset := (hour * 3600) + (minute * 60)
+	if sign == '-' {
+		offset = -offset
+	}
 
-	return string(hostname), nil
+	return time.FixedZone(name, offset), nil
 }
 
 func ShowCursorPos(buff []byte, cursor int) {
@@ -2,6 +2,7 @@ package parsercommon
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -56,6 +57,20 @@ func TestParsePriority(t *testing.T) {
 			expectedCursorPos: 0,
 			expectedErr:       ErrPriorityNonDigit,
 		},
+		{
+			description:       "out of range",
+			input:             []byte("<192>"),
+			expectedPri:       nil,
+			expectedCursorPos: 0,
+			expectedErr:       ErrPriorityInvalid,
+		},
+		{
+			description:       "highest",
+			input:             []byte("<191>"),
+			expectedPri:       NewPriority(191),
+			expectedCursorPos: 5,
+			expectedErr:       nil,
+		},
 		{
 			description:       "all good",
 			input:             []byte("<190>"),
@@ -86,6 +101,27 @@ func TestParsePriority(t *testing.T) {
 	}
 }
 
+func TestParseLenientPriority(t *testing.T) {
+	c := NewCursor([]byte("<999>"), 5)
+
+	obtained, err := c.ParseLenientPriority()
+	require.Nil(t, err)
+	require.Equal(t, NewPriority(999), obtained)
+	require.Equal(t, 5, c.Pos())
+
+	c = NewCursor([]byte("<999>"), 5)
+
+	obtained, err = c.ParsePriority()
+	require.Equal(t, ErrPriorityInvalid, err)
+	require.Nil(t, obtained)
+	require.Equal(t, 0, c.Pos())
+
+	c = NewCursor([]byte("<7a8>"), 5)
+
+	_, err = c.ParseLenientPriority()
+	require.Equal(t, ErrPriorityNonDigit, err)
+}
+
 func TestNewPriority(t *testing.T) {
 	require.Equal(
 		t,
@@ -192,6 +228,17 @@ func TestParseHostname(t *testing.T) {
 	}
 }
 
+func TestUnsafeString(t *testing.T) {
+	require.Equal(t, "", UnsafeString(nil))
+
+	buff := []byte("mymachine")
+	obtained := UnsafeString(buff)
+	require.Equal(t, "mymachine", obtained)
+
+	buff[0] = 'M'
+	require.Equal(t, "Mymachine", obtained)
+}
+
 func TestFindNextSpace(t *testing.T) {
 	testCases := []struct {
 		description       string
@@ -228,6 +275,79 @@ func TestFindNextSpace(t *testing.T) {
 	}
 }
 
+func TestParseFixedZone(t *testing.T) {
+	testCases := []struct {
+		description    string
+		input          string
+		expectedOffset int
+		expectedErr    error
+	}{
+		{
+			description:    "empty",
+			input:          "",
+			expectedOffset: 0,
+			expectedErr:    nil,
+		},
+		{
+			description:    "zulu",
+			input:          "Z",
+			expectedOffset: 0,
+			expectedErr:    nil,
+		},
+		{
+			description:    "with colon",
+			input:          "+05:45",
+			expectedOffset: 20700,
+			expectedErr:    nil,
+		},
+		{
+			description:    "without colon",
+			input:          "-0700",
+			expectedOffset: -25200,
+			expectedErr:    nil,
+		},
+		{
+			description:    "invalid hour",
+			input:          "+25:00",
+			expectedOffset: 0,
+			expectedErr:    ErrUnknownLocation,
+		},
+		{
+			description:    "invalid separator",
+			input:          "+05-45",
+			expectedOffset: 0,
+			expectedErr:    ErrUnknownLocation,
+		},
+		{
+			description:    "zone name",
+			input:          "Europe/Paris",
+			expectedOffset: 0,
+			expectedErr:    ErrUnknownLocation,
+		},
+	}
+
+	for _, tc := range testCases {
+		obtained, err := ParseFixedZone(tc.input)
+
+		require.Equal(
+			t, tc.expectedErr, err, tc.description,
+		)
+
+		if err != nil {
+			require.Nil(t, obtained, tc.description)
+			continue
+		}
+
+		_, offset := time.Date(
+			2003, time.October, 11, 22, 14, 15, 0, obtained,
+		).Zone()
+
+		require.Equal(
+			t, tc.expectedOffset, offset, tc.description,
+		)
+	}
+}
+
 func BenchmarkParsePriority(b *testing.B) {
 	buff := []byte("<190>")
 	var start int
@@ -255,3 +375,40 @@ func BenchmarkParseVersion(b *testing.B) {
 		}
 	}
 }
+
+func TestIsNilValue(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected bool
+	}{
+		{"-", true},
+		{"", false},
+		{"--", false},
+		{"a", false},
+		{" -", false},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.expected, IsNilValue([]byte(tc.input)), tc.input)
+		require.Equal(t, tc.expected, IsNilString(tc.input), tc.input)
+	}
+}
+
+func TestStartsWithMonth(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected bool
+	}{
+		{"Oct 11 22:14:15", true},
+		{"Jan  2 15:04:05", true},
+		{"Oct", false},
+		{"October 11", false},
+		{"oct 11", false},
+		{"Foo 11", false},
+		{"", false},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.expected, StartsWithMonth([]byte(tc.input)), tc.input)
+	}
+}
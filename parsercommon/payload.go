@@ -0,0 +1,62 @@
+package parsercommon
+
+const (
+	// prefix of payload keys with PAYLOAD_PREFIXED
+	PAYLOAD_PREFIX = "payload_"
+)
+
+// Extracts fields from the free form part of a message: the content of
+// RFC 3164 messages or the message of RFC 5424 ones, ie. key=value pairs or
+// JSON. Parsers run it after the header when set with WithPayloadParser().
+type PayloadParser interface {
+	ParsePayload(payload string) (LogParts, error)
+}
+
+type PayloadParserFunc func(payload string) (LogParts, error)
+
+func (f PayloadParserFunc) ParsePayload(payload string) (LogParts, error) {
+	return f(payload)
+}
+
+// Tells which value is kept when a payload key is also set by the parser,
+// ie. "hostname" or "timestamp"
+type KeyPolicy uint8
+
+const (
+	// values set by the parser are kept, colliding payload keys are dropped
+	HEADER_WINS KeyPolicy = iota
+
+	// payload values replace the ones set by the parser
+	PAYLOAD_WINS
+
+	// every payload key is prefixed with PAYLOAD_PREFIX so none collides
+	PAYLOAD_PREFIXED
+)
+
+// Adds payload to parts following policy. Whatever the iteration order of
+// both maps the result is the same.
+func Merge(parts LogParts, payload LogParts, policy KeyPolicy) {
+	for k, v := range payload {
+		switch policy {
+		case PAYLOAD_WINS:
+			parts[k] = v
+		case PAYLOAD_PREFIXED:
+			parts[PAYLOAD_PREFIX+k] = v
+		default:
+			if _, ok := parts[k]; !ok {
+				parts[k] = v
+			}
+		}
+	}
+}
+
+// Adds the result of a PayloadParser to parts: payload merged following
+// policy or, when parsing failed, err as "payload_error"
+func MergePayload(parts LogParts, payload LogParts, err error, policy KeyPolicy) {
+	if err != nil {
+		parts["payload_error"] = err.Error()
+		return
+	}
+
+	Merge(parts, payload, policy)
+}
@@ -0,0 +1,70 @@
+package parsercommon
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerge(t *testing.T) {
+	testCases := []struct {
+		description   string
+		policy        KeyPolicy
+		expectedParts LogParts
+	}{
+		{
+			description: "header wins",
+			policy:      HEADER_WINS,
+			expectedParts: LogParts{
+				"hostname": "mymachine",
+				"user":     "root",
+			},
+		},
+		{
+			description: "payload wins",
+			policy:      PAYLOAD_WINS,
+			expectedParts: LogParts{
+				"hostname": "spoofed",
+				"user":     "root",
+			},
+		},
+		{
+			description: "payload prefixed",
+			policy:      PAYLOAD_PREFIXED,
+			expectedParts: LogParts{
+				"hostname":         "mymachine",
+				"payload_hostname": "spoofed",
+				"payload_user":     "root",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		parts := LogParts{"hostname": "mymachine"}
+
+		Merge(
+			parts, LogParts{"hostname": "spoofed", "user": "root"}, tc.policy,
+		)
+
+		require.Equal(
+			t, tc.expectedParts, parts, tc.description,
+		)
+	}
+}
+
+func TestMergePayload(t *testing.T) {
+	parts := LogParts{"hostname": "mymachine"}
+
+	MergePayload(parts, LogParts{"user": "root"}, nil, HEADER_WINS)
+	require.Equal(t, LogParts{"hostname": "mymachine", "user": "root"}, parts)
+
+	parts = LogParts{"hostname": "mymachine"}
+
+	MergePayload(parts, nil, errors.New("Invalid payload"), HEADER_WINS)
+	require.Equal(
+		t,
+		LogParts{"hostname": "mymachine", "payload_error": "Invalid payload"},
+		parts,
+	)
+}
@@ -0,0 +1,204 @@
+package parsercommon
+
+import (
+	"strings"
+)
+
+// Facility codes as defined in https://tools.ietf.org/html/rfc5424#section-6.2.1
+type FacilityCode int
+
+const (
+	FacilityKern FacilityCode = iota
+	FacilityUser
+	FacilityMail
+	FacilityDaemon
+	FacilityAuth
+	FacilitySyslog
+	FacilityLpr
+	FacilityNews
+	FacilityUucp
+	FacilityCron
+	FacilityAuthpriv
+	FacilityFtp
+	FacilityNtp
+	FacilitySecurity
+	FacilityConsole
+	FacilitySolarisCron
+	FacilityLocal0
+	FacilityLocal1
+	FacilityLocal2
+	FacilityLocal3
+	FacilityLocal4
+	FacilityLocal5
+	FacilityLocal6
+	FacilityLocal7
+)
+
+// Severity codes as defined in https://tools.ietf.org/html/rfc5424#section-6.2.1
+type SeverityCode int
+
+const (
+	SeverityEmerg SeverityCode = iota
+	SeverityAlert
+	SeverityCrit
+	SeverityErr
+	SeverityWarning
+	SeverityNotice
+	SeverityInfo
+	SeverityDebug
+)
+
+var (
+	ErrInvalidSelector = &ParserError{ErrorString: "Invalid selector"}
+	ErrUnknownFacility = &ParserError{ErrorString: "Unknown facility"}
+	ErrUnknownSeverity = &ParserError{ErrorString: "Unknown severity"}
+)
+
+// Keywords used by syslog.conf(5) and most syslog daemons, indexed by code
+var facilityNames = [...]string{
+	"kern",
+	"user",
+	"mail",
+	"daemon",
+	"auth",
+	"syslog",
+	"lpr",
+	"news",
+	"uucp",
+	"cron",
+	"authpriv",
+	"ftp",
+	"ntp",
+	"security",
+	"console",
+	"solaris-cron",
+	"local0",
+	"local1",
+	"local2",
+	"local3",
+	"local4",
+	"local5",
+	"local6",
+	"local7",
+}
+
+var severityNames = [...]string{
+	"emerg",
+	"alert",
+	"crit",
+	"err",
+	"warning",
+	"notice",
+	"info",
+	"debug",
+}
+
+// Deprecated keywords still accepted by syslog.conf(5)
+var severityAliases = map[string]SeverityCode{
+	"panic": SeverityEmerg,
+	"error": SeverityErr,
+	"warn":  SeverityWarning,
+}
+
+// Returns the keyword of the facility ("daemon", "auth", "local0" ...) or an
+// empty string when the value is out of range
+func (f Facility) Name() string {
+	return FacilityCode(f.Value).String()
+}
+
+// Returns the keyword of the severity ("err", "warning" ...) or an empty
+// string when the value is out of range
+func (s Severity) Name() string {
+	return SeverityCode(s.Value).String()
+}
+
+func (f FacilityCode) String() string {
+	if f < 0 || int(f) >= len(facilityNames) {
+		return ""
+	}
+
+	return facilityNames[f]
+}
+
+func (s SeverityCode) String() string {
+	if s < 0 || int(s) >= len(severityNames) {
+		return ""
+	}
+
+	return severityNames[s]
+}
+
+// Builds a priority from a "facility.severity" selector as used in
+// syslog.conf(5), ie. "auth.err" or "local0.info"
+func ParseFacilitySeverity(selector string) (*Priority, error) {
+	i := strings.IndexByte(selector, '.')
+	if i < 0 {
+		return nil, ErrInvalidSelector
+	}
+
+	f, err := ParseFacility(selector[:i])
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := ParseSeverity(selector[i+1:])
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPriorityFromFacilitySeverity(f, s)
+}
+
+// Builds a priority from its facility and severity codes.
+// ErrUnknownFacility or ErrUnknownSeverity is returned when a code is out of
+// range.
+func NewPriorityFromFacilitySeverity(f FacilityCode, s SeverityCode) (*Priority, error) {
+	if f.String() == "" {
+		return nil, ErrUnknownFacility
+	}
+
+	if s.String() == "" {
+		return nil, ErrUnknownSeverity
+	}
+
+	return NewPriority(int(f)*8 + int(s)), nil
+}
+
+// Checks the value is within 0-191 and matches the facility and severity
+func (p *Priority) Validate() error {
+	if p.P < 0 || p.P > MAX_PRIORITY {
+		return ErrPriorityInvalid
+	}
+
+	if p.F.Value*8+p.S.Value != p.P || p.S.Name() == "" {
+		return ErrPriorityInvalid
+	}
+
+	return nil
+}
+
+// Returns the facility code of a keyword, ie. "daemon"
+func ParseFacility(name string) (FacilityCode, error) {
+	for i, n := range facilityNames {
+		if n == name {
+			return FacilityCode(i), nil
+		}
+	}
+
+	return 0, ErrUnknownFacility
+}
+
+// Returns the severity code of a keyword, ie. "err" or its alias "error"
+func ParseSeverity(name string) (SeverityCode, error) {
+	for i, n := range severityNames {
+		if n == name {
+			return SeverityCode(i), nil
+		}
+	}
+
+	if s, ok := severityAliases[name]; ok {
+		return s, nil
+	}
+
+	return 0, ErrUnknownSeverity
+}
@@ -0,0 +1,208 @@
+package parsercommon
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFacilitySeverityName(t *testing.T) {
+	testCases := []struct {
+		description      string
+		input            int
+		expectedFacility string
+		expectedSeverity string
+	}{
+		{
+			description:      "kern emerg",
+			input:            0,
+			expectedFacility: "kern",
+			expectedSeverity: "emerg",
+		},
+		{
+			description:      "auth crit",
+			input:            34,
+			expectedFacility: "auth",
+			expectedSeverity: "crit",
+		},
+		{
+			description:      "local4 notice",
+			input:            165,
+			expectedFacility: "local4",
+			expectedSeverity: "notice",
+		},
+		{
+			description:      "local7 debug",
+			input:            191,
+			expectedFacility: "local7",
+			expectedSeverity: "debug",
+		},
+		{
+			description:      "out of range",
+			input:            192,
+			expectedFacility: "",
+			expectedSeverity: "emerg",
+		},
+	}
+
+	for _, tc := range testCases {
+		pri := NewPriority(tc.input)
+
+		require.Equal(
+			t, tc.expectedFacility, pri.F.Name(), tc.description,
+		)
+
+		require.Equal(
+			t, tc.expectedSeverity, pri.S.Name(), tc.description,
+		)
+	}
+
+	require.Equal(t, "", Severity{Value: -1}.Name())
+	require.Equal(t, "", Facility{Value: -1}.Name())
+}
+
+func TestCodeString(t *testing.T) {
+	require.Equal(t, "kern", FacilityKern.String())
+	require.Equal(t, "solaris-cron", FacilitySolarisCron.String())
+	require.Equal(t, "local7", FacilityLocal7.String())
+	require.Equal(t, "", FacilityCode(24).String())
+
+	require.Equal(t, "emerg", SeverityEmerg.String())
+	require.Equal(t, "debug", SeverityDebug.String())
+	require.Equal(t, "", SeverityCode(8).String())
+}
+
+func TestParseFacilitySeverity(t *testing.T) {
+	testCases := []struct {
+		description string
+		input       string
+		expectedPri *Priority
+		expectedErr error
+	}{
+		{
+			description: "auth.err",
+			input:       "auth.err",
+			expectedPri: NewPriority(35),
+		},
+		{
+			description: "local4.notice",
+			input:       "local4.notice",
+			expectedPri: NewPriority(165),
+		},
+		{
+			description: "kern.emerg",
+			input:       "kern.emerg",
+			expectedPri: NewPriority(0),
+		},
+		{
+			description: "severity alias",
+			input:       "daemon.warn",
+			expectedPri: NewPriority(28),
+		},
+		{
+			description: "no dot",
+			input:       "auth",
+			expectedErr: ErrInvalidSelector,
+		},
+		{
+			description: "unknown facility",
+			input:       "foo.err",
+			expectedErr: ErrUnknownFacility,
+		},
+		{
+			description: "unknown severity",
+			input:       "auth.foo",
+			expectedErr: ErrUnknownSeverity,
+		},
+		{
+			description: "wildcard",
+			input:       "*.err",
+			expectedErr: ErrUnknownFacility,
+		},
+	}
+
+	for _, tc := range testCases {
+		obtained, err := ParseFacilitySeverity(tc.input)
+
+		require.Equal(
+			t, tc.expectedErr, err, tc.description,
+		)
+
+		require.Equal(
+			t, tc.expectedPri, obtained, tc.description,
+		)
+	}
+
+	pri, err := ParseFacilitySeverity(
+		FacilityDaemon.String() + "." + SeverityInfo.String(),
+	)
+	require.Nil(t, err)
+	require.Equal(t, int(FacilityDaemon), pri.F.Value)
+	require.Equal(t, int(SeverityInfo), pri.S.Value)
+}
+
+func TestNewPriorityFromFacilitySeverity(t *testing.T) {
+	testCases := []struct {
+		description string
+		facility    FacilityCode
+		severity    SeverityCode
+		expectedPri *Priority
+		expectedErr error
+	}{
+		{
+			description: "auth.crit",
+			facility:    FacilityAuth,
+			severity:    SeverityCrit,
+			expectedPri: NewPriority(34),
+		},
+		{
+			description: "local7.debug",
+			facility:    FacilityLocal7,
+			severity:    SeverityDebug,
+			expectedPri: NewPriority(MAX_PRIORITY),
+		},
+		{
+			description: "facility out of range",
+			facility:    FacilityCode(24),
+			severity:    SeverityDebug,
+			expectedErr: ErrUnknownFacility,
+		},
+		{
+			description: "negative facility",
+			facility:    FacilityCode(-1),
+			severity:    SeverityDebug,
+			expectedErr: ErrUnknownFacility,
+		},
+		{
+			description: "severity out of range",
+			facility:    FacilityKern,
+			severity:    SeverityCode(8),
+			expectedErr: ErrUnknownSeverity,
+		},
+	}
+
+	for _, tc := range testCases {
+		obtained, err := NewPriorityFromFacilitySeverity(tc.facility, tc.severity)
+
+		require.Equal(
+			t, tc.expectedErr, err, tc.description,
+		)
+
+		require.Equal(
+			t, tc.expectedPri, obtained, tc.description,
+		)
+	}
+}
+
+func TestPriorityValidate(t *testing.T) {
+	require.Nil(t, NewPriority(0).Validate())
+	require.Nil(t, NewPriority(MAX_PRIORITY).Validate())
+	require.Equal(t, ErrPriorityInvalid, NewPriority(192).Validate())
+	require.Equal(t, ErrPriorityInvalid, NewPriority(-1).Validate())
+
+	require.Equal(
+		t,
+		ErrPriorityInvalid,
+		(&Priority{P: 34, F: Facility{Value: 4}, S: Severity{Value: 3}}).Validate(),
+	)
+}
@@ -0,0 +1,18 @@
+package parsercommon
+
+import (
+	"strings"
+)
+
+// Splits tags of the form "daemon/subprogram", as logged by postfix or
+// opensmtpd, ie. "postfix/smtpd" => "postfix", "smtpd". Only the first "/"
+// splits: "postfix/submission/smtpd" => "postfix", "submission/smtpd".
+// ok is false when tag has no "/" with text on both sides.
+func SplitProgram(tag string) (program string, subprogram string, ok bool) {
+	i := strings.IndexByte(tag, '/')
+	if i <= 0 || i == len(tag)-1 {
+		return "", "", false
+	}
+
+	return tag[:i], tag[i+1:], true
+}
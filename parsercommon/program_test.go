@@ -0,0 +1,56 @@
+package parsercommon
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitProgram(t *testing.T) {
+	testCases := []struct {
+		description        string
+		tag                string
+		expectedProgram    string
+		expectedSubprogram string
+		expectedOk         bool
+	}{
+		{
+			description:        "postfix",
+			tag:                "postfix/smtpd",
+			expectedProgram:    "postfix",
+			expectedSubprogram: "smtpd",
+			expectedOk:         true,
+		},
+		{
+			description:        "multi-part",
+			tag:                "postfix/submission/smtpd",
+			expectedProgram:    "postfix",
+			expectedSubprogram: "submission/smtpd",
+			expectedOk:         true,
+		},
+		{
+			description: "no slash",
+			tag:         "sshd",
+		},
+		{
+			description: "leading slash",
+			tag:         "/usr/sbin/cron",
+		},
+		{
+			description: "trailing slash",
+			tag:         "postfix/",
+		},
+		{
+			description: "empty",
+			tag:         "",
+		},
+	}
+
+	for _, tc := range testCases {
+		program, subprogram, ok := SplitProgram(tc.tag)
+
+		require.Equal(t, tc.expectedProgram, program, tc.description)
+		require.Equal(t, tc.expectedSubprogram, subprogram, tc.description)
+		require.Equal(t, tc.expectedOk, ok, tc.description)
+	}
+}
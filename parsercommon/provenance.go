@@ -0,0 +1,27 @@
+package parsercommon
+
+// Origin of field values, reported by parsers in the "provenance" key when
+// enabled
+const (
+	// read from the message
+	PROVENANCE_PARSED = "parsed"
+
+	// given with a With* option
+	PROVENANCE_FORCED = "forced"
+
+	// absent from the message format, a default value is used
+	PROVENANCE_DEFAULT = "default"
+
+	// partly or fully computed from other values or from the context, ie. the
+	// year of RFC 3164 timestamps
+	PROVENANCE_INFERRED = "inferred"
+)
+
+// Returns PROVENANCE_FORCED when forced, PROVENANCE_PARSED otherwise
+func ParsedOrForced(forced bool) string {
+	if forced {
+		return PROVENANCE_FORCED
+	}
+
+	return PROVENANCE_PARSED
+}
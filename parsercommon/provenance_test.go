@@ -0,0 +1,12 @@
+package parsercommon
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsedOrForced(t *testing.T) {
+	require.Equal(t, PROVENANCE_PARSED, ParsedOrForced(false))
+	require.Equal(t, PROVENANCE_FORCED, ParsedOrForced(true))
+}
@@ -0,0 +1,18 @@
+package parsercommon
+
+// [Start, End) offsets in the parsed buffer of the bytes a field was parsed
+// from, ie. for relays forwarding them untouched. Start equals End for fields
+// absent from the message.
+type Span struct {
+	Start int
+	End   int
+}
+
+func (s Span) Len() int {
+	return s.End - s.Start
+}
+
+// Returns buff[s.Start:s.End], sharing the memory of buff
+func (s Span) Bytes(buff []byte) []byte {
+	return buff[s.Start:s.End]
+}
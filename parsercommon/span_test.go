@@ -0,0 +1,19 @@
+package parsercommon
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpan(t *testing.T) {
+	buff := []byte("<34>Oct 11 22:14:15 mymachine su: hello")
+
+	s := Span{Start: 34, End: 39}
+	require.Equal(t, 5, s.Len())
+	require.Equal(t, []byte("hello"), s.Bytes(buff))
+
+	s = Span{Start: 39, End: 39}
+	require.Equal(t, 0, s.Len())
+	require.Empty(t, s.Bytes(buff))
+}
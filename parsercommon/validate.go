@@ -0,0 +1,104 @@
+package parsercommon
+
+import (
+	"time"
+)
+
+var (
+	ErrMissingKey   = &ParserError{ErrorString: "Missing key"}
+	ErrInvalidType  = &ParserError{ErrorString: "Invalid type"}
+	ErrInvalidValue = &ParserError{ErrorString: "Invalid value"}
+)
+
+// Keys every parser sets
+var REQUIRED_KEYS = []string{
+	"priority",
+	"facility",
+	"severity",
+	"version",
+	"timestamp",
+	"hostname",
+}
+
+type ValidationError struct {
+	Key string
+	Err error
+}
+
+func (err *ValidationError) Error() string {
+	return err.Err.Error() + ": " + err.Key
+}
+
+func (err *ValidationError) Unwrap() error {
+	return err.Err
+}
+
+// Checks parts against the schema described by SCHEMA_KEYS. Required keys
+// must be present, schema keys must have the documented type and
+// priority, facility and severity must be consistent. Keys which are not
+// part of the schema are not checked.
+func Validate(parts LogParts) error {
+	for _, k := range REQUIRED_KEYS {
+		if _, ok := parts[k]; !ok {
+			return &ValidationError{Key: k, Err: ErrMissingKey}
+		}
+	}
+
+	for _, k := range SCHEMA_KEYS {
+		v, ok := parts[k]
+		if !ok {
+			continue
+		}
+
+		err := validateType(k, v)
+		if err != nil {
+			return &ValidationError{Key: k, Err: err}
+		}
+	}
+
+	return validatePriority(parts)
+}
+
+func validateType(key string, v interface{}) error {
+	switch key {
+	case "priority", "facility", "severity", "version":
+		if _, ok := v.(int); !ok {
+			return ErrInvalidType
+		}
+	case "timestamp":
+		if _, ok := v.(time.Time); !ok {
+			return ErrInvalidType
+		}
+	default:
+		if _, ok := v.(string); !ok {
+			return ErrInvalidType
+		}
+	}
+
+	return nil
+}
+
+func validatePriority(parts LogParts) error {
+	p := parts["priority"].(int)
+	f := parts["facility"].(int)
+	s := parts["severity"].(int)
+
+	if f < 0 || f > 23 {
+		return &ValidationError{Key: "facility", Err: ErrInvalidValue}
+	}
+
+	if s < 0 || s > 7 {
+		return &ValidationError{Key: "severity", Err: ErrInvalidValue}
+	}
+
+	if p != f*8+s {
+		return &ValidationError{Key: "priority", Err: ErrInvalidValue}
+	}
+
+	v := parts["version"].(int)
+	if v != NO_VERSION && v < 1 {
+		return &ValidationError{Key: "version", Err: ErrInvalidValue}
+	}
+
+	return nil
+}
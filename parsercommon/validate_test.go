@@ -0,0 +1,124 @@
+package parsercommon
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func validParts() LogParts {
+	return LogParts{
+		"priority":  34,
+		"facility":  4,
+		"severity":  2,
+		"version":   NO_VERSION,
+		"timestamp": time.Date(2003, time.October, 11, 22, 14, 15, 0, time.UTC),
+		"hostname":  "mymachine",
+		"tag":       "su",
+		"content":   "'su root' failed for lonvick on /dev/pts/8",
+	}
+}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		description string
+		change      func(parts LogParts)
+		expectedKey string
+		expectedErr error
+	}{
+		{
+			description: "valid",
+			change:      func(parts LogParts) {},
+		},
+		{
+			description: "extra keys are not checked",
+			change: func(parts LogParts) {
+				parts["geoip"] = map[string]string{"country": "FR"}
+			},
+		},
+		{
+			description: "missing hostname",
+			change: func(parts LogParts) {
+				delete(parts, "hostname")
+			},
+			expectedKey: "hostname",
+			expectedErr: ErrMissingKey,
+		},
+		{
+			description: "string priority",
+			change: func(parts LogParts) {
+				parts["priority"] = "34"
+			},
+			expectedKey: "priority",
+			expectedErr: ErrInvalidType,
+		},
+		{
+			description: "string timestamp",
+			change: func(parts LogParts) {
+				parts["timestamp"] = "2003-10-11T22:14:15Z"
+			},
+			expectedKey: "timestamp",
+			expectedErr: ErrInvalidType,
+		},
+		{
+			description: "byte slice content",
+			change: func(parts LogParts) {
+				parts["content"] = []byte("foo")
+			},
+			expectedKey: "content",
+			expectedErr: ErrInvalidType,
+		},
+		{
+			description: "facility out of range",
+			change: func(parts LogParts) {
+				parts["facility"] = 24
+				parts["priority"] = 24*8 + 2
+			},
+			expectedKey: "facility",
+			expectedErr: ErrInvalidValue,
+		},
+		{
+			description: "severity out of range",
+			change: func(parts LogParts) {
+				parts["severity"] = -1
+			},
+			expectedKey: "severity",
+			expectedErr: ErrInvalidValue,
+		},
+		{
+			description: "inconsistent priority",
+			change: func(parts LogParts) {
+				parts["severity"] = 3
+			},
+			expectedKey: "priority",
+			expectedErr: ErrInvalidValue,
+		},
+		{
+			description: "invalid version",
+			change: func(parts LogParts) {
+				parts["version"] = 0
+			},
+			expectedKey: "version",
+			expectedErr: ErrInvalidValue,
+		},
+	}
+
+	for _, tc := range testCases {
+		parts := validParts()
+		tc.change(parts)
+
+		err := Validate(parts)
+
+		if tc.expectedErr == nil {
+			require.Nil(t, err, tc.description)
+			continue
+		}
+
+		verr, ok := err.(*ValidationError)
+		require.True(t, ok, tc.description)
+		require.Equal(t, tc.expectedKey, verr.Key, tc.description)
+		require.True(t, errors.Is(err, tc.expectedErr), tc.description)
+	}
+}
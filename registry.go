@@ -0,0 +1,144 @@
+package syslogparser
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/jeromer/syslogparser/rfc3164"
+	"github.com/jeromer/syslogparser/rfc5424"
+)
+
+var (
+	ErrInvalidDialect = errors.New("Invalid dialect")
+	ErrDialectExists  = errors.New("Dialect already registered")
+	ErrUnknownDialect = errors.New("Unknown dialect")
+)
+
+// Parser of a vendor specific format, registered under Name
+type Dialect struct {
+	Name string
+
+	// returns true when buff is formatted with the dialect. Dialects without
+	// Detect are never auto detected, only built by name.
+	Detect func(buff []byte) bool
+
+	// returns a parser ready to Parse() buff
+	New func(buff []byte) LogParser
+}
+
+// Dialects tried before the built-in RFC parsers. A Registry is safe for
+// concurrent use, dialects are tried in registration order.
+type Registry struct {
+	mu       sync.RWMutex
+	dialects []Dialect
+}
+
+// Registry used by Register()
+var DefaultRegistry = NewRegistry()
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Registers d in DefaultRegistry, usually from the init() of the package
+// providing the dialect
+func Register(d Dialect) error {
+	return DefaultRegistry.Register(d)
+}
+
+// ErrDialectExists is returned when the name is already used, including by
+// the built-in "rfc3164" and "rfc5424" parsers.
+func (r *Registry) Register(d Dialect) error {
+	if d.Name == "" || d.New == nil {
+		return ErrInvalidDialect
+	}
+
+	if d.Name == rfc3164.PARSER_NAME || d.Name == rfc5424.PARSER_NAME {
+		return ErrDialectExists
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, registered := range r.dialects {
+		if registered.Name == d.Name {
+			return ErrDialectExists
+		}
+	}
+
+	r.dialects = append(r.dialects, d)
+
+	return nil
+}
+
+func (r *Registry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, d := range r.dialects {
+		if d.Name == name {
+			r.dialects = append(r.dialects[:i:i], r.dialects[i+1:]...)
+			return
+		}
+	}
+}
+
+// Names of the registered dialects, in registration order
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, len(r.dialects))
+	for i, d := range r.dialects {
+		names[i] = d.Name
+	}
+
+	return names
+}
+
+// Returns the parser of the dialect called name for buff, "rfc3164" and
+// "rfc5424" being the built-in parsers
+func (r *Registry) Parser(name string, buff []byte) (LogParser, error) {
+	switch name {
+	case rfc3164.PARSER_NAME:
+		return rfc3164.NewParser(buff), nil
+	case rfc5424.PARSER_NAME:
+		return rfc5424.NewParser(buff), nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, d := range r.dialects {
+		if d.Name == name {
+			return d.New(buff), nil
+		}
+	}
+
+	return nil, ErrUnknownDialect
+}
+
+// Same as NewAutoParser() trying registered dialects first. Returns the name
+// of the dialect which detected buff, or of the built-in parser used.
+func (r *Registry) NewAutoParser(buff []byte) (LogParser, string, error) {
+	r.mu.RLock()
+	dialects := r.dialects
+	r.mu.RUnlock()
+
+	for _, d := range dialects {
+		if d.Detect != nil && d.Detect(buff) {
+			return d.New(buff), d.Name, nil
+		}
+	}
+
+	p, rfc, err := NewAutoParser(buff)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if rfc == RFC_5424 {
+		return p, rfc5424.PARSER_NAME, nil
+	}
+
+	return p, rfc3164.PARSER_NAME, nil
+}
@@ -0,0 +1,155 @@
+package syslogparser
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jeromer/syslogparser/rfc3164"
+	"github.com/jeromer/syslogparser/rfc5424"
+	"github.com/stretchr/testify/require"
+)
+
+// "ACME: <message>"
+type acmeParser struct {
+	buff []byte
+}
+
+func (p *acmeParser) Parse() error {
+	return nil
+}
+
+func (p *acmeParser) Dump() LogParts {
+	return LogParts{
+		"message": string(bytes.TrimPrefix(p.buff, []byte("ACME: "))),
+	}
+}
+
+func (p *acmeParser) WithTimestampFormat(string)  {}
+func (p *acmeParser) WithLocation(*time.Location) {}
+func (p *acmeParser) WithHostname(string)         {}
+func (p *acmeParser) WithTag(string)              {}
+
+var acme = Dialect{
+	Name: "acme",
+	Detect: func(buff []byte) bool {
+		return bytes.HasPrefix(buff, []byte("ACME: "))
+	},
+	New: func(buff []byte) LogParser {
+		return &acmeParser{buff: buff}
+	},
+}
+
+func TestRegistryRegister(t *testing.T) {
+	r := NewRegistry()
+
+	require.Equal(t, ErrInvalidDialect, r.Register(Dialect{Name: "acme"}))
+	require.Equal(t, ErrInvalidDialect, r.Register(Dialect{New: acme.New}))
+	require.Equal(t, ErrDialectExists, r.Register(Dialect{Name: "rfc3164", New: acme.New}))
+
+	require.Nil(t, r.Register(acme))
+	require.Equal(t, ErrDialectExists, r.Register(acme))
+
+	require.Nil(t, r.Register(Dialect{Name: "other", New: acme.New}))
+	require.Equal(t, []string{"acme", "other"}, r.Names())
+
+	r.Unregister("acme")
+	require.Equal(t, []string{"other"}, r.Names())
+
+	r.Unregister("unknown")
+	require.Equal(t, []string{"other"}, r.Names())
+}
+
+func TestRegistryParser(t *testing.T) {
+	r := NewRegistry()
+	require.Nil(t, r.Register(acme))
+
+	buff := []byte("ACME: hello")
+
+	p, err := r.Parser("acme", buff)
+	require.Nil(t, err)
+	require.Equal(t, "hello", p.Dump()["message"])
+
+	p, err = r.Parser("rfc3164", buff)
+	require.Nil(t, err)
+	require.IsType(t, &rfc3164.Parser{}, p)
+
+	p, err = r.Parser("rfc5424", buff)
+	require.Nil(t, err)
+	require.IsType(t, &rfc5424.Parser{}, p)
+
+	p, err = r.Parser("unknown", buff)
+	require.Equal(t, ErrUnknownDialect, err)
+	require.Nil(t, p)
+}
+
+func TestRegistryNewAutoParser(t *testing.T) {
+	r := NewRegistry()
+	require.Nil(t, r.Register(acme))
+
+	testCases := []struct {
+		description  string
+		input        string
+		expectedName string
+		expectedErr  error
+	}{
+		{
+			description:  "dialect",
+			input:        "ACME: hello",
+			expectedName: "acme",
+		},
+		{
+			description:  "rfc3164",
+			input:        "<34>Oct 11 22:14:15 mymachine su: 'su root' failed",
+			expectedName: rfc3164.PARSER_NAME,
+		},
+		{
+			description:  "rfc5424",
+			input:        "<165>1 2003-10-11T22:14:15.003Z mymachine su - ID47 - msg",
+			expectedName: rfc5424.PARSER_NAME,
+		},
+		{
+			description: "unknown",
+			input:       "hello world",
+			expectedErr: ErrUnknownRFC,
+		},
+	}
+
+	for _, tc := range testCases {
+		p, name, err := r.NewAutoParser([]byte(tc.input))
+
+		require.Equal(t, tc.expectedErr, err, tc.description)
+		require.Equal(t, tc.expectedName, name, tc.description)
+
+		if tc.expectedErr != nil {
+			continue
+		}
+
+		require.Nil(t, p.Parse(), tc.description)
+	}
+}
+
+func TestRegistryConcurrency(t *testing.T) {
+	r := NewRegistry()
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			if i%2 == 0 {
+				_ = r.Register(acme)
+				r.Unregister(acme.Name)
+				return
+			}
+
+			_, _, _ = r.NewAutoParser([]byte("ACME: hello"))
+		}(i)
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,183 @@
+// Package replay runs two parser configurations over the same corpus and
+// reports field level differences, so behavior changes can be reviewed
+// before being rolled out.
+package replay
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/jeromer/syslogparser"
+)
+
+// Parses a single message, usually by configuring and running a parser
+type ParseFunc func(buff []byte) (syslogparser.LogParts, error)
+
+type FieldDiff struct {
+	Key string
+	A   interface{}
+	B   interface{}
+	InA bool
+	InB bool
+}
+
+type Diff struct {
+	Index  int
+	Input  []byte
+	ErrA   error
+	ErrB   error
+	Fields []FieldDiff
+}
+
+type Report struct {
+	Total     int
+	Identical int
+	Diffs     []Diff
+}
+
+// Parses every message of corpus with a and b and reports differences
+func Compare(corpus [][]byte, a ParseFunc, b ParseFunc) *Report {
+	r := &Report{}
+
+	for i, buff := range corpus {
+		r.add(i, buff, a, b)
+	}
+
+	return r
+}
+
+// Same as Compare() using every line of rd as a message
+func CompareReader(rd io.Reader, a ParseFunc, b ParseFunc) (*Report, error) {
+	r := &Report{}
+	s := bufio.NewScanner(rd)
+
+	for i := 0; s.Scan(); i++ {
+		buff := append([]byte(nil), s.Bytes()...)
+		r.add(i, buff, a, b)
+	}
+
+	return r, s.Err()
+}
+
+func (r *Report) add(i int, buff []byte, a ParseFunc, b ParseFunc) {
+	r.Total++
+
+	d, same := diff(i, buff, a, b)
+	if same {
+		r.Identical++
+		return
+	}
+
+	r.Diffs = append(r.Diffs, d)
+}
+
+// Writes a human readable version of the report
+func (r *Report) Write(w io.Writer) error {
+	for _, d := range r.Diffs {
+		_, err := fmt.Fprintf(w, "#%d %q\n", d.Index, d.Input)
+		if err != nil {
+			return err
+		}
+
+		if !sameError(d.ErrA, d.ErrB) {
+			_, err = fmt.Fprintf(w, "\terror: %v => %v\n", d.ErrA, d.ErrB)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, f := range d.Fields {
+			_, err = fmt.Fprintf(
+				w, "\t%s: %s => %s\n", f.Key, value(f.A, f.InA), value(f.B, f.InB),
+			)
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err := fmt.Fprintf(
+		w, "%d messages, %d identical, %d different\n",
+		r.Total, r.Identical, len(r.Diffs),
+	)
+
+	return err
+}
+
+func diff(i int, buff []byte, a ParseFunc, b ParseFunc) (Diff, bool) {
+	partsA, errA := a(buff)
+	partsB, errB := b(buff)
+
+	d := Diff{
+		Index: i,
+		Input: buff,
+		ErrA:  errA,
+		ErrB:  errB,
+	}
+
+	keys := make(map[string]struct{})
+	for k := range partsA {
+		keys[k] = struct{}{}
+	}
+
+	for k := range partsB {
+		keys[k] = struct{}{}
+	}
+
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+
+	sort.Strings(sorted)
+
+	for _, k := range sorted {
+		va, inA := partsA[k]
+		vb, inB := partsB[k]
+
+		if inA == inB && sameValue(va, vb) {
+			continue
+		}
+
+		d.Fields = append(d.Fields, FieldDiff{
+			Key: k,
+			A:   va,
+			B:   vb,
+			InA: inA,
+			InB: inB,
+		})
+	}
+
+	return d, len(d.Fields) == 0 && sameError(errA, errB)
+}
+
+func sameValue(a interface{}, b interface{}) bool {
+	ta, ok := a.(time.Time)
+	if ok {
+		tb, ok := b.(time.Time)
+		return ok && ta.Equal(tb)
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+func sameError(a error, b error) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return a.Error() == b.Error()
+}
+
+func value(v interface{}, present bool) string {
+	if !present {
+		return "<missing>"
+	}
+
+	return fmt.Sprintf("%#v", v)
+}
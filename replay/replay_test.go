@@ -0,0 +1,123 @@
+package replay
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/jeromer/syslogparser"
+	"github.com/jeromer/syslogparser/rfc3164"
+	"github.com/stretchr/testify/require"
+)
+
+func parser(configure func(p *rfc3164.Parser)) ParseFunc {
+	return func(buff []byte) (syslogparser.LogParts, error) {
+		p := rfc3164.NewParser(buff)
+		configure(p)
+
+		err := p.Parse()
+		if err != nil {
+			return nil, err
+		}
+
+		return p.Dump(), nil
+	}
+}
+
+func TestCompare(t *testing.T) {
+	corpus := [][]byte{
+		[]byte("<30>Jun 23 13:17:42 myhost chronyd[1119]: Selected source 192.168.65.1"),
+		[]byte("<30>Jun 23 13:17:42 - chronyd[1119]: Selected source 192.168.65.1"),
+		[]byte("<30>Jun 34 13:17:42 - chronyd[1119]: Selected source 192.168.65.1"),
+	}
+
+	a := parser(func(p *rfc3164.Parser) {})
+	b := parser(func(p *rfc3164.Parser) {
+		p.WithDashAsEmptyHostname()
+		p.WithDiagnostics()
+	})
+
+	r := Compare(corpus, a, a)
+	require.Equal(t, 3, r.Total)
+	require.Equal(t, 3, r.Identical)
+	require.Empty(t, r.Diffs)
+
+	r = Compare(corpus, a, b)
+	require.Equal(t, 3, r.Total)
+	require.Equal(t, 1, r.Identical)
+	require.Len(t, r.Diffs, 2)
+
+	require.Equal(t, 0, r.Diffs[0].Index)
+	require.Equal(t, []string{"parse_duration", "parser"}, keys(r.Diffs[0]))
+
+	require.Equal(t, 1, r.Diffs[1].Index)
+	require.Equal(t, []string{"hostname", "parse_duration", "parser"}, keys(r.Diffs[1]))
+	require.Equal(
+		t,
+		FieldDiff{Key: "hostname", A: "-", B: "", InA: true, InB: true},
+		r.Diffs[1].Fields[0],
+	)
+
+	require.Equal(
+		t,
+		FieldDiff{Key: "parser", A: nil, B: rfc3164.PARSER_NAME, InA: false, InB: true},
+		r.Diffs[1].Fields[2],
+	)
+}
+
+func TestCompareErrors(t *testing.T) {
+	corpus := [][]byte{
+		[]byte("<30>Jun 23 13:17:42 myhost chronyd[1119]: Selected source 192.168.65.1"),
+	}
+
+	a := parser(func(p *rfc3164.Parser) {})
+	b := parser(func(p *rfc3164.Parser) {
+		p.WithTimestampFormat("2006-01-02T15:04:05")
+	})
+
+	r := Compare(corpus, a, b)
+	require.Len(t, r.Diffs, 1)
+	require.Nil(t, r.Diffs[0].ErrA)
+	require.NotNil(t, r.Diffs[0].ErrB)
+}
+
+func TestCompareReader(t *testing.T) {
+	corpus := strings.Join(
+		[]string{
+			"<30>Jun 23 13:17:42 myhost chronyd[1119]: Selected source 192.168.65.1",
+			"<30>Jun 23 13:17:42 - chronyd[1119]: Selected source 192.168.65.1",
+		},
+		"\n",
+	)
+
+	a := parser(func(p *rfc3164.Parser) {})
+	b := parser(func(p *rfc3164.Parser) {
+		p.WithDashAsEmptyHostname()
+	})
+
+	r, err := CompareReader(strings.NewReader(corpus), a, b)
+	require.Nil(t, err)
+	require.Equal(t, 2, r.Total)
+	require.Equal(t, 1, r.Identical)
+
+	out := new(bytes.Buffer)
+	err = r.Write(out)
+	require.Nil(t, err)
+
+	require.Equal(
+		t,
+		"#1 \"<30>Jun 23 13:17:42 - chronyd[1119]: Selected source 192.168.65.1\"\n"+
+			"\thostname: \"-\" => \"\"\n"+
+			"2 messages, 1 identical, 1 different\n",
+		out.String(),
+	)
+}
+
+func keys(d Diff) []string {
+	var k []string
+	for _, f := range d.Fields {
+		k = append(k, f.Key)
+	}
+
+	return k
+}
@@ -0,0 +1,57 @@
+package rfc3164
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+)
+
+const (
+	// digits of UNIX timestamps in seconds and milliseconds, ie. 1697056455
+	// and 1697056455123
+	EPOCH_SECONDS_LEN      = 10
+	EPOCH_MILLISECONDS_LEN = 13
+)
+
+// Accepts UNIX timestamps in place of the RFC 3164 one, as sent by some
+// embedded devices: "<34>1697056455 mymachine su: ...". Timestamps of
+// EPOCH_SECONDS_LEN digits are seconds, EPOCH_MILLISECONDS_LEN digits ones
+// milliseconds. They are reported in the location set with WithLocation().
+// WithStrict() takes precedence.
+func (p *Parser) WithEpochTimestamp() {
+	p.epochTimestamp = true
+}
+
+// Parses the UNIX timestamp at from, ok is false when there is none
+func (p *Parser) parseEpochTimestamp(from int) (ts time.Time, ok bool) {
+	rest := p.cursor.Slice(from, p.cursor.Len())
+
+	n := 0
+	for n < len(rest) && parsercommon.IsDigit(rest[n]) {
+		n++
+	}
+
+	if n < len(rest) && rest[n] != ' ' {
+		return ts, false
+	}
+
+	if n != EPOCH_SECONDS_LEN && n != EPOCH_MILLISECONDS_LEN {
+		return ts, false
+	}
+
+	v, err := strconv.ParseInt(string(rest[:n]), 10, 64)
+	if err != nil {
+		return ts, false
+	}
+
+	if n == EPOCH_SECONDS_LEN {
+		ts = time.Unix(v, 0)
+	} else {
+		ts = time.Unix(v/1000, (v%1000)*int64(time.Millisecond))
+	}
+
+	p.cursor.SetPos(from)
+
+	return p.lenientTimestampFound(ts.In(p.location), rest[:n]), true
+}
@@ -0,0 +1,90 @@
+package rfc3164
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWithEpochTimestamp(t *testing.T) {
+	testCases := []struct {
+		description       string
+		input             string
+		expectedTimestamp time.Time
+		expectedHostname  string
+		expectedErr       error
+	}{
+		{
+			description:       "seconds",
+			input:             "<34>1697056455 mymachine su: 'su root' failed",
+			expectedTimestamp: time.Date(2023, time.October, 11, 20, 34, 15, 0, time.UTC),
+			expectedHostname:  "mymachine",
+		},
+		{
+			description:       "milliseconds",
+			input:             "<34>1697056455123 mymachine su: 'su root' failed",
+			expectedTimestamp: time.Date(2023, time.October, 11, 20, 34, 15, 123*1000*1000, time.UTC),
+			expectedHostname:  "mymachine",
+		},
+		{
+			description:       "RFC 3164 timestamp",
+			input:             "<34>Oct 11 22:14:15 mymachine su: 'su root' failed",
+			expectedTimestamp: time.Date(time.Now().Year(), time.October, 11, 22, 14, 15, 0, time.UTC),
+			expectedHostname:  "mymachine",
+		},
+		{
+			description: "too short",
+			input:       "<34>169705645 mymachine su: 'su root' failed",
+			expectedErr: parsercommon.ErrTimestampUnknownFormat,
+		},
+		{
+			description: "followed by a letter",
+			input:       "<34>1697056455x mymachine su: 'su root' failed",
+			expectedErr: parsercommon.ErrTimestampUnknownFormat,
+		},
+	}
+
+	for _, tc := range testCases {
+		p := NewParser([]byte(tc.input))
+		p.WithEpochTimestamp()
+		p.WithRawTimestamp()
+
+		err := p.Parse()
+		if tc.expectedErr != nil {
+			require.ErrorIs(t, err, tc.expectedErr, tc.description)
+			continue
+		}
+
+		require.Nil(t, err, tc.description)
+
+		obtained := p.Dump()
+		require.True(t, tc.expectedTimestamp.Equal(obtained["timestamp"].(time.Time)), tc.description)
+		require.Equal(t, tc.expectedHostname, obtained["hostname"], tc.description)
+		require.Equal(t, "su", obtained["tag"], tc.description)
+	}
+}
+
+func TestParseEpochTimestampLocation(t *testing.T) {
+	loc := time.FixedZone("+02:00", 2*60*60)
+
+	p := NewParser([]byte("<34>1697056455 mymachine su: 'su root' failed"))
+	p.WithEpochTimestamp()
+	p.WithLocation(loc)
+	p.WithRawTimestamp()
+
+	err := p.Parse()
+	require.Nil(t, err)
+
+	obtained := p.Dump()
+	require.Equal(t, time.Date(2023, time.October, 11, 22, 34, 15, 0, loc), obtained["timestamp"])
+	require.Equal(t, "1697056455", obtained["timestamp_raw"])
+}
+
+func TestParseEpochTimestampWithoutOption(t *testing.T) {
+	p := NewParser([]byte("<34>1697056455 mymachine su: 'su root' failed"))
+
+	err := p.Parse()
+	require.ErrorIs(t, err, parsercommon.ErrTimestampUnknownFormat)
+}
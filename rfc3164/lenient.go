@@ -0,0 +1,111 @@
+package rfc3164
+
+import (
+	"bytes"
+	"strings"
+	"time"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+)
+
+// Tried after the RFC 3164 formats by WithLenient(), the year being given
+var lenientTimestampFormats = []string{
+	"Jan _2 2006 15:04:05",
+	"2006-01-02 15:04:05",
+}
+
+// Tolerates the deviations commonly found in the wild, with sane defaults,
+// instead of having to enable them one by one:
+//   - priorities above 191 and missing PRI, see WithLenientPriority() and
+//     WithDefaultPriority()
+//   - hostname before the timestamp, see WithSwappedHeader()
+//   - missing, "-" or overlong hostname, see WithHostnameHeuristic(),
+//     WithDashAsEmptyHostname() and WithLongHostname()
+//   - RFC3339 timestamps, timestamps with a year ("Oct 11 2003 22:14:15",
+//     "2003-10-11 22:14:15") and missing timestamps, reported as a zero
+//     time.Time
+//
+// WithStrict() takes precedence.
+func (p *Parser) WithLenient() {
+	p.WithLenientPriority()
+	p.WithDefaultPriority()
+	p.WithSwappedHeader()
+	p.WithHostnameHeuristic()
+	p.WithDashAsEmptyHostname()
+	p.WithLongHostname()
+
+	p.lenientTimestamp = true
+}
+
+// Parses the timestamp at from, which is not an RFC 3164 one. A zero
+// time.Time is returned, and the cursor left at from, when there is none.
+func (p *Parser) parseLenientTimestamp(from int) time.Time {
+	p.cursor.SetPos(from)
+
+	rest := p.cursor.Rest()
+
+	for _, tsFmt := range lenientTimestampFormats {
+		if len(rest) < len(tsFmt) {
+			continue
+		}
+
+		ts, err := time.ParseInLocation(tsFmt, string(rest[:len(tsFmt)]), p.location)
+		if err == nil {
+			n := len(tsFmt) + secFracLen(rest[len(tsFmt):])
+
+			ts, err = time.ParseInLocation(
+				tsFmt+".999999999", commaToDot(rest[:n]), p.location,
+			)
+			if err == nil {
+				return p.lenientTimestampFound(ts, rest[:n])
+			}
+		}
+	}
+
+	word := rest
+	if end := bytes.IndexByte(rest, ' '); end >= 0 {
+		word = rest[:end]
+	}
+
+	ts, err := time.Parse(time.RFC3339Nano, commaToDot(word))
+	if err == nil {
+		return p.lenientTimestampFound(ts, word)
+	}
+
+	return time.Time{}
+}
+
+func (p *Parser) lenientTimestampFound(ts time.Time, text []byte) time.Time {
+	p.hasTimestamp = true
+	p.timestampText = text
+	p.setSpan("timestamp", p.cursor.Pos(), p.cursor.Pos()+len(text))
+
+	p.cursor.Advance(len(text))
+	p.cursor.Expect(' ')
+
+	return ts
+}
+
+// Length of the fraction of second starting b, ie. ".003" or ",003", 0 when
+// there is none
+func secFracLen(b []byte) int {
+	if len(b) < 2 || (b[0] != '.' && b[0] != ',') {
+		return 0
+	}
+
+	n := 1
+	for n < len(b) && n <= 9 && parsercommon.IsDigit(b[n]) {
+		n++
+	}
+
+	if n == 1 {
+		return 0
+	}
+
+	return n
+}
+
+// XXX : some European firmwares separate the fraction of second with a ','
+func commaToDot(b []byte) string {
+	return strings.Replace(string(b), ",", ".", 1)
+}
@@ -0,0 +1,147 @@
+package rfc3164
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWithLenient(t *testing.T) {
+	testCases := []struct {
+		description       string
+		input             string
+		expectedTimestamp time.Time
+		expectedHostname  string
+		expectedTag       string
+		expectedContent   string
+	}{
+		{
+			description:       "rfc3164",
+			input:             "<34>Oct 11 22:14:15 mymachine su: ok",
+			expectedTimestamp: time.Date(time.Now().Year(), time.October, 11, 22, 14, 15, 0, time.UTC),
+			expectedHostname:  "mymachine",
+			expectedTag:       "su",
+			expectedContent:   "ok",
+		},
+		{
+			description:       "rfc3339 timestamp",
+			input:             "<34>2003-10-11T22:14:15.003+02:00 mymachine su: ok",
+			expectedTimestamp: time.Date(2003, time.October, 11, 22, 14, 15, 3*1000*1000, time.FixedZone("", 2*60*60)),
+			expectedHostname:  "mymachine",
+			expectedTag:       "su",
+			expectedContent:   "ok",
+		},
+		{
+			description:       "rfc3339 timestamp with comma",
+			input:             "<34>2003-10-11T22:14:15,003Z mymachine su: ok",
+			expectedTimestamp: time.Date(2003, time.October, 11, 22, 14, 15, 3*1000*1000, time.UTC),
+			expectedHostname:  "mymachine",
+			expectedTag:       "su",
+			expectedContent:   "ok",
+		},
+		{
+			description:       "timestamp with year",
+			input:             "<34>Oct 11 2003 22:14:15 mymachine su: ok",
+			expectedTimestamp: time.Date(2003, time.October, 11, 22, 14, 15, 0, time.UTC),
+			expectedHostname:  "mymachine",
+			expectedTag:       "su",
+			expectedContent:   "ok",
+		},
+		{
+			description:       "numeric date",
+			input:             "<34>2003-10-11 22:14:15 mymachine su: ok",
+			expectedTimestamp: time.Date(2003, time.October, 11, 22, 14, 15, 0, time.UTC),
+			expectedHostname:  "mymachine",
+			expectedTag:       "su",
+			expectedContent:   "ok",
+		},
+		{
+			description:       "numeric date with comma",
+			input:             "<34>2003-10-11 22:14:15,003 mymachine su: ok",
+			expectedTimestamp: time.Date(2003, time.October, 11, 22, 14, 15, 3*1000*1000, time.UTC),
+			expectedHostname:  "mymachine",
+			expectedTag:       "su",
+			expectedContent:   "ok",
+		},
+		{
+			description:       "timestamp with year and ms",
+			input:             "<34>Oct 11 2003 22:14:15.003 mymachine su: ok",
+			expectedTimestamp: time.Date(2003, time.October, 11, 22, 14, 15, 3*1000*1000, time.UTC),
+			expectedHostname:  "mymachine",
+			expectedTag:       "su",
+			expectedContent:   "ok",
+		},
+		{
+			description:      "missing timestamp",
+			input:            "<34>mymachine su: ok",
+			expectedHostname: "mymachine",
+			expectedTag:      "su",
+			expectedContent:  "ok",
+		},
+		{
+			description:      "missing timestamp and hostname",
+			input:            "<34>su: ok",
+			expectedHostname: "",
+			expectedTag:      "su",
+			expectedContent:  "ok",
+		},
+		{
+			description:       "missing priority and hostname",
+			input:             "Oct 11 22:14:15 su[42]: ok",
+			expectedTimestamp: time.Date(time.Now().Year(), time.October, 11, 22, 14, 15, 0, time.UTC),
+			expectedHostname:  "",
+			expectedTag:       "su",
+			expectedContent:   "ok",
+		},
+		{
+			description:       "swapped header",
+			input:             "<34>mymachine Oct 11 22:14:15 su: ok",
+			expectedTimestamp: time.Date(time.Now().Year(), time.October, 11, 22, 14, 15, 0, time.UTC),
+			expectedHostname:  "mymachine",
+			expectedTag:       "su",
+			expectedContent:   "ok",
+		},
+		{
+			description:       "dash hostname",
+			input:             "<34>Oct 11 22:14:15 - su: ok",
+			expectedTimestamp: time.Date(time.Now().Year(), time.October, 11, 22, 14, 15, 0, time.UTC),
+			expectedHostname:  "",
+			expectedTag:       "su",
+			expectedContent:   "ok",
+		},
+	}
+
+	for _, tc := range testCases {
+		p := NewParser([]byte(tc.input))
+		p.WithLenient()
+
+		err := p.Parse()
+		require.Nil(t, err, tc.description)
+
+		obtained := p.Dump()
+		require.Equal(t, tc.expectedTimestamp, obtained["timestamp"], tc.description)
+		require.Equal(t, tc.expectedHostname, obtained["hostname"], tc.description)
+		require.Equal(t, tc.expectedTag, obtained["tag"], tc.description)
+		require.Equal(t, tc.expectedContent, obtained["content"], tc.description)
+	}
+}
+
+func TestParseWithLenientTimestampPresence(t *testing.T) {
+	p := NewParser([]byte("<34>mymachine su: ok"))
+	p.WithLenient()
+	p.WithTimestampPresence()
+
+	err := p.Parse()
+	require.Nil(t, err)
+	require.Equal(t, false, p.Dump()["timestamp_present"])
+}
+
+func TestParseWithLenientAndStrict(t *testing.T) {
+	p := NewParser([]byte("<34>2003-10-11T22:14:15Z mymachine su: ok"))
+	p.WithLenient()
+	p.WithStrict()
+
+	err := p.Parse()
+	require.NotNil(t, err)
+}
@@ -2,10 +2,9 @@ package rfc3164
 
 import (
 	"bytes"
-	"math"
+	"net"
 	"time"
 
-	"github.com/jeromer/syslogparser"
 	"github.com/jeromer/syslogparser/parsercommon"
 )
 
@@ -14,12 +13,23 @@ const (
 	// "The total length of the packet MUST be 1024 bytes or less"
 	// However we will accept a bit more while protecting from exhaustion
 	MAX_PACKET_LEN = 2048
+
+	// reported as "parser" when diagnostics are enabled
+	PARSER_NAME = "rfc3164"
+
+	// reported as "rfc" by WithRaw()
+	RFC_NUMBER = 3164
+
+	// https://tools.ietf.org/html/rfc3164#section-4.1.3
+	MAX_TAG_LEN = 32
+
+	// longest tag parsed by default, as the APP-NAME of RFC 5424. Longer
+	// ones are cut, the rest being part of the content.
+	RELAXED_MAX_TAG_LEN = 48
 )
 
 type Parser struct {
-	buff                  []byte
-	cursor                int
-	l                     int
+	cursor                parsercommon.Cursor
 	priority              *parsercommon.Priority
 	version               int
 	header                *header
@@ -27,7 +37,60 @@ type Parser struct {
 	location              *time.Location
 	hostname              string
 	customTag             string
+	noTag                 bool
 	customTimestampFormat string
+	zeroCopy              bool
+	dashHostnameAsEmpty   bool
+	swappedHeader         bool
+	noHostname            bool
+	hostnameHeuristic     bool
+	hostnameMissing       bool
+	hostnameValidation    bool
+	hostnameType          bool
+	longHostname          bool
+	tagLenCheck           bool
+	programSplit          bool
+	kernelOffset          bool
+	bootOffset            time.Duration
+	hasBootOffset         bool
+	cee                   bool
+	ceeFields             map[string]interface{}
+	hasCEE                bool
+	ceeErr                error
+	jsonBody              bool
+	jsonMaxDepth          int
+	jsonMaxSize           int
+	jsonFields            map[string]interface{}
+	hasJSON               bool
+	jsonErr               error
+	diagnostics           bool
+	raw                   bool
+	rawTimestamp          bool
+	timestampPresence     bool
+	hasTimestamp          bool
+	timestampText         []byte
+	names                 bool
+	lenientPriority       bool
+	defaultPriority       bool
+	priorityDefaulted     bool
+	priorityForced        bool
+	provenance            bool
+	strict                bool
+	lenientTimestamp      bool
+	epochTimestamp        bool
+	timestampZone         bool
+	yearInferred          bool
+	payloadParser         parsercommon.PayloadParser
+	keyPolicy             parsercommon.KeyPolicy
+	keyMapper             parsercommon.KeyMapper
+	payload               parsercommon.LogParts
+	payloadErr            error
+	parseDuration         time.Duration
+	spans                 [len(spanFields)]parsercommon.Span
+
+	// field being parsed and its offset
+	field    string
+	fieldPos int
 }
 
 type header struct {
@@ -37,26 +100,30 @@ type header struct {
 
 type message struct {
 	tag     string
+	pid     string
 	content string
 }
 
 func NewParser(buff []byte) *Parser {
+	cursor := parsercommon.NewCursor(buff, MAX_PACKET_LEN)
+	cursor.TrimTrailer()
+
 	return &Parser{
-		buff:     buff,
-		cursor:   0,
+		cursor:   cursor,
 		location: time.UTC,
-		l: int(
-			math.Min(
-				float64(len(buff)),
-				MAX_PACKET_LEN,
-			),
-		),
 	}
 }
 
+// Prepares the parser for a new buffer so it can be reused instead of
+// allocating a new one. Options previously set are discarded.
+func (p *Parser) Reset(buff []byte) {
+	*p = *NewParser(buff)
+}
+
 // Forces a priority for this parser. Priority will not be parsed.
 func (p *Parser) WithPriority(pri *parsercommon.Priority) {
 	p.priority = pri
+	p.priorityForced = pri != nil
 }
 
 // Forces a location. UTC will be used otherwise.
@@ -74,6 +141,13 @@ func (p *Parser) WithTag(t string) {
 	p.customTag = t
 }
 
+// Parses messages without tag, as in "<13>Oct 11 22:14:15 mymachine free
+// form text" sent by some forwarders: tag and pid are empty and everything
+// following the hostname is the content. WithStrict() takes precedence.
+func (p *Parser) WithNoTag() {
+	p.noTag = true
+}
+
 // Forces a given time format.
 // Refer to pkg/time layouts for more informations
 // By default the following formats will be tried in order:
@@ -85,6 +159,194 @@ func (p *Parser) WithTimestampFormat(s string) {
 	p.customTimestampFormat = s
 }
 
+// A bare "-" found where the hostname is expected, as sent by some RFC5424
+// influenced senders, will be considered as no hostname and reported as an
+// empty hostname instead of a literal dash.
+func (p *Parser) WithDashAsEmptyHostname() {
+	p.dashHostnameAsEmpty = true
+}
+
+// Accepts headers where the hostname comes before the timestamp, as in
+// "mymachine Oct 11 22:14:15 su: ...", sent by some devices. Such headers
+// are recognized when the first word is not a month but the second one is.
+func (p *Parser) WithSwappedHeader() {
+	p.swappedHeader = true
+}
+
+// Messages have no hostname, as in "<13>Oct 11 22:14:15 myapp: started". The
+// tag is parsed right after the timestamp and hostname is empty.
+func (p *Parser) WithoutHostname() {
+	p.noHostname = true
+}
+
+// Parses the local format written by glibc syslog(3) to /dev/log,
+// "<PRI>Mmm dd hh:mm:ss tag[pid]: message", which has no hostname. Messages
+// without PRI are accepted as well, see WithDefaultPriority(). Use
+// WithHostname() to report the local host name.
+func (p *Parser) WithLocal() {
+	p.WithoutHostname()
+	p.WithDefaultPriority()
+}
+
+// Guesses whether messages have a hostname: the word following the
+// timestamp is only considered as the hostname when it contains neither ":"
+// nor "[", IPv6 addresses excepted, and is followed by another word, the tag.
+// Otherwise hostname is empty and the word is parsed as the tag.
+func (p *Parser) WithHostnameHeuristic() {
+	p.hostnameHeuristic = true
+}
+
+// Only considers the word following the timestamp as the hostname when it is
+// an RFC 1123 host name or an IP address, see
+// parsercommon.IsValidHostname(), ie. neither "su:" nor "sshd[42]:".
+// Otherwise hostname is empty and the word is parsed as the tag.
+func (p *Parser) WithHostnameValidation() {
+	p.hostnameValidation = true
+}
+
+// Accepts hostnames longer than the parsercommon.MAX_HOSTNAME_LEN bytes
+// allowed, rejected with ErrInvalidHostname otherwise. WithStrict() takes
+// precedence.
+func (p *Parser) WithLongHostname() {
+	p.longHostname = true
+}
+
+// Rejects tags longer than the MAX_TAG_LEN characters allowed by RFC 3164
+// with ErrInvalidTag, located at the first character in excess. Tags of up
+// to RELAXED_MAX_TAG_LEN characters are accepted otherwise.
+func (p *Parser) WithTagLenCheck() {
+	p.tagLenCheck = true
+}
+
+// Adds "program" and "subprogram" to Dump() for tags of the form
+// "daemon/subprogram", ie. "postfix" and "smtpd" for "postfix/smtpd", see
+// parsercommon.SplitProgram(). "tag" is kept as is.
+func (p *Parser) WithProgramSplit() {
+	p.programSplit = true
+}
+
+// Adds the kind of hostname to Dump(): "hostname_type" is one of
+// parsercommon.HOSTNAME_TYPE_IP4, _IP6, _FQDN or _NAME, see
+// parsercommon.HostnameType(). It is not set for empty hostnames.
+func (p *Parser) WithHostnameType() {
+	p.hostnameType = true
+}
+
+// Adds the parser name ("parser") and the time spent in Parse()
+// ("parse_duration", a time.Duration) to Dump(), for debugging and
+// comparing parsers.
+func (p *Parser) WithDiagnostics() {
+	p.diagnostics = true
+}
+
+// Accepts priorities above 191 (ie. <999>) as older versions did. Facility
+// and severity are then computed as for valid priorities.
+func (p *Parser) WithLenientPriority() {
+	p.lenientPriority = true
+}
+
+// Messages not starting with "<", as sent by devices omitting PRI, are given
+// parsercommon.DEFAULT_PRIORITY (user.notice) instead of failing with
+// ErrPriorityNoStart, as relays do per RFC 3164 section 4.3.3.
+func (p *Parser) WithDefaultPriority() {
+	p.defaultPriority = true
+}
+
+// Adds "provenance" to Dump(), a map[string]string giving the origin of every
+// field: parsercommon.PROVENANCE_PARSED, PROVENANCE_FORCED when set with a
+// With* option, PROVENANCE_DEFAULT for version and PROVENANCE_INFERRED for
+// timestamps without year, completed with the current one.
+func (p *Parser) WithProvenance() {
+	p.provenance = true
+}
+
+// Runs pp on the content once the message has been parsed and adds the keys it
+// returns to Dump(). Keys also set by the parser are handled following
+// policy. When pp fails its error is reported as "payload_error".
+func (p *Parser) WithPayloadParser(pp parsercommon.PayloadParser, policy parsercommon.KeyPolicy) {
+	p.payloadParser = pp
+	p.keyPolicy = policy
+}
+
+// Renames the keys returned by Dump() with m, ie. parsercommon.KeysECS or
+// parsercommon.KeysCamelCase. DumpMessage() is not affected.
+func (p *Parser) WithKeyMapper(m parsercommon.KeyMapper) {
+	p.keyMapper = m
+}
+
+// Adds the RFC ("rfc", RFC_NUMBER) and the message as received ("raw", a
+// string) to Dump(), for audit trails and reprocessing.
+func (p *Parser) WithRaw() {
+	p.raw = true
+}
+
+// Adds the timestamp as sent ("timestamp_raw", ie. "Oct 11 22:14:15") to Dump(), for
+// compliance systems which must store exactly what the device sent.
+func (p *Parser) WithRawTimestamp() {
+	p.rawTimestamp = true
+}
+
+// Returns true when the message had a timestamp. RFC3164 timestamps are
+// mandatory so this is true once Parse() succeeded.
+func (p *Parser) HasTimestamp() bool {
+	return p.hasTimestamp
+}
+
+// Adds "timestamp_present" to Dump(), see HasTimestamp()
+func (p *Parser) WithTimestampPresence() {
+	p.timestampPresence = true
+}
+
+// Moves the time elapsed since boot prefixing kernel messages (tag
+// "kernel"), as in "[12345.678901] usb 1-1: new device", from the content to
+// "boot_offset", a time.Duration.
+func (p *Parser) WithKernelOffset() {
+	p.kernelOffset = true
+}
+
+// Parses the JSON following the CEE cookie of structured messages, as in
+// `myapp: @cee: {"event":"login"}`, and adds it to Dump() as "cee", a
+// map[string]interface{}. Invalid JSON is reported as "cee_error".
+func (p *Parser) WithCEE() {
+	p.cee = true
+}
+
+// Unmarshals the content when it is a JSON object, as in `myapp: {"log":"started"}`,
+// and adds it to Dump() as "json", a map[string]interface{}. Bodies larger
+// than maxSize bytes or nested deeper than maxDepth are reported as
+// "json_error", 0 meaning parsercommon.JSON_MAX_SIZE and JSON_MAX_DEPTH.
+func (p *Parser) WithJSON(maxDepth int, maxSize int) {
+	p.jsonBody = true
+	p.jsonMaxDepth = maxDepth
+	p.jsonMaxSize = maxSize
+}
+
+// Adds the keywords of the facility ("facility_name", ie. "daemon") and of
+// the severity ("severity_name", ie. "err") to Dump().
+func (p *Parser) WithNames() {
+	p.names = true
+}
+
+// Number of bytes of the buffer which are parsed, MAX_PACKET_LEN by default.
+// Longer messages are truncated. The limit is lifted when n <= 0.
+// MUST be called before Parse().
+func (p *Parser) WithMaxPacketLen(n int) {
+	if n <= 0 {
+		n = len(p.cursor.Buffer())
+	}
+
+	p.cursor = parsercommon.NewCursor(p.cursor.Buffer(), n)
+	p.cursor.TrimTrailer()
+}
+
+// String fields (hostname, tag, content) will share the memory of the
+// buffer given to NewParser() instead of being copied.
+// The buffer MUST NOT be modified nor reused as long as the values returned
+// by Dump() are in use.
+func (p *Parser) WithZeroCopy() {
+	p.zeroCopy = true
+}
+
 // DEPRECATED. Use WithLocation() instead
 func (p *Parser) Location(location *time.Location) {
 	p.WithLocation(location)
@@ -95,14 +357,52 @@ func (p *Parser) Hostname(hostname string) {
 	p.WithHostname(hostname)
 }
 
+// Errors are *parsercommon.ParserError located at the field which could not
+// be parsed. Use errors.Is() to compare them with the exported errors.
 func (p *Parser) Parse() error {
+	if !p.diagnostics {
+		return p.locate(p.parse())
+	}
+
+	start := time.Now()
+	err := p.parse()
+	p.parseDuration = time.Since(start)
+
+	return p.locate(err)
+}
+
+// Records the field about to be parsed so errors can be located
+func (p *Parser) begin(field string) {
+	p.field = field
+	p.fieldPos = p.cursor.Pos()
+}
+
+func (p *Parser) locate(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return p.cursor.Locate(err, p.fieldPos, p.field)
+}
+
+func (p *Parser) parse() error {
 	p.version = parsercommon.NO_VERSION
 
+	if p.strict {
+		if err := p.checkLength(); err != nil {
+			return err
+		}
+	}
+
+	p.begin("priority")
+
 	pri, err := p.parsePriority()
 	if err != nil {
 		return err
 	}
 
+	p.endSpan()
+
 	p.priority = pri
 
 	hdr, err := p.parseHeader()
@@ -112,29 +412,198 @@ func (p *Parser) Parse() error {
 
 	p.header = hdr
 
-	if p.buff[p.cursor] == ' ' {
-		p.cursor++
-	}
+	p.cursor.Expect(' ')
+
+	msgPos := p.cursor.Pos()
 
 	msg, err := p.parsemessage()
 	if err != parsercommon.ErrEOL {
 		return err
 	}
 
+	if p.cee {
+		// XXX : "mymachine @cee:{...}" has no tag, the cookie is parsed as one
+		s := msg.content
+		if !parsercommon.HasCEECookie(s) {
+			s = string(p.cursor.Slice(msgPos, p.cursor.Len()))
+		}
+
+		p.ceeFields, p.hasCEE, p.ceeErr = parsercommon.ParseCEE(s)
+	}
+
+	if p.jsonBody {
+		// XXX : same for "mymachine {...}", the tag being '{"key":'
+		s := msg.content
+		if len(msg.tag) > 0 && msg.tag[0] == '{' {
+			s = string(p.cursor.Slice(msgPos, p.cursor.Len()))
+		}
+
+		p.jsonFields, p.hasJSON, p.jsonErr = parsercommon.ParseJSONBody(
+			s, p.jsonMaxDepth, p.jsonMaxSize,
+		)
+	}
+
+	if p.kernelOffset && msg.tag == parsercommon.KERNEL_TAG {
+		p.bootOffset, msg.content, p.hasBootOffset = parsercommon.ParseBootOffset(msg.content)
+	}
+
 	p.message = msg
 
+	if p.payloadParser != nil {
+		p.payload, p.payloadErr = p.payloadParser.ParsePayload(msg.content)
+	}
+
 	return nil
 }
 
-func (p *Parser) Dump() syslogparser.LogParts {
-	return syslogparser.LogParts{
+func (p *Parser) Dump() parsercommon.LogParts {
+	parts := parsercommon.LogParts{
 		"timestamp": p.header.timestamp,
 		"hostname":  p.header.hostname,
 		"tag":       p.message.tag,
+		"pid":       p.message.pid,
 		"content":   p.message.content,
 		"priority":  p.priority.P,
 		"facility":  p.priority.F.Value,
 		"severity":  p.priority.S.Value,
+		"version":   p.version,
+	}
+
+	if p.cursor.Truncated() {
+		parts["truncated"] = true
+	}
+
+	if p.names {
+		parts["facility_name"] = p.priority.F.Name()
+		parts["severity_name"] = p.priority.S.Name()
+	}
+
+	if p.hostnameType {
+		if t := parsercommon.HostnameType(p.header.hostname); t != "" {
+			parts["hostname_type"] = t
+		}
+	}
+
+	if p.programSplit {
+		if program, subprogram, ok := parsercommon.SplitProgram(p.message.tag); ok {
+			parts["program"] = program
+			parts["subprogram"] = subprogram
+		}
+	}
+
+	if p.provenance {
+		parts["provenance"] = p.dumpProvenance()
+	}
+
+	if p.hasBootOffset {
+		parts["boot_offset"] = p.bootOffset
+	}
+
+	if p.timestampPresence {
+		parts["timestamp_present"] = p.hasTimestamp
+	}
+
+	if p.hasCEE {
+		if p.ceeErr != nil {
+			parts["cee_error"] = p.ceeErr.Error()
+		} else {
+			parts[parsercommon.CEE_KEY] = p.ceeFields
+		}
+	}
+
+	if p.hasJSON {
+		if p.jsonErr != nil {
+			parts["json_error"] = p.jsonErr.Error()
+		} else {
+			parts[parsercommon.JSON_KEY] = p.jsonFields
+		}
+	}
+
+	if p.rawTimestamp {
+		parts["timestamp_raw"] = p.str(p.timestampText)
+	}
+
+	if p.raw {
+		parts["rfc"] = RFC_NUMBER
+		parts["raw"] = p.str(p.cursor.Buffer())
+	}
+
+	if p.diagnostics {
+		parts["parser"] = PARSER_NAME
+		parts["parse_duration"] = p.parseDuration
+	}
+
+	if p.payloadParser != nil {
+		parsercommon.MergePayload(parts, p.payload, p.payloadErr, p.keyPolicy)
+	}
+
+	return parsercommon.MapKeys(parts, p.keyMapper)
+}
+
+func (p *Parser) dumpProvenance() map[string]string {
+	pri := parsercommon.ParsedOrForced(p.priorityForced)
+	if p.priorityDefaulted {
+		pri = parsercommon.PROVENANCE_DEFAULT
+	}
+
+	ts := parsercommon.PROVENANCE_PARSED
+	if p.yearInferred {
+		ts = parsercommon.PROVENANCE_INFERRED
+	}
+
+	prov := map[string]string{
+		"priority":  pri,
+		"facility":  pri,
+		"severity":  pri,
+		"version":   parsercommon.PROVENANCE_DEFAULT,
+		"timestamp": ts,
+		"hostname":  p.hostnameProvenance(),
+		"tag":       p.tagProvenance(),
+		"pid":       parsercommon.PROVENANCE_PARSED,
+		"content":   parsercommon.PROVENANCE_PARSED,
+	}
+
+	if p.names {
+		prov["facility_name"] = parsercommon.PROVENANCE_INFERRED
+		prov["severity_name"] = parsercommon.PROVENANCE_INFERRED
+	}
+
+	return prov
+}
+
+func (p *Parser) tagProvenance() string {
+	if p.customTag != "" {
+		return parsercommon.PROVENANCE_FORCED
+	}
+
+	if p.noTag && !p.strict {
+		return parsercommon.PROVENANCE_DEFAULT
+	}
+
+	return parsercommon.PROVENANCE_PARSED
+}
+
+func (p *Parser) hostnameProvenance() string {
+	if p.hostnameMissing {
+		return parsercommon.PROVENANCE_DEFAULT
+	}
+
+	return parsercommon.ParsedOrForced(p.hostname != "")
+}
+
+// Same as Dump() without allocating a map. m is overwritten, options adding
+// keys to Dump() are ignored.
+func (p *Parser) DumpMessage(m *parsercommon.Message) {
+	*m = parsercommon.Message{
+		Priority:  p.priority.P,
+		Facility:  p.priority.F.Value,
+		Severity:  p.priority.S.Value,
+		Version:   p.version,
+		Timestamp: p.header.timestamp,
+		Hostname:  p.header.hostname,
+		Tag:       p.message.tag,
+		Pid:       p.message.pid,
+		Content:   p.message.content,
 	}
 }
 
@@ -143,9 +612,18 @@ func (p *Parser) parsePriority() (*parsercommon.Priority, error) {
 		return p.priority, nil
 	}
 
-	return parsercommon.ParsePriority(
-		p.buff, &p.cursor, p.l,
-	)
+	if p.defaultPriority && !p.strict {
+		if b, ok := p.cursor.Peek(); ok && b != '<' {
+			p.priorityDefaulted = true
+			return parsercommon.NewPriority(parsercommon.DEFAULT_PRIORITY), nil
+		}
+	}
+
+	if p.lenientPriority && !p.strict {
+		return p.cursor.ParseLenientPriority()
+	}
+
+	return p.cursor.ParsePriority()
 }
 
 // HEADER: TIMESTAMP + HOSTNAME (or IP)
@@ -153,15 +631,21 @@ func (p *Parser) parsePriority() (*parsercommon.Priority, error) {
 func (p *Parser) parseHeader() (*header, error) {
 	var err error
 
-	if p.buff[p.cursor] == ' ' {
-		p.cursor++
+	p.cursor.Expect(' ')
+
+	if p.swappedHeader && !p.strict && p.isSwappedHeader() {
+		return p.parseSwappedHeader()
 	}
 
+	p.begin("timestamp")
+
 	ts, err := p.parseTimestamp()
 	if err != nil {
 		return nil, err
 	}
 
+	p.begin("hostname")
+
 	h, err := p.parseHostname()
 	if err != nil {
 		return nil, err
@@ -175,16 +659,78 @@ func (p *Parser) parseHeader() (*header, error) {
 	return hdr, nil
 }
 
+// "mymachine Oct 11 22:14:15"
+func (p *Parser) isSwappedHeader() bool {
+	rest := p.cursor.Rest()
+
+	if parsercommon.StartsWithMonth(rest) {
+		return false
+	}
+
+	i := bytes.IndexByte(rest, ' ')
+
+	return i > 0 && parsercommon.StartsWithMonth(rest[i+1:])
+}
+
+func (p *Parser) parseSwappedHeader() (*header, error) {
+	p.begin("hostname")
+
+	h := p.cursor.ScanHostname()
+	p.endSpan()
+
+	if err := p.checkHostnameLen(h); err != nil {
+		return nil, err
+	}
+
+	p.cursor.Expect(' ')
+
+	p.begin("timestamp")
+
+	ts, err := p.parseTimestamp()
+	if err != nil {
+		return nil, err
+	}
+
+	hostname := p.hostname
+	if hostname == "" {
+		hostname = p.hostnameValue(h)
+	}
+
+	hdr := &header{
+		timestamp: ts,
+		hostname:  hostname,
+	}
+
+	return hdr, nil
+}
+
 // MSG: TAG + CONTENT
 // https://tools.ietf.org/html/rfc3164#section-4.1.3
 func (p *Parser) parsemessage() (*message, error) {
 	var err error
 
-	tag, err := p.parseTag()
+	p.begin("tag")
+
+	tag, pid, err := p.parseTag()
 	if err != nil {
 		return nil, err
 	}
 
+	if p.tagLenCheck && p.customTag == "" && len(tag) > MAX_TAG_LEN {
+		p.fieldPos += MAX_TAG_LEN
+		return nil, ErrInvalidTag
+	}
+
+	if p.strict && p.customTag == "" {
+		next, _ := p.cursor.At(p.fieldPos + len(tag))
+
+		if err := checkTag(tag, next); err != nil {
+			return nil, err
+		}
+	}
+
+	p.begin("content")
+
 	content, err := p.parseContent()
 	if err != parsercommon.ErrEOL {
 		return nil, err
@@ -192,6 +738,7 @@ func (p *Parser) parsemessage() (*message, error) {
 
 	msg := &message{
 		tag:     tag,
+		pid:     pid,
 		content: content,
 	}
 
@@ -216,43 +763,78 @@ func (p *Parser) parseTimestamp() (time.Time, error) {
 		}
 	}
 
+	if p.strict {
+		tsFmts = []string{
+			STRICT_TIMESTAMP_FORMAT,
+		}
+	}
+
+	from := p.cursor.Pos()
+
 	found := false
 	for _, tsFmt := range tsFmts {
 		tsFmtLen = len(tsFmt)
 
-		if p.cursor+tsFmtLen > p.l {
+		if from+tsFmtLen > p.cursor.Len() {
 			continue
 		}
 
-		sub = p.buff[p.cursor : tsFmtLen+p.cursor]
+		sub = p.cursor.Slice(from, from+tsFmtLen)
 		ts, err = time.ParseInLocation(
 			tsFmt, string(sub), p.location,
 		)
 
+		if err == nil && p.strict && !isStrictTimestamp(sub) {
+			continue
+		}
+
 		if err == nil {
 			found = true
 			break
 		}
 	}
 
+	if !found && p.epochTimestamp && !p.strict {
+		if ts, ok := p.parseEpochTimestamp(from); ok {
+			return ts, nil
+		}
+	}
+
+	if !found && p.lenientTimestamp && !p.strict {
+		return p.parseLenientTimestamp(from), nil
+	}
+
 	if !found {
-		p.cursor = tsFmtLen
+		p.cursor.SetPos(tsFmtLen)
 
 		// XXX : If the timestamp is invalid we try to push the cursor one byte
 		// XXX : further, in case it is a space
-		if (p.cursor < p.l) && (p.buff[p.cursor] == ' ') {
-			p.cursor++
+		p.cursor.Expect(' ')
+
+		if err == nil {
+			return ts, parsercommon.ErrTimestampUnknownFormat
 		}
 
-		return ts, parsercommon.ErrTimestampUnknownFormat
+		return ts, parsercommon.Wrap(parsercommon.ErrTimestampUnknownFormat, err)
 	}
 
+	p.hasTimestamp = true
+	p.yearInferred = ts.Year() == 0
+	p.timestampText = sub
+	p.setSpan("timestamp", from, from+len(sub))
+
 	fixTimestampIfNeeded(&ts)
 
-	p.cursor += tsFmtLen
+	p.cursor.Advance(tsFmtLen)
+	p.cursor.Expect(' ')
 
-	if (p.cursor < p.l) && (p.buff[p.cursor] == ' ') {
-		p.cursor++
+	if p.timestampZone && !p.strict {
+		if to := p.parseTimestampZone(&ts, p.cursor.Pos()); to > p.cursor.Pos() {
+			// the zone is part of the timestamp, its SP excepted
+			p.timestampText = p.cursor.Slice(from, to-1)
+			p.setSpan("timestamp", from, to-1)
+			p.cursor.SetPos(to)
+		}
 	}
 
 	return ts, nil
@@ -263,69 +845,176 @@ func (p *Parser) parseHostname() (string, error) {
 		return p.hostname, nil
 	}
 
-	return parsercommon.ParseHostname(
-		p.buff, &p.cursor, p.l,
-	)
+	if p.strict {
+		h := p.cursor.ScanHostname()
+		p.endSpan()
+
+		if err := p.checkHostnameLen(h); err != nil {
+			return "", err
+		}
+
+		return p.str(h), checkHostname(h)
+	}
+
+	if p.noHostname || (p.hostnameHeuristic && !p.looksLikeHostname()) ||
+		(p.hostnameValidation && !p.isValidHostname()) {
+		p.hostnameMissing = true
+		return "", nil
+	}
+
+	h := p.cursor.ScanHostname()
+	p.endSpan()
+
+	if err := p.checkHostnameLen(h); err != nil {
+		return "", err
+	}
+
+	return p.hostnameValue(h), nil
+}
+
+func (p *Parser) checkHostnameLen(h []byte) error {
+	if len(h) > parsercommon.MAX_HOSTNAME_LEN && (!p.longHostname || p.strict) {
+		return ErrInvalidHostname
+	}
+
+	return nil
+}
+
+// "mymachine su: ..." but neither "myapp: ..." nor "sshd[42]: ..."
+func (p *Parser) looksLikeHostname() bool {
+	rest := p.cursor.Rest()
+
+	end := bytes.IndexByte(rest, ' ')
+	if end <= 0 || end == len(rest)-1 {
+		return false
+	}
+
+	word := rest[:end]
+
+	if word[0] == '[' {
+		return parsercommon.IsValidHostname(word)
+	}
+
+	if bytes.IndexByte(word, '[') >= 0 {
+		return false
+	}
+
+	if bytes.IndexByte(word, ':') >= 0 {
+		return net.ParseIP(string(word)) != nil
+	}
+
+	return true
+}
+
+func (p *Parser) isValidHostname() bool {
+	word := p.cursor.Rest()
+	if end := bytes.IndexByte(word, ' '); end >= 0 {
+		word = word[:end]
+	}
+
+	if p.dashHostnameAsEmpty && parsercommon.IsNilValue(word) {
+		return true
+	}
+
+	return parsercommon.IsValidHostname(word)
+}
+
+func (p *Parser) hostnameValue(h []byte) string {
+	if p.dashHostnameAsEmpty && parsercommon.IsNilValue(h) {
+		return ""
+	}
+
+	return p.str(h)
 }
 
 // http://tools.ietf.org/html/rfc3164#section-4.1.3
-func (p *Parser) parseTag() (string, error) {
+// Returns the tag and the process ID following it between brackets, as in
+// "sshd[1234]:", if any. Scanning stops at the end of the buffer: datagrams
+// truncated in the middle of the tag give the partial tag, and no process ID
+// when its closing bracket is missing.
+func (p *Parser) parseTag() (string, string, error) {
 	if p.customTag != "" {
-		return p.customTag, nil
+		return p.customTag, "", nil
+	}
+
+	if p.noTag && !p.strict {
+		return "", "", nil
 	}
 
-	var b byte
-	var tag []byte
 	var err error
 	var enough bool
 
-	previous := p.cursor
+	previous := p.cursor.Pos()
+	end := previous
 
 	// "The TAG is a string of ABNF alphanumeric characters that MUST NOT exceed 32 characters."
-	to := int(
-		math.Min(
-			float64(p.l),
-			float64(p.cursor+32),
-		),
-	)
+	// XXX : longer tags are common, up to RELAXED_MAX_TAG_LEN are accepted
+	for p.cursor.Pos() < previous+RELAXED_MAX_TAG_LEN {
+		b, ok := p.cursor.Peek()
+		if !ok {
+			break
+		}
 
-	for p.cursor < to {
-		b = p.buff[p.cursor]
+		p.cursor.Advance(1)
 
 		if b == ' ' {
-			p.cursor++
 			break
 		}
 
 		if b == '[' || b == ']' || b == ':' || enough {
 			enough = true
-			p.cursor++
 			continue
 		}
 
-		tag = append(tag, b)
-		p.cursor++
+		end = p.cursor.Pos()
+	}
+
+	if end == previous {
+		p.cursor.SetPos(previous)
 	}
 
-	if len(tag) == 0 {
-		p.cursor = previous
+	pid := parsePid(p.cursor.Slice(end, p.cursor.Pos()))
+
+	p.setSpan("tag", previous, end)
+	if pid != nil {
+		p.setSpan("pid", end+1, end+1+len(pid))
 	}
 
-	return string(tag), err
+	return p.str(p.cursor.Slice(previous, end)), p.str(pid), err
 }
 
-func (p *Parser) parseContent() (string, error) {
-	if p.cursor > p.l {
-		return "", parsercommon.ErrEOL
+// "[1234]:" => "1234"
+func parsePid(b []byte) []byte {
+	if len(b) < 2 || b[0] != '[' {
+		return nil
 	}
 
-	content := bytes.Trim(
-		p.buff[p.cursor:p.l], " ",
-	)
+	end := bytes.IndexByte(b, ']')
+	if end < 0 {
+		return nil
+	}
+
+	return b[1:end]
+}
+
+func (p *Parser) parseContent() (string, error) {
+	rest := p.cursor.Rest()
+	content := bytes.Trim(rest, " ")
+
+	start := p.cursor.Pos() + len(rest) - len(bytes.TrimLeft(rest, " "))
+	p.setSpan("content", start, start+len(content))
 
-	p.cursor += len(content)
+	p.cursor.Advance(len(content))
+
+	return p.str(content), parsercommon.ErrEOL
+}
+
+func (p *Parser) str(b []byte) string {
+	if p.zeroCopy {
+		return parsercommon.UnsafeString(b)
+	}
 
-	return string(content), parsercommon.ErrEOL
+	return string(b)
 }
 
 func fixTimestampIfNeeded(ts *time.Time) {
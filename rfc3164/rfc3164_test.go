@@ -2,11 +2,11 @@ package rfc3164
 
 import (
 	"bytes"
+	"errors"
 	"strings"
 	"testing"
 	"time"
 
-	"github.com/jeromer/syslogparser"
 	"github.com/jeromer/syslogparser/parsercommon"
 	"github.com/stretchr/testify/require"
 )
@@ -27,9 +27,7 @@ func TestParserValid(t *testing.T) {
 	require.Equal(
 		t,
 		&Parser{
-			buff:     buff,
-			cursor:   0,
-			l:        len(buff),
+			cursor:   parsercommon.NewCursor(buff, MAX_PACKET_LEN),
 			location: time.UTC,
 		},
 		p,
@@ -43,7 +41,7 @@ func TestParserValid(t *testing.T) {
 
 	require.Equal(
 		t,
-		syslogparser.LogParts{
+		parsercommon.LogParts{
 			"timestamp": time.Date(
 				time.Now().Year(),
 				time.October,
@@ -52,10 +50,12 @@ func TestParserValid(t *testing.T) {
 			),
 			"hostname": "mymachine",
 			"tag":      "very.large.syslog.message.tag",
+			"pid":      "",
 			"content":  "'su root' failed for lonvick on /dev/pts/8",
 			"priority": 34,
 			"facility": 4,
 			"severity": 2,
+			"version":  parsercommon.NO_VERSION,
 		},
 		p.Dump(),
 	)
@@ -74,11 +74,10 @@ func TestParserWithPriority(t *testing.T) {
 	require.Equal(
 		t,
 		&Parser{
-			buff:     buff,
-			cursor:   0,
-			l:        len(buff),
-			location: time.UTC,
-			priority: pri,
+			cursor:         parsercommon.NewCursor(buff, MAX_PACKET_LEN),
+			location:       time.UTC,
+			priority:       pri,
+			priorityForced: true,
 		},
 		p,
 	)
@@ -91,7 +90,7 @@ func TestParserWithPriority(t *testing.T) {
 
 	require.Equal(
 		t,
-		syslogparser.LogParts{
+		parsercommon.LogParts{
 			"timestamp": time.Date(
 				time.Now().Year(),
 				time.October,
@@ -100,10 +99,12 @@ func TestParserWithPriority(t *testing.T) {
 			),
 			"hostname": "mymachine",
 			"tag":      "very.large.syslog.message.tag",
+			"pid":      "",
 			"content":  "'su root' failed for lonvick on /dev/pts/8",
 			"priority": 0,
 			"facility": 0,
 			"severity": 0,
+			"version":  parsercommon.NO_VERSION,
 		},
 		p.Dump(),
 	)
@@ -122,7 +123,7 @@ func TestParserWithHostname(t *testing.T) {
 
 	require.Equal(
 		t,
-		syslogparser.LogParts{
+		parsercommon.LogParts{
 			"timestamp": time.Date(
 				time.Now().Year(),
 				time.June,
@@ -131,10 +132,12 @@ func TestParserWithHostname(t *testing.T) {
 			),
 			"hostname": "dummy",
 			"tag":      "chronyd",
+			"pid":      "1119",
 			"content":  "Selected source 192.168.65.1",
 			"priority": 30,
 			"facility": 3,
 			"severity": 6,
+			"version":  parsercommon.NO_VERSION,
 		},
 		p.Dump(),
 	)
@@ -154,7 +157,7 @@ func TestParserWithTag(t *testing.T) {
 
 	require.Equal(
 		t,
-		syslogparser.LogParts{
+		parsercommon.LogParts{
 			"timestamp": time.Date(
 				time.Now().Year(),
 				time.June,
@@ -163,10 +166,12 @@ func TestParserWithTag(t *testing.T) {
 			),
 			"hostname": "localhost",
 			"tag":      "chronyd",
+			"pid":      "",
 			"content":  "Selected source 192.168.65.1",
 			"priority": 30,
 			"facility": 3,
 			"severity": 6,
+			"version":  parsercommon.NO_VERSION,
 		},
 		p.Dump(),
 	)
@@ -188,7 +193,7 @@ func TestParserWithLocation(t *testing.T) {
 
 	require.Equal(
 		t,
-		syslogparser.LogParts{
+		parsercommon.LogParts{
 			"timestamp": time.Date(
 				time.Now().Year(),
 				time.June,
@@ -197,10 +202,12 @@ func TestParserWithLocation(t *testing.T) {
 			),
 			"hostname": "localhost",
 			"tag":      "foo",
+			"pid":      "",
 			"content":  "Selected source 192.168.65.1",
 			"priority": 30,
 			"facility": 3,
 			"severity": 6,
+			"version":  parsercommon.NO_VERSION,
 		},
 		p.Dump(),
 	)
@@ -221,7 +228,7 @@ func TestParserWithTimestampFormat(t *testing.T) {
 
 	require.Equal(
 		t,
-		syslogparser.LogParts{
+		parsercommon.LogParts{
 			"timestamp": time.Date(
 				2006, time.January, 2,
 				15, 4, 5, 0,
@@ -229,15 +236,52 @@ func TestParserWithTimestampFormat(t *testing.T) {
 			),
 			"hostname": "localhost",
 			"tag":      "foo",
+			"pid":      "",
 			"content":  "Selected source 192.168.65.1",
 			"priority": 30,
 			"facility": 3,
 			"severity": 6,
+			"version":  parsercommon.NO_VERSION,
 		},
 		p.Dump(),
 	)
 }
 
+func TestParserWithTimestampFormatBeyond32Bits(t *testing.T) {
+	testCases := []struct {
+		description  string
+		input        string
+		expectedUnix int64
+	}{
+		{
+			description:  "after 2038",
+			input:        "<30>2038-01-19T03:14:08 localhost foo: bar",
+			expectedUnix: 2147483648,
+		},
+		{
+			description:  "before 1901",
+			input:        "<30>1901-12-13T20:45:51 localhost foo: bar",
+			expectedUnix: -2147483649,
+		},
+	}
+
+	for _, tc := range testCases {
+		p := NewParser([]byte(tc.input))
+		p.WithTimestampFormat(
+			"2006-01-02T15:04:05",
+		)
+
+		err := p.Parse()
+		require.Nil(t, err, tc.description)
+
+		ts := p.Dump()["timestamp"].(time.Time)
+
+		require.Equal(
+			t, tc.expectedUnix, ts.Unix(), tc.description,
+		)
+	}
+}
+
 func TestParserWithPriorityHostnameTag(t *testing.T) {
 	buff := []byte(
 		"Oct 11 22:14:15 'su root' failed for lonvick on /dev/pts/8",
@@ -255,13 +299,12 @@ func TestParserWithPriorityHostnameTag(t *testing.T) {
 	require.Equal(
 		t,
 		&Parser{
-			buff:      buff,
-			cursor:    0,
-			l:         len(buff),
-			location:  time.UTC,
-			priority:  pri,
-			hostname:  h,
-			customTag: tag,
+			cursor:         parsercommon.NewCursor(buff, MAX_PACKET_LEN),
+			location:       time.UTC,
+			priority:       pri,
+			priorityForced: true,
+			hostname:       h,
+			customTag:      tag,
 		},
 		p,
 	)
@@ -274,7 +317,7 @@ func TestParserWithPriorityHostnameTag(t *testing.T) {
 
 	require.Equal(
 		t,
-		syslogparser.LogParts{
+		parsercommon.LogParts{
 			"timestamp": time.Date(
 				time.Now().Year(),
 				time.October,
@@ -283,15 +326,868 @@ func TestParserWithPriorityHostnameTag(t *testing.T) {
 			),
 			"hostname": h,
 			"tag":      tag,
+			"pid":      "",
 			"content":  "'su root' failed for lonvick on /dev/pts/8",
 			"priority": 0,
 			"facility": 0,
 			"severity": 0,
+			"version":  parsercommon.NO_VERSION,
 		},
 		p.Dump(),
 	)
 }
 
+func TestParserWithDashAsEmptyHostname(t *testing.T) {
+	testCases := []struct {
+		description      string
+		input            string
+		enabled          bool
+		expectedHostname string
+	}{
+		{
+			description:      "disabled",
+			input:            "<30>Jun 23 13:17:42 - chronyd[1119]: Selected source 192.168.65.1",
+			enabled:          false,
+			expectedHostname: "-",
+		},
+		{
+			description:      "enabled",
+			input:            "<30>Jun 23 13:17:42 - chronyd[1119]: Selected source 192.168.65.1",
+			enabled:          true,
+			expectedHostname: "",
+		},
+		{
+			description:      "enabled with dash in hostname",
+			input:            "<30>Jun 23 13:17:42 my-host chronyd[1119]: Selected source 192.168.65.1",
+			enabled:          true,
+			expectedHostname: "my-host",
+		},
+	}
+
+	for _, tc := range testCases {
+		p := NewParser([]byte(tc.input))
+		if tc.enabled {
+			p.WithDashAsEmptyHostname()
+		}
+
+		err := p.Parse()
+		require.Nil(t, err, tc.description)
+
+		obtained := p.Dump()
+
+		require.Equal(
+			t, tc.expectedHostname, obtained["hostname"], tc.description,
+		)
+
+		require.Equal(
+			t, "chronyd", obtained["tag"], tc.description,
+		)
+
+		require.Equal(
+			t, "Selected source 192.168.65.1", obtained["content"], tc.description,
+		)
+	}
+}
+
+func TestParserWithZeroCopy(t *testing.T) {
+	buff := []byte(
+		"<34>Oct 11 22:14:15 mymachine su: 'su root' failed for lonvick on /dev/pts/8",
+	)
+
+	expected := NewParser(buff)
+	err := expected.Parse()
+	require.Nil(t, err)
+
+	p := NewParser(buff)
+	p.WithZeroCopy()
+
+	err = p.Parse()
+	require.Nil(t, err)
+
+	obtained := p.Dump()
+	require.Equal(t, expected.Dump(), obtained)
+
+	// values share the buffer memory
+	copy(buff[20:], "MYMACHINE")
+	require.Equal(t, "MYMACHINE", obtained["hostname"])
+	require.Equal(t, "mymachine", expected.Dump()["hostname"])
+}
+
+func TestParserReset(t *testing.T) {
+	p := NewParser([]byte("foo"))
+	p.WithHostname("dummy")
+
+	buff := []byte("bar")
+	p.Reset(buff)
+
+	require.Equal(t, NewParser(buff), p)
+}
+
+func TestParserWithNames(t *testing.T) {
+	buff := []byte(
+		"<34>Oct 11 22:14:15 mymachine su: 'su root' failed for lonvick on /dev/pts/8",
+	)
+
+	p := NewParser(buff)
+	err := p.Parse()
+	require.Nil(t, err)
+
+	obtained := p.Dump()
+	require.NotContains(t, obtained, "facility_name")
+	require.NotContains(t, obtained, "severity_name")
+
+	p = NewParser(buff)
+	p.WithNames()
+
+	err = p.Parse()
+	require.Nil(t, err)
+
+	obtained = p.Dump()
+	require.Equal(t, "auth", obtained["facility_name"])
+	require.Equal(t, "crit", obtained["severity_name"])
+}
+
+func TestParseLenientPriority(t *testing.T) {
+	buff := []byte(
+		"<999>Oct 11 22:14:15 mymachine su: 'su root' failed for lonvick on /dev/pts/8",
+	)
+
+	p := NewParser(buff)
+	err := p.Parse()
+	require.ErrorIs(t, err, parsercommon.ErrPriorityInvalid)
+
+	p = NewParser(buff)
+	p.WithLenientPriority()
+
+	err = p.Parse()
+	require.Nil(t, err)
+
+	obtained := p.Dump()
+	require.Equal(t, 999, obtained["priority"])
+	require.Equal(t, 124, obtained["facility"])
+	require.Equal(t, 7, obtained["severity"])
+}
+
+func TestParseErrorLocation(t *testing.T) {
+	testCases := []struct {
+		description    string
+		input          string
+		expectedErr    error
+		expectedOffset int
+		expectedField  string
+	}{
+		{
+			description:    "priority",
+			input:          "<999>Oct 11 22:14:15 mymachine su: ok",
+			expectedErr:    parsercommon.ErrPriorityInvalid,
+			expectedOffset: 0,
+			expectedField:  "priority",
+		},
+		{
+			description:    "timestamp",
+			input:          "<34>Oct 32 22:14:15 mymachine su: ok",
+			expectedErr:    parsercommon.ErrTimestampUnknownFormat,
+			expectedOffset: 4,
+			expectedField:  "timestamp",
+		},
+	}
+
+	for _, tc := range testCases {
+		p := NewParser([]byte(tc.input))
+		err := p.Parse()
+
+		require.ErrorIs(t, err, tc.expectedErr, tc.description)
+
+		pe, ok := err.(*parsercommon.ParserError)
+		require.True(t, ok, tc.description)
+
+		require.Equal(
+			t, tc.expectedOffset, pe.Offset(), tc.description,
+		)
+
+		require.Equal(
+			t, tc.expectedField, pe.Field(), tc.description,
+		)
+
+		snippet := tc.input[tc.expectedOffset:]
+		if len(snippet) > parsercommon.SNIPPET_LEN {
+			snippet = snippet[:parsercommon.SNIPPET_LEN]
+		}
+
+		require.Equal(
+			t, snippet, pe.Snippet(), tc.description,
+		)
+	}
+}
+
+func TestParseWithProvenance(t *testing.T) {
+	buff := []byte(
+		"<34>Oct 11 22:14:15 mymachine su: 'su root' failed for lonvick on /dev/pts/8",
+	)
+
+	p := NewParser(buff)
+	err := p.Parse()
+	require.Nil(t, err)
+	require.NotContains(t, p.Dump(), "provenance")
+
+	p = NewParser(buff)
+	p.WithProvenance()
+	p.WithNames()
+
+	err = p.Parse()
+	require.Nil(t, err)
+
+	require.Equal(
+		t,
+		map[string]string{
+			"priority":      parsercommon.PROVENANCE_PARSED,
+			"facility":      parsercommon.PROVENANCE_PARSED,
+			"severity":      parsercommon.PROVENANCE_PARSED,
+			"version":       parsercommon.PROVENANCE_DEFAULT,
+			"timestamp":     parsercommon.PROVENANCE_INFERRED,
+			"hostname":      parsercommon.PROVENANCE_PARSED,
+			"tag":           parsercommon.PROVENANCE_PARSED,
+			"pid":           parsercommon.PROVENANCE_PARSED,
+			"content":       parsercommon.PROVENANCE_PARSED,
+			"facility_name": parsercommon.PROVENANCE_INFERRED,
+			"severity_name": parsercommon.PROVENANCE_INFERRED,
+		},
+		p.Dump()["provenance"],
+	)
+
+	p = NewParser([]byte("2003 Oct 11 22:14:15 'su root' failed for lonvick on /dev/pts/8"))
+	p.WithProvenance()
+	p.WithPriority(parsercommon.NewPriority(34))
+	p.WithHostname("mymachine")
+	p.WithTag("su")
+	p.WithTimestampFormat("2006 Jan 02 15:04:05")
+
+	err = p.Parse()
+	require.Nil(t, err)
+
+	require.Equal(
+		t,
+		map[string]string{
+			"priority":  parsercommon.PROVENANCE_FORCED,
+			"facility":  parsercommon.PROVENANCE_FORCED,
+			"severity":  parsercommon.PROVENANCE_FORCED,
+			"version":   parsercommon.PROVENANCE_DEFAULT,
+			"timestamp": parsercommon.PROVENANCE_PARSED,
+			"hostname":  parsercommon.PROVENANCE_FORCED,
+			"tag":       parsercommon.PROVENANCE_FORCED,
+			"pid":       parsercommon.PROVENANCE_PARSED,
+			"content":   parsercommon.PROVENANCE_PARSED,
+		},
+		p.Dump()["provenance"],
+	)
+}
+
+func TestParseWithPayloadParser(t *testing.T) {
+	buff := []byte(
+		"<34>Oct 11 22:14:15 mymachine su: hostname=spoofed user=root",
+	)
+
+	kv := parsercommon.PayloadParserFunc(func(payload string) (parsercommon.LogParts, error) {
+		parts := parsercommon.LogParts{}
+
+		for _, field := range strings.Fields(payload) {
+			kv := strings.SplitN(field, "=", 2)
+			if len(kv) != 2 {
+				return nil, errors.New("Invalid pair")
+			}
+
+			parts[kv[0]] = kv[1]
+		}
+
+		return parts, nil
+	})
+
+	p := NewParser(buff)
+	p.WithPayloadParser(kv, parsercommon.HEADER_WINS)
+
+	err := p.Parse()
+	require.Nil(t, err)
+
+	obtained := p.Dump()
+	require.Equal(t, "mymachine", obtained["hostname"])
+	require.Equal(t, "root", obtained["user"])
+	require.Equal(t, "hostname=spoofed user=root", obtained["content"])
+
+	p = NewParser(buff)
+	p.WithPayloadParser(kv, parsercommon.PAYLOAD_PREFIXED)
+
+	err = p.Parse()
+	require.Nil(t, err)
+
+	obtained = p.Dump()
+	require.Equal(t, "mymachine", obtained["hostname"])
+	require.Equal(t, "spoofed", obtained["payload_hostname"])
+	require.Equal(t, "root", obtained["payload_user"])
+	require.NotContains(t, obtained, "user")
+
+	p = NewParser([]byte(strings.Replace(string(buff), "user=root", "root", 1)))
+	p.WithPayloadParser(kv, parsercommon.PAYLOAD_WINS)
+
+	err = p.Parse()
+	require.Nil(t, err)
+
+	obtained = p.Dump()
+	require.Equal(t, "mymachine", obtained["hostname"])
+	require.Equal(t, "Invalid pair", obtained["payload_error"])
+}
+
+func TestParseDefaultPriority(t *testing.T) {
+	buff := []byte(
+		"Oct 11 22:14:15 mymachine su: 'su root' failed",
+	)
+
+	p := NewParser(buff)
+	err := p.Parse()
+	require.ErrorIs(t, err, parsercommon.ErrPriorityNoStart)
+
+	p = NewParser(buff)
+	p.WithDefaultPriority()
+	p.WithProvenance()
+
+	err = p.Parse()
+	require.Nil(t, err)
+
+	obtained := p.Dump()
+	require.Equal(t, parsercommon.DEFAULT_PRIORITY, obtained["priority"])
+	require.Equal(t, 1, obtained["facility"])
+	require.Equal(t, 5, obtained["severity"])
+	require.Equal(t, "mymachine", obtained["hostname"])
+
+	prov := obtained["provenance"].(map[string]string)
+	require.Equal(t, parsercommon.PROVENANCE_DEFAULT, prov["priority"])
+
+	p = NewParser([]byte("<34>" + string(buff)))
+	p.WithDefaultPriority()
+
+	err = p.Parse()
+	require.Nil(t, err)
+	require.Equal(t, 34, p.Dump()["priority"])
+}
+
+func TestParseSwappedHeader(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		description      string
+		input            string
+		hostname         string
+		expectedHostname string
+		expectedTag      string
+		expectedErr      error
+	}{
+		{
+			description:      "swapped",
+			input:            "<34>mymachine Oct 11 22:14:15 su: 'su root' failed",
+			expectedHostname: "mymachine",
+			expectedTag:      "su",
+		},
+		{
+			description:      "not swapped",
+			input:            "<34>Oct 11 22:14:15 mymachine su: 'su root' failed",
+			expectedHostname: "mymachine",
+			expectedTag:      "su",
+		},
+		{
+			description:      "forced hostname",
+			input:            "<34>mymachine Oct 11 22:14:15 su: 'su root' failed",
+			hostname:         "forced",
+			expectedHostname: "forced",
+			expectedTag:      "su",
+		},
+		{
+			description: "no month",
+			input:       "<34>mymachine Foo 11 22:14:15 su: 'su root' failed",
+			expectedErr: parsercommon.ErrTimestampUnknownFormat,
+		},
+	}
+
+	for _, tc := range testCases {
+		p := NewParser([]byte(tc.input))
+		p.WithSwappedHeader()
+
+		if tc.hostname != "" {
+			p.WithHostname(tc.hostname)
+		}
+
+		err := p.Parse()
+		require.ErrorIs(t, err, tc.expectedErr, tc.description)
+
+		if tc.expectedErr != nil {
+			continue
+		}
+
+		obtained := p.Dump()
+		require.Equal(
+			t,
+			time.Date(now.Year(), time.October, 11, 22, 14, 15, 0, time.UTC),
+			obtained["timestamp"],
+			tc.description,
+		)
+		require.Equal(t, tc.expectedHostname, obtained["hostname"], tc.description)
+		require.Equal(t, tc.expectedTag, obtained["tag"], tc.description)
+	}
+
+	p := NewParser([]byte("<34>mymachine Oct 11 22:14:15 su: 'su root' failed"))
+	err := p.Parse()
+	require.ErrorIs(t, err, parsercommon.ErrTimestampUnknownFormat)
+}
+
+func TestParseWithKeyMapper(t *testing.T) {
+	buff := []byte(
+		"<34>Oct 11 22:14:15 mymachine su: 'su root' failed",
+	)
+
+	p := NewParser(buff)
+	p.WithKeyMapper(parsercommon.KeysECS)
+
+	err := p.Parse()
+	require.Nil(t, err)
+
+	obtained := p.Dump()
+	require.Equal(t, "mymachine", obtained["log.syslog.hostname"])
+	require.Equal(t, "su", obtained["process.name"])
+	require.Equal(t, "'su root' failed", obtained["message"])
+	require.NotContains(t, obtained, "hostname")
+
+	p = NewParser(buff)
+	p.WithKeyMapper(parsercommon.KeysCamelCase)
+
+	err = p.Parse()
+	require.Nil(t, err)
+
+	obtained = p.Dump()
+	require.Equal(t, "mymachine", obtained["hostname"])
+	require.Equal(t, "'su root' failed", obtained["content"])
+}
+
+func TestParseWithRaw(t *testing.T) {
+	buff := []byte(
+		"<34>Oct 11 22:14:15 mymachine su: 'su root' failed",
+	)
+
+	p := NewParser(buff)
+
+	err := p.Parse()
+	require.Nil(t, err)
+
+	obtained := p.Dump()
+	require.NotContains(t, obtained, "rfc")
+	require.NotContains(t, obtained, "raw")
+
+	p = NewParser(buff)
+	p.WithRaw()
+
+	err = p.Parse()
+	require.Nil(t, err)
+
+	obtained = p.Dump()
+	require.Equal(t, 3164, obtained["rfc"])
+	require.Equal(t, string(buff), obtained["raw"])
+}
+
+func TestParseWithRawTimestamp(t *testing.T) {
+	testCases := []struct {
+		description string
+		input       string
+		expected    string
+	}{
+		{
+			description: "day with leading zero",
+			input:       "<34>Oct 01 22:14:15 mymachine su: 'su root' failed",
+			expected:    "Oct 01 22:14:15",
+		},
+		{
+			description: "day with leading space",
+			input:       "<34>Oct  1 22:14:15 mymachine su: 'su root' failed",
+			expected:    "Oct  1 22:14:15",
+		},
+	}
+
+	for _, tc := range testCases {
+		p := NewParser([]byte(tc.input))
+		p.WithRawTimestamp()
+
+		err := p.Parse()
+		require.Nil(t, err, tc.description)
+		require.Equal(t, tc.expected, p.Dump()["timestamp_raw"], tc.description)
+	}
+}
+
+func TestParseTimestampPresence(t *testing.T) {
+	p := NewParser([]byte("<34>Oct 11 22:14:15 mymachine su: 'su root' failed"))
+	p.WithTimestampPresence()
+
+	err := p.Parse()
+	require.Nil(t, err)
+	require.True(t, p.HasTimestamp())
+	require.Equal(t, true, p.Dump()["timestamp_present"])
+}
+
+func TestParseWithoutHostname(t *testing.T) {
+	testCases := []struct {
+		description      string
+		input            string
+		heuristic        bool
+		expectedHostname string
+		expectedTag      string
+		expectedPid      string
+		expectedContent  string
+	}{
+		{
+			description:     "without hostname",
+			input:           "<13>Oct 11 22:14:15 myapp: started",
+			expectedTag:     "myapp",
+			expectedContent: "started",
+		},
+		{
+			description:     "heuristic, tag",
+			input:           "<13>Oct 11 22:14:15 myapp: started",
+			heuristic:       true,
+			expectedTag:     "myapp",
+			expectedContent: "started",
+		},
+		{
+			description:     "heuristic, tag and pid",
+			input:           "<13>Oct 11 22:14:15 sshd[42]: started",
+			heuristic:       true,
+			expectedTag:     "sshd",
+			expectedPid:     "42",
+			expectedContent: "started",
+		},
+		{
+			description:      "heuristic, hostname",
+			input:            "<13>Oct 11 22:14:15 mymachine myapp: started",
+			heuristic:        true,
+			expectedHostname: "mymachine",
+			expectedTag:      "myapp",
+			expectedContent:  "started",
+		},
+		{
+			description:      "heuristic, ipv6 hostname",
+			input:            "<13>Oct 11 22:14:15 fe80::1 myapp: started",
+			heuristic:        true,
+			expectedHostname: "fe80::1",
+			expectedTag:      "myapp",
+			expectedContent:  "started",
+		},
+		{
+			description: "heuristic, single word",
+			input:       "<13>Oct 11 22:14:15 myapp",
+			heuristic:   true,
+			expectedTag: "myapp",
+		},
+	}
+
+	for _, tc := range testCases {
+		p := NewParser([]byte(tc.input))
+		p.WithProvenance()
+
+		if tc.heuristic {
+			p.WithHostnameHeuristic()
+		} else {
+			p.WithoutHostname()
+		}
+
+		err := p.Parse()
+		require.Nil(t, err, tc.description)
+
+		obtained := p.Dump()
+		require.Equal(t, tc.expectedHostname, obtained["hostname"], tc.description)
+		require.Equal(t, tc.expectedTag, obtained["tag"], tc.description)
+		require.Equal(t, tc.expectedPid, obtained["pid"], tc.description)
+		require.Equal(t, tc.expectedContent, obtained["content"], tc.description)
+
+		expectedProvenance := parsercommon.PROVENANCE_PARSED
+		if tc.expectedHostname == "" {
+			expectedProvenance = parsercommon.PROVENANCE_DEFAULT
+		}
+
+		prov := obtained["provenance"].(map[string]string)
+		require.Equal(t, expectedProvenance, prov["hostname"], tc.description)
+	}
+}
+
+func TestParseWithHostnameValidation(t *testing.T) {
+	testCases := []struct {
+		description      string
+		input            string
+		expectedHostname string
+		expectedTag      string
+		expectedContent  string
+	}{
+		{
+			description:      "FQDN",
+			input:            "<13>Oct 11 22:14:15 mymachine.example.com myapp: started",
+			expectedHostname: "mymachine.example.com",
+			expectedTag:      "myapp",
+			expectedContent:  "started",
+		},
+		{
+			description:      "IPv4",
+			input:            "<13>Oct 11 22:14:15 192.0.2.1 myapp: started",
+			expectedHostname: "192.0.2.1",
+			expectedTag:      "myapp",
+			expectedContent:  "started",
+		},
+		{
+			description:      "IPv6",
+			input:            "<13>Oct 11 22:14:15 2001:db8::1 myapp: started",
+			expectedHostname: "2001:db8::1",
+			expectedTag:      "myapp",
+			expectedContent:  "started",
+		},
+		{
+			description:     "tag with colon",
+			input:           "<13>Oct 11 22:14:15 myapp: started",
+			expectedTag:     "myapp",
+			expectedContent: "started",
+		},
+		{
+			description:     "tag and pid",
+			input:           "<13>Oct 11 22:14:15 sshd[42]: started",
+			expectedTag:     "sshd",
+			expectedContent: "started",
+		},
+		{
+			description:     "control character",
+			input:           "<13>Oct 11 22:14:15 my\x01machine myapp: started",
+			expectedTag:     "my\x01machine",
+			expectedContent: "myapp: started",
+		},
+	}
+
+	for _, tc := range testCases {
+		p := NewParser([]byte(tc.input))
+		p.WithHostnameValidation()
+
+		err := p.Parse()
+		require.Nil(t, err, tc.description)
+
+		obtained := p.Dump()
+		require.Equal(t, tc.expectedHostname, obtained["hostname"], tc.description)
+		require.Equal(t, tc.expectedTag, obtained["tag"], tc.description)
+		require.Equal(t, tc.expectedContent, obtained["content"], tc.description)
+	}
+}
+
+func TestParseLocal(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		description      string
+		input            string
+		hostname         string
+		expectedPriority int
+		expectedHostname string
+	}{
+		{
+			description:      "glibc",
+			input:            "<13>Oct 11 22:14:15 myapp[42]: started",
+			expectedPriority: 13,
+		},
+		{
+			description:      "without priority",
+			input:            "Oct 11 22:14:15 myapp[42]: started",
+			expectedPriority: parsercommon.DEFAULT_PRIORITY,
+		},
+		{
+			description:      "local hostname",
+			input:            "<14>Oct 11 22:14:15 myapp[42]: started",
+			hostname:         "localhost",
+			expectedPriority: 14,
+			expectedHostname: "localhost",
+		},
+	}
+
+	for _, tc := range testCases {
+		p := NewParser([]byte(tc.input))
+		p.WithLocal()
+
+		if tc.hostname != "" {
+			p.WithHostname(tc.hostname)
+		}
+
+		err := p.Parse()
+		require.Nil(t, err, tc.description)
+
+		require.Equal(
+			t,
+			parsercommon.LogParts{
+				"timestamp": time.Date(now.Year(), time.October, 11, 22, 14, 15, 0, time.UTC),
+				"hostname":  tc.expectedHostname,
+				"tag":       "myapp",
+				"pid":       "42",
+				"content":   "started",
+				"priority":  tc.expectedPriority,
+				"facility":  tc.expectedPriority / 8,
+				"severity":  tc.expectedPriority % 8,
+				"version":   parsercommon.NO_VERSION,
+			},
+			p.Dump(),
+			tc.description,
+		)
+	}
+}
+
+func TestParseWithKernelOffset(t *testing.T) {
+	testCases := []struct {
+		description     string
+		input           string
+		kernelOffset    bool
+		expectedOffset  interface{}
+		expectedContent string
+	}{
+		{
+			description:     "kernel",
+			input:           "<6>Oct 11 22:14:15 mymachine kernel: [12345.678901] usb 1-1: new device",
+			kernelOffset:    true,
+			expectedOffset:  12345*time.Second + 678901*time.Microsecond,
+			expectedContent: "usb 1-1: new device",
+		},
+		{
+			description:     "disabled",
+			input:           "<6>Oct 11 22:14:15 mymachine kernel: [12345.678901] usb 1-1: new device",
+			expectedOffset:  nil,
+			expectedContent: "[12345.678901] usb 1-1: new device",
+		},
+		{
+			description:     "not kernel",
+			input:           "<6>Oct 11 22:14:15 mymachine myapp: [12345.678901] usb 1-1: new device",
+			kernelOffset:    true,
+			expectedOffset:  nil,
+			expectedContent: "[12345.678901] usb 1-1: new device",
+		},
+	}
+
+	for _, tc := range testCases {
+		p := NewParser([]byte(tc.input))
+
+		if tc.kernelOffset {
+			p.WithKernelOffset()
+		}
+
+		err := p.Parse()
+		require.Nil(t, err, tc.description)
+
+		obtained := p.Dump()
+		require.Equal(t, tc.expectedOffset, obtained["boot_offset"], tc.description)
+		require.Equal(t, tc.expectedContent, obtained["content"], tc.description)
+	}
+}
+
+func TestParseWithCEE(t *testing.T) {
+	testCases := []struct {
+		description   string
+		input         string
+		cee           bool
+		expectedCEE   interface{}
+		expectedError interface{}
+	}{
+		{
+			description: "cookie in content",
+			input:       `<13>Oct 11 22:14:15 mymachine myapp: @cee: {"event":"login","user":"bob"}`,
+			cee:         true,
+			expectedCEE: map[string]interface{}{
+				"event": "login",
+				"user":  "bob",
+			},
+			expectedError: nil,
+		},
+		{
+			description: "cookie as tag",
+			input:       `<13>Oct 11 22:14:15 mymachine @cee:{"event":"login", "user":"bob"}`,
+			cee:         true,
+			expectedCEE: map[string]interface{}{
+				"event": "login",
+				"user":  "bob",
+			},
+			expectedError: nil,
+		},
+		{
+			description:   "no cookie",
+			input:         `<13>Oct 11 22:14:15 mymachine myapp: {"event":"login"}`,
+			cee:           true,
+			expectedCEE:   nil,
+			expectedError: nil,
+		},
+		{
+			description:   "invalid json",
+			input:         `<13>Oct 11 22:14:15 mymachine myapp: @cee: {"event":`,
+			cee:           true,
+			expectedCEE:   nil,
+			expectedError: parsercommon.ErrInvalidCEE.Error(),
+		},
+		{
+			description:   "disabled",
+			input:         `<13>Oct 11 22:14:15 mymachine myapp: @cee: {"event":"login"}`,
+			expectedCEE:   nil,
+			expectedError: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		p := NewParser([]byte(tc.input))
+
+		if tc.cee {
+			p.WithCEE()
+		}
+
+		err := p.Parse()
+		require.Nil(t, err, tc.description)
+
+		obtained := p.Dump()
+		require.Equal(t, tc.expectedCEE, obtained["cee"], tc.description)
+		require.Equal(t, tc.expectedError, obtained["cee_error"], tc.description)
+	}
+}
+
+func TestDumpMessage(t *testing.T) {
+	buffs := []string{
+		"<34>Oct 11 22:14:15 mymachine su: 'su root' failed for lonvick on /dev/pts/8",
+		"<34>Oct 11 22:14:15 mymachine sshd[1234]: accepted",
+	}
+
+	for _, buff := range buffs {
+		p := NewParser([]byte(buff))
+		err := p.Parse()
+		require.Nil(t, err)
+
+		m := parsercommon.Message{Hostname: "previous"}
+		p.DumpMessage(&m)
+
+		require.Equal(t, p.Dump(), m.Parts(), buff)
+	}
+}
+
+func TestParserWithDiagnostics(t *testing.T) {
+	buff := []byte(
+		"<34>Oct 11 22:14:15 mymachine su: 'su root' failed for lonvick on /dev/pts/8",
+	)
+
+	p := NewParser(buff)
+	err := p.Parse()
+	require.Nil(t, err)
+
+	obtained := p.Dump()
+	require.NotContains(t, obtained, "parser")
+	require.NotContains(t, obtained, "parse_duration")
+
+	p = NewParser(buff)
+	p.WithDiagnostics()
+
+	err = p.Parse()
+	require.Nil(t, err)
+
+	obtained = p.Dump()
+	require.Equal(t, PARSER_NAME, obtained["parser"])
+	require.IsType(t, time.Duration(0), obtained["parse_duration"])
+}
+
 func TestParseHeader(t *testing.T) {
 	date := time.Date(
 		time.Now().Year(),
@@ -340,8 +1236,8 @@ func TestParseHeader(t *testing.T) {
 		p := NewParser([]byte(tc.input))
 		obtained, err := p.parseHeader()
 
-		require.Equal(
-			t, tc.expectedErr, err, tc.description,
+		require.True(
+			t, errors.Is(err, tc.expectedErr), tc.description,
 		)
 
 		require.Equal(
@@ -349,7 +1245,7 @@ func TestParseHeader(t *testing.T) {
 		)
 
 		require.Equal(
-			t, tc.expectedCursorPos, p.cursor, tc.description,
+			t, tc.expectedCursorPos, p.cursor.Pos(), tc.description,
 		)
 	}
 }
@@ -361,6 +1257,7 @@ func TestParsemessage(t *testing.T) {
 
 	msg := &message{
 		tag:     "sometag",
+		pid:     "123",
 		content: content,
 	}
 
@@ -376,7 +1273,7 @@ func TestParsemessage(t *testing.T) {
 	)
 
 	require.Equal(
-		t, len(buff), p.cursor,
+		t, len(buff), p.cursor.Pos(),
 	)
 }
 
@@ -441,20 +1338,31 @@ func TestParseTimestamp(t *testing.T) {
 		)
 
 		require.Equal(
-			t, tc.expectedCursorPos, p.cursor, tc.description,
+			t, tc.expectedCursorPos, p.cursor.Pos(), tc.description,
 		)
 
-		require.Equal(
-			t, tc.expectedErr, err, tc.description,
+		require.True(
+			t, errors.Is(err, tc.expectedErr), tc.description,
 		)
 	}
 }
 
+func TestParseTimestampCause(t *testing.T) {
+	p := NewParser([]byte("Oct 34 32:72:82 mymachine "))
+
+	_, err := p.parseTimestamp()
+
+	var parseErr *time.ParseError
+	require.True(t, errors.As(err, &parseErr))
+	require.Equal(t, "Oct 34 32:72:82", parseErr.Value)
+}
+
 func TestParseTag(t *testing.T) {
 	testCases := []struct {
 		description       string
 		input             string
 		expectedTag       string
+		expectedPid       string
 		expectedCursorPos int
 		expectedErr       error
 	}{
@@ -462,6 +1370,7 @@ func TestParseTag(t *testing.T) {
 			description:       "with pid",
 			input:             "apache2[10]:",
 			expectedTag:       "apache2",
+			expectedPid:       "10",
 			expectedCursorPos: 12,
 			expectedErr:       nil,
 		},
@@ -479,25 +1388,72 @@ func TestParseTag(t *testing.T) {
 			expectedCursorPos: 9,
 			expectedErr:       nil,
 		},
+		{
+			description:       "longer than allowed by RFC 3164",
+			input:             strings.Repeat("a", 40) + ":",
+			expectedTag:       strings.Repeat("a", 40),
+			expectedCursorPos: 41,
+			expectedErr:       nil,
+		},
+		{
+			description:       "end of buffer",
+			input:             "apache2",
+			expectedTag:       "apache2",
+			expectedCursorPos: 7,
+			expectedErr:       nil,
+		},
+		{
+			description:       "end of buffer after the opening bracket",
+			input:             "apache2[",
+			expectedTag:       "apache2",
+			expectedCursorPos: 8,
+			expectedErr:       nil,
+		},
+		{
+			description:       "end of buffer in the pid",
+			input:             "apache2[10",
+			expectedTag:       "apache2",
+			expectedCursorPos: 10,
+			expectedErr:       nil,
+		},
+		{
+			description:       "end of buffer after the pid",
+			input:             "apache2[10]",
+			expectedTag:       "apache2",
+			expectedPid:       "10",
+			expectedCursorPos: 11,
+			expectedErr:       nil,
+		},
+		{
+			description:       "empty",
+			input:             "",
+			expectedTag:       "",
+			expectedCursorPos: 0,
+			expectedErr:       nil,
+		},
 		{
 			description:       "super long",
 			input:             strings.Repeat("a", 50) + "",
-			expectedTag:       strings.Repeat("a", 32),
-			expectedCursorPos: 32,
+			expectedTag:       strings.Repeat("a", RELAXED_MAX_TAG_LEN),
+			expectedCursorPos: RELAXED_MAX_TAG_LEN,
 			expectedErr:       nil,
 		},
 	}
 
 	for _, tc := range testCases {
 		p := NewParser([]byte(tc.input))
-		obtained, err := p.parseTag()
+		obtained, pid, err := p.parseTag()
 
 		require.Equal(
 			t, obtained, tc.expectedTag, tc.description,
 		)
 
 		require.Equal(
-			t, tc.expectedCursorPos, p.cursor, tc.description,
+			t, tc.expectedPid, pid, tc.description,
+		)
+
+		require.Equal(
+			t, tc.expectedCursorPos, p.cursor.Pos(), tc.description,
 		)
 
 		require.Equal(
@@ -506,6 +1462,65 @@ func TestParseTag(t *testing.T) {
 	}
 }
 
+func TestParseWithTagLenCheck(t *testing.T) {
+	start := "<34>Oct 11 22:14:15 mymachine "
+
+	testCases := []struct {
+		description string
+		tag         string
+		expectedErr error
+	}{
+		{
+			description: "short",
+			tag:         "su",
+		},
+		{
+			description: "longest",
+			tag:         strings.Repeat("a", MAX_TAG_LEN),
+		},
+		{
+			description: "too long",
+			tag:         strings.Repeat("a", MAX_TAG_LEN+1),
+			expectedErr: ErrInvalidTag,
+		},
+		{
+			description: "too long for the relaxed limit",
+			tag:         strings.Repeat("a", RELAXED_MAX_TAG_LEN+1),
+			expectedErr: ErrInvalidTag,
+		},
+	}
+
+	for _, tc := range testCases {
+		input := start + tc.tag + "[42]: 'su root' failed"
+
+		p := NewParser([]byte(input))
+		p.WithTagLenCheck()
+
+		err := p.Parse()
+		if tc.expectedErr == nil {
+			require.Nil(t, err, tc.description)
+			require.Equal(t, tc.tag, p.Dump()["tag"], tc.description)
+			continue
+		}
+
+		require.ErrorIs(t, err, tc.expectedErr, tc.description)
+
+		pe := err.(*parsercommon.ParserError)
+		require.Equal(t, "tag", pe.Field(), tc.description)
+		require.Equal(t, len(start)+MAX_TAG_LEN, pe.Offset(), tc.description)
+
+		// tolerated by default
+		p = NewParser([]byte(input))
+		require.Nil(t, p.Parse(), tc.description)
+	}
+
+	p := NewParser([]byte(start + strings.Repeat("a", MAX_TAG_LEN+1) + ": ok"))
+	p.WithTagLenCheck()
+	p.WithTag("su")
+
+	require.Nil(t, p.Parse())
+}
+
 func TestParseContent(t *testing.T) {
 	buff := []byte(" foo bar baz quux ")
 	content := string(bytes.Trim(buff, " "))
@@ -522,7 +1537,7 @@ func TestParseContent(t *testing.T) {
 	)
 
 	require.Equal(
-		t, len(content), p.cursor,
+		t, len(content), p.cursor.Pos(),
 	)
 }
 
@@ -544,6 +1559,8 @@ func TestParseMessageSizeChecks(t *testing.T) {
 		MAX_PACKET_LEN-len(start),
 	)
 
+	require.Equal(t, true, fields["truncated"])
+
 	// ---
 
 	msg = start + "hello"
@@ -558,6 +1575,8 @@ func TestParseMessageSizeChecks(t *testing.T) {
 	require.Equal(
 		t, "hello", fields["content"],
 	)
+
+	require.NotContains(t, fields, "truncated")
 }
 
 func TestParseWithoutTag(t *testing.T) {
@@ -571,17 +1590,19 @@ func TestParseWithoutTag(t *testing.T) {
 	now := time.Now()
 
 	obtained := p.Dump()
-	expected := syslogparser.LogParts{
+	expected := parsercommon.LogParts{
 		"timestamp": time.Date(
 			now.Year(), time.June, 23,
 			13, 17, 42, 0, time.UTC,
 		),
 		"hostname": "127.0.0.1",
 		"tag":      "java.lang.NullPointerException",
+		"pid":      "",
 		"content":  "",
 		"priority": 30,
 		"facility": 3,
 		"severity": 6,
+		"version":  parsercommon.NO_VERSION,
 	}
 
 	require.Equal(
@@ -589,6 +1610,19 @@ func TestParseWithoutTag(t *testing.T) {
 	)
 }
 
+func TestParseTruncated(t *testing.T) {
+	msg := "<34>Oct 11 22:14:15 mymachine su[12]: 'su root' failed for lonvick on /dev/pts/8"
+
+	// every prefix must be rejected or parsed, not panic
+	for i := 0; i <= len(msg); i++ {
+		p := NewParser([]byte(msg[:i]))
+
+		require.NotPanics(t, func() {
+			_ = p.Parse()
+		}, msg[:i])
+	}
+}
+
 func BenchmarkParseTimestamp(b *testing.B) {
 	buff := []byte("Oct 11 22:14:15")
 
@@ -600,7 +1634,7 @@ func BenchmarkParseTimestamp(b *testing.B) {
 			panic(err)
 		}
 
-		p.cursor = 0
+		p.cursor.SetPos(0)
 	}
 }
 
@@ -615,7 +1649,7 @@ func BenchmarkParseHostname(b *testing.B) {
 			panic(err)
 		}
 
-		p.cursor = 0
+		p.cursor.SetPos(0)
 	}
 }
 
@@ -625,12 +1659,12 @@ func BenchmarkParseTag(b *testing.B) {
 	p := NewParser(buff)
 
 	for i := 0; i < b.N; i++ {
-		_, err := p.parseTag()
+		_, _, err := p.parseTag()
 		if err != nil {
 			panic(err)
 		}
 
-		p.cursor = 0
+		p.cursor.SetPos(0)
 	}
 }
 
@@ -645,7 +1679,7 @@ func BenchmarkParseHeader(b *testing.B) {
 			panic(err)
 		}
 
-		p.cursor = 0
+		p.cursor.SetPos(0)
 	}
 }
 
@@ -660,7 +1694,7 @@ func BenchmarkParsemessage(b *testing.B) {
 			panic(err)
 		}
 
-		p.cursor = 0
+		p.cursor.SetPos(0)
 	}
 }
 
@@ -679,6 +1713,23 @@ func BenchmarkParseFull(b *testing.B) {
 	}
 }
 
+func BenchmarkParseFullZeroCopy(b *testing.B) {
+	msg := "<34>Oct 11 22:14:15 mymachine su: 'su root' failed for lonvick on /dev/pts/8"
+
+	for i := 0; i < b.N; i++ {
+		p := NewParser(
+			[]byte(msg),
+		)
+
+		p.WithZeroCopy()
+
+		err := p.Parse()
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
 func TestBenchmarkParseTimestamp(t *testing.T) {
 	type args struct {
 		b *testing.B
@@ -780,3 +1831,388 @@ func TestBenchmarkParseFull(t *testing.T) {
 		})
 	}
 }
+
+func TestParseWithJSON(t *testing.T) {
+	testCases := []struct {
+		description   string
+		input         string
+		maxDepth      int
+		maxSize       int
+		expectedJSON  interface{}
+		expectedError interface{}
+	}{
+		{
+			description: "json",
+			input:       `<13>Oct 11 22:14:15 mymachine myapp: {"log":"started","stream":"stdout"}`,
+			maxDepth:    0,
+			maxSize:     0,
+			expectedJSON: map[string]interface{}{
+				"log":    "started",
+				"stream": "stdout",
+			},
+			expectedError: nil,
+		},
+		{
+			description: "no tag",
+			input:       `<13>Oct 11 22:14:15 mymachine {"log":"started", "stream":"stdout"}`,
+			maxDepth:    0,
+			maxSize:     0,
+			expectedJSON: map[string]interface{}{
+				"log":    "started",
+				"stream": "stdout",
+			},
+			expectedError: nil,
+		},
+		{
+			description:   "not json",
+			input:         `<13>Oct 11 22:14:15 mymachine myapp: {started}`,
+			maxDepth:      0,
+			maxSize:       0,
+			expectedJSON:  nil,
+			expectedError: nil,
+		},
+		{
+			description:   "too deep",
+			input:         `<13>Oct 11 22:14:15 mymachine myapp: {"attrs":{"tag":"web"}}`,
+			maxDepth:      1,
+			maxSize:       0,
+			expectedJSON:  nil,
+			expectedError: parsercommon.ErrJSONTooDeep.Error(),
+		},
+		{
+			description:   "too large",
+			input:         `<13>Oct 11 22:14:15 mymachine myapp: {"log":"started"}`,
+			maxDepth:      0,
+			maxSize:       8,
+			expectedJSON:  nil,
+			expectedError: parsercommon.ErrJSONTooLarge.Error(),
+		},
+	}
+
+	for _, tc := range testCases {
+		p := NewParser([]byte(tc.input))
+		p.WithJSON(tc.maxDepth, tc.maxSize)
+
+		err := p.Parse()
+		require.Nil(t, err, tc.description)
+
+		obtained := p.Dump()
+		require.Equal(t, tc.expectedJSON, obtained["json"], tc.description)
+		require.Equal(t, tc.expectedError, obtained["json_error"], tc.description)
+	}
+}
+
+func TestParseTrailer(t *testing.T) {
+	testCases := []struct {
+		description     string
+		input           string
+		expectedContent string
+	}{
+		{
+			description:     "LF",
+			input:           "<34>Oct 11 22:14:15 mymachine su: 'su root' failed\n",
+			expectedContent: "'su root' failed",
+		},
+		{
+			description:     "CRLF",
+			input:           "<34>Oct 11 22:14:15 mymachine su: 'su root' failed\r\n",
+			expectedContent: "'su root' failed",
+		},
+		{
+			description:     "NUL",
+			input:           "<34>Oct 11 22:14:15 mymachine su: 'su root' failed\x00",
+			expectedContent: "'su root' failed",
+		},
+		{
+			description:     "inner LF kept",
+			input:           "<34>Oct 11 22:14:15 mymachine su: first\nsecond\r\n",
+			expectedContent: "first\nsecond",
+		},
+	}
+
+	for _, tc := range testCases {
+		p := NewParser([]byte(tc.input))
+
+		err := p.Parse()
+		require.Nil(t, err, tc.description)
+		require.Equal(t, tc.expectedContent, p.Dump()["content"], tc.description)
+	}
+}
+
+func TestParseWithMaxPacketLen(t *testing.T) {
+	start := "<34>Oct 11 22:14:15 mymachine su: "
+	msg := start + strings.Repeat("a", 8192)
+
+	testCases := []struct {
+		description string
+		maxLen      int
+		expectedLen int
+	}{
+		{
+			description: "raised",
+			maxLen:      65536,
+			expectedLen: 8192,
+		},
+		{
+			description: "lowered",
+			maxLen:      len(start) + 16,
+			expectedLen: 16,
+		},
+		{
+			description: "unlimited",
+			maxLen:      0,
+			expectedLen: 8192,
+		},
+	}
+
+	for _, tc := range testCases {
+		p := NewParser([]byte(msg))
+		p.WithMaxPacketLen(tc.maxLen)
+
+		err := p.Parse()
+		require.Nil(t, err, tc.description)
+		require.Len(t, p.Dump()["content"], tc.expectedLen, tc.description)
+	}
+}
+
+func TestParseIPv6Hostname(t *testing.T) {
+	testCases := []struct {
+		description string
+		hostname    string
+		options     func(p *Parser)
+	}{
+		{
+			description: "unbracketed",
+			hostname:    "2001:db8::1",
+		},
+		{
+			description: "bracketed",
+			hostname:    "[2001:db8::1]",
+		},
+		{
+			description: "unbracketed, heuristic",
+			hostname:    "2001:db8::1",
+			options:     func(p *Parser) { p.WithHostnameHeuristic() },
+		},
+		{
+			description: "bracketed, heuristic",
+			hostname:    "[2001:db8::1]",
+			options:     func(p *Parser) { p.WithHostnameHeuristic() },
+		},
+		{
+			description: "bracketed, validation",
+			hostname:    "[2001:db8::1]",
+			options:     func(p *Parser) { p.WithHostnameValidation() },
+		},
+	}
+
+	for _, tc := range testCases {
+		p := NewParser([]byte("<13>Oct 11 22:14:15 " + tc.hostname + " sshd[42]: started"))
+		if tc.options != nil {
+			tc.options(p)
+		}
+
+		p.WithHostnameType()
+
+		err := p.Parse()
+		require.Nil(t, err, tc.description)
+
+		obtained := p.Dump()
+		require.Equal(t, tc.hostname, obtained["hostname"], tc.description)
+		require.Equal(t, parsercommon.HOSTNAME_TYPE_IP6, obtained["hostname_type"], tc.description)
+		require.Equal(t, "sshd", obtained["tag"], tc.description)
+		require.Equal(t, "42", obtained["pid"], tc.description)
+		require.Equal(t, "started", obtained["content"], tc.description)
+	}
+}
+
+func TestParseWithHostnameType(t *testing.T) {
+	testCases := []struct {
+		description  string
+		input        string
+		expectedType interface{}
+	}{
+		{
+			description:  "IPv4",
+			input:        "<13>Oct 11 22:14:15 192.0.2.1 myapp: started",
+			expectedType: parsercommon.HOSTNAME_TYPE_IP4,
+		},
+		{
+			description:  "FQDN",
+			input:        "<13>Oct 11 22:14:15 mymachine.example.com myapp: started",
+			expectedType: parsercommon.HOSTNAME_TYPE_FQDN,
+		},
+		{
+			description:  "name",
+			input:        "<13>Oct 11 22:14:15 mymachine myapp: started",
+			expectedType: parsercommon.HOSTNAME_TYPE_NAME,
+		},
+	}
+
+	for _, tc := range testCases {
+		p := NewParser([]byte(tc.input))
+		p.WithHostnameType()
+
+		err := p.Parse()
+		require.Nil(t, err, tc.description)
+		require.Equal(t, tc.expectedType, p.Dump()["hostname_type"], tc.description)
+	}
+
+	p := NewParser([]byte("<13>Oct 11 22:14:15 myapp: started"))
+	p.WithoutHostname()
+	p.WithHostnameType()
+
+	require.Nil(t, p.Parse())
+	require.NotContains(t, p.Dump(), "hostname_type")
+}
+
+func TestParseLongHostname(t *testing.T) {
+	long := strings.Repeat("a", parsercommon.MAX_HOSTNAME_LEN+1)
+
+	for _, input := range []string{
+		"<34>Oct 11 22:14:15 " + long + " su: 'su root' failed",
+		"<34>" + long + " Oct 11 22:14:15 su: 'su root' failed",
+	} {
+		p := NewParser([]byte(input))
+		p.WithSwappedHeader()
+
+		err := p.Parse()
+		require.ErrorIs(t, err, ErrInvalidHostname, input)
+		require.Equal(t, "hostname", err.(*parsercommon.ParserError).Field(), input)
+
+		p = NewParser([]byte(input))
+		p.WithSwappedHeader()
+		p.WithLongHostname()
+
+		require.Nil(t, p.Parse(), input)
+		require.Equal(t, long, p.Dump()["hostname"], input)
+		require.Equal(t, "su", p.Dump()["tag"], input)
+
+		p = NewParser([]byte(input))
+		p.WithLenient()
+
+		require.Nil(t, p.Parse(), input)
+		require.Equal(t, long, p.Dump()["hostname"], input)
+	}
+
+	p := NewParser([]byte("<34>Oct 11 22:14:15 " + long[1:] + " su: 'su root' failed"))
+	require.Nil(t, p.Parse())
+}
+
+func TestParseWithProgramSplit(t *testing.T) {
+	p := NewParser([]byte("<22>Oct 11 22:14:15 mail postfix/smtpd[1234]: connect from unknown[192.0.2.1]"))
+	p.WithProgramSplit()
+
+	err := p.Parse()
+	require.Nil(t, err)
+
+	obtained := p.Dump()
+	require.Equal(t, "postfix/smtpd", obtained["tag"])
+	require.Equal(t, "1234", obtained["pid"])
+	require.Equal(t, "postfix", obtained["program"])
+	require.Equal(t, "smtpd", obtained["subprogram"])
+
+	p = NewParser([]byte("<22>Oct 11 22:14:15 mail sshd[1234]: started"))
+	p.WithProgramSplit()
+
+	err = p.Parse()
+	require.Nil(t, err)
+	require.NotContains(t, p.Dump(), "program")
+	require.NotContains(t, p.Dump(), "subprogram")
+}
+
+func TestParseWithNoTag(t *testing.T) {
+	testCases := []struct {
+		description      string
+		input            string
+		noHostname       bool
+		expectedHostname string
+		expectedContent  string
+	}{
+		{
+			description:      "with hostname",
+			input:            "<13>Oct 11 22:14:15 mymachine link down on port 3",
+			expectedHostname: "mymachine",
+			expectedContent:  "link down on port 3",
+		},
+		{
+			description:     "without hostname",
+			input:           "<13>Oct 11 22:14:15 link down on port 3",
+			noHostname:      true,
+			expectedContent: "link down on port 3",
+		},
+		{
+			description:      "colon kept in content",
+			input:            "<13>Oct 11 22:14:15 mymachine sshd[42]: started",
+			expectedHostname: "mymachine",
+			expectedContent:  "sshd[42]: started",
+		},
+	}
+
+	for _, tc := range testCases {
+		p := NewParser([]byte(tc.input))
+		p.WithNoTag()
+		p.WithProvenance()
+
+		if tc.noHostname {
+			p.WithoutHostname()
+		}
+
+		err := p.Parse()
+		require.Nil(t, err, tc.description)
+
+		obtained := p.Dump()
+		require.Equal(t, tc.expectedHostname, obtained["hostname"], tc.description)
+		require.Equal(t, "", obtained["tag"], tc.description)
+		require.Equal(t, "", obtained["pid"], tc.description)
+		require.Equal(t, tc.expectedContent, obtained["content"], tc.description)
+
+		prov := obtained["provenance"].(map[string]string)
+		require.Equal(t, parsercommon.PROVENANCE_DEFAULT, prov["tag"], tc.description)
+	}
+
+	// WithStrict() takes precedence
+	p := NewParser([]byte("<13>Oct 11 22:14:15 mymachine su: started"))
+	p.WithNoTag()
+	p.WithStrict()
+
+	err := p.Parse()
+	require.Nil(t, err)
+	require.Equal(t, "su", p.Dump()["tag"])
+}
+
+func TestParseTruncatedTag(t *testing.T) {
+	input := "<34>Oct 11 22:14:15 mymachine sshd[1234]: 'su root' failed"
+	start := len("<34>Oct 11 22:14:15 mymachine ")
+
+	testCases := []struct {
+		description string
+		l           int
+		expectedTag string
+		expectedPid string
+	}{
+		{"before the tag", start, "", ""},
+		{"in the tag", start + 2, "ss", ""},
+		{"after the tag", start + 4, "sshd", ""},
+		{"in the pid", start + 7, "sshd", ""},
+		{"after the pid", start + 10, "sshd", "1234"},
+	}
+
+	for _, tc := range testCases {
+		// as cut by the length limit or sent truncated, the buffer ending
+		// right after the limit
+		for _, buff := range [][]byte{[]byte(input), []byte(input[:tc.l])} {
+			p := NewParser(buff)
+			p.WithMaxPacketLen(tc.l)
+
+			err := p.Parse()
+			require.Nil(t, err, tc.description)
+
+			obtained := p.Dump()
+			require.Equal(t, "mymachine", obtained["hostname"], tc.description)
+			require.Equal(t, tc.expectedTag, obtained["tag"], tc.description)
+			require.Equal(t, tc.expectedPid, obtained["pid"], tc.description)
+			require.Equal(t, "", obtained["content"], tc.description)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package rfc3164
+
+import (
+	"github.com/jeromer/syslogparser/parsercommon"
+)
+
+// fields whose span is recorded, named as in Dump()
+var spanFields = [...]string{
+	"priority",
+	"timestamp",
+	"hostname",
+	"tag",
+	"pid",
+	"content",
+}
+
+// Offsets of CONTENT in the parsed buffer, surrounding spaces excluded, so it
+// can be forwarded without being copied. The boot offset stripped by
+// WithKernelOffset() is part of it.
+func (p *Parser) MessageSpan() parsercommon.Span {
+	return p.spans[len(spanFields)-1]
+}
+
+// Offsets in the parsed buffer of the header fields, the tag, the process ID
+// and CONTENT, keyed as in Dump(). Fields absent from the message or set with
+// WithHostname(), WithTag() or WithPriority() have empty spans.
+func (p *Parser) FieldSpans() map[string]parsercommon.Span {
+	spans := make(map[string]parsercommon.Span, len(spanFields))
+
+	for i, f := range spanFields {
+		spans[f] = p.spans[i]
+	}
+
+	return spans
+}
+
+// Records the span of the field being parsed, from its beginning to the
+// cursor
+func (p *Parser) endSpan() {
+	p.setSpan(p.field, p.fieldPos, p.cursor.Pos())
+}
+
+func (p *Parser) setSpan(field string, start int, end int) {
+	for i, f := range spanFields {
+		if f == field {
+			p.spans[i] = parsercommon.Span{Start: start, End: end}
+			return
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package rfc3164
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldSpans(t *testing.T) {
+	testCases := []struct {
+		description string
+		input       string
+		options     func(p *Parser)
+		expected    map[string]string
+	}{
+		{
+			description: "all fields",
+			input:       "<34>Oct 11 22:14:15 mymachine sshd[1234]: 'su root' failed",
+			expected: map[string]string{
+				"priority":  "<34>",
+				"timestamp": "Oct 11 22:14:15",
+				"hostname":  "mymachine",
+				"tag":       "sshd",
+				"pid":       "1234",
+				"content":   "'su root' failed",
+			},
+		},
+		{
+			description: "no pid, content surrounded by spaces",
+			input:       "<34>Oct 11 22:14:15 mymachine su:   hello  ",
+			expected: map[string]string{
+				"priority":  "<34>",
+				"timestamp": "Oct 11 22:14:15",
+				"hostname":  "mymachine",
+				"tag":       "su",
+				"pid":       "",
+				"content":   "hello",
+			},
+		},
+		{
+			description: "swapped header",
+			input:       "<34>mymachine Oct 11 22:14:15 su: hello",
+			options:     func(p *Parser) { p.WithSwappedHeader() },
+			expected: map[string]string{
+				"priority":  "<34>",
+				"timestamp": "Oct 11 22:14:15",
+				"hostname":  "mymachine",
+				"tag":       "su",
+				"pid":       "",
+				"content":   "hello",
+			},
+		},
+		{
+			description: "lenient timestamp",
+			input:       "<34>2003-10-11 22:14:15 mymachine su: hello",
+			options:     func(p *Parser) { p.WithLenient() },
+			expected: map[string]string{
+				"priority":  "<34>",
+				"timestamp": "2003-10-11 22:14:15",
+				"hostname":  "mymachine",
+				"tag":       "su",
+				"pid":       "",
+				"content":   "hello",
+			},
+		},
+		{
+			description: "forced hostname and tag",
+			input:       "<34>Oct 11 22:14:15 hello",
+			options: func(p *Parser) {
+				p.WithHostname("mymachine")
+				p.WithTag("su")
+			},
+			expected: map[string]string{
+				"priority":  "<34>",
+				"timestamp": "Oct 11 22:14:15",
+				"hostname":  "",
+				"tag":       "",
+				"pid":       "",
+				"content":   "hello",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		buff := []byte(tc.input)
+
+		p := NewParser(buff)
+		if tc.options != nil {
+			tc.options(p)
+		}
+
+		require.Nil(t, p.Parse(), tc.description)
+
+		obtained := make(map[string]string)
+		for k, s := range p.FieldSpans() {
+			obtained[k] = string(s.Bytes(buff))
+		}
+
+		require.Equal(t, tc.expected, obtained, tc.description)
+		require.Equal(
+			t, tc.expected["content"], string(p.MessageSpan().Bytes(buff)), tc.description,
+		)
+	}
+}
@@ -0,0 +1,89 @@
+package rfc3164
+
+import (
+	"net"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+)
+
+const (
+	// https://tools.ietf.org/html/rfc3164#section-4.1
+	// "The total length of the packet MUST be 1024 bytes or less"
+	STRICT_MAX_PACKET_LEN = 1024
+
+	// "Mmm dd hh:mm:ss", days below 10 being padded with a space
+	STRICT_TIMESTAMP_FORMAT = "Jan _2 15:04:05"
+)
+
+var (
+	ErrMessageTooLong  = &parsercommon.ParserError{ErrorString: "Message too long"}
+	ErrInvalidHostname = &parsercommon.ParserError{ErrorString: "Invalid hostname"}
+	ErrInvalidTag      = &parsercommon.ParserError{ErrorString: "Invalid tag"}
+)
+
+// Enforces RFC 3164 instead of tolerating common deviations, ie. for
+// conformance test rigs of embedded devices:
+//   - messages are at most STRICT_MAX_PACKET_LEN bytes long
+//   - PRI is present
+//   - the timestamp is "Mmm dd hh:mm:ss", days below 10 padded with a space
+//   - the hostname is made of letters, digits, '-' and '.' or is an IP
+//   - the tag is made of 1 to MAX_TAG_LEN alphanumerics
+//
+// WithLenientPriority(), WithDefaultPriority(), WithSwappedHeader(),
+// WithTimestampFormat(), WithoutHostname(), WithHostnameHeuristic(),
+// WithLongHostname(), WithNoTag(), WithEpochTimestamp() and
+// WithTimestampZone() are ignored.
+// Hostnames and tags set with WithHostname() and WithTag() are not checked.
+func (p *Parser) WithStrict() {
+	p.strict = true
+}
+
+func (p *Parser) checkLength() error {
+	if p.cursor.Len() > STRICT_MAX_PACKET_LEN || p.cursor.Truncated() {
+		return ErrMessageTooLong
+	}
+
+	return nil
+}
+
+// "Oct  2" but not "Oct 02", which time.Parse() accepts with "_2"
+func isStrictTimestamp(ts []byte) bool {
+	return len(ts) > 4 && ts[4] != '0'
+}
+
+func checkHostname(h []byte) error {
+	if len(h) == 0 {
+		return ErrInvalidHostname
+	}
+
+	if net.ParseIP(string(h)) != nil {
+		return nil
+	}
+
+	for _, c := range h {
+		if !isAlphanumeric(c) && c != '-' && c != '.' {
+			return ErrInvalidHostname
+		}
+	}
+
+	return nil
+}
+
+// next is the byte following the tag, which MUST NOT be alphanumeric
+func checkTag(tag string, next byte) error {
+	if len(tag) == 0 || len(tag) > MAX_TAG_LEN || isAlphanumeric(next) {
+		return ErrInvalidTag
+	}
+
+	for i := 0; i < len(tag); i++ {
+		if !isAlphanumeric(tag[i]) {
+			return ErrInvalidTag
+		}
+	}
+
+	return nil
+}
+
+func isAlphanumeric(c byte) bool {
+	return parsercommon.IsDigit(c) || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
@@ -0,0 +1,108 @@
+package rfc3164
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWithStrict(t *testing.T) {
+	testCases := []struct {
+		description   string
+		input         string
+		expectedField string
+		expectedErr   error
+	}{
+		{
+			description: "valid",
+			input:       "<34>Oct 11 22:14:15 mymachine su: 'su root' failed for lonvick on /dev/pts/8",
+		},
+		{
+			description: "valid with pid and space padded day",
+			input:       "<34>Oct  2 22:14:15 192.0.2.1 sshd[42]: Accepted publickey",
+		},
+		{
+			description: "valid IPv6 hostname",
+			input:       "<34>Oct 11 22:14:15 2001:db8::1 su: ok",
+		},
+		{
+			description:   "too long",
+			input:         "<34>Oct 11 22:14:15 mymachine su: " + strings.Repeat("a", STRICT_MAX_PACKET_LEN),
+			expectedField: "",
+			expectedErr:   ErrMessageTooLong,
+		},
+		{
+			description:   "no priority",
+			input:         "Oct 11 22:14:15 mymachine su: ok",
+			expectedField: "priority",
+			expectedErr:   parsercommon.ErrPriorityNoStart,
+		},
+		{
+			description:   "zero padded day",
+			input:         "<34>Oct 02 22:14:15 mymachine su: ok",
+			expectedField: "timestamp",
+			expectedErr:   parsercommon.ErrTimestampUnknownFormat,
+		},
+		{
+			description:   "hostname with underscore",
+			input:         "<34>Oct 11 22:14:15 my_machine su: ok",
+			expectedField: "hostname",
+			expectedErr:   ErrInvalidHostname,
+		},
+		{
+			description:   "missing hostname",
+			input:         "<34>Oct 11 22:14:15  su: ok",
+			expectedField: "hostname",
+			expectedErr:   ErrInvalidHostname,
+		},
+		{
+			description:   "tag not alphanumeric",
+			input:         "<34>Oct 11 22:14:15 mymachine very.large.syslog.message.tag: ok",
+			expectedField: "tag",
+			expectedErr:   ErrInvalidTag,
+		},
+		{
+			description:   "tag too long",
+			input:         "<34>Oct 11 22:14:15 mymachine " + strings.Repeat("a", MAX_TAG_LEN+1) + ": ok",
+			expectedField: "tag",
+			expectedErr:   ErrInvalidTag,
+		},
+		{
+			description:   "no tag",
+			input:         "<34>Oct 11 22:14:15 mymachine : ok",
+			expectedField: "tag",
+			expectedErr:   ErrInvalidTag,
+		},
+	}
+
+	for _, tc := range testCases {
+		p := NewParser([]byte(tc.input))
+		p.WithDefaultPriority()
+		p.WithHostnameHeuristic()
+		p.WithStrict()
+
+		err := p.Parse()
+		if tc.expectedErr == nil {
+			require.Nil(t, err, tc.description)
+			continue
+		}
+
+		require.ErrorIs(t, err, tc.expectedErr, tc.description)
+		require.Equal(
+			t, tc.expectedField, err.(*parsercommon.ParserError).Field(), tc.description,
+		)
+	}
+}
+
+func TestParseWithStrictForcedFields(t *testing.T) {
+	p := NewParser([]byte("<34>Oct 11 22:14:15 my_machine very.large.tag: ok"))
+	p.WithStrict()
+	p.WithHostname("mymachine")
+	p.WithTag("app")
+
+	err := p.Parse()
+	require.Nil(t, err)
+	require.Equal(t, "mymachine", p.Dump()["hostname"])
+}
@@ -0,0 +1,92 @@
+package rfc3164
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+)
+
+// Offsets in seconds of the zone abbreviations accepted by
+// WithTimestampZone(). Ambiguous ones are given their North American
+// meaning (CST), or left out (IST).
+var zoneAbbreviations = map[string]int{
+	"UTC":  0,
+	"GMT":  0,
+	"WET":  0,
+	"WEST": 1 * 3600,
+	"CET":  1 * 3600,
+	"CEST": 2 * 3600,
+	"EET":  2 * 3600,
+	"EEST": 3 * 3600,
+	"MSK":  3 * 3600,
+	"JST":  9 * 3600,
+	"KST":  9 * 3600,
+	"AEST": 10 * 3600,
+	"AEDT": 11 * 3600,
+	"NZST": 12 * 3600,
+	"NZDT": 13 * 3600,
+	"HST":  -10 * 3600,
+	"AKST": -9 * 3600,
+	"AKDT": -8 * 3600,
+	"PST":  -8 * 3600,
+	"PDT":  -7 * 3600,
+	"MST":  -7 * 3600,
+	"MDT":  -6 * 3600,
+	"CST":  -6 * 3600,
+	"CDT":  -5 * 3600,
+	"EST":  -5 * 3600,
+	"EDT":  -4 * 3600,
+}
+
+// Accepts a zone following the RFC 3164 timestamp, as sent by AIX, Solaris
+// and several appliances: an abbreviation ("Oct 11 22:14:15 EST") or an
+// offset ("Oct 11 22:14:15 +02:00", "-0500"). The timestamp is then in that
+// zone instead of the location set with WithLocation(). Only the
+// abbreviations of zoneAbbreviations are known, unknown words are parsed as
+// the hostname. WithStrict() takes precedence.
+func (p *Parser) WithTimestampZone() {
+	p.timestampZone = true
+}
+
+// Parses the zone following the timestamp, at from, and moves ts to it.
+// from is returned when there is none, the position following the zone and
+// its SP otherwise.
+func (p *Parser) parseTimestampZone(ts *time.Time, from int) int {
+	rest := p.cursor.Slice(from, p.cursor.Len())
+
+	end := bytes.IndexByte(rest, ' ')
+	if end <= 0 {
+		return from
+	}
+
+	loc := zoneLocation(string(rest[:end]))
+	if loc == nil {
+		return from
+	}
+
+	*ts = time.Date(
+		ts.Year(), ts.Month(), ts.Day(),
+		ts.Hour(), ts.Minute(), ts.Second(), ts.Nanosecond(),
+		loc,
+	)
+
+	return from + end + 1
+}
+
+func zoneLocation(name string) *time.Location {
+	if offset, ok := zoneAbbreviations[name]; ok {
+		return time.FixedZone(name, offset)
+	}
+
+	if name[0] != '+' && name[0] != '-' {
+		return nil
+	}
+
+	loc, err := parsercommon.ParseFixedZone(name)
+	if err != nil {
+		return nil
+	}
+
+	return loc
+}
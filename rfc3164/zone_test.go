@@ -0,0 +1,90 @@
+package rfc3164
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWithTimestampZone(t *testing.T) {
+	year := time.Now().Year()
+
+	testCases := []struct {
+		description      string
+		input            string
+		expectedOffset   int
+		expectedRaw      string
+		expectedHostname string
+	}{
+		{
+			description:      "abbreviation",
+			input:            "<34>Oct 11 22:14:15 EST mymachine su: 'su root' failed",
+			expectedOffset:   -5 * 3600,
+			expectedRaw:      "Oct 11 22:14:15 EST",
+			expectedHostname: "mymachine",
+		},
+		{
+			description:      "daylight saving abbreviation",
+			input:            "<34>Oct 11 22:14:15 CEST mymachine su: 'su root' failed",
+			expectedOffset:   2 * 3600,
+			expectedRaw:      "Oct 11 22:14:15 CEST",
+			expectedHostname: "mymachine",
+		},
+		{
+			description:      "offset with colon",
+			input:            "<34>Oct 11 22:14:15 +02:00 mymachine su: 'su root' failed",
+			expectedOffset:   2 * 3600,
+			expectedRaw:      "Oct 11 22:14:15 +02:00",
+			expectedHostname: "mymachine",
+		},
+		{
+			description:      "offset without colon",
+			input:            "<34>Oct 11 22:14:15 -0530 mymachine su: 'su root' failed",
+			expectedOffset:   -(5*3600 + 30*60),
+			expectedRaw:      "Oct 11 22:14:15 -0530",
+			expectedHostname: "mymachine",
+		},
+		{
+			description:      "no zone",
+			input:            "<34>Oct 11 22:14:15 mymachine su: 'su root' failed",
+			expectedRaw:      "Oct 11 22:14:15",
+			expectedHostname: "mymachine",
+		},
+		{
+			description:      "unknown abbreviation is the hostname",
+			input:            "<34>Oct 11 22:14:15 IST su: 'su root' failed",
+			expectedRaw:      "Oct 11 22:14:15",
+			expectedHostname: "IST",
+		},
+	}
+
+	for _, tc := range testCases {
+		p := NewParser([]byte(tc.input))
+		p.WithTimestampZone()
+		p.WithRawTimestamp()
+
+		err := p.Parse()
+		require.Nil(t, err, tc.description)
+
+		obtained := p.Dump()
+
+		ts := obtained["timestamp"].(time.Time)
+		_, offset := ts.Zone()
+
+		require.Equal(t, year, ts.Year(), tc.description)
+		require.Equal(t, 22, ts.Hour(), tc.description)
+		require.Equal(t, tc.expectedOffset, offset, tc.description)
+		require.Equal(t, tc.expectedRaw, obtained["timestamp_raw"], tc.description)
+		require.Equal(t, tc.expectedHostname, obtained["hostname"], tc.description)
+		require.Equal(t, "su", obtained["tag"], tc.description)
+	}
+}
+
+func TestParseTimestampZoneWithoutOption(t *testing.T) {
+	p := NewParser([]byte("<34>Oct 11 22:14:15 EST mymachine su: 'su root' failed"))
+
+	err := p.Parse()
+	require.Nil(t, err)
+	require.Equal(t, "EST", p.Dump()["hostname"])
+}
@@ -0,0 +1,110 @@
+package rfc5424
+
+import (
+	"time"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+)
+
+// Keys of RFC3164 parts which are replaced by their RFC5424 counterpart
+var rfc3164Keys = map[string]bool{
+	"tag":     true,
+	"pid":     true,
+	"content": true,
+}
+
+// Upgrades parts returned by rfc3164.Parser.Dump() to the parts
+// rfc5424.Parser.Dump() would return for the same message, so they can be
+// given to Formatter
+type Converter struct {
+	structuredData string
+	now            func() time.Time
+}
+
+func NewConverter() *Converter {
+	return &Converter{
+		structuredData: string(NILVALUE),
+		now:            time.Now,
+	}
+}
+
+// Adds elements as structured data to every converted message, ie. the
+// origin of the message as described in
+// https://tools.ietf.org/html/rfc5424#section-7.2
+func (c *Converter) WithStructuredData(elements []SDElement) error {
+	sd, err := FormatStructuredData(elements)
+	if err != nil {
+		return err
+	}
+
+	c.structuredData = sd
+
+	return nil
+}
+
+// Maps tag to app_name, pid to proc_id and content to message. Messages
+// without timestamp get the current time. Keys which are not part of
+// RFC3164 parts are kept as is.
+func (c *Converter) Convert(parts parsercommon.LogParts) parsercommon.LogParts {
+	converted := make(parsercommon.LogParts, len(parts)+4)
+
+	for k, v := range parts {
+		if !rfc3164Keys[k] {
+			converted[k] = v
+		}
+	}
+
+	converted["version"] = 1
+
+	ts, _ := parts["timestamp"].(time.Time)
+	if ts.IsZero() {
+		ts = c.now()
+	}
+
+	converted["timestamp"] = ts
+
+	converted["hostname"] = headerField(parts["hostname"], 255)
+	converted["app_name"] = headerField(parts["tag"], 48)
+	converted["proc_id"] = headerField(parts["pid"], 128)
+	converted["msg_id"] = string(NILVALUE)
+	converted["structured_data"] = c.structuredData
+
+	msg, _ := parts["content"].(string)
+	converted["message"] = msg
+
+	return converted
+}
+
+// Shortcut for NewConverter().Convert(parts)
+func FromRFC3164(parts parsercommon.LogParts) parsercommon.LogParts {
+	return NewConverter().Convert(parts)
+}
+
+// Header fields are truncated to their maximum length and non printable
+// characters are replaced so the converted parts can always be formatted
+func headerField(v interface{}, maxLen int) string {
+	s, _ := v.(string)
+	if s == "" {
+		return string(NILVALUE)
+	}
+
+	if len(s) > maxLen {
+		s = s[:maxLen]
+	}
+
+	for i := 0; i < len(s); i++ {
+		if !isPrintUSASCII(s[i]) {
+			b := []byte(s)
+
+			for j := i; j < len(b); j++ {
+				if !isPrintUSASCII(b[j]) {
+					b[j] = '_'
+				}
+			}
+
+			return string(b)
+		}
+	}
+
+	return s
+}
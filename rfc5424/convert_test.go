@@ -0,0 +1,135 @@
+package rfc5424
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+	"github.com/jeromer/syslogparser/rfc3164"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConverter(t *testing.T) {
+	ts := time.Date(2003, time.October, 11, 22, 14, 15, 0, time.UTC)
+	now := time.Date(2004, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		description string
+		input       parsercommon.LogParts
+		expected    parsercommon.LogParts
+	}{
+		{
+			description: "full",
+			input: parsercommon.LogParts{
+				"timestamp": ts,
+				"hostname":  "mymachine",
+				"tag":       "su",
+				"pid":       "12",
+				"content":   "'su root' failed for lonvick on /dev/pts/8",
+				"priority":  34,
+				"facility":  4,
+				"severity":  2,
+				"version":   parsercommon.NO_VERSION,
+				"geoip":     "FR",
+			},
+			expected: parsercommon.LogParts{
+				"priority":        34,
+				"facility":        4,
+				"severity":        2,
+				"version":         1,
+				"timestamp":       ts,
+				"hostname":        "mymachine",
+				"app_name":        "su",
+				"proc_id":         "12",
+				"msg_id":          "-",
+				"structured_data": "-",
+				"message":         "'su root' failed for lonvick on /dev/pts/8",
+				"geoip":           "FR",
+			},
+		},
+		{
+			description: "empty",
+			input: parsercommon.LogParts{
+				"priority": 13,
+			},
+			expected: parsercommon.LogParts{
+				"priority":        13,
+				"version":         1,
+				"timestamp":       now,
+				"hostname":        "-",
+				"app_name":        "-",
+				"proc_id":         "-",
+				"msg_id":          "-",
+				"structured_data": "-",
+				"message":         "",
+			},
+		},
+		{
+			description: "invalid header fields",
+			input: parsercommon.LogParts{
+				"priority": 13,
+				"hostname": "my machine",
+				"tag":      strings.Repeat("a", 50),
+			},
+			expected: parsercommon.LogParts{
+				"priority":        13,
+				"version":         1,
+				"timestamp":       now,
+				"hostname":        "my_machine",
+				"app_name":        strings.Repeat("a", 48),
+				"proc_id":         "-",
+				"msg_id":          "-",
+				"structured_data": "-",
+				"message":         "",
+			},
+		},
+	}
+
+	c := NewConverter()
+	c.now = func() time.Time {
+		return now
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.expected, c.Convert(tc.input), tc.description)
+	}
+}
+
+func TestConverterWithStructuredData(t *testing.T) {
+	c := NewConverter()
+
+	err := c.WithStructuredData([]SDElement{
+		{
+			ID:     "origin",
+			Params: []SDParam{{"software", "syslogparser"}},
+		},
+	})
+
+	require.Nil(t, err)
+
+	converted := c.Convert(parsercommon.LogParts{"priority": 13})
+	require.Equal(t, `[origin software="syslogparser"]`, converted["structured_data"])
+
+	err = c.WithStructuredData([]SDElement{{ID: "in valid"}})
+	require.Equal(t, ErrInvalidSDName, err)
+}
+
+func TestFromRFC3164(t *testing.T) {
+	buff := []byte("<34>Oct 11 22:14:15 mymachine su[12]: 'su root' failed for lonvick on /dev/pts/8")
+
+	p := rfc3164.NewParser(buff)
+	require.Nil(t, p.Parse())
+
+	obtained, err := NewFormatter().Format(FromRFC3164(p.Dump()))
+	require.Nil(t, err)
+
+	year := time.Now().Year()
+
+	require.Equal(
+		t,
+		"<34>1 "+time.Date(year, time.October, 11, 22, 14, 15, 0, time.UTC).Format(TIMESTAMP_FORMAT)+
+			" mymachine su 12 - - 'su root' failed for lonvick on /dev/pts/8",
+		string(obtained),
+	)
+}
@@ -0,0 +1,293 @@
+package rfc5424
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+)
+
+const (
+	// TIME-SECFRAC has at most 6 digits
+	TIMESTAMP_FORMAT = "2006-01-02T15:04:05.999999Z07:00"
+
+	// timestamps located in UnknownOffset
+	UNKNOWN_OFFSET_TIMESTAMP_FORMAT = "2006-01-02T15:04:05.999999-00:00"
+)
+
+var (
+	ErrInvalidPriority       = &parsercommon.ParserError{ErrorString: "Invalid priority"}
+	ErrInvalidVersion        = &parsercommon.ParserError{ErrorString: "Invalid version"}
+	ErrInvalidTimestamp      = &parsercommon.ParserError{ErrorString: "Invalid timestamp"}
+	ErrInvalidHostname       = &parsercommon.ParserError{ErrorString: "Invalid hostname"}
+	ErrInvalidStructuredData = &parsercommon.ParserError{ErrorString: "Invalid structured data"}
+	ErrInvalidSDName         = &parsercommon.ParserError{ErrorString: "Invalid SD name"}
+	ErrDuplicateSDID         = &parsercommon.ParserError{ErrorString: "Duplicate SD-ID"}
+	ErrInvalidMessage        = &parsercommon.ParserError{ErrorString: "Invalid message"}
+)
+
+// SD-PARAM = PARAM-NAME "=" %d34 PARAM-VALUE %d34
+type SDParam struct {
+	Name  string
+	Value string
+}
+
+// SD-ELEMENT = "[" SD-ID *(SP SD-PARAM) "]"
+type SDElement struct {
+	ID     string
+	Params []SDParam
+}
+
+// Builds RFC5424 messages from LogParts, the reverse of Parser
+type Formatter struct {
+	buff []byte
+}
+
+func NewFormatter() *Formatter {
+	return &Formatter{}
+}
+
+// Formats parts using the keys returned by Parser.Dump():
+//
+//	priority (or facility and severity), version, timestamp, hostname,
+//	app_name, proc_id, msg_id, structured_data, message
+//
+// Missing or empty header fields are formatted as NILVALUE, a missing
+// version as 1. The returned slice is only valid until the next call.
+func (f *Formatter) Format(parts parsercommon.LogParts) ([]byte, error) {
+	buff, err := AppendFormat(f.buff[:0], parts)
+	if err != nil {
+		return nil, err
+	}
+
+	f.buff = buff
+
+	return buff, nil
+}
+
+// Appends the RFC5424 formatting of parts to dst, see Formatter.Format()
+func AppendFormat(dst []byte, parts parsercommon.LogParts) ([]byte, error) {
+	pri, err := formatPriority(parts)
+	if err != nil {
+		return nil, err
+	}
+
+	version := 1
+	if v, ok := parts["version"]; ok {
+		version, ok = v.(int)
+		if !ok || version < 1 || version > 999 {
+			return nil, ErrInvalidVersion
+		}
+	}
+
+	dst = append(dst, '<')
+	dst = strconv.AppendInt(dst, int64(pri), 10)
+	dst = append(dst, '>')
+	dst = strconv.AppendInt(dst, int64(version), 10)
+	dst = append(dst, ' ')
+
+	switch ts := parts["timestamp"].(type) {
+	case nil:
+		dst = append(dst, NILVALUE)
+	case time.Time:
+		if ts.IsZero() {
+			dst = append(dst, NILVALUE)
+			break
+		}
+
+		// DATE-FULLYEAR = 4DIGIT
+		if ts.Year() < 0 || ts.Year() > 9999 {
+			return nil, ErrInvalidTimestamp
+		}
+
+		if ts.Location() == UnknownOffset {
+			dst = ts.AppendFormat(dst, UNKNOWN_OFFSET_TIMESTAMP_FORMAT)
+			break
+		}
+
+		dst = ts.AppendFormat(dst, TIMESTAMP_FORMAT)
+	default:
+		return nil, ErrInvalidTimestamp
+	}
+
+	fields := []struct {
+		key    string
+		maxLen int
+		err    error
+	}{
+		{"hostname", 255, ErrInvalidHostname},
+		{"app_name", 48, ErrInvalidAppName},
+		{"proc_id", 128, ErrInvalidProcId},
+		{"msg_id", 32, ErrInvalidMsgId},
+	}
+
+	for _, field := range fields {
+		dst = append(dst, ' ')
+
+		dst, err = appendHeaderField(dst, parts[field.key], field.maxLen, field.err)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	dst = append(dst, ' ')
+
+	switch sd := parts["structured_data"].(type) {
+	case nil:
+		dst = append(dst, NILVALUE)
+	case string:
+		if !isStructuredData(sd) {
+			return nil, ErrInvalidStructuredData
+		}
+
+		if sd == "" {
+			dst = append(dst, NILVALUE)
+		} else {
+			dst = append(dst, sd...)
+		}
+	default:
+		return nil, ErrInvalidStructuredData
+	}
+
+	switch msg := parts["message"].(type) {
+	case nil:
+	case string:
+		if msg != "" {
+			dst = append(dst, ' ')
+			dst = append(dst, msg...)
+		}
+	default:
+		return nil, ErrInvalidMessage
+	}
+
+	return dst, nil
+}
+
+// Formats elements as STRUCTURED-DATA, escaping '"', '\' and ']' in
+// parameter values. No element is formatted as NILVALUE.
+func FormatStructuredData(elements []SDElement) (string, error) {
+	if len(elements) == 0 {
+		return string(NILVALUE), nil
+	}
+
+	var buff []byte
+	var err error
+
+	for _, e := range elements {
+		buff, err = e.append(buff)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return string(buff), nil
+}
+
+func (e SDElement) append(dst []byte) ([]byte, error) {
+	if !isSDName(e.ID) {
+		return nil, ErrInvalidSDName
+	}
+
+	dst = append(dst, '[')
+	dst = append(dst, e.ID...)
+
+	for _, p := range e.Params {
+		if !isSDName(p.Name) {
+			return nil, ErrInvalidSDName
+		}
+
+		dst = append(dst, ' ')
+		dst = append(dst, p.Name...)
+		dst = append(dst, '=', '"')
+
+		for i := 0; i < len(p.Value); i++ {
+			switch c := p.Value[i]; c {
+			case '"', '\\', ']':
+				dst = append(dst, '\\', c)
+			default:
+				dst = append(dst, c)
+			}
+		}
+
+		dst = append(dst, '"')
+	}
+
+	return append(dst, ']'), nil
+}
+
+func formatPriority(parts parsercommon.LogParts) (int, error) {
+	if v, ok := parts["priority"]; ok {
+		p, ok := v.(int)
+		if !ok || p < 0 || p > 191 {
+			return 0, ErrInvalidPriority
+		}
+
+		return p, nil
+	}
+
+	f, ok := parts["facility"].(int)
+	if !ok || f < 0 || f > 23 {
+		return 0, ErrInvalidPriority
+	}
+
+	s, ok := parts["severity"].(int)
+	if !ok || s < 0 || s > 7 {
+		return 0, ErrInvalidPriority
+	}
+
+	return f*8 + s, nil
+}
+
+// NILVALUE / 1*maxLen PRINTUSASCII
+func appendHeaderField(dst []byte, v interface{}, maxLen int, e error) ([]byte, error) {
+	if v == nil {
+		return append(dst, NILVALUE), nil
+	}
+
+	s, ok := v.(string)
+	if !ok || len(s) > maxLen {
+		return nil, e
+	}
+
+	if s == "" {
+		return append(dst, NILVALUE), nil
+	}
+
+	for i := 0; i < len(s); i++ {
+		if !isPrintUSASCII(s[i]) {
+			return nil, e
+		}
+	}
+
+	return append(dst, s...), nil
+}
+
+func isPrintUSASCII(c byte) bool {
+	return c >= 33 && c <= 126
+}
+
+// SD-NAME = 1*32PRINTUSASCII except '=', SP, ']', %d34 (")
+func isSDName(s string) bool {
+	if len(s) == 0 || len(s) > 32 {
+		return false
+	}
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		if !isPrintUSASCII(c) || c == '=' || c == ']' || c == '"' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Only checks the overall shape, the parser does not validate SD content
+func isStructuredData(sd string) bool {
+	if sd == "" || parsercommon.IsNilString(sd) {
+		return true
+	}
+
+	return sd[0] == '[' && sd[len(sd)-1] == ']'
+}
@@ -0,0 +1,284 @@
+package rfc5424
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatterRoundTrip(t *testing.T) {
+	testCases := []string{
+		"<34>1 2003-10-11T22:14:15.003Z mymachine.example.com su - ID47 - 'su root' failed for lonvick on /dev/pts/8",
+		"<165>1 2003-08-24T05:14:15.000003-07:00 192.0.2.1 myproc 8710 - - %% It's time to make the do-nuts.",
+		`<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 [exampleSDID@32473 iut="3" eventSource="Application" eventID="1011"] An application event log entry...`,
+		`<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 [exampleSDID@32473 iut="3"][examplePriority@32473 class="high"]`,
+		"<0>1 2003-10-11T22:14:15Z host app proc msg -",
+	}
+
+	f := NewFormatter()
+
+	for _, input := range testCases {
+		p := NewParser([]byte(input))
+		require.Nil(t, p.Parse(), input)
+
+		obtained, err := f.Format(p.Dump())
+
+		require.Nil(t, err, input)
+		require.Equal(t, input, string(obtained))
+	}
+}
+
+func TestFormatter(t *testing.T) {
+	ts := time.Date(2003, time.October, 11, 22, 14, 15, 3000000, time.UTC)
+
+	testCases := []struct {
+		description string
+		input       parsercommon.LogParts
+		expected    string
+		expectedErr error
+	}{
+		{
+			description: "empty",
+			input: parsercommon.LogParts{
+				"priority": 13,
+			},
+			expected: "<13>1 - - - - - -",
+		},
+		{
+			description: "facility and severity",
+			input: parsercommon.LogParts{
+				"facility":  4,
+				"severity":  2,
+				"timestamp": ts,
+				"hostname":  "mymachine",
+				"message":   "hello",
+			},
+			expected: "<34>1 2003-10-11T22:14:15.003Z mymachine - - - - hello",
+		},
+		{
+			description: "empty values are NILVALUE",
+			input: parsercommon.LogParts{
+				"priority":        13,
+				"timestamp":       time.Time{},
+				"hostname":        "",
+				"app_name":        "",
+				"proc_id":         "",
+				"msg_id":          "",
+				"structured_data": "",
+				"message":         "",
+			},
+			expected: "<13>1 - - - - - -",
+		},
+		{
+			description: "nanoseconds are truncated",
+			input: parsercommon.LogParts{
+				"priority":  13,
+				"timestamp": time.Date(2003, time.October, 11, 22, 14, 15, 123456789, time.FixedZone("", 3600)),
+			},
+			expected: "<13>1 2003-10-11T22:14:15.123456+01:00 - - - - -",
+		},
+		{
+			description: "invalid priority",
+			input: parsercommon.LogParts{
+				"priority": 192,
+			},
+			expectedErr: ErrInvalidPriority,
+		},
+		{
+			description: "missing priority",
+			input:       parsercommon.LogParts{},
+			expectedErr: ErrInvalidPriority,
+		},
+		{
+			description: "invalid version",
+			input: parsercommon.LogParts{
+				"priority": 13,
+				"version":  parsercommon.NO_VERSION,
+			},
+			expectedErr: ErrInvalidVersion,
+		},
+		{
+			description: "invalid timestamp",
+			input: parsercommon.LogParts{
+				"priority":  13,
+				"timestamp": "2003-10-11T22:14:15Z",
+			},
+			expectedErr: ErrInvalidTimestamp,
+		},
+		{
+			description: "year beyond 9999",
+			input: parsercommon.LogParts{
+				"priority":  13,
+				"timestamp": time.Date(10000, time.January, 1, 0, 0, 0, 0, time.UTC),
+			},
+			expectedErr: ErrInvalidTimestamp,
+		},
+		{
+			description: "hostname with space",
+			input: parsercommon.LogParts{
+				"priority": 13,
+				"hostname": "my machine",
+			},
+			expectedErr: ErrInvalidHostname,
+		},
+		{
+			description: "app name too long",
+			input: parsercommon.LogParts{
+				"priority": 13,
+				"app_name": strings.Repeat("a", 49),
+			},
+			expectedErr: ErrInvalidAppName,
+		},
+		{
+			description: "non ASCII proc id",
+			input: parsercommon.LogParts{
+				"priority": 13,
+				"proc_id":  "é",
+			},
+			expectedErr: ErrInvalidProcId,
+		},
+		{
+			description: "msg id too long",
+			input: parsercommon.LogParts{
+				"priority": 13,
+				"msg_id":   strings.Repeat("a", 33),
+			},
+			expectedErr: ErrInvalidMsgId,
+		},
+		{
+			description: "invalid structured data",
+			input: parsercommon.LogParts{
+				"priority":        13,
+				"structured_data": "foo",
+			},
+			expectedErr: ErrInvalidStructuredData,
+		},
+		{
+			description: "invalid message",
+			input: parsercommon.LogParts{
+				"priority": 13,
+				"message":  []byte("foo"),
+			},
+			expectedErr: ErrInvalidMessage,
+		},
+	}
+
+	f := NewFormatter()
+
+	for _, tc := range testCases {
+		obtained, err := f.Format(tc.input)
+
+		require.Equal(t, tc.expectedErr, err, tc.description)
+		require.Equal(t, tc.expected, string(obtained), tc.description)
+	}
+}
+
+func TestFormatStructuredData(t *testing.T) {
+	testCases := []struct {
+		description string
+		input       []SDElement
+		expected    string
+		expectedErr error
+	}{
+		{
+			description: "none",
+			input:       nil,
+			expected:    "-",
+		},
+		{
+			description: "without params",
+			input: []SDElement{
+				{ID: "foo@32473"},
+			},
+			expected: "[foo@32473]",
+		},
+		{
+			description: "multiple",
+			input: []SDElement{
+				{
+					ID: "exampleSDID@32473",
+					Params: []SDParam{
+						{"iut", "3"},
+						{"eventSource", "Application"},
+					},
+				},
+				{
+					ID: "examplePriority@32473",
+					Params: []SDParam{
+						{"class", "high"},
+					},
+				},
+			},
+			expected: `[exampleSDID@32473 iut="3" eventSource="Application"][examplePriority@32473 class="high"]`,
+		},
+		{
+			description: "escaping",
+			input: []SDElement{
+				{
+					ID: "foo@32473",
+					Params: []SDParam{
+						{"v", `a"b\c]d`},
+					},
+				},
+			},
+			expected: `[foo@32473 v="a\"b\\c\]d"]`,
+		},
+		{
+			description: "invalid ID",
+			input: []SDElement{
+				{ID: "foo bar"},
+			},
+			expectedErr: ErrInvalidSDName,
+		},
+		{
+			description: "invalid param name",
+			input: []SDElement{
+				{
+					ID: "foo@32473",
+					Params: []SDParam{
+						{"a=b", "c"},
+					},
+				},
+			},
+			expectedErr: ErrInvalidSDName,
+		},
+		{
+			description: "ID too long",
+			input: []SDElement{
+				{ID: strings.Repeat("a", 33)},
+			},
+			expectedErr: ErrInvalidSDName,
+		},
+	}
+
+	for _, tc := range testCases {
+		obtained, err := FormatStructuredData(tc.input)
+
+		require.Equal(t, tc.expectedErr, err, tc.description)
+		require.Equal(t, tc.expected, obtained, tc.description)
+	}
+}
+
+func BenchmarkFormat(b *testing.B) {
+	p := NewParser([]byte(
+		`<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 [exampleSDID@32473 iut="3" eventSource="Application" eventID="1011"] An application event log entry...`,
+	))
+
+	err := p.Parse()
+	if err != nil {
+		panic(err)
+	}
+
+	parts := p.Dump()
+	f := NewFormatter()
+
+	for i := 0; i < b.N; i++ {
+		_, err := f.Format(parts)
+		if err != nil {
+			panic(err)
+		}
+	}
+}
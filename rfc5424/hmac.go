@@ -0,0 +1,134 @@
+package rfc5424
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+)
+
+const (
+	// SD-ID of the element carrying the HMAC of a message.
+	// 32473 is the private enterprise number reserved for documentation,
+	// see https://tools.ietf.org/html/rfc5612
+	HMAC_SD_ID = "hmac@32473"
+)
+
+var (
+	ErrHMACNotFound = &parsercommon.ParserError{ErrorString: "HMAC not found"}
+	ErrHMACMismatch = &parsercommon.ParserError{ErrorString: "HMAC mismatch"}
+)
+
+// Returns a copy of the message with an extra SD element holding an
+// HMAC-SHA256 of the original message computed with key:
+// [hmac@32473 sig="base64 encoded HMAC"]
+// The element is appended to existing structured data or replaces NILVALUE.
+// Use VerifyHMAC() to check the message was not tampered with.
+func StampHMAC(buff []byte, key []byte) ([]byte, error) {
+	from, to, err := structuredDataBounds(buff)
+	if err != nil {
+		return nil, err
+	}
+
+	sd := buff[from:to]
+	if parsercommon.IsNilValue(sd) {
+		sd = nil
+	}
+
+	elem := hmacElement(buff, key)
+
+	stamped := make([]byte, 0, len(buff)+len(elem))
+	stamped = append(stamped, buff[:from]...)
+	stamped = append(stamped, sd...)
+	stamped = append(stamped, elem...)
+	stamped = append(stamped, buff[to:]...)
+
+	return stamped, nil
+}
+
+// Checks the HMAC added by StampHMAC() matches the message
+func VerifyHMAC(buff []byte, key []byte) error {
+	from, to, err := structuredDataBounds(buff)
+	if err != nil {
+		return err
+	}
+
+	sd := buff[from:to]
+	prefix := []byte("[" + HMAC_SD_ID + ` sig="`)
+
+	start := bytes.LastIndex(sd, prefix)
+	if start < 0 {
+		return ErrHMACNotFound
+	}
+
+	end := bytes.Index(sd[start:], []byte(`"]`))
+	if end < 0 {
+		return ErrHMACNotFound
+	}
+
+	end += start + 2
+
+	sig, err := base64.StdEncoding.DecodeString(
+		string(sd[start+len(prefix) : end-2]),
+	)
+
+	if err != nil {
+		return ErrHMACNotFound
+	}
+
+	remaining := append(
+		append([]byte(nil), sd[:start]...), sd[end:]...,
+	)
+
+	if len(remaining) == 0 {
+		remaining = []byte{NILVALUE}
+	}
+
+	original := make([]byte, 0, len(buff))
+	original = append(original, buff[:from]...)
+	original = append(original, remaining...)
+	original = append(original, buff[to:]...)
+
+	if !hmac.Equal(sig, computeHMAC(original, key)) {
+		return ErrHMACMismatch
+	}
+
+	return nil
+}
+
+func hmacElement(buff []byte, key []byte) []byte {
+	sig := base64.StdEncoding.EncodeToString(
+		computeHMAC(buff, key),
+	)
+
+	return []byte("[" + HMAC_SD_ID + ` sig="` + sig + `"]`)
+}
+
+func computeHMAC(buff []byte, key []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write(buff)
+
+	return h.Sum(nil)
+}
+
+// Returns the [from, to) offsets of the STRUCTURED-DATA in buff
+func structuredDataBounds(buff []byte) (int, int, error) {
+	p := NewParser(buff)
+	p.cursor = parsercommon.NewCursor(buff, len(buff))
+
+	_, err := p.parseHeader()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	from := p.cursor.Pos()
+
+	_, err = p.parseStructuredData()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return from, p.cursor.Pos(), nil
+}
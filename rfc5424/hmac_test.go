@@ -0,0 +1,81 @@
+package rfc5424
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStampHMAC(t *testing.T) {
+	key := []byte("secret")
+
+	testCases := []struct {
+		description string
+		input       string
+	}{
+		{
+			description: "no STRUCTURED-DATA",
+			input:       "<34>1 2003-10-11T22:14:15.003Z mymachine.example.com su - ID47 - 'su root' failed for lonvick on /dev/pts/8",
+		},
+		{
+			description: "with STRUCTURED-DATA",
+			input:       `<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 [exampleSDID@32473 iut="3"][examplePriority@32473 class="high"] An application event log entry...`,
+		},
+		{
+			description: "STRUCTURED-DATA only",
+			input:       `<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 [exampleSDID@32473 iut="3"]`,
+		},
+	}
+
+	for _, tc := range testCases {
+		original := NewParser([]byte(tc.input))
+		err := original.Parse()
+		require.Nil(t, err, tc.description)
+
+		stamped, err := StampHMAC([]byte(tc.input), key)
+		require.Nil(t, err, tc.description)
+
+		p := NewParser(stamped)
+		err = p.Parse()
+		require.Nil(t, err, tc.description)
+
+		expected := original.Dump()
+		obtained := p.Dump()
+
+		require.Contains(
+			t, obtained["structured_data"], "["+HMAC_SD_ID+` sig="`, tc.description,
+		)
+
+		delete(expected, "structured_data")
+		delete(obtained, "structured_data")
+		require.Equal(t, expected, obtained, tc.description)
+
+		require.Nil(
+			t, VerifyHMAC(stamped, key), tc.description,
+		)
+
+		require.Equal(
+			t, ErrHMACMismatch, VerifyHMAC(stamped, []byte("other")), tc.description,
+		)
+
+		tampered := append([]byte(nil), stamped...)
+		tampered[32] = 'X'
+
+		require.Equal(
+			t, ErrHMACMismatch, VerifyHMAC(tampered, key), tc.description,
+		)
+
+		require.Equal(
+			t, ErrHMACNotFound, VerifyHMAC([]byte(tc.input), key), tc.description,
+		)
+	}
+}
+
+func TestStampHMACInvalid(t *testing.T) {
+	_, err := StampHMAC(
+		[]byte("<34>1 2003-13-11T22:14:15.003Z mymachine su - ID47 - message"),
+		[]byte("secret"),
+	)
+
+	require.Equal(t, ErrMonthInvalid, err)
+}
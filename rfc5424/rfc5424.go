@@ -2,24 +2,44 @@ package rfc5424
 
 import (
 	"bytes"
-	"fmt"
 	"math"
 	"strconv"
+	"sync"
 	"time"
 
-	"github.com/jeromer/syslogparser"
 	"github.com/jeromer/syslogparser/parsercommon"
 )
 
 const (
-	NILVALUE = '-'
+	NILVALUE = parsercommon.NILVALUE
 
 	// according to https://tools.ietf.org/html/rfc5424#section-6.1
 	// the length of the packet MUST be 2048 bytes or less.
 	// However we will accept a bit more while protecting from exhaustion
 	MAX_PACKET_LEN = 3048
+
+	// reported as "parser" when diagnostics are enabled
+	PARSER_NAME = "rfc5424"
+
+	// reported as "rfc" by WithRaw()
+	RFC_NUMBER = 5424
+
+	// HOSTNAME = NILVALUE / 1*255PRINTUSASCII
+	MAX_HOSTNAME_LEN = 255
 )
 
+// Location of timestamps whose offset is "-00:00", meaning the offset to the
+// local time is unknown (RFC3339 section 4.3). Such times are UTC.
+var UnknownOffset = time.FixedZone("-00:00", 0)
+
+// time zone offset in seconds => *time.Location
+var tzCache = struct {
+	sync.RWMutex
+	zones map[int]*time.Location
+}{
+	zones: make(map[int]*time.Location),
+}
+
 var (
 	ErrYearInvalid       = &parsercommon.ParserError{ErrorString: "Invalid year in timestamp"}
 	ErrMonthInvalid      = &parsercommon.ParserError{ErrorString: "Invalid month in timestamp"}
@@ -37,15 +57,65 @@ var (
 )
 
 type Parser struct {
-	buff           []byte
-	cursor         int
-	l              int
+	cursor         parsercommon.Cursor
 	header         *header
 	structuredData string
 	message        string
 
-	tmpHostname string
-	tmpPriority *parsercommon.Priority
+	tmpHostname        string
+	tmpPriority        *parsercommon.Priority
+	zeroCopy           bool
+	lenientPriority    bool
+	defaultPriority    bool
+	priorityDefaulted  bool
+	provenance         bool
+	longAppName        bool
+	strict             bool
+	sdValidation       bool
+	calendar           bool
+	printUSASCII       bool
+	versionPolicy      VersionPolicy
+	hostnameValidation bool
+	hostnameType       bool
+	longHostname       bool
+	programSplit       bool
+
+	// set when APP-NAME, PROCID and MSGID were split by recoverIdentifiers()
+	identifiersRecovered bool
+
+	spans [len(spanFields)]parsercommon.Span
+
+	payloadParser parsercommon.PayloadParser
+	keyMapper     parsercommon.KeyMapper
+	keyPolicy     parsercommon.KeyPolicy
+	payload       parsercommon.LogParts
+	payloadErr    error
+
+	diagnostics       bool
+	raw               bool
+	rawTimestamp      bool
+	timestampPresence bool
+	hasTimestamp      bool
+	kernelOffset      bool
+	bootOffset        time.Duration
+	hasBootOffset     bool
+	cee               bool
+	ceeFields         map[string]interface{}
+	hasCEE            bool
+	ceeErr            error
+	jsonBody          bool
+	jsonMaxDepth      int
+	jsonMaxSize       int
+	jsonFields        map[string]interface{}
+	hasJSON           bool
+	jsonErr           error
+	timestampText     []byte
+	names             bool
+	parseDuration     time.Duration
+
+	// field being parsed and its offset
+	field    string
+	fieldPos int
 }
 
 type header struct {
@@ -63,6 +133,9 @@ type partialTime struct {
 	minute  int
 	seconds int
 	secFrac float64
+
+	// set when TIME-SECFRAC follows a ',' rather than a '.'
+	commaSecFrac bool
 }
 
 type fullTime struct {
@@ -77,18 +150,20 @@ type fullDate struct {
 }
 
 func NewParser(buff []byte) *Parser {
+	cursor := parsercommon.NewCursor(buff, MAX_PACKET_LEN)
+	cursor.TrimTrailer()
+
 	return &Parser{
-		buff:   buff,
-		cursor: 0,
-		l: int(
-			math.Min(
-				float64(len(buff)),
-				MAX_PACKET_LEN,
-			),
-		),
+		cursor: cursor,
 	}
 }
 
+// Prepares the parser for a new buffer so it can be reused instead of
+// allocating a new one. Options previously set are discarded.
+func (p *Parser) Reset(buff []byte) {
+	*p = *NewParser(buff)
+}
+
 // Forces a priority for this parser. Priority will not be parsed.
 func (p *Parser) WithPriority(pri *parsercommon.Priority) {
 	p.tmpPriority = pri
@@ -111,11 +186,228 @@ func (p *Parser) WithHostname(h string) {
 func (p *Parser) WithTag(t string) {
 }
 
+// Adds the parser name ("parser") and the time spent in Parse()
+// ("parse_duration", a time.Duration) to Dump(), for debugging and
+// comparing parsers.
+func (p *Parser) WithDiagnostics() {
+	p.diagnostics = true
+}
+
+// Accepts priorities above 191 (ie. <999>) as older versions did. Facility
+// and severity are then computed as for valid priorities.
+func (p *Parser) WithLenientPriority() {
+	p.lenientPriority = true
+}
+
+// Messages not starting with "<", as sent by devices omitting PRI, are given
+// parsercommon.DEFAULT_PRIORITY (user.notice) instead of failing with
+// ErrPriorityNoStart, as relays do per RFC 3164 section 4.3.3.
+func (p *Parser) WithDefaultPriority() {
+	p.defaultPriority = true
+}
+
+// Adds "provenance" to Dump(), a map[string]string giving the origin of every
+// field: parsercommon.PROVENANCE_PARSED or PROVENANCE_FORCED when set with a
+// With* option.
+func (p *Parser) WithProvenance() {
+	p.provenance = true
+}
+
+// Runs pp on the message once the message has been parsed and adds the keys it
+// returns to Dump(). Keys also set by the parser are handled following
+// policy. When pp fails its error is reported as "payload_error".
+func (p *Parser) WithPayloadParser(pp parsercommon.PayloadParser, policy parsercommon.KeyPolicy) {
+	p.payloadParser = pp
+	p.keyPolicy = policy
+}
+
+// Salvages messages whose APP-NAME is longer than the 48 characters allowed,
+// rejected with ErrInvalidAppName otherwise. STRUCTURED-DATA is then located
+// first and the header split from its right: the last word is MSGID, the
+// previous one PROCID and everything before is APP-NAME.
+func (p *Parser) WithLongAppName() {
+	p.longAppName = true
+}
+
+// Validates STRUCTURED-DATA instead of keeping it as a whole string: SD-IDs
+// and PARAM-NAMEs MUST be valid SD-NAMEs (ErrInvalidSDName), PARAM-VALUEs
+// quoted (ErrInvalidStructuredData) and SD-IDs unique within the message
+// (ErrDuplicateSDID). Errors are located at the invalid element.
+func (p *Parser) WithSDValidation() {
+	p.sdValidation = true
+}
+
+// Rejects timestamps whose day does not exist in their month, ie. Feb 30 or
+// Feb 29 of a non leap year, with ErrDayInvalid. Without it days are only
+// checked to be in range [01 -> 31].
+func (p *Parser) WithCalendarValidation() {
+	p.calendar = true
+}
+
+// Rejects HOSTNAME, APP-NAME, PROCID and MSGID holding bytes other than
+// PRINTUSASCII, ie. NUL or UTF-8 sequences, with ErrInvalidHostname,
+// ErrInvalidAppName, ErrInvalidProcId or ErrInvalidMsgId located at the
+// first invalid byte. WithStrict() enables it.
+func (p *Parser) WithPrintUSASCII() {
+	p.printUSASCII = true
+}
+
+// Rejects HOSTNAMEs which are neither NILVALUE, an RFC 1123 host name nor an
+// IP address with ErrInvalidHostname, see parsercommon.IsValidHostname()
+func (p *Parser) WithHostnameValidation() {
+	p.hostnameValidation = true
+}
+
+// Accepts HOSTNAMEs longer than the MAX_HOSTNAME_LEN bytes allowed, rejected
+// with ErrInvalidHostname otherwise. WithStrict() takes precedence.
+func (p *Parser) WithLongHostname() {
+	p.longHostname = true
+}
+
+// Adds "program" and "subprogram" to Dump() for APP-NAMEs of the form
+// "daemon/subprogram", ie. "postfix" and "smtpd" for "postfix/smtpd", see
+// parsercommon.SplitProgram(). "app_name" is kept as is.
+func (p *Parser) WithProgramSplit() {
+	p.programSplit = true
+}
+
+// Adds the kind of HOSTNAME to Dump(): "hostname_type" is one of
+// parsercommon.HOSTNAME_TYPE_IP4, _IP6, _FQDN or _NAME, see
+// parsercommon.HostnameType(). It is not set for NILVALUE.
+func (p *Parser) WithHostnameType() {
+	p.hostnameType = true
+}
+
+// Tolerates the deviations commonly found in the wild instead of having to
+// enable them one by one: priorities above 191, missing PRI, overlong
+// APP-NAMEs and HOSTNAMEs, see WithLenientPriority(), WithDefaultPriority(),
+// WithLongAppName() and WithLongHostname(). WithStrict() takes precedence.
+func (p *Parser) WithLenient() {
+	p.WithLenientPriority()
+	p.WithDefaultPriority()
+	p.WithLongAppName()
+	p.WithLongHostname()
+}
+
+// Renames the keys returned by Dump() with m, ie. parsercommon.KeysECS or
+// parsercommon.KeysCamelCase. DumpMessage() is not affected.
+func (p *Parser) WithKeyMapper(m parsercommon.KeyMapper) {
+	p.keyMapper = m
+}
+
+// Adds the RFC ("rfc", RFC_NUMBER) and the message as received ("raw", a
+// string) to Dump(), for audit trails and reprocessing.
+func (p *Parser) WithRaw() {
+	p.raw = true
+}
+
+// Adds the timestamp as sent ("timestamp_raw", ie. "2003-10-11T22:14:15.003Z") to Dump(), for
+// compliance systems which must store exactly what the device sent.
+func (p *Parser) WithRawTimestamp() {
+	p.rawTimestamp = true
+}
+
+// Returns false when the timestamp was NILVALUE, "timestamp" is then a zero
+// time.Time which HasTimestamp() tells apart from January 1 of year 1.
+func (p *Parser) HasTimestamp() bool {
+	return p.hasTimestamp
+}
+
+// Adds "timestamp_present" to Dump(), see HasTimestamp()
+func (p *Parser) WithTimestampPresence() {
+	p.timestampPresence = true
+}
+
+// Moves the time elapsed since boot prefixing kernel messages (app name
+// "kernel"), as in "[12345.678901] usb 1-1: new device", from the message to
+// "boot_offset", a time.Duration.
+func (p *Parser) WithKernelOffset() {
+	p.kernelOffset = true
+}
+
+// Parses the JSON following the CEE cookie of structured messages, as in
+// `@cee: {"event":"login"}`, and adds it to Dump() as "cee", a
+// map[string]interface{}. Invalid JSON is reported as "cee_error".
+func (p *Parser) WithCEE() {
+	p.cee = true
+}
+
+// Unmarshals the message when it is a JSON object, as in `{"log":"started"}`,
+// and adds it to Dump() as "json", a map[string]interface{}. Bodies larger
+// than maxSize bytes or nested deeper than maxDepth are reported as
+// "json_error", 0 meaning parsercommon.JSON_MAX_SIZE and JSON_MAX_DEPTH.
+func (p *Parser) WithJSON(maxDepth int, maxSize int) {
+	p.jsonBody = true
+	p.jsonMaxDepth = maxDepth
+	p.jsonMaxSize = maxSize
+}
+
+// Adds the keywords of the facility ("facility_name", ie. "daemon") and of
+// the severity ("severity_name", ie. "err") to Dump().
+func (p *Parser) WithNames() {
+	p.names = true
+}
+
+// Number of bytes of the buffer which are parsed, MAX_PACKET_LEN by default.
+// Longer messages are truncated. The limit is lifted when n <= 0.
+// MUST be called before Parse().
+func (p *Parser) WithMaxPacketLen(n int) {
+	if n <= 0 {
+		n = len(p.cursor.Buffer())
+	}
+
+	p.cursor = parsercommon.NewCursor(p.cursor.Buffer(), n)
+	p.cursor.TrimTrailer()
+}
+
+// String fields (hostname, app_name, proc_id, msg_id, structured_data,
+// message) will share the memory of the buffer given to NewParser() instead
+// of being copied.
+// The buffer MUST NOT be modified nor reused as long as the values returned
+// by Dump() are in use.
+func (p *Parser) WithZeroCopy() {
+	p.zeroCopy = true
+}
+
 // DEPRECATED. Use WithLocation() instead
 func (p *Parser) Location(location *time.Location) {
 }
 
+// Errors are *parsercommon.ParserError located at the field which could not
+// be parsed. Use errors.Is() to compare them with the exported errors.
 func (p *Parser) Parse() error {
+	if !p.diagnostics {
+		return p.locate(p.parse())
+	}
+
+	start := time.Now()
+	err := p.parse()
+	p.parseDuration = time.Since(start)
+
+	return p.locate(err)
+}
+
+// Records the field about to be parsed so errors can be located
+func (p *Parser) begin(field string) {
+	p.field = field
+	p.fieldPos = p.cursor.Pos()
+}
+
+func (p *Parser) locate(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return p.cursor.Locate(err, p.fieldPos, p.field)
+}
+
+func (p *Parser) parse() error {
+	if p.strict {
+		if err := p.checkLength(); err != nil {
+			return err
+		}
+	}
+
 	hdr, err := p.parseHeader()
 	if err != nil {
 		return err
@@ -123,27 +415,49 @@ func (p *Parser) Parse() error {
 
 	p.header = hdr
 
+	p.begin("structured_data")
+
 	sd, err := p.parseStructuredData()
 	if err != nil {
 		return err
 	}
 
+	p.endSpan()
+
 	p.structuredData = sd
-	p.cursor++
 
-	if p.cursor < p.l {
-		p.message = string(
-			bytes.Trim(
-				p.buff[p.cursor:p.l], " ",
-			),
+	p.begin("message")
+
+	msg, err := p.parseMessage()
+	if err != nil {
+		return err
+	}
+
+	if p.kernelOffset && hdr.appName == parsercommon.KERNEL_TAG {
+		p.bootOffset, msg, p.hasBootOffset = parsercommon.ParseBootOffset(msg)
+	}
+
+	p.message = msg
+
+	if p.cee {
+		p.ceeFields, p.hasCEE, p.ceeErr = parsercommon.ParseCEE(msg)
+	}
+
+	if p.jsonBody {
+		p.jsonFields, p.hasJSON, p.jsonErr = parsercommon.ParseJSONBody(
+			msg, p.jsonMaxDepth, p.jsonMaxSize,
 		)
 	}
 
+	if p.payloadParser != nil {
+		p.payload, p.payloadErr = p.payloadParser.ParsePayload(msg)
+	}
+
 	return nil
 }
 
-func (p *Parser) Dump() syslogparser.LogParts {
-	return syslogparser.LogParts{
+func (p *Parser) Dump() parsercommon.LogParts {
+	parts := parsercommon.LogParts{
 		"priority":        p.header.priority.P,
 		"facility":        p.header.priority.F.Value,
 		"severity":        p.header.priority.S.Value,
@@ -156,28 +470,179 @@ func (p *Parser) Dump() syslogparser.LogParts {
 		"structured_data": p.structuredData,
 		"message":         p.message,
 	}
+
+	if p.cursor.Truncated() {
+		parts["truncated"] = true
+	}
+
+	if p.names {
+		parts["facility_name"] = p.header.priority.F.Name()
+		parts["severity_name"] = p.header.priority.S.Name()
+	}
+
+	if p.hostnameType {
+		if t := parsercommon.HostnameType(p.header.hostname); t != "" {
+			parts["hostname_type"] = t
+		}
+	}
+
+	if p.programSplit {
+		if program, subprogram, ok := parsercommon.SplitProgram(p.header.appName); ok {
+			parts["program"] = program
+			parts["subprogram"] = subprogram
+		}
+	}
+
+	if p.provenance {
+		parts["provenance"] = p.dumpProvenance()
+	}
+
+	if p.hasBootOffset {
+		parts["boot_offset"] = p.bootOffset
+	}
+
+	if p.timestampPresence {
+		parts["timestamp_present"] = p.hasTimestamp
+	}
+
+	if p.hasCEE {
+		if p.ceeErr != nil {
+			parts["cee_error"] = p.ceeErr.Error()
+		} else {
+			parts[parsercommon.CEE_KEY] = p.ceeFields
+		}
+	}
+
+	if p.hasJSON {
+		if p.jsonErr != nil {
+			parts["json_error"] = p.jsonErr.Error()
+		} else {
+			parts[parsercommon.JSON_KEY] = p.jsonFields
+		}
+	}
+
+	if p.rawTimestamp {
+		parts["timestamp_raw"] = p.str(p.timestampText)
+	}
+
+	if p.raw {
+		parts["rfc"] = RFC_NUMBER
+		parts["raw"] = p.str(p.cursor.Buffer())
+	}
+
+	if p.diagnostics {
+		parts["parser"] = PARSER_NAME
+		parts["parse_duration"] = p.parseDuration
+	}
+
+	if p.payloadParser != nil {
+		parsercommon.MergePayload(parts, p.payload, p.payloadErr, p.keyPolicy)
+	}
+
+	return parsercommon.MapKeys(parts, p.keyMapper)
+}
+
+func (p *Parser) dumpProvenance() map[string]string {
+	pri := parsercommon.ParsedOrForced(p.tmpPriority != nil)
+	if p.priorityDefaulted {
+		pri = parsercommon.PROVENANCE_DEFAULT
+	}
+
+	prov := map[string]string{
+		"priority":        pri,
+		"facility":        pri,
+		"severity":        pri,
+		"version":         parsercommon.PROVENANCE_PARSED,
+		"timestamp":       parsercommon.PROVENANCE_PARSED,
+		"hostname":        parsercommon.ParsedOrForced(p.tmpHostname != ""),
+		"app_name":        parsercommon.PROVENANCE_PARSED,
+		"proc_id":         parsercommon.PROVENANCE_PARSED,
+		"msg_id":          parsercommon.PROVENANCE_PARSED,
+		"structured_data": parsercommon.PROVENANCE_PARSED,
+		"message":         parsercommon.PROVENANCE_PARSED,
+	}
+
+	if !p.hasTimestamp {
+		prov["timestamp"] = parsercommon.PROVENANCE_DEFAULT
+	}
+
+	if p.identifiersRecovered {
+		prov["app_name"] = parsercommon.PROVENANCE_INFERRED
+		prov["proc_id"] = parsercommon.PROVENANCE_INFERRED
+		prov["msg_id"] = parsercommon.PROVENANCE_INFERRED
+	}
+
+	if p.names {
+		prov["facility_name"] = parsercommon.PROVENANCE_INFERRED
+		prov["severity_name"] = parsercommon.PROVENANCE_INFERRED
+	}
+
+	return prov
+}
+
+// Same as Dump() without allocating a map. m is overwritten, options adding
+// keys to Dump() are ignored.
+func (p *Parser) DumpMessage(m *parsercommon.Message) {
+	*m = parsercommon.Message{
+		Priority:       p.header.priority.P,
+		Facility:       p.header.priority.F.Value,
+		Severity:       p.header.priority.S.Value,
+		Version:        p.header.version,
+		Timestamp:      p.header.timestamp,
+		Hostname:       p.header.hostname,
+		AppName:        p.header.appName,
+		ProcId:         p.header.procId,
+		MsgId:          p.header.msgId,
+		StructuredData: p.structuredData,
+		Message:        p.message,
+	}
 }
 
 // HEADER = PRI VERSION SP TIMESTAMP SP HOSTNAME SP APP-NAME SP PROCID SP MSGID
 func (p *Parser) parseHeader() (*header, error) {
+	p.begin("priority")
+
 	pri, err := p.parsePriority()
 	if err != nil {
 		return nil, err
 	}
 
+	p.endSpan()
+
+	p.begin("version")
+
 	ver, err := p.parseVersion()
 	if err != nil {
 		return nil, err
 	}
 
-	p.cursor++
+	if err := p.checkVersionPolicy(ver); err != nil {
+		return nil, err
+	}
+
+	if p.strict {
+		if err := p.checkVersion(ver); err != nil {
+			return nil, err
+		}
+	}
+
+	p.endSpan()
+
+	p.cursor.Advance(1)
+
+	p.begin("timestamp")
 
 	ts, err := p.parseTimestamp()
 	if err != nil {
 		return nil, err
 	}
 
-	p.cursor++
+	p.timestampText = p.cursor.Slice(p.fieldPos, p.cursor.Pos())
+	p.endSpan()
+
+	p.cursor.Advance(1)
+
+	p.begin("hostname")
 
 	host, err := p.parseHostname()
 	if err != nil {
@@ -186,26 +651,20 @@ func (p *Parser) parseHeader() (*header, error) {
 
 	// cursor is moved in p.parseHostname()
 
-	appName, err := p.parseAppName()
-	if err != nil {
-		return nil, err
-	}
+	p.begin("app_name")
 
-	p.cursor++
+	from := p.cursor.Pos()
 
-	procId, err := p.parseProcId()
-	if err != nil {
-		return nil, err
+	appName, procId, msgId, err := p.parseIdentifiers()
+	if err == ErrInvalidAppName && p.longAppName && !p.strict {
+		appName, procId, msgId, err = p.recoverIdentifiers(from)
 	}
 
-	p.cursor++
-
-	msgId, err := p.parseMsgId()
 	if err != nil {
 		return nil, err
 	}
 
-	p.cursor++
+	p.cursor.Advance(1)
 
 	hdr := &header{
 		version:   ver,
@@ -220,49 +679,167 @@ func (p *Parser) parseHeader() (*header, error) {
 	return hdr, nil
 }
 
+// APP-NAME SP PROCID SP MSGID
+func (p *Parser) parseIdentifiers() (string, string, string, error) {
+	appName, err := p.parseAppName()
+	if err != nil {
+		return "", "", "", err
+	}
+
+	p.endSpan()
+
+	p.cursor.Advance(1)
+
+	p.begin("proc_id")
+
+	procId, err := p.parseProcId()
+	if err != nil {
+		return "", "", "", err
+	}
+
+	p.endSpan()
+
+	p.cursor.Advance(1)
+
+	p.begin("msg_id")
+
+	msgId, err := p.parseMsgId()
+	if err != nil {
+		return "", "", "", err
+	}
+
+	p.endSpan()
+
+	return appName, procId, msgId, nil
+}
+
+// Locates STRUCTURED-DATA after from, at the first "[" or NILVALUE following
+// a SP which leaves two words before it, and splits APP-NAME, PROCID and
+// MSGID from the right of it. The cursor is left on the SP preceding
+// STRUCTURED-DATA.
+func (p *Parser) recoverIdentifiers(from int) (string, string, string, error) {
+	for k := from + 1; k < p.cursor.Len(); k++ {
+		if prev, _ := p.cursor.At(k - 1); prev != ' ' {
+			continue
+		}
+
+		b, _ := p.cursor.At(k)
+		if b != '[' && b != NILVALUE {
+			continue
+		}
+
+		if next, ok := p.cursor.At(k + 1); b == NILVALUE && ok && next != ' ' {
+			continue
+		}
+
+		hdr := p.cursor.Slice(from, k-1)
+
+		i := bytes.LastIndexByte(hdr, ' ')
+		if i < 0 {
+			continue
+		}
+
+		j := bytes.LastIndexByte(hdr[:i], ' ')
+		if j <= 0 {
+			continue
+		}
+
+		procId := hdr[j+1 : i]
+		msgId := hdr[i+1:]
+
+		if len(procId) == 0 || len(procId) > 128 || len(msgId) == 0 || len(msgId) > 32 {
+			continue
+		}
+
+		p.cursor.SetPos(k - 1)
+		p.identifiersRecovered = true
+
+		p.setSpan("app_name", from, from+j)
+		p.setSpan("proc_id", from+j+1, from+i)
+		p.setSpan("msg_id", from+i+1, k-1)
+
+		return p.str(hdr[:j]), p.str(procId), p.str(msgId), nil
+	}
+
+	return "", "", "", ErrInvalidAppName
+}
+
 func (p *Parser) parsePriority() (*parsercommon.Priority, error) {
 	if p.tmpPriority != nil {
 		return p.tmpPriority, nil
 	}
 
-	return parsercommon.ParsePriority(
-		p.buff, &p.cursor, p.l,
-	)
+	if p.defaultPriority && !p.strict {
+		if b, ok := p.cursor.Peek(); ok && b != '<' {
+			p.priorityDefaulted = true
+			return parsercommon.NewPriority(parsercommon.DEFAULT_PRIORITY), nil
+		}
+	}
+
+	if p.lenientPriority && !p.strict {
+		return p.cursor.ParseLenientPriority()
+	}
+
+	return p.cursor.ParsePriority()
 }
 
+// VERSION = NONZERO-DIGIT 0*2DIGIT
 func (p *Parser) parseVersion() (int, error) {
-	return parsercommon.ParseVersion(p.buff, &p.cursor, p.l)
+	ver, err := p.cursor.ParseVersion()
+	if err != nil || ver == parsercommon.NO_VERSION {
+		return ver, err
+	}
+
+	for i := 0; i < 2; i++ {
+		b, ok := p.cursor.Peek()
+		if !ok || !parsercommon.IsDigit(b) {
+			break
+		}
+
+		ver = ver*10 + int(b-'0')
+		p.cursor.Advance(1)
+	}
+
+	return ver, nil
 }
 
 // https://tools.ietf.org/html/rfc5424#section-6.2.3
 func (p *Parser) parseTimestamp() (*time.Time, error) {
-	if p.buff[p.cursor] == NILVALUE {
-		p.cursor++
+	if p.cursor.Expect(NILVALUE) {
 		return new(time.Time), nil
 	}
 
-	fd, err := parseFullDate(
-		p.buff, &p.cursor, p.l,
-	)
+	p.hasTimestamp = true
 
+	fd, err := parseFullDate(&p.cursor)
 	if err != nil {
 		return nil, err
 	}
 
-	if p.buff[p.cursor] != 'T' {
-		return nil, ErrInvalidTimeFormat
+	if (p.strict || p.calendar) && fd.day > daysIn(fd.year, fd.month) {
+		// points the error at DATE-MDAY
+		p.fieldPos += len("2006-01-")
+		return nil, ErrDayInvalid
 	}
 
-	p.cursor++
+	if !p.cursor.Expect('T') {
+		return nil, ErrInvalidTimeFormat
+	}
 
-	ft, err := parseFullTime(
-		p.buff, &p.cursor, p.l,
-	)
+	ft, err := parseFullTime(&p.cursor)
 
 	if err != nil {
 		return nil, parsercommon.ErrTimestampUnknownFormat
 	}
 
+	if p.strict && ft.pt.seconds == LEAP_SECOND {
+		return nil, ErrSecondInvalid
+	}
+
+	if p.strict && ft.pt.commaSecFrac {
+		return nil, ErrSecFracInvalid
+	}
+
 	nSec, err := toNSec(
 		ft.pt.secFrac,
 	)
@@ -271,6 +848,7 @@ func (p *Parser) parseTimestamp() (*time.Time, error) {
 		return nil, err
 	}
 
+	// leap seconds are normalized to the first second of the next minute
 	ts := time.Date(
 		fd.year,
 		time.Month(fd.month),
@@ -291,67 +869,137 @@ func (p *Parser) parseHostname() (string, error) {
 		return p.tmpHostname, nil
 	}
 
-	h, err := parsercommon.ParseHostname(p.buff, &p.cursor, p.l)
+	h := p.cursor.ScanHostname()
+
+	if len(h) > MAX_HOSTNAME_LEN && !p.longHostname {
+		return "", ErrInvalidHostname
+	}
+
+	if p.strict {
+		if err := p.checkHostname(h); err != nil {
+			return "", err
+		}
+	} else if p.printUSASCII {
+		if err := p.checkPrintUSASCII(h, ErrInvalidHostname); err != nil {
+			return "", err
+		}
+	}
+
+	if p.hostnameValidation && !parsercommon.IsNilValue(h) && !parsercommon.IsValidHostname(h) {
+		return "", ErrInvalidHostname
+	}
+
+	p.endSpan()
 
-	p.cursor++
+	p.cursor.Advance(1)
 
-	return h, err
+	return p.str(h), nil
 }
 
 // APP-NAME = NILVALUE / 1*48PRINTUSASCII
 func (p *Parser) parseAppName() (string, error) {
-	return parseUpToLen(p.buff, &p.cursor, p.l, 48, ErrInvalidAppName)
+	appName, err := parseUpToLen(&p.cursor, 48, ErrInvalidAppName)
+	if err == nil && (p.strict || p.printUSASCII) {
+		err = p.checkPrintUSASCII(appName, ErrInvalidAppName)
+	}
+
+	return p.str(appName), err
 }
 
 // PROCID = NILVALUE / 1*128PRINTUSASCII
 func (p *Parser) parseProcId() (string, error) {
-	return parseUpToLen(p.buff, &p.cursor, p.l, 128, ErrInvalidProcId)
+	procId, err := parseUpToLen(&p.cursor, 128, ErrInvalidProcId)
+	if err == nil && (p.strict || p.printUSASCII) {
+		err = p.checkPrintUSASCII(procId, ErrInvalidProcId)
+	}
+
+	return p.str(procId), err
 }
 
 // MSGID = NILVALUE / 1*32PRINTUSASCII
 func (p *Parser) parseMsgId() (string, error) {
-	return parseUpToLen(
-		p.buff, &p.cursor, p.l, 32, ErrInvalidMsgId,
-	)
+	msgId, err := parseUpToLen(&p.cursor, 32, ErrInvalidMsgId)
+	if err == nil && (p.strict || p.printUSASCII) {
+		err = p.checkPrintUSASCII(msgId, ErrInvalidMsgId)
+	}
+
+	return p.str(msgId), err
 }
 
 func (p *Parser) parseStructuredData() (string, error) {
-	return parseStructuredData(p.buff, &p.cursor, p.l)
+	sd, err := parseStructuredData(&p.cursor)
+	if err == nil && (p.strict || p.sdValidation) {
+		err = p.checkStructuredData(sd)
+	}
+
+	return p.str(sd), err
+}
+
+// [SP MSG] following STRUCTURED-DATA, surrounding spaces are trimmed.
+// The message is empty when the line ends right after STRUCTURED-DATA,
+// with or without a trailing SP.
+func (p *Parser) parseMessage() (string, error) {
+	if p.cursor.EOF() {
+		p.endSpan()
+		return "", nil
+	}
+
+	if !p.cursor.Expect(' ') {
+		return "", parsercommon.ErrNoSpace
+	}
+
+	rest := p.cursor.Rest()
+	msg := bytes.Trim(rest, " ")
+
+	start := p.cursor.Pos() + len(rest) - len(bytes.TrimLeft(rest, " "))
+	p.setSpan("message", start, start+len(msg))
+
+	if p.strict {
+		if err := p.checkMessage(msg); err != nil {
+			return "", err
+		}
+	}
+
+	p.cursor.SetPos(p.cursor.Len())
+
+	return p.str(msg), nil
+}
+
+func (p *Parser) str(b []byte) string {
+	if p.zeroCopy {
+		return parsercommon.UnsafeString(b)
+	}
+
+	return string(b)
 }
 
 // ----------------------------------------------
 // https://tools.ietf.org/html/rfc5424#section-6
 // ----------------------------------------------
 
-// XXX : bind them to Parser ?
-
 // FULL-DATE : DATE-FULLYEAR "-" DATE-MONTH "-" DATE-MDAY
-func parseFullDate(buff []byte, cursor *int, l int) (fullDate, error) {
+func parseFullDate(c *parsercommon.Cursor) (fullDate, error) {
 	var fd fullDate
 
-	year, err := parseYear(buff, cursor, l)
+	year, err := parseYear(c)
 	if err != nil {
 		return fd, err
 	}
 
-	if buff[*cursor] != '-' {
+	if !c.Expect('-') {
 		return fd, parsercommon.ErrTimestampUnknownFormat
 	}
 
-	*cursor++
-
-	month, err := parseMonth(buff, cursor, l)
+	month, err := parseMonth(c)
 	if err != nil {
 		return fd, err
 	}
 
-	if buff[*cursor] != '-' {
+	if !c.Expect('-') {
 		return fd, parsercommon.ErrTimestampUnknownFormat
 	}
 
-	*cursor++
-
-	day, err := parseDay(buff, cursor, l)
+	day, err := parseDay(c)
 	if err != nil {
 		return fd, err
 	}
@@ -366,49 +1014,54 @@ func parseFullDate(buff []byte, cursor *int, l int) (fullDate, error) {
 }
 
 // DATE-FULLYEAR   = 4DIGIT
-func parseYear(buff []byte, cursor *int, l int) (int, error) {
+func parseYear(c *parsercommon.Cursor) (int, error) {
 	yearLen := 4
 
-	if *cursor+yearLen > l {
+	from := c.Pos()
+	if !c.Advance(yearLen) {
+		c.SetPos(from)
 		return 0, parsercommon.ErrEOL
 	}
 
 	// XXX : we do not check for a valid year (ie. 1999, 2013 etc)
 	// XXX : we only checks the format is correct
-	sub := string(buff[*cursor : *cursor+yearLen])
-
-	*cursor += yearLen
+	sub := string(c.Slice(from, from+yearLen))
 
 	year, err := strconv.Atoi(sub)
 	if err != nil {
-		return 0, ErrYearInvalid
+		return 0, parsercommon.Wrap(ErrYearInvalid, err)
 	}
 
 	return year, nil
 }
 
 // DATE-MONTH = 2DIGIT  ; 01-12
-func parseMonth(buff []byte, cursor *int, l int) (int, error) {
-	return parsercommon.Parse2Digits(buff, cursor, l, 1, 12, ErrMonthInvalid)
+func parseMonth(c *parsercommon.Cursor) (int, error) {
+	return c.Parse2Digits(1, 12, ErrMonthInvalid)
 }
 
 // DATE-MDAY = 2DIGIT  ; 01-28, 01-29, 01-30, 01-31 based on month/year
-func parseDay(buff []byte, cursor *int, l int) (int, error) {
+func parseDay(c *parsercommon.Cursor) (int, error) {
 	// XXX : this is a relaxed constraint
 	// XXX : we do not check if valid regarding February or leap years
 	// XXX : we only checks that day is in range [01 -> 31]
 	// XXX : in other words this function will not rant if you provide Feb 31th
-	return parsercommon.Parse2Digits(buff, cursor, l, 1, 31, ErrDayInvalid)
+	return c.Parse2Digits(1, 31, ErrDayInvalid)
+}
+
+// Days in month of year, leap years included
+func daysIn(year int, month int) int {
+	return time.Date(year, time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC).Day()
 }
 
 // FULL-TIME = PARTIAL-TIME TIME-OFFSET
-func parseFullTime(buff []byte, cursor *int, l int) (*fullTime, error) {
-	pt, err := parsePartialTime(buff, cursor, l)
+func parseFullTime(c *parsercommon.Cursor) (*fullTime, error) {
+	pt, err := parsePartialTime(c)
 	if err != nil {
 		return nil, err
 	}
 
-	loc, err := parseTimeOffset(buff, cursor, l)
+	loc, err := parseTimeOffset(c)
 	if err != nil {
 		return nil, err
 	}
@@ -422,27 +1075,19 @@ func parseFullTime(buff []byte, cursor *int, l int) (*fullTime, error) {
 }
 
 // PARTIAL-TIME = TIME-HOUR ":" TIME-MINUTE ":" TIME-SECOND[TIME-SECFRAC]
-func parsePartialTime(buff []byte, cursor *int, l int) (*partialTime, error) {
-	hour, minute, err := getHourMinute(
-		buff, cursor, l,
-	)
-
+func parsePartialTime(c *parsercommon.Cursor) (*partialTime, error) {
+	hour, minute, err := getHourMinute(c)
 	if err != nil {
 		return nil, err
 	}
 
-	if buff[*cursor] != ':' {
+	if !c.Expect(':') {
 		return nil, ErrInvalidTimeFormat
 	}
 
-	*cursor++
-
 	// ----
 
-	seconds, err := parseSecond(
-		buff, cursor, l,
-	)
-
+	seconds, err := parseSecond(c)
 	if err != nil {
 		return nil, err
 	}
@@ -455,16 +1100,14 @@ func parsePartialTime(buff []byte, cursor *int, l int) (*partialTime, error) {
 
 	// ----
 
-	if buff[*cursor] != '.' {
+	// XXX : ',' is accepted as well, as sent by some European firmwares
+	if c.Expect(',') {
+		pt.commaSecFrac = true
+	} else if !c.Expect('.') {
 		return pt, nil
 	}
 
-	*cursor++
-
-	secFrac, err := parseSecFrac(
-		buff, cursor, l,
-	)
-
+	secFrac, err := parseSecFrac(c)
 	if err != nil {
 		return pt, nil
 	}
@@ -475,103 +1118,119 @@ func parsePartialTime(buff []byte, cursor *int, l int) (*partialTime, error) {
 }
 
 // TIME-HOUR = 2DIGIT  ; 00-23
-func parseHour(buff []byte, cursor *int, l int) (int, error) {
-	return parsercommon.Parse2Digits(buff, cursor, l, 0, 23, ErrHourInvalid)
+func parseHour(c *parsercommon.Cursor) (int, error) {
+	return c.Parse2Digits(0, 23, ErrHourInvalid)
 }
 
 // TIME-MINUTE = 2DIGIT  ; 00-59
-func parseMinute(buff []byte, cursor *int, l int) (int, error) {
-	return parsercommon.Parse2Digits(buff, cursor, l, 0, 59, ErrMinuteInvalid)
+func parseMinute(c *parsercommon.Cursor) (int, error) {
+	return c.Parse2Digits(0, 59, ErrMinuteInvalid)
 }
 
 // TIME-SECOND = 2DIGIT  ; 00-59
-func parseSecond(buff []byte, cursor *int, l int) (int, error) {
-	return parsercommon.Parse2Digits(buff, cursor, l, 0, 59, ErrSecondInvalid)
+// XXX : 60 is accepted for leap seconds, which RFC3339 allows, though RFC5424
+// XXX : forbids them. See WithStrict().
+func parseSecond(c *parsercommon.Cursor) (int, error) {
+	return c.Parse2Digits(0, 60, ErrSecondInvalid)
 }
 
 // TIME-SECFRAC = "." 1*6DIGIT
-func parseSecFrac(buff []byte, cursor *int, l int) (float64, error) {
+func parseSecFrac(c *parsercommon.Cursor) (float64, error) {
 	maxDigitLen := 6
 
-	max := *cursor + maxDigitLen
-	from := *cursor
-	to := 0
+	from := c.Pos()
 
-	for to = from; to < max; to++ {
-		if to >= l {
+	for c.Pos()-from < maxDigitLen {
+		b, ok := c.Peek()
+		if !ok || !parsercommon.IsDigit(b) {
 			break
 		}
 
-		c := buff[to]
-		if !parsercommon.IsDigit(c) {
-			break
-		}
+		c.Advance(1)
 	}
 
-	sub := string(buff[from:to])
+	sub := string(c.Slice(from, c.Pos()))
 	if len(sub) == 0 {
 		return 0, ErrSecFracInvalid
 	}
 
 	secFrac, err := strconv.ParseFloat("0."+sub, 64)
-	*cursor = to
 	if err != nil {
-		return 0, ErrSecFracInvalid
+		return 0, parsercommon.Wrap(ErrSecFracInvalid, err)
 	}
 
 	return secFrac, nil
 }
 
 // TIME-OFFSET = "Z" / TIME-NUMOFFSET
-func parseTimeOffset(buff []byte, cursor *int, l int) (*time.Location, error) {
-
-	if buff[*cursor] == 'Z' {
-		*cursor++
+func parseTimeOffset(c *parsercommon.Cursor) (*time.Location, error) {
+	if c.Expect('Z') {
 		return time.UTC, nil
 	}
 
-	return parseNumericalTimeOffset(buff, cursor, l)
+	return parseNumericalTimeOffset(c)
 }
 
 // TIME-NUMOFFSET  = ("+" / "-") TIME-HOUR ":" TIME-MINUTE
-func parseNumericalTimeOffset(buff []byte, cursor *int, l int) (*time.Location, error) {
-	var loc = new(time.Location)
-
-	sign := buff[*cursor]
+func parseNumericalTimeOffset(c *parsercommon.Cursor) (*time.Location, error) {
+	sign, _ := c.Peek()
 
 	if (sign != '+') && (sign != '-') {
-		return loc, ErrTimeZoneInvalid
+		return nil, ErrTimeZoneInvalid
 	}
 
-	*cursor++
+	c.Advance(1)
 
-	hour, minute, err := getHourMinute(buff, cursor, l)
+	hour, minute, err := getHourMinute(c)
 	if err != nil {
-		return loc, err
+		return nil, err
 	}
 
-	tzStr := fmt.Sprintf("%s%02d:%02d", string(sign), hour, minute)
-	tmpTs, err := time.Parse("-07:00", tzStr)
-	if err != nil {
-		return loc, err
+	offset := (hour * 3600) + (minute * 60)
+	if sign == '-' {
+		if offset == 0 {
+			return UnknownOffset, nil
+		}
+
+		offset = -offset
+	}
+
+	return fixedZone(offset), nil
+}
+
+// Locations are immutable so they are shared between messages
+// instead of being rebuilt for every timestamp
+func fixedZone(offset int) *time.Location {
+	tzCache.RLock()
+	loc, ok := tzCache.zones[offset]
+	tzCache.RUnlock()
+
+	if ok {
+		return loc
+	}
+
+	tzCache.Lock()
+	defer tzCache.Unlock()
+
+	if loc, ok = tzCache.zones[offset]; !ok {
+		loc = time.FixedZone("", offset)
+		tzCache.zones[offset] = loc
 	}
 
-	return tmpTs.Location(), nil
+	return loc
 }
 
-func getHourMinute(buff []byte, cursor *int, l int) (int, int, error) {
-	hour, err := parseHour(buff, cursor, l)
+func getHourMinute(c *parsercommon.Cursor) (int, int, error) {
+	hour, err := parseHour(c)
 	if err != nil {
 		return 0, 0, err
 	}
 
-	if buff[*cursor] != ':' {
+	if !c.Expect(':') {
 		return 0, 0, ErrInvalidTimeFormat
 	}
 
-	*cursor++
-
-	minute, err := parseMinute(buff, cursor, l)
+	minute, err := parseMinute(c)
 	if err != nil {
 		return 0, 0, err
 	}
@@ -579,6 +1238,7 @@ func getHourMinute(buff []byte, cursor *int, l int) (int, int, error) {
 	return hour, minute, nil
 }
 
+// nanoseconds are below 1e9 and fit in int, even on 32-bit platforms
 func toNSec(sec float64) (int, error) {
 	_, frac := math.Modf(sec)
 	fracStr := strconv.FormatFloat(frac, 'f', 9, 64)
@@ -594,70 +1254,84 @@ func toNSec(sec float64) (int, error) {
 // https://tools.ietf.org/html/rfc5424#section-6.3
 // ------------------------------------------------
 
-func parseStructuredData(buff []byte, cursor *int, l int) (string, error) {
-	var sdData string
-	var found bool
+func parseStructuredData(c *parsercommon.Cursor) ([]byte, error) {
+	var sdData []byte
+
+	from := c.Pos()
 
-	if buff[*cursor] == NILVALUE {
-		*cursor++
-		return "-", nil
+	if c.Expect(NILVALUE) {
+		return c.Slice(from, c.Pos()), nil
 	}
 
-	if buff[*cursor] != '[' {
+	if b, _ := c.Peek(); b != '[' {
 		return sdData, ErrNoStructuredData
 	}
 
-	from := *cursor
-	to := 0
+	if to, ok := scanStructuredData(c, from); ok {
+		c.SetPos(to)
+		return c.Slice(from, to), nil
+	}
 
-	for to = from; to < l; to++ {
-		if found {
-			break
+	// XXX : quotes are unbalanced, SD ends at the first ']' followed by a SP
+	// XXX : or the end of the buffer
+	for to := from; to < c.Len(); to++ {
+		if b, _ := c.At(to); b != ']' {
+			continue
 		}
 
-		b := buff[to]
-
-		if b == ']' {
-			switch t := to + 1; {
-			case t == l:
-				found = true
-			case t <= l && buff[t] == ' ':
-				found = true
-			}
+		next, ok := c.At(to + 1)
+		if !ok || next == ' ' {
+			c.SetPos(to + 1)
+			return c.Slice(from, to+1), nil
 		}
 	}
 
-	if found {
-		*cursor = to
-		return string(buff[from:to]), nil
-	}
-
 	return sdData, ErrNoStructuredData
 }
 
-func parseUpToLen(buff []byte, cursor *int, l int, maxLen int, e error) (string, error) {
-	var to int
-	var found bool
-	var result string
-
-	max := *cursor + maxLen
+// Returns the offset following the SD-ELEMENTs starting at from. A ']' within
+// a quoted PARAM-VALUE, escaped or not, does not end an element.
+func scanStructuredData(c *parsercommon.Cursor, from int) (int, bool) {
+	quoted := false
+
+	for i := from; i < c.Len(); i++ {
+		b, _ := c.At(i)
+
+		switch {
+		case quoted && b == '\\':
+			i++
+		case b == '"':
+			quoted = !quoted
+		case !quoted && b == ']':
+			next, ok := c.At(i + 1)
+			if !ok || next == ' ' {
+				return i + 1, true
+			}
 
-	for to = *cursor; (to < max) && (to < l); to++ {
-		if buff[to] == ' ' {
-			found = true
-			break
+			if next != '[' {
+				return 0, false
+			}
 		}
 	}
 
-	if found {
-		result = string(buff[*cursor:to])
-	}
+	return 0, false
+}
 
-	*cursor = to
+func parseUpToLen(c *parsercommon.Cursor, maxLen int, e error) ([]byte, error) {
+	from := c.Pos()
+
+	for c.Pos()-from < maxLen {
+		b, ok := c.Peek()
+		if !ok {
+			return nil, e
+		}
+
+		if b == ' ' {
+			return c.Slice(from, c.Pos()), nil
+		}
 
-	if found {
-		return result, nil
+		c.Advance(1)
 	}
 
-	return "", e
+	return nil, e
 }
@@ -1,12 +1,13 @@
 package rfc5424
 
 import (
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
 
-	"github.com/jeromer/syslogparser"
 	"github.com/jeromer/syslogparser/parsercommon"
 	"github.com/stretchr/testify/require"
 )
@@ -18,12 +19,12 @@ func TestParser(t *testing.T) {
 	testCases := []struct {
 		description   string
 		input         string
-		expectedParts syslogparser.LogParts
+		expectedParts parsercommon.LogParts
 	}{
 		{
 			description: "no STRUCTURED-DATA 1/2",
 			input:       "<34>1 2003-10-11T22:14:15.003Z mymachine.example.com su - ID47 - 'su root' failed for lonvick on /dev/pts/8",
-			expectedParts: syslogparser.LogParts{
+			expectedParts: parsercommon.LogParts{
 				"priority": 34,
 				"facility": 4,
 				"severity": 2,
@@ -44,7 +45,7 @@ func TestParser(t *testing.T) {
 		{
 			description: "no STRUCTURED_DATA 2/2",
 			input:       "<165>1 2003-08-24T05:14:15.000003-07:00 192.0.2.1 myproc 8710 - - %% It's time to make the do-nuts.",
-			expectedParts: syslogparser.LogParts{
+			expectedParts: parsercommon.LogParts{
 				"priority": 165,
 				"facility": 20,
 				"severity": 5,
@@ -65,7 +66,7 @@ func TestParser(t *testing.T) {
 		{
 			description: "with STRUCTURED_DATA",
 			input:       `<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 [exampleSDID@32473 iut="3" eventSource="Application" eventID="1011"] An application event log entry...`,
-			expectedParts: syslogparser.LogParts{
+			expectedParts: parsercommon.LogParts{
 				"priority": 165,
 				"facility": 20,
 				"severity": 5,
@@ -86,7 +87,7 @@ func TestParser(t *testing.T) {
 		{
 			description: "STRUCTURED_DATA only",
 			input:       `<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 [exampleSDID@32473 iut="3" eventSource= "Application" eventID="1011"][examplePriority@32473 class="high"]`,
-			expectedParts: syslogparser.LogParts{
+			expectedParts: parsercommon.LogParts{
 				"priority": 165,
 				"facility": 20,
 				"severity": 5,
@@ -113,9 +114,7 @@ func TestParser(t *testing.T) {
 		require.Equal(
 			t,
 			&Parser{
-				buff:   buff,
-				cursor: 0,
-				l:      len(tc.input),
+				cursor: parsercommon.NewCursor(buff, MAX_PACKET_LEN),
 			},
 			p,
 			tc.description,
@@ -144,9 +143,7 @@ func TestParseWithHostname(t *testing.T) {
 	require.Equal(
 		t,
 		&Parser{
-			buff:        buff,
-			cursor:      0,
-			l:           len(buff),
+			cursor:      parsercommon.NewCursor(buff, MAX_PACKET_LEN),
 			tmpHostname: "mymachine.example.com",
 		},
 		p,
@@ -156,7 +153,7 @@ func TestParseWithHostname(t *testing.T) {
 	require.Nil(t, err)
 
 	require.Equal(
-		t, syslogparser.LogParts{
+		t, parsercommon.LogParts{
 			"priority": 34,
 			"facility": 4,
 			"severity": 2,
@@ -189,9 +186,7 @@ func TestParseWithPriority(t *testing.T) {
 	require.Equal(
 		t,
 		&Parser{
-			buff:        buff,
-			cursor:      0,
-			l:           len(buff),
+			cursor:      parsercommon.NewCursor(buff, MAX_PACKET_LEN),
 			tmpPriority: pri,
 		},
 		p,
@@ -201,7 +196,7 @@ func TestParseWithPriority(t *testing.T) {
 	require.Nil(t, err)
 
 	require.Equal(
-		t, syslogparser.LogParts{
+		t, parsercommon.LogParts{
 			"priority": 34,
 			"facility": 4,
 			"severity": 2,
@@ -235,9 +230,7 @@ func TestParseWithPriorityAndHostname(t *testing.T) {
 	require.Equal(
 		t,
 		&Parser{
-			buff:        buff,
-			cursor:      0,
-			l:           len(buff),
+			cursor:      parsercommon.NewCursor(buff, MAX_PACKET_LEN),
 			tmpHostname: "mymachine.example.com",
 			tmpPriority: pri,
 		},
@@ -248,7 +241,7 @@ func TestParseWithPriorityAndHostname(t *testing.T) {
 	require.Nil(t, err)
 
 	require.Equal(
-		t, syslogparser.LogParts{
+		t, parsercommon.LogParts{
 			"priority": 34,
 			"facility": 4,
 			"severity": 2,
@@ -268,281 +261,1324 @@ func TestParseWithPriorityAndHostname(t *testing.T) {
 	)
 }
 
-func TestParseHeader(t *testing.T) {
-	ts := time.Date(2003, time.October, 11, 22, 14, 15, 3*10e5, time.UTC)
-	tsString := "2003-10-11T22:14:15.003Z"
-	hostname := "mymachine.example.com"
-	appName := "su"
-	procId := "123"
-	msgId := "ID47"
-	nilValue := string(NILVALUE)
-	headerFmt := "<165>1 %s %s %s %s %s "
-	pri := &parsercommon.Priority{
-		P: 165,
-		F: parsercommon.Facility{Value: 20},
-		S: parsercommon.Severity{Value: 5},
+func TestParseWithZeroCopy(t *testing.T) {
+	buff := []byte(
+		`<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 [exampleSDID@32473 iut="3"] An application event log entry...`,
+	)
+
+	expected := NewParser(buff)
+	err := expected.Parse()
+	require.Nil(t, err)
+
+	p := NewParser(buff)
+	p.WithZeroCopy()
+
+	err = p.Parse()
+	require.Nil(t, err)
+
+	obtained := p.Dump()
+	require.Equal(t, expected.Dump(), obtained)
+
+	// values share the buffer memory
+	copy(buff[32:], "MYMACHINE")
+	require.Equal(t, "MYMACHINE.example.com", obtained["hostname"])
+	require.Equal(t, "mymachine.example.com", expected.Dump()["hostname"])
+}
+
+func TestParseReset(t *testing.T) {
+	p := NewParser([]byte("foo"))
+	p.WithHostname("dummy")
+
+	buff := []byte("bar")
+	p.Reset(buff)
+
+	require.Equal(t, NewParser(buff), p)
+}
+
+func TestParseWithNames(t *testing.T) {
+	buff := []byte(
+		"<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 - An application event log entry...",
+	)
+
+	p := NewParser(buff)
+	err := p.Parse()
+	require.Nil(t, err)
+
+	obtained := p.Dump()
+	require.NotContains(t, obtained, "facility_name")
+	require.NotContains(t, obtained, "severity_name")
+
+	p = NewParser(buff)
+	p.WithNames()
+
+	err = p.Parse()
+	require.Nil(t, err)
+
+	obtained = p.Dump()
+	require.Equal(t, "local4", obtained["facility_name"])
+	require.Equal(t, "notice", obtained["severity_name"])
+}
+
+func TestParseLenientPriority(t *testing.T) {
+	buff := []byte(
+		"<999>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 - An application event log entry...",
+	)
+
+	p := NewParser(buff)
+	err := p.Parse()
+	require.ErrorIs(t, err, parsercommon.ErrPriorityInvalid)
+
+	p = NewParser(buff)
+	p.WithLenientPriority()
+
+	err = p.Parse()
+	require.Nil(t, err)
+
+	obtained := p.Dump()
+	require.Equal(t, 999, obtained["priority"])
+	require.Equal(t, 124, obtained["facility"])
+	require.Equal(t, 7, obtained["severity"])
+}
+
+func TestParseErrorLocation(t *testing.T) {
+	testCases := []struct {
+		description    string
+		input          string
+		expectedErr    error
+		expectedOffset int
+		expectedField  string
+	}{
+		{
+			description:    "timestamp",
+			input:          "<34>1 2003-13-11T22:14:15.003Z mymachine su - ID47 - message",
+			expectedErr:    ErrMonthInvalid,
+			expectedOffset: 6,
+			expectedField:  "timestamp",
+		},
+		{
+			description:    "structured_data",
+			input:          "<34>1 2003-10-11T22:14:15.003Z mymachine su - ID47 ",
+			expectedErr:    ErrNoStructuredData,
+			expectedOffset: 51,
+			expectedField:  "structured_data",
+		},
+	}
+
+	for _, tc := range testCases {
+		p := NewParser([]byte(tc.input))
+		err := p.Parse()
+
+		require.ErrorIs(t, err, tc.expectedErr, tc.description)
+
+		pe, ok := err.(*parsercommon.ParserError)
+		require.True(t, ok, tc.description)
+
+		require.Equal(
+			t, tc.expectedOffset, pe.Offset(), tc.description,
+		)
+
+		require.Equal(
+			t, tc.expectedField, pe.Field(), tc.description,
+		)
+
+		snippet := tc.input[tc.expectedOffset:]
+		if len(snippet) > parsercommon.SNIPPET_LEN {
+			snippet = snippet[:parsercommon.SNIPPET_LEN]
+		}
+
+		require.Equal(
+			t, snippet, pe.Snippet(), tc.description,
+		)
+	}
+}
+
+func TestParseWithProvenance(t *testing.T) {
+	buff := []byte(
+		"<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 - An application event log entry...",
+	)
+
+	p := NewParser(buff)
+	err := p.Parse()
+	require.Nil(t, err)
+	require.NotContains(t, p.Dump(), "provenance")
+
+	p = NewParser([]byte(
+		"<165>1 2003-10-11T22:14:15.003Z evntslog - ID47 - An application event log entry...",
+	))
+	p.WithProvenance()
+	p.WithHostname("forced")
+
+	err = p.Parse()
+	require.Nil(t, err)
+
+	require.Equal(
+		t,
+		map[string]string{
+			"priority":        parsercommon.PROVENANCE_PARSED,
+			"facility":        parsercommon.PROVENANCE_PARSED,
+			"severity":        parsercommon.PROVENANCE_PARSED,
+			"version":         parsercommon.PROVENANCE_PARSED,
+			"timestamp":       parsercommon.PROVENANCE_PARSED,
+			"hostname":        parsercommon.PROVENANCE_FORCED,
+			"app_name":        parsercommon.PROVENANCE_PARSED,
+			"proc_id":         parsercommon.PROVENANCE_PARSED,
+			"msg_id":          parsercommon.PROVENANCE_PARSED,
+			"structured_data": parsercommon.PROVENANCE_PARSED,
+			"message":         parsercommon.PROVENANCE_PARSED,
+		},
+		p.Dump()["provenance"],
+	)
+}
+
+func TestParseWithPayloadParser(t *testing.T) {
+	buff := []byte(
+		"<165>1 2003-10-11T22:14:15.003Z mymachine evntslog - ID47 - hostname=spoofed user=root",
+	)
+
+	kv := parsercommon.PayloadParserFunc(func(payload string) (parsercommon.LogParts, error) {
+		parts := parsercommon.LogParts{}
+
+		for _, field := range strings.Fields(payload) {
+			kv := strings.SplitN(field, "=", 2)
+			if len(kv) != 2 {
+				return nil, errors.New("Invalid pair")
+			}
+
+			parts[kv[0]] = kv[1]
+		}
+
+		return parts, nil
+	})
+
+	p := NewParser(buff)
+	p.WithPayloadParser(kv, parsercommon.HEADER_WINS)
+
+	err := p.Parse()
+	require.Nil(t, err)
+
+	obtained := p.Dump()
+	require.Equal(t, "mymachine", obtained["hostname"])
+	require.Equal(t, "root", obtained["user"])
+	require.Equal(t, "hostname=spoofed user=root", obtained["message"])
+
+	p = NewParser(buff)
+	p.WithPayloadParser(kv, parsercommon.PAYLOAD_PREFIXED)
+
+	err = p.Parse()
+	require.Nil(t, err)
+
+	obtained = p.Dump()
+	require.Equal(t, "mymachine", obtained["hostname"])
+	require.Equal(t, "spoofed", obtained["payload_hostname"])
+	require.Equal(t, "root", obtained["payload_user"])
+	require.NotContains(t, obtained, "user")
+
+	p = NewParser([]byte(strings.Replace(string(buff), "user=root", "root", 1)))
+	p.WithPayloadParser(kv, parsercommon.PAYLOAD_WINS)
+
+	err = p.Parse()
+	require.Nil(t, err)
+
+	obtained = p.Dump()
+	require.Equal(t, "mymachine", obtained["hostname"])
+	require.Equal(t, "Invalid pair", obtained["payload_error"])
+}
+
+func TestParseDefaultPriority(t *testing.T) {
+	buff := []byte(
+		"1 2003-10-11T22:14:15.003Z mymachine su - ID47 - msg",
+	)
+
+	p := NewParser(buff)
+	err := p.Parse()
+	require.ErrorIs(t, err, parsercommon.ErrPriorityNoStart)
+
+	p = NewParser(buff)
+	p.WithDefaultPriority()
+	p.WithProvenance()
+
+	err = p.Parse()
+	require.Nil(t, err)
+
+	obtained := p.Dump()
+	require.Equal(t, parsercommon.DEFAULT_PRIORITY, obtained["priority"])
+	require.Equal(t, 1, obtained["facility"])
+	require.Equal(t, 5, obtained["severity"])
+	require.Equal(t, "mymachine", obtained["hostname"])
+
+	prov := obtained["provenance"].(map[string]string)
+	require.Equal(t, parsercommon.PROVENANCE_DEFAULT, prov["priority"])
+
+	p = NewParser([]byte("<34>" + string(buff)))
+	p.WithDefaultPriority()
+
+	err = p.Parse()
+	require.Nil(t, err)
+	require.Equal(t, 34, p.Dump()["priority"])
+}
+
+func TestParseLongAppName(t *testing.T) {
+	long := strings.Repeat("a", 60)
+
+	testCases := []struct {
+		description     string
+		input           string
+		expectedAppName string
+		expectedProcId  string
+		expectedMsgId   string
+		expectedSD      string
+		expectedMessage string
+		expectedErr     error
+	}{
+		{
+			description:     "nil structured data",
+			input:           "<165>1 2003-10-11T22:14:15.003Z mymachine " + long + " 1234 ID47 - message - with dashes",
+			expectedAppName: long,
+			expectedProcId:  "1234",
+			expectedMsgId:   "ID47",
+			expectedSD:      "-",
+			expectedMessage: "message - with dashes",
+		},
+		{
+			description:     "nil proc id",
+			input:           "<165>1 2003-10-11T22:14:15.003Z mymachine " + long + " - ID47 - message",
+			expectedAppName: long,
+			expectedProcId:  "-",
+			expectedMsgId:   "ID47",
+			expectedSD:      "-",
+			expectedMessage: "message",
+		},
+		{
+			description:     "structured data",
+			input:           "<165>1 2003-10-11T22:14:15.003Z mymachine " + long + " - - [exampleSDID@32473 iut=\"3\"] message",
+			expectedAppName: long,
+			expectedProcId:  "-",
+			expectedMsgId:   "-",
+			expectedSD:      "[exampleSDID@32473 iut=\"3\"]",
+			expectedMessage: "message",
+		},
+		{
+			description: "no structured data",
+			input:       "<165>1 2003-10-11T22:14:15.003Z mymachine " + long + " 1234 ID47",
+			expectedErr: ErrInvalidAppName,
+		},
+	}
+
+	for _, tc := range testCases {
+		p := NewParser([]byte(tc.input))
+		p.WithLongAppName()
+		p.WithProvenance()
+
+		err := p.Parse()
+		require.ErrorIs(t, err, tc.expectedErr, tc.description)
+
+		if tc.expectedErr != nil {
+			continue
+		}
+
+		obtained := p.Dump()
+		require.Equal(t, tc.expectedAppName, obtained["app_name"], tc.description)
+		require.Equal(t, tc.expectedProcId, obtained["proc_id"], tc.description)
+		require.Equal(t, tc.expectedMsgId, obtained["msg_id"], tc.description)
+		require.Equal(t, tc.expectedSD, obtained["structured_data"], tc.description)
+		require.Equal(t, tc.expectedMessage, obtained["message"], tc.description)
+
+		prov := obtained["provenance"].(map[string]string)
+		require.Equal(t, parsercommon.PROVENANCE_INFERRED, prov["app_name"], tc.description)
+	}
+
+	p := NewParser([]byte(testCases[0].input))
+	err := p.Parse()
+	require.ErrorIs(t, err, ErrInvalidAppName)
+}
+
+func TestParseWithKeyMapper(t *testing.T) {
+	buff := []byte(
+		"<165>1 2003-10-11T22:14:15.003Z mymachine su - ID47 - msg",
+	)
+
+	p := NewParser(buff)
+	p.WithKeyMapper(parsercommon.KeysECS)
+
+	err := p.Parse()
+	require.Nil(t, err)
+
+	obtained := p.Dump()
+	require.Equal(t, "mymachine", obtained["log.syslog.hostname"])
+	require.Equal(t, "su", obtained["log.syslog.appname"])
+	require.Equal(t, "msg", obtained["message"])
+	require.NotContains(t, obtained, "hostname")
+
+	p = NewParser(buff)
+	p.WithKeyMapper(parsercommon.KeysCamelCase)
+
+	err = p.Parse()
+	require.Nil(t, err)
+
+	obtained = p.Dump()
+	require.Equal(t, "su", obtained["appName"])
+	require.Equal(t, "ID47", obtained["msgId"])
+}
+
+func TestParseWithRaw(t *testing.T) {
+	buff := []byte(
+		"<165>1 2003-10-11T22:14:15.003Z mymachine su - ID47 - msg",
+	)
+
+	p := NewParser(buff)
+
+	err := p.Parse()
+	require.Nil(t, err)
+
+	obtained := p.Dump()
+	require.NotContains(t, obtained, "rfc")
+	require.NotContains(t, obtained, "raw")
+
+	p = NewParser(buff)
+	p.WithRaw()
+
+	err = p.Parse()
+	require.Nil(t, err)
+
+	obtained = p.Dump()
+	require.Equal(t, 5424, obtained["rfc"])
+	require.Equal(t, string(buff), obtained["raw"])
+}
+
+func TestParseWithRawTimestamp(t *testing.T) {
+	testCases := []struct {
+		description string
+		input       string
+		expected    string
+	}{
+		{
+			description: "fraction of second",
+			input:       "<165>1 2003-10-11T22:14:15.003Z mymachine su - ID47 - msg",
+			expected:    "2003-10-11T22:14:15.003Z",
+		},
+		{
+			description: "time zone",
+			input:       "<165>1 2003-08-24T05:14:15.000003-07:00 mymachine su - ID47 - msg",
+			expected:    "2003-08-24T05:14:15.000003-07:00",
+		},
+		{
+			description: "nil timestamp",
+			input:       "<165>1 - mymachine su - ID47 - msg",
+			expected:    "-",
+		},
+	}
+
+	for _, tc := range testCases {
+		p := NewParser([]byte(tc.input))
+		p.WithRawTimestamp()
+
+		err := p.Parse()
+		require.Nil(t, err, tc.description)
+		require.Equal(t, tc.expected, p.Dump()["timestamp_raw"], tc.description)
 	}
+}
+
+func TestParseTimestampPresence(t *testing.T) {
 	testCases := []struct {
 		description string
 		input       string
-		expectedHdr *header
+		expected    bool
+		zero        bool
+	}{
+		{
+			description: "timestamp",
+			input:       "<165>1 2003-10-11T22:14:15.003Z mymachine su - ID47 - msg",
+			expected:    true,
+		},
+		{
+			description: "year 1",
+			input:       "<165>1 0001-01-01T00:00:00Z mymachine su - ID47 - msg",
+			expected:    true,
+			zero:        true,
+		},
+		{
+			description: "nil timestamp",
+			input:       "<165>1 - mymachine su - ID47 - msg",
+			expected:    false,
+			zero:        true,
+		},
+	}
+
+	for _, tc := range testCases {
+		p := NewParser([]byte(tc.input))
+		p.WithTimestampPresence()
+		p.WithProvenance()
+
+		err := p.Parse()
+		require.Nil(t, err, tc.description)
+		require.Equal(t, tc.expected, p.HasTimestamp(), tc.description)
+
+		obtained := p.Dump()
+		require.Equal(t, tc.expected, obtained["timestamp_present"], tc.description)
+		require.Equal(t, tc.zero, obtained["timestamp"].(time.Time).IsZero(), tc.description)
+
+		expectedProvenance := parsercommon.PROVENANCE_PARSED
+		if !tc.expected {
+			expectedProvenance = parsercommon.PROVENANCE_DEFAULT
+		}
+
+		prov := obtained["provenance"].(map[string]string)
+		require.Equal(t, expectedProvenance, prov["timestamp"], tc.description)
+	}
+}
+
+func TestParseWithKernelOffset(t *testing.T) {
+	testCases := []struct {
+		description     string
+		input           string
+		kernelOffset    bool
+		expectedOffset  interface{}
+		expectedContent string
+	}{
+		{
+			description:     "kernel",
+			input:           "<6>1 2003-10-11T22:14:15.003Z mymachine kernel - - - [12345.678901] usb 1-1: new device",
+			kernelOffset:    true,
+			expectedOffset:  12345*time.Second + 678901*time.Microsecond,
+			expectedContent: "usb 1-1: new device",
+		},
+		{
+			description:     "disabled",
+			input:           "<6>1 2003-10-11T22:14:15.003Z mymachine kernel - - - [12345.678901] usb 1-1: new device",
+			expectedOffset:  nil,
+			expectedContent: "[12345.678901] usb 1-1: new device",
+		},
+		{
+			description:     "not kernel",
+			input:           "<6>1 2003-10-11T22:14:15.003Z mymachine myapp - - - [12345.678901] usb 1-1: new device",
+			kernelOffset:    true,
+			expectedOffset:  nil,
+			expectedContent: "[12345.678901] usb 1-1: new device",
+		},
+	}
+
+	for _, tc := range testCases {
+		p := NewParser([]byte(tc.input))
+
+		if tc.kernelOffset {
+			p.WithKernelOffset()
+		}
+
+		err := p.Parse()
+		require.Nil(t, err, tc.description)
+
+		obtained := p.Dump()
+		require.Equal(t, tc.expectedOffset, obtained["boot_offset"], tc.description)
+		require.Equal(t, tc.expectedContent, obtained["message"], tc.description)
+	}
+}
+
+func TestParseWithCEE(t *testing.T) {
+	testCases := []struct {
+		description   string
+		input         string
+		cee           bool
+		expectedCEE   interface{}
+		expectedError interface{}
+	}{
+		{
+			description: "cookie",
+			input:       `<13>1 2003-10-11T22:14:15.003Z mymachine myapp - - - @cee: {"event":"login","user":"bob"}`,
+			cee:         true,
+			expectedCEE: map[string]interface{}{
+				"event": "login",
+				"user":  "bob",
+			},
+			expectedError: nil,
+		},
+		{
+			description:   "no cookie",
+			input:         `<13>1 2003-10-11T22:14:15.003Z mymachine myapp - - - {"event":"login"}`,
+			cee:           true,
+			expectedCEE:   nil,
+			expectedError: nil,
+		},
+		{
+			description:   "invalid json",
+			input:         `<13>1 2003-10-11T22:14:15.003Z mymachine myapp - - - @cee: {"event":`,
+			cee:           true,
+			expectedCEE:   nil,
+			expectedError: parsercommon.ErrInvalidCEE.Error(),
+		},
+		{
+			description:   "disabled",
+			input:         `<13>1 2003-10-11T22:14:15.003Z mymachine myapp - - - @cee: {"event":"login"}`,
+			expectedCEE:   nil,
+			expectedError: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		p := NewParser([]byte(tc.input))
+
+		if tc.cee {
+			p.WithCEE()
+		}
+
+		err := p.Parse()
+		require.Nil(t, err, tc.description)
+
+		obtained := p.Dump()
+		require.Equal(t, tc.expectedCEE, obtained["cee"], tc.description)
+		require.Equal(t, tc.expectedError, obtained["cee_error"], tc.description)
+	}
+}
+
+func TestDumpMessage(t *testing.T) {
+	buffs := []string{
+		`<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 [exampleSDID@32473 iut="3"] An application event log entry...`,
+		"<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog 123 ID47 -",
+	}
+
+	for _, buff := range buffs {
+		p := NewParser([]byte(buff))
+		err := p.Parse()
+		require.Nil(t, err)
+
+		m := parsercommon.Message{Hostname: "previous"}
+		p.DumpMessage(&m)
+
+		require.Equal(t, p.Dump(), m.Parts(), buff)
+	}
+}
+
+func TestParseWithDiagnostics(t *testing.T) {
+	buff := []byte(
+		"<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 - An application event log entry...",
+	)
+
+	p := NewParser(buff)
+	err := p.Parse()
+	require.Nil(t, err)
+
+	obtained := p.Dump()
+	require.NotContains(t, obtained, "parser")
+	require.NotContains(t, obtained, "parse_duration")
+
+	p = NewParser(buff)
+	p.WithDiagnostics()
+
+	err = p.Parse()
+	require.Nil(t, err)
+
+	obtained = p.Dump()
+	require.Equal(t, PARSER_NAME, obtained["parser"])
+	require.IsType(t, time.Duration(0), obtained["parse_duration"])
+}
+
+func TestParseHeader(t *testing.T) {
+	ts := time.Date(2003, time.October, 11, 22, 14, 15, 3*10e5, time.UTC)
+	tsString := "2003-10-11T22:14:15.003Z"
+	hostname := "mymachine.example.com"
+	appName := "su"
+	procId := "123"
+	msgId := "ID47"
+	nilValue := string(NILVALUE)
+	headerFmt := "<165>1 %s %s %s %s %s "
+	pri := &parsercommon.Priority{
+		P: 165,
+		F: parsercommon.Facility{Value: 20},
+		S: parsercommon.Severity{Value: 5},
+	}
+	testCases := []struct {
+		description string
+		input       string
+		expectedHdr *header
+	}{
+		{
+			description: "HEADER complete",
+			input:       fmt.Sprintf(headerFmt, tsString, hostname, appName, procId, msgId),
+			expectedHdr: &header{
+				priority:  pri,
+				version:   1,
+				timestamp: ts,
+				hostname:  hostname,
+				appName:   appName,
+				procId:    procId,
+				msgId:     msgId,
+			},
+		},
+		{
+			description: "TIMESTAMP as NILVALUE",
+			input:       fmt.Sprintf(headerFmt, nilValue, hostname, appName, procId, msgId),
+			expectedHdr: &header{
+				priority:  pri,
+				version:   1,
+				timestamp: time.Time{},
+				hostname:  hostname,
+				appName:   appName,
+				procId:    procId,
+				msgId:     msgId,
+			},
+		},
+		{
+			description: "HOSTNAME as NILVALUE",
+			input:       fmt.Sprintf(headerFmt, tsString, nilValue, appName, procId, msgId),
+			expectedHdr: &header{
+				priority:  pri,
+				version:   1,
+				timestamp: ts,
+				hostname:  nilValue,
+				appName:   appName,
+				procId:    procId,
+				msgId:     msgId,
+			},
+		},
+		{
+			description: "APP-NAME as NILVALUE",
+			input:       fmt.Sprintf(headerFmt, tsString, hostname, nilValue, procId, msgId),
+			expectedHdr: &header{
+				priority:  pri,
+				version:   1,
+				timestamp: ts,
+				hostname:  hostname,
+				appName:   nilValue,
+				procId:    procId,
+				msgId:     msgId,
+			},
+		},
+		{
+			description: "PROCID as NILVALUE",
+			input:       fmt.Sprintf(headerFmt, tsString, hostname, appName, nilValue, msgId),
+			expectedHdr: &header{
+				priority:  pri,
+				version:   1,
+				timestamp: ts,
+				hostname:  hostname,
+				appName:   appName,
+				procId:    nilValue,
+				msgId:     msgId,
+			},
+		},
+		{
+			description: "MSGID as NILVALUE",
+			input:       fmt.Sprintf(headerFmt, tsString, hostname, appName, procId, nilValue),
+			expectedHdr: &header{
+				priority:  pri,
+				version:   1,
+				timestamp: ts,
+				hostname:  hostname,
+				appName:   appName,
+				procId:    procId,
+				msgId:     nilValue,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		p := NewParser([]byte(tc.input))
+		obtained, err := p.parseHeader()
+
+		require.Nil(
+			t, err, tc.description,
+		)
+
+		require.Equal(
+			t, tc.expectedHdr, obtained, tc.description,
+		)
+
+		require.Equal(
+			t, len(tc.input), p.cursor.Pos(), tc.description,
+		)
+	}
+}
+
+func TestParseTimestamp(t *testing.T) {
+	tz := "-04:00"
+	tmpTZ, err := time.Parse("-07:00", tz)
+	require.Nil(t, err)
+	require.NotNil(t, tmpTZ)
+
+	dt1 := time.Date(
+		1985, time.April, 12,
+		23, 20, 50, 52*10e6,
+		time.UTC,
+	)
+
+	dt2 := time.Date(
+		1985, time.April, 12,
+		19, 20, 50, 52*10e6,
+		tmpTZ.Location(),
+	)
+
+	dt3 := time.Date(
+		2003, time.October, 11,
+		22, 14, 15, 3*10e5,
+		time.UTC,
+	)
+
+	dt4 := time.Date(
+		2003, time.August, 24,
+		5, 14, 15, 3*10e2,
+		tmpTZ.Location(),
+	)
+	testCases := []struct {
+		description       string
+		input             string
+		expectedTS        *time.Time
+		expectedCursorPos int
+		expectedErr       error
+	}{
+		{
+			description:       "UTC timestamp",
+			input:             "1985-04-12T23:20:50.52Z",
+			expectedTS:        &dt1,
+			expectedCursorPos: 23,
+			expectedErr:       nil,
+		},
+		{
+			description:       "numeric timezone",
+			input:             "1985-04-12T19:20:50.52" + tz,
+			expectedTS:        &dt2,
+			expectedCursorPos: 28,
+			expectedErr:       nil,
+		},
+		{
+			description:       "timestamp with ms",
+			input:             "2003-10-11T22:14:15.003Z",
+			expectedTS:        &dt3,
+			expectedCursorPos: 24,
+			expectedErr:       nil,
+		},
+		{
+			description:       "timestamp with comma",
+			input:             "2003-10-11T22:14:15,003Z",
+			expectedTS:        &dt3,
+			expectedCursorPos: 24,
+			expectedErr:       nil,
+		},
+		{
+			description:       "timestamp with us",
+			input:             "2003-08-24T05:14:15.000003" + tz,
+			expectedTS:        &dt4,
+			expectedCursorPos: 32,
+			expectedErr:       nil,
+		},
+		{
+			description:       "timestamp with ns",
+			input:             "2003-08-24T05:14:15.000000003-07:00",
+			expectedCursorPos: 26,
+			expectedTS:        nil,
+			expectedErr:       parsercommon.ErrTimestampUnknownFormat,
+		},
+		{
+			description:       "nil timestamp",
+			input:             "-",
+			expectedCursorPos: 1,
+			expectedTS:        nil,
+			expectedErr:       nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		p := NewParser([]byte(tc.input))
+		obtained, err := p.parseTimestamp()
+
+		require.Equal(
+			t, tc.expectedErr, err, tc.description,
+		)
+
+		require.Equal(
+			t,
+			tc.expectedCursorPos,
+			p.cursor.Pos(),
+			tc.description,
+		)
+
+		if tc.expectedErr != nil {
+			require.Nil(
+				t, obtained, tc.description,
+			)
+
+			continue
+		}
+
+		if tc.description == "nil timestamp" {
+			continue
+		}
+
+		tfmt := time.RFC3339Nano
+		require.Equal(
+			t,
+			tc.expectedTS.Format(tfmt),
+			obtained.Format(tfmt),
+			tc.description,
+		)
+	}
+}
+
+func TestParseTimestampBeyond32Bits(t *testing.T) {
+	testCases := []struct {
+		description  string
+		input        string
+		expectedUnix int64
+		expectedNSec int
+	}{
+		{
+			description:  "after 2038",
+			input:        "2038-01-19T03:14:08.000001Z",
+			expectedUnix: 2147483648,
+			expectedNSec: 1000,
+		},
+		{
+			description:  "far future",
+			input:        "9999-12-31T23:59:59.999999Z",
+			expectedUnix: 253402300799,
+			expectedNSec: 999999000,
+		},
+		{
+			description:  "before 1901",
+			input:        "1901-12-13T20:45:51Z",
+			expectedUnix: -2147483649,
+			expectedNSec: 0,
+		},
+		{
+			description:  "far past",
+			input:        "0001-01-01T00:00:00.5+01:00",
+			expectedUnix: -62135600400,
+			expectedNSec: 500000000,
+		},
+	}
+
+	for _, tc := range testCases {
+		p := NewParser([]byte(tc.input))
+		obtained, err := p.parseTimestamp()
+
+		require.Nil(t, err, tc.description)
+
+		require.Equal(
+			t, tc.expectedUnix, obtained.Unix(), tc.description,
+		)
+
+		require.Equal(
+			t, tc.expectedNSec, obtained.Nanosecond(), tc.description,
+		)
+	}
+}
+
+func TestParseYear(t *testing.T) {
+	testCases := []struct {
+		description       string
+		input             string
+		expectedYear      int
+		expectedCursorPos int
+		expectedErr       error
+	}{
+		{
+			description:       "invalid year",
+			input:             "1a2b",
+			expectedYear:      0,
+			expectedCursorPos: 4,
+			expectedErr:       ErrYearInvalid,
+		},
+		{
+			description:       "year too short",
+			input:             "123",
+			expectedYear:      0,
+			expectedCursorPos: 0,
+			expectedErr:       parsercommon.ErrEOL,
+		},
+		{
+			description:       "valid",
+			input:             "2013",
+			expectedYear:      2013,
+			expectedCursorPos: 4,
+			expectedErr:       nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		c := parsercommon.NewCursor([]byte(tc.input), len(tc.input))
+		obtained, err := parseYear(&c)
+
+		require.Equal(
+			t, tc.expectedYear, obtained, tc.description,
+		)
+
+		require.True(
+			t, errors.Is(err, tc.expectedErr), tc.description,
+		)
+
+		require.Equal(
+			t, tc.expectedCursorPos, c.Pos(), tc.description,
+		)
+	}
+}
+
+func TestParseYearCause(t *testing.T) {
+	c := parsercommon.NewCursor([]byte("1a2b"), 4)
+
+	_, err := parseYear(&c)
+
+	var numErr *strconv.NumError
+	require.True(t, errors.As(err, &numErr))
+	require.Equal(t, "1a2b", numErr.Num)
+}
+
+func TestParseMonth(t *testing.T) {
+	testCases := []struct {
+		description       string
+		input             string
+		expectedMonth     int
+		expectedCursorPos int
+		expectedErr       error
+	}{
+		{
+			description:       "invalid string",
+			input:             "ab",
+			expectedMonth:     0,
+			expectedCursorPos: 2,
+			expectedErr:       ErrMonthInvalid,
+		},
+		{
+			description:       "invalid range 1/2",
+			input:             "00",
+			expectedMonth:     0,
+			expectedCursorPos: 2,
+			expectedErr:       ErrMonthInvalid,
+		},
+		{
+			description:       "invalid range 2/2",
+			input:             "13",
+			expectedMonth:     0,
+			expectedCursorPos: 2,
+			expectedErr:       ErrMonthInvalid,
+		},
+		{
+			description:       "too short",
+			input:             "1",
+			expectedMonth:     0,
+			expectedCursorPos: 0,
+			expectedErr:       parsercommon.ErrEOL,
+		},
+		{
+			description:       "valid",
+			input:             "02",
+			expectedMonth:     2,
+			expectedCursorPos: 2,
+			expectedErr:       nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		c := parsercommon.NewCursor([]byte(tc.input), len(tc.input))
+		obtained, err := parseMonth(&c)
+
+		require.Equal(
+			t, tc.expectedMonth, obtained, tc.description,
+		)
+
+		require.Equal(
+			t, tc.expectedErr, err, tc.description,
+		)
+
+		require.Equal(
+			t, tc.expectedCursorPos, c.Pos(), tc.description,
+		)
+	}
+}
+
+func TestParseDay(t *testing.T) {
+	testCases := []struct {
+		description       string
+		input             string
+		expectedDay       int
+		expectedCursorPos int
+		expectedErr       error
 	}{
 		{
-			description: "HEADER complete",
-			input:       fmt.Sprintf(headerFmt, tsString, hostname, appName, procId, msgId),
-			expectedHdr: &header{
-				priority:  pri,
-				version:   1,
-				timestamp: ts,
-				hostname:  hostname,
-				appName:   appName,
-				procId:    procId,
-				msgId:     msgId,
-			},
-		},
-		{
-			description: "TIMESTAMP as NILVALUE",
-			input:       fmt.Sprintf(headerFmt, nilValue, hostname, appName, procId, msgId),
-			expectedHdr: &header{
-				priority:  pri,
-				version:   1,
-				timestamp: time.Time{},
-				hostname:  hostname,
-				appName:   appName,
-				procId:    procId,
-				msgId:     msgId,
-			},
+			description:       "invalid string",
+			input:             "ab",
+			expectedDay:       0,
+			expectedCursorPos: 2,
+			expectedErr:       ErrDayInvalid,
 		},
 		{
-			description: "HOSTNAME as NILVALUE",
-			input:       fmt.Sprintf(headerFmt, tsString, nilValue, appName, procId, msgId),
-			expectedHdr: &header{
-				priority:  pri,
-				version:   1,
-				timestamp: ts,
-				hostname:  nilValue,
-				appName:   appName,
-				procId:    procId,
-				msgId:     msgId,
-			},
+			description:       "too short",
+			input:             "1",
+			expectedDay:       0,
+			expectedCursorPos: 0,
+			expectedErr:       parsercommon.ErrEOL,
 		},
 		{
-			description: "APP-NAME as NILVALUE",
-			input:       fmt.Sprintf(headerFmt, tsString, hostname, nilValue, procId, msgId),
-			expectedHdr: &header{
-				priority:  pri,
-				version:   1,
-				timestamp: ts,
-				hostname:  hostname,
-				appName:   nilValue,
-				procId:    procId,
-				msgId:     msgId,
-			},
+			description:       "invalid range 1/2",
+			input:             "00",
+			expectedDay:       0,
+			expectedCursorPos: 2,
+			expectedErr:       ErrDayInvalid,
 		},
 		{
-			description: "PROCID as NILVALUE",
-			input:       fmt.Sprintf(headerFmt, tsString, hostname, appName, nilValue, msgId),
-			expectedHdr: &header{
-				priority:  pri,
-				version:   1,
-				timestamp: ts,
-				hostname:  hostname,
-				appName:   appName,
-				procId:    nilValue,
-				msgId:     msgId,
-			},
+			description:       "invalid range 2/2",
+			input:             "32",
+			expectedDay:       0,
+			expectedCursorPos: 2,
+			expectedErr:       ErrDayInvalid,
 		},
 		{
-			description: "MSGID as NILVALUE",
-			input:       fmt.Sprintf(headerFmt, tsString, hostname, appName, procId, nilValue),
-			expectedHdr: &header{
-				priority:  pri,
-				version:   1,
-				timestamp: ts,
-				hostname:  hostname,
-				appName:   appName,
-				procId:    procId,
-				msgId:     nilValue,
-			},
+			description:       "valid",
+			input:             "02",
+			expectedDay:       2,
+			expectedCursorPos: 2,
+			expectedErr:       nil,
 		},
 	}
 
 	for _, tc := range testCases {
-		p := NewParser([]byte(tc.input))
-		obtained, err := p.parseHeader()
+		c := parsercommon.NewCursor([]byte(tc.input), len(tc.input))
+		obtained, err := parseDay(&c)
 
-		require.Nil(
-			t, err, tc.description,
+		require.Equal(
+			t, tc.expectedDay, obtained, tc.description,
 		)
 
 		require.Equal(
-			t, tc.expectedHdr, obtained, tc.description,
+			t, tc.expectedErr, err, tc.description,
 		)
 
 		require.Equal(
-			t, len(tc.input), p.cursor, tc.description,
+			t, tc.expectedCursorPos, c.Pos(), tc.description,
 		)
 	}
 }
 
-func TestParseTimestamp(t *testing.T) {
-	tz := "-04:00"
-	tmpTZ, err := time.Parse("-07:00", tz)
-	require.Nil(t, err)
-	require.NotNil(t, tmpTZ)
+func TestParseFullDate(t *testing.T) {
+	testCases := []struct {
+		description       string
+		input             string
+		expectedDate      fullDate
+		expectedCursorPos int
+		expectedErr       error
+	}{
+		{
+			description:       "invalid separator 1/2",
+			input:             "2013+10-28",
+			expectedDate:      fullDate{},
+			expectedCursorPos: 4,
+			expectedErr:       parsercommon.ErrTimestampUnknownFormat,
+		},
+		{
+			description:       "invalid separator 2/2",
+			input:             "2013-10+28",
+			expectedDate:      fullDate{},
+			expectedCursorPos: 7,
+			expectedErr:       parsercommon.ErrTimestampUnknownFormat,
+		},
+		{
+			description:       "valid",
+			input:             "2013-10-28",
+			expectedDate:      fullDate{2013, 10, 28},
+			expectedCursorPos: 10,
+			expectedErr:       nil,
+		},
+	}
 
-	dt1 := time.Date(
-		1985, time.April, 12,
-		23, 20, 50, 52*10e6,
-		time.UTC,
-	)
+	for _, tc := range testCases {
+		c := parsercommon.NewCursor([]byte(tc.input), len(tc.input))
+		obtained, err := parseFullDate(&c)
 
-	dt2 := time.Date(
-		1985, time.April, 12,
-		19, 20, 50, 52*10e6,
-		tmpTZ.Location(),
-	)
+		require.Equal(
+			t, tc.expectedErr, err, tc.description,
+		)
 
-	dt3 := time.Date(
-		2003, time.October, 11,
-		22, 14, 15, 3*10e5,
-		time.UTC,
-	)
+		require.Equal(
+			t, tc.expectedDate, obtained, tc.description,
+		)
 
-	dt4 := time.Date(
-		2003, time.August, 24,
-		5, 14, 15, 3*10e2,
-		tmpTZ.Location(),
-	)
+		require.Equal(
+			t, tc.expectedCursorPos, c.Pos(), tc.description,
+		)
+	}
+}
+
+func TestParseHour(t *testing.T) {
 	testCases := []struct {
 		description       string
 		input             string
-		expectedTS        *time.Time
+		expectedHour      int
 		expectedCursorPos int
 		expectedErr       error
 	}{
 		{
-			description:       "UTC timestamp",
-			input:             "1985-04-12T23:20:50.52Z",
-			expectedTS:        &dt1,
-			expectedCursorPos: 23,
-			expectedErr:       nil,
+			description:       "invalid",
+			input:             "azer",
+			expectedHour:      0,
+			expectedCursorPos: 2,
+			expectedErr:       ErrHourInvalid,
 		},
 		{
-			description:       "numeric timezone",
-			input:             "1985-04-12T19:20:50.52" + tz,
-			expectedTS:        &dt2,
-			expectedCursorPos: 28,
-			expectedErr:       nil,
+			description:       "too short",
+			input:             "1",
+			expectedHour:      0,
+			expectedCursorPos: 0,
+			expectedErr:       parsercommon.ErrEOL,
 		},
 		{
-			description:       "timestamp with ms",
-			input:             "2003-10-11T22:14:15.003Z",
-			expectedTS:        &dt3,
-			expectedCursorPos: 24,
-			expectedErr:       nil,
+			description:       "invalid range 1/2",
+			input:             "-1",
+			expectedHour:      0,
+			expectedCursorPos: 2,
+			expectedErr:       ErrHourInvalid,
 		},
 		{
-			description:       "timestamp with us",
-			input:             "2003-08-24T05:14:15.000003" + tz,
-			expectedTS:        &dt4,
-			expectedCursorPos: 32,
+			description:       "invalid range 2/2",
+			input:             "24",
+			expectedHour:      0,
+			expectedCursorPos: 2,
+			expectedErr:       ErrHourInvalid,
+		},
+		{
+			description:       "valid",
+			input:             "12",
+			expectedHour:      12,
+			expectedCursorPos: 2,
 			expectedErr:       nil,
 		},
+	}
+
+	for _, tc := range testCases {
+		c := parsercommon.NewCursor([]byte(tc.input), len(tc.input))
+		obtained, err := parseHour(&c)
+
+		require.Equal(
+			t, tc.expectedHour, obtained, tc.description,
+		)
+
+		require.Equal(
+			t, tc.expectedErr, err, tc.description,
+		)
+
+		require.Equal(
+			t, tc.expectedCursorPos, c.Pos(), tc.description,
+		)
+	}
+}
+
+func TestParseMinute(t *testing.T) {
+	testCases := []struct {
+		description       string
+		input             string
+		expectedMinute    int
+		expectedCursorPos int
+		expectedErr       error
+	}{
+		{
+			description:       "invalid",
+			input:             "azer",
+			expectedMinute:    0,
+			expectedCursorPos: 2,
+			expectedErr:       ErrMinuteInvalid,
+		},
+		{
+			description:       "too short",
+			input:             "1",
+			expectedMinute:    0,
+			expectedCursorPos: 0,
+			expectedErr:       parsercommon.ErrEOL,
+		},
 		{
-			description:       "timestamp with ns",
-			input:             "2003-08-24T05:14:15.000000003-07:00",
-			expectedCursorPos: 26,
-			expectedTS:        nil,
-			expectedErr:       parsercommon.ErrTimestampUnknownFormat,
+			description:       "invalid range 1/2",
+			input:             "-1",
+			expectedMinute:    0,
+			expectedCursorPos: 2,
+			expectedErr:       ErrMinuteInvalid,
 		},
 		{
-			description:       "nil timestamp",
-			input:             "-",
-			expectedCursorPos: 1,
-			expectedTS:        nil,
+			description:       "invalid range 2/2",
+			input:             "60",
+			expectedMinute:    0,
+			expectedCursorPos: 2,
+			expectedErr:       ErrMinuteInvalid,
+		},
+		{
+			description:       "valid",
+			input:             "12",
+			expectedMinute:    12,
+			expectedCursorPos: 2,
 			expectedErr:       nil,
 		},
 	}
 
 	for _, tc := range testCases {
-		p := NewParser([]byte(tc.input))
-		obtained, err := p.parseTimestamp()
+		c := parsercommon.NewCursor([]byte(tc.input), len(tc.input))
+		obtained, err := parseMinute(&c)
 
 		require.Equal(
-			t, tc.expectedErr, err, tc.description,
+			t, tc.expectedMinute, obtained, tc.description,
 		)
 
 		require.Equal(
-			t,
-			tc.expectedCursorPos,
-			p.cursor,
-			tc.description,
+			t, tc.expectedErr, err, tc.description,
 		)
 
-		if tc.expectedErr != nil {
-			require.Nil(
-				t, obtained, tc.description,
-			)
-
-			continue
-		}
-
-		if tc.description == "nil timestamp" {
-			continue
-		}
-
-		tfmt := time.RFC3339Nano
 		require.Equal(
-			t,
-			tc.expectedTS.Format(tfmt),
-			obtained.Format(tfmt),
-			tc.description,
+			t, tc.expectedCursorPos, c.Pos(), tc.description,
 		)
 	}
 }
 
-func TestParseYear(t *testing.T) {
+func TestParseSecond(t *testing.T) {
 	testCases := []struct {
 		description       string
 		input             string
-		expectedYear      int
+		expectedSecond    int
 		expectedCursorPos int
 		expectedErr       error
 	}{
 		{
-			description:       "invalid year",
-			input:             "1a2b",
-			expectedYear:      0,
-			expectedCursorPos: 4,
-			expectedErr:       ErrYearInvalid,
+			description:       "invalid",
+			input:             "azer",
+			expectedSecond:    0,
+			expectedCursorPos: 2,
+			expectedErr:       ErrSecondInvalid,
 		},
 		{
-			description:       "year too short",
-			input:             "123",
-			expectedYear:      0,
+			description:       "too short",
+			input:             "1",
+			expectedSecond:    0,
 			expectedCursorPos: 0,
 			expectedErr:       parsercommon.ErrEOL,
 		},
+		{
+			description:       "invalid range 1/2",
+			input:             "-1",
+			expectedSecond:    0,
+			expectedCursorPos: 2,
+			expectedErr:       ErrSecondInvalid,
+		},
+		{
+			description:       "invalid range 2/2",
+			input:             "61",
+			expectedSecond:    0,
+			expectedCursorPos: 2,
+			expectedErr:       ErrSecondInvalid,
+		},
+		{
+			description:       "leap second",
+			input:             "60",
+			expectedSecond:    60,
+			expectedCursorPos: 2,
+			expectedErr:       nil,
+		},
 		{
 			description:       "valid",
-			input:             "2013",
-			expectedYear:      2013,
-			expectedCursorPos: 4,
+			input:             "12",
+			expectedSecond:    12,
+			expectedCursorPos: 2,
 			expectedErr:       nil,
 		},
 	}
 
 	for _, tc := range testCases {
-		cursor := 0
-		obtained, err := parseYear(
-			[]byte(tc.input),
-			&cursor,
-			len(tc.input),
-		)
+		c := parsercommon.NewCursor([]byte(tc.input), len(tc.input))
+		obtained, err := parseSecond(&c)
 
 		require.Equal(
-			t, tc.expectedYear, obtained, tc.description,
+			t, tc.expectedSecond, obtained, tc.description,
 		)
 
 		require.Equal(
@@ -550,845 +1586,1159 @@ func TestParseYear(t *testing.T) {
 		)
 
 		require.Equal(
-			t, tc.expectedCursorPos, cursor, tc.description,
+			t, tc.expectedCursorPos, c.Pos(), tc.description,
 		)
 	}
 }
 
-func TestParseMonth(t *testing.T) {
+func TestParseSecFrac(t *testing.T) {
 	testCases := []struct {
 		description       string
 		input             string
-		expectedMonth     int
+		expectedSecFrac   float64
 		expectedCursorPos int
 		expectedErr       error
 	}{
 		{
-			description:       "invalid string",
-			input:             "ab",
-			expectedMonth:     0,
-			expectedCursorPos: 2,
-			expectedErr:       ErrMonthInvalid,
+			description:       "invalid",
+			input:             "azerty",
+			expectedSecFrac:   0,
+			expectedCursorPos: 0,
+			expectedErr:       ErrSecFracInvalid,
 		},
 		{
-			description:       "invalid range 1/2",
-			input:             "00",
-			expectedMonth:     0,
-			expectedCursorPos: 2,
-			expectedErr:       ErrMonthInvalid,
+			description:       "nanoseconds",
+			input:             "123456789",
+			expectedSecFrac:   0.123456,
+			expectedCursorPos: 6,
+			expectedErr:       nil,
 		},
 		{
-			description:       "invalid range 2/2",
-			input:             "13",
-			expectedMonth:     0,
+			description:       "valid 1/4",
+			input:             "0",
+			expectedSecFrac:   0,
+			expectedCursorPos: 1,
+			expectedErr:       nil,
+		},
+		{
+			description:       "valid 2/4",
+			input:             "52",
+			expectedSecFrac:   0.52,
 			expectedCursorPos: 2,
-			expectedErr:       ErrMonthInvalid,
+			expectedErr:       nil,
 		},
 		{
-			description:       "too short",
-			input:             "1",
-			expectedMonth:     0,
-			expectedCursorPos: 0,
-			expectedErr:       parsercommon.ErrEOL,
+			description:       "valid 3/4",
+			input:             "003",
+			expectedSecFrac:   0.003,
+			expectedCursorPos: 3,
+			expectedErr:       nil,
 		},
 		{
-			description:       "valid",
-			input:             "02",
-			expectedMonth:     2,
-			expectedCursorPos: 2,
+			description:       "valid 4/4",
+			input:             "000003",
+			expectedSecFrac:   0.000003,
+			expectedCursorPos: 6,
 			expectedErr:       nil,
 		},
 	}
 
-	for _, tc := range testCases {
-		cursor := 0
-		obtained, err := parseMonth(
-			[]byte(tc.input),
-			&cursor,
-			len(tc.input),
-		)
-
-		require.Equal(
-			t, tc.expectedMonth, obtained, tc.description,
-		)
+	for _, tc := range testCases {
+		c := parsercommon.NewCursor([]byte(tc.input), len(tc.input))
+		obtained, err := parseSecFrac(&c)
+
+		require.Equal(
+			t, tc.expectedSecFrac, obtained, tc.description,
+		)
+
+		require.Equal(
+			t, tc.expectedErr, err, tc.description,
+		)
+
+		require.Equal(
+			t, tc.expectedCursorPos, c.Pos(), tc.description,
+		)
+	}
+}
+
+func TestParseNumericalTimeOffset(t *testing.T) {
+	buff := []byte("+02:00")
+	l := len(buff)
+	c := parsercommon.NewCursor(buff, l)
+
+	tmpTs, err := time.Parse("-07:00", string(buff))
+	require.Nil(t, err)
+
+	obtained, err := parseNumericalTimeOffset(&c)
+
+	require.Nil(t, err)
+
+	expected := tmpTs.Location()
+	require.Equal(t, expected, obtained)
+	require.Equal(t, 6, c.Pos())
+
+	c.SetPos(0)
+	again, err := parseNumericalTimeOffset(&c)
+
+	require.Nil(t, err)
+	require.True(t, obtained == again)
+}
+
+func TestFixedZone(t *testing.T) {
+	testCases := map[string]int{
+		"+02:00": 7200,
+		"-07:00": -25200,
+		"+05:45": 20700,
+		"+00:00": 0,
+	}
+
+	for tz, offset := range testCases {
+		buff := []byte(tz)
+		c := parsercommon.NewCursor(buff, len(buff))
+
+		loc, err := parseNumericalTimeOffset(&c)
+
+		require.Nil(t, err, tz)
+
+		_, obtained := time.Date(
+			2003, time.October, 11, 22, 14, 15, 0, loc,
+		).Zone()
+
+		require.Equal(t, offset, obtained, tz)
+		require.True(t, fixedZone(offset) == loc, tz)
+	}
+}
+
+func TestUnknownOffset(t *testing.T) {
+	buff := []byte("-00:00")
+	c := parsercommon.NewCursor(buff, len(buff))
+
+	loc, err := parseNumericalTimeOffset(&c)
+	require.Nil(t, err)
+	require.True(t, loc == UnknownOffset)
+
+	p := NewParser([]byte(`<165>1 2003-10-11T22:14:15.003-00:00 mymachine evntslog - ID47 - hello`))
+	require.Nil(t, p.Parse())
+
+	ts := p.Dump()["timestamp"].(time.Time)
+	require.True(t, ts.Location() == UnknownOffset)
+	require.True(t, ts.Equal(time.Date(2003, time.October, 11, 22, 14, 15, 3*1000*1000, time.UTC)))
+
+	b, err := NewFormatter().Format(p.Dump())
+	require.Nil(t, err)
+	require.Equal(t, `<165>1 2003-10-11T22:14:15.003-00:00 mymachine evntslog - ID47 - hello`, string(b))
+}
+
+func TestParseTimeOffset(t *testing.T) {
+	buff := []byte("Z")
+	l := len(buff)
+	c := parsercommon.NewCursor(buff, l)
+
+	obtained, err := parseTimeOffset(&c)
+
+	require.Nil(t, err)
+	require.Equal(t, time.UTC, obtained)
+	require.Equal(t, 1, c.Pos())
+}
+
+func TestGetHourMin(t *testing.T) {
+	buff := []byte("12:34")
+	l := len(buff)
+	c := parsercommon.NewCursor(buff, l)
+
+	expectedH := 12
+	expectedM := 34
+
+	obtainedH, obtainedM, err := getHourMinute(&c)
+
+	require.Nil(t, err)
+	require.Equal(t, expectedH, obtainedH)
+	require.Equal(t, expectedM, obtainedM)
+	require.Equal(t, l, c.Pos())
+}
+
+func TestParsePartialTime(t *testing.T) {
+	buff := []byte("05:14:15.000003")
+	l := len(buff)
+	c := parsercommon.NewCursor(buff, l)
+
+	obtained, err := parsePartialTime(&c)
+
+	expected := &partialTime{
+		hour:    5,
+		minute:  14,
+		seconds: 15,
+		secFrac: 0.000003,
+	}
+
+	require.Nil(t, err)
+	require.Equal(t, expected, obtained)
+	require.Equal(t, l, c.Pos())
+}
+
+func TestParseFullTime(t *testing.T) {
+	tz := "-02:00"
+	buff := []byte("05:14:15.000003" + tz)
+	l := len(buff)
+	c := parsercommon.NewCursor(buff, l)
+
+	tmpTs, err := time.Parse("-07:00", string(tz))
+	require.Nil(t, err)
+
+	obtained, err := parseFullTime(&c)
+
+	expected := &fullTime{
+		pt: &partialTime{
+			hour:    5,
+			minute:  14,
+			seconds: 15,
+			secFrac: 0.000003,
+		},
+		loc: tmpTs.Location(),
+	}
+
+	require.Nil(t, err)
+	require.Equal(t, expected, obtained)
+	require.Equal(t, 21, c.Pos())
+}
 
-		require.Equal(
-			t, tc.expectedErr, err, tc.description,
-		)
+func TestToNSec(t *testing.T) {
+	testCases := map[float64]int{
+		0.52:     520000000,
+		0.003:    3000000,
+		0.000003: 3000,
+	}
 
-		require.Equal(
-			t, tc.expectedCursorPos, cursor, tc.description,
-		)
+	for src, expected := range testCases {
+		obtained, err := toNSec(src)
+		require.Nil(t, err)
+		require.Equal(t, expected, obtained)
 	}
 }
 
-func TestParseDay(t *testing.T) {
+func TestParseAppName(t *testing.T) {
 	testCases := []struct {
 		description       string
 		input             string
-		expectedDay       int
+		expectedAppName   string
 		expectedCursorPos int
 		expectedErr       error
 	}{
-		{
-			description:       "invalid string",
-			input:             "ab",
-			expectedDay:       0,
-			expectedCursorPos: 2,
-			expectedErr:       ErrDayInvalid,
-		},
-		{
-			description:       "too short",
-			input:             "1",
-			expectedDay:       0,
-			expectedCursorPos: 0,
-			expectedErr:       parsercommon.ErrEOL,
-		},
-		{
-			description:       "invalid range 1/2",
-			input:             "00",
-			expectedDay:       0,
-			expectedCursorPos: 2,
-			expectedErr:       ErrDayInvalid,
-		},
-		{
-			description:       "invalid range 2/2",
-			input:             "32",
-			expectedDay:       0,
-			expectedCursorPos: 2,
-			expectedErr:       ErrDayInvalid,
-		},
 		{
 			description:       "valid",
-			input:             "02",
-			expectedDay:       2,
+			input:             "su ",
+			expectedAppName:   "su",
 			expectedCursorPos: 2,
 			expectedErr:       nil,
 		},
+		{
+			description:       "too long",
+			input:             "suuuuuuuuuuuuuuuuuuuuuuuuuuuuuuuuuuuuuuuuuuuuuuuu ",
+			expectedAppName:   "",
+			expectedCursorPos: 48,
+			expectedErr:       ErrInvalidAppName,
+		},
 	}
 
 	for _, tc := range testCases {
-		cursor := 0
-		obtained, err := parseDay(
-			[]byte(tc.input),
-			&cursor,
-			len(tc.input),
-		)
+		p := NewParser([]byte(tc.input))
+		obtained, err := p.parseAppName()
 
 		require.Equal(
-			t, tc.expectedDay, obtained, tc.description,
+			t, tc.expectedErr, err, tc.description,
 		)
 
 		require.Equal(
-			t, tc.expectedErr, err, tc.description,
+			t, tc.expectedAppName, obtained, tc.description,
 		)
 
 		require.Equal(
-			t, tc.expectedCursorPos, cursor, tc.description,
+			t, tc.expectedCursorPos, p.cursor.Pos(), tc.description,
 		)
 	}
 }
 
-func TestParseFullDate(t *testing.T) {
+func TestParseProcID(t *testing.T) {
 	testCases := []struct {
 		description       string
 		input             string
-		expectedDate      fullDate
+		expectedProcID    string
 		expectedCursorPos int
 		expectedErr       error
 	}{
-		{
-			description:       "invalid separator 1/2",
-			input:             "2013+10-28",
-			expectedDate:      fullDate{},
-			expectedCursorPos: 4,
-			expectedErr:       parsercommon.ErrTimestampUnknownFormat,
-		},
-		{
-			description:       "invalid separator 2/2",
-			input:             "2013-10+28",
-			expectedDate:      fullDate{},
-			expectedCursorPos: 7,
-			expectedErr:       parsercommon.ErrTimestampUnknownFormat,
-		},
 		{
 			description:       "valid",
-			input:             "2013-10-28",
-			expectedDate:      fullDate{2013, 10, 28},
-			expectedCursorPos: 10,
+			input:             "123foo ",
+			expectedProcID:    "123foo",
+			expectedCursorPos: 6,
 			expectedErr:       nil,
 		},
+		{
+			description:       "too long",
+			input:             strings.Repeat("a", 129),
+			expectedProcID:    "",
+			expectedCursorPos: 128,
+			expectedErr:       ErrInvalidProcId,
+		},
 	}
 
 	for _, tc := range testCases {
-		cursor := 0
-		obtained, err := parseFullDate(
-			[]byte(tc.input),
-			&cursor,
-			len(tc.input),
-		)
+		p := NewParser([]byte(tc.input))
+		obtained, err := p.parseProcId()
 
 		require.Equal(
 			t, tc.expectedErr, err, tc.description,
 		)
 
 		require.Equal(
-			t, tc.expectedDate, obtained, tc.description,
+			t, tc.expectedProcID, obtained, tc.description,
 		)
 
 		require.Equal(
-			t, tc.expectedCursorPos, cursor, tc.description,
+			t, tc.expectedCursorPos, p.cursor.Pos(), tc.description,
 		)
 	}
 }
 
-func TestParseHour(t *testing.T) {
+func TestParseMsgID(t *testing.T) {
 	testCases := []struct {
 		description       string
 		input             string
-		expectedHour      int
+		expectedMsgID     string
 		expectedCursorPos int
 		expectedErr       error
 	}{
-		{
-			description:       "invalid",
-			input:             "azer",
-			expectedHour:      0,
-			expectedCursorPos: 2,
-			expectedErr:       ErrHourInvalid,
-		},
-		{
-			description:       "too short",
-			input:             "1",
-			expectedHour:      0,
-			expectedCursorPos: 0,
-			expectedErr:       parsercommon.ErrEOL,
-		},
-		{
-			description:       "invalid range 1/2",
-			input:             "-1",
-			expectedHour:      0,
-			expectedCursorPos: 2,
-			expectedErr:       ErrHourInvalid,
-		},
-		{
-			description:       "invalid range 2/2",
-			input:             "24",
-			expectedHour:      0,
-			expectedCursorPos: 2,
-			expectedErr:       ErrHourInvalid,
-		},
 		{
 			description:       "valid",
-			input:             "12",
-			expectedHour:      12,
-			expectedCursorPos: 2,
+			input:             "123foo ",
+			expectedMsgID:     "123foo",
+			expectedCursorPos: 6,
 			expectedErr:       nil,
 		},
+		{
+			description:       "too long",
+			input:             strings.Repeat("a", 33),
+			expectedMsgID:     "",
+			expectedCursorPos: 32,
+			expectedErr:       ErrInvalidMsgId,
+		},
 	}
 
 	for _, tc := range testCases {
-		cursor := 0
-		obtained, err := parseHour(
-			[]byte(tc.input),
-			&cursor,
-			len(tc.input),
-		)
+		p := NewParser([]byte(tc.input))
+		obtained, err := p.parseMsgId()
 
 		require.Equal(
-			t, tc.expectedHour, obtained, tc.description,
+			t, tc.expectedErr, err, tc.description,
 		)
 
 		require.Equal(
-			t, tc.expectedErr, err, tc.description,
+			t, tc.expectedMsgID, obtained, tc.description,
 		)
 
 		require.Equal(
-			t, tc.expectedCursorPos, cursor, tc.description,
+			t, tc.expectedCursorPos, p.cursor.Pos(), tc.description,
 		)
 	}
 }
 
-func TestParseMinute(t *testing.T) {
+func TestParseStructuredData(t *testing.T) {
 	testCases := []struct {
 		description       string
 		input             string
-		expectedMinute    int
+		expectedData      string
 		expectedCursorPos int
 		expectedErr       error
 	}{
 		{
-			description:       "invalid",
-			input:             "azer",
-			expectedMinute:    0,
-			expectedCursorPos: 2,
-			expectedErr:       ErrMinuteInvalid,
-		},
-		{
-			description:       "too short",
-			input:             "1",
-			expectedMinute:    0,
-			expectedCursorPos: 0,
-			expectedErr:       parsercommon.ErrEOL,
-		},
-		{
-			description:       "invalid range 1/2",
-			input:             "-1",
-			expectedMinute:    0,
-			expectedCursorPos: 2,
-			expectedErr:       ErrMinuteInvalid,
-		},
-		{
-			description:       "invalid range 2/2",
-			input:             "60",
-			expectedMinute:    0,
-			expectedCursorPos: 2,
-			expectedErr:       ErrMinuteInvalid,
+			description:       "nil",
+			input:             "-",
+			expectedData:      "-",
+			expectedCursorPos: 1,
+			expectedErr:       nil,
 		},
 		{
-			description:       "valid",
-			input:             "12",
-			expectedMinute:    12,
-			expectedCursorPos: 2,
+			description:       "single",
+			input:             `[exampleSDID@32473 iut="3" eventSource="Application"eventID="1011"]`,
+			expectedData:      `[exampleSDID@32473 iut="3" eventSource="Application"eventID="1011"]`,
+			expectedCursorPos: 67,
 			expectedErr:       nil,
 		},
-	}
-
-	for _, tc := range testCases {
-		cursor := 0
-		obtained, err := parseMinute(
-			[]byte(tc.input),
-			&cursor,
-			len(tc.input),
-		)
-
-		require.Equal(
-			t, tc.expectedMinute, obtained, tc.description,
-		)
-
-		require.Equal(
-			t, tc.expectedErr, err, tc.description,
-		)
-
-		require.Equal(
-			t, tc.expectedCursorPos, cursor, tc.description,
-		)
-	}
-}
-
-func TestParseSecond(t *testing.T) {
-	testCases := []struct {
-		description       string
-		input             string
-		expectedSecond    int
-		expectedCursorPos int
-		expectedErr       error
-	}{
 		{
-			description:       "invalid",
-			input:             "azer",
-			expectedSecond:    0,
-			expectedCursorPos: 2,
-			expectedErr:       ErrSecondInvalid,
+			description:       "multiple",
+			input:             `[exampleSDID@32473 iut="3" eventSource="Application"eventID="1011"][examplePriority@32473 class="high"]`,
+			expectedData:      `[exampleSDID@32473 iut="3" eventSource="Application"eventID="1011"][examplePriority@32473 class="high"]`,
+			expectedCursorPos: 103,
+			expectedErr:       nil,
 		},
 		{
-			description:       "too short",
-			input:             "1",
-			expectedSecond:    0,
+			description:       "empty",
+			input:             "",
+			expectedData:      "",
 			expectedCursorPos: 0,
-			expectedErr:       parsercommon.ErrEOL,
+			expectedErr:       ErrNoStructuredData,
 		},
 		{
-			description:       "invalid range 1/2",
-			input:             "-1",
-			expectedSecond:    0,
-			expectedCursorPos: 2,
-			expectedErr:       ErrSecondInvalid,
+			description:       "bracket in quoted value",
+			input:             `[sd@1 msg="a ] b"] message`,
+			expectedData:      `[sd@1 msg="a ] b"]`,
+			expectedCursorPos: 18,
+			expectedErr:       nil,
+		},
+		{
+			description:       "escaped bracket and quote in value",
+			input:             `[sd@1 msg="a \] \"b] \\"][sd@2 x="]"] message`,
+			expectedData:      `[sd@1 msg="a \] \"b] \\"][sd@2 x="]"]`,
+			expectedCursorPos: 37,
+			expectedErr:       nil,
 		},
 		{
-			description:       "invalid range 2/2",
-			input:             "60",
-			expectedSecond:    0,
-			expectedCursorPos: 2,
-			expectedErr:       ErrSecondInvalid,
+			description:       "unbalanced quotes",
+			input:             `[sd@1 msg="a] b`,
+			expectedData:      `[sd@1 msg="a]`,
+			expectedCursorPos: 13,
+			expectedErr:       nil,
 		},
 		{
-			description:       "valid",
-			input:             "12",
-			expectedSecond:    12,
-			expectedCursorPos: 2,
+			description:       "multiple invalid",
+			input:             `[exampleSDID@32473 iut="3" eventSource="Application"eventID="1011"] [examplePriority@32473 class="high"]`,
+			expectedData:      `[exampleSDID@32473 iut="3" eventSource="Application"eventID="1011"]`,
+			expectedCursorPos: 67,
 			expectedErr:       nil,
 		},
 	}
 
 	for _, tc := range testCases {
-		cursor := 0
-		obtained, err := parseSecond(
-			[]byte(tc.input),
-			&cursor,
-			len(tc.input),
-		)
+		c := parsercommon.NewCursor([]byte(tc.input), len(tc.input))
+		obtained, err := parseStructuredData(&c)
 
 		require.Equal(
-			t, tc.expectedSecond, obtained, tc.description,
+			t, tc.expectedErr, err, tc.description,
 		)
 
 		require.Equal(
-			t, tc.expectedErr, err, tc.description,
+			t, tc.expectedData, string(obtained), tc.description,
 		)
 
 		require.Equal(
-			t, tc.expectedCursorPos, cursor, tc.description,
+			t, tc.expectedCursorPos, c.Pos(), tc.description,
 		)
 	}
 }
 
-func TestParseSecFrac(t *testing.T) {
+func TestParseEmptyMessage(t *testing.T) {
+	start := "<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 "
+	sd := `[exampleSDID@32473 iut="3"]`
+
 	testCases := []struct {
-		description       string
-		input             string
-		expectedSecFrac   float64
-		expectedCursorPos int
-		expectedErr       error
+		description     string
+		input           string
+		expectedSD      string
+		expectedMessage string
+		expectedErr     error
 	}{
 		{
-			description:       "invalid",
-			input:             "azerty",
-			expectedSecFrac:   0,
-			expectedCursorPos: 0,
-			expectedErr:       ErrSecFracInvalid,
+			description:     "nil SD without trailing SP",
+			input:           start + "-",
+			expectedSD:      "-",
+			expectedMessage: "",
 		},
 		{
-			description:       "nanoseconds",
-			input:             "123456789",
-			expectedSecFrac:   0.123456,
-			expectedCursorPos: 6,
-			expectedErr:       nil,
+			description:     "nil SD with trailing SP",
+			input:           start + "- ",
+			expectedSD:      "-",
+			expectedMessage: "",
 		},
 		{
-			description:       "valid 1/4",
-			input:             "0",
-			expectedSecFrac:   0,
-			expectedCursorPos: 1,
-			expectedErr:       nil,
+			description:     "nil SD with trailing spaces",
+			input:           start + "-   ",
+			expectedSD:      "-",
+			expectedMessage: "",
 		},
 		{
-			description:       "valid 2/4",
-			input:             "52",
-			expectedSecFrac:   0.52,
-			expectedCursorPos: 2,
-			expectedErr:       nil,
+			description:     "SD without trailing SP",
+			input:           start + sd,
+			expectedSD:      sd,
+			expectedMessage: "",
 		},
 		{
-			description:       "valid 3/4",
-			input:             "003",
-			expectedSecFrac:   0.003,
-			expectedCursorPos: 3,
-			expectedErr:       nil,
+			description:     "SD with trailing SP",
+			input:           start + sd + " ",
+			expectedSD:      sd,
+			expectedMessage: "",
 		},
 		{
-			description:       "valid 4/4",
-			input:             "000003",
-			expectedSecFrac:   0.000003,
-			expectedCursorPos: 6,
-			expectedErr:       nil,
+			description:     "single char message",
+			input:           start + "- a",
+			expectedSD:      "-",
+			expectedMessage: "a",
+		},
+		{
+			description: "no SP between SD and MSG",
+			input:       start + "-a",
+			expectedErr: parsercommon.ErrNoSpace,
+		},
+		{
+			description: "no SD",
+			input:       start,
+			expectedErr: ErrNoStructuredData,
 		},
 	}
 
 	for _, tc := range testCases {
-		cursor := 0
-		obtained, err := parseSecFrac(
-			[]byte(tc.input),
-			&cursor,
-			len(tc.input),
-		)
+		p := NewParser([]byte(tc.input))
+		err := p.Parse()
 
-		require.Equal(
-			t, tc.expectedSecFrac, obtained, tc.description,
-		)
+		if tc.expectedErr != nil {
+			require.ErrorIs(t, err, tc.expectedErr, tc.description)
+			continue
+		}
 
-		require.Equal(
-			t, tc.expectedErr, err, tc.description,
-		)
+		require.Nil(t, err, tc.description)
 
-		require.Equal(
-			t, tc.expectedCursorPos, cursor, tc.description,
-		)
+		parts := p.Dump()
+
+		require.Equal(t, tc.expectedSD, parts["structured_data"], tc.description)
+		require.Equal(t, tc.expectedMessage, parts["message"], tc.description)
 	}
 }
 
-func TestParseNumericalTimeOffset(t *testing.T) {
-	buff := []byte("+02:00")
-	cursor := 0
-	l := len(buff)
+func TestParseMessageSizeChecks(t *testing.T) {
+	start := `<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 [exampleSDID@32473 iut="3" eventSource="Application" eventID="1011"] `
+	msg := start + strings.Repeat("a", MAX_PACKET_LEN)
 
-	tmpTs, err := time.Parse("-07:00", string(buff))
-	require.Nil(t, err)
+	p := NewParser([]byte(msg))
+	err := p.Parse()
+	fields := p.Dump()
+
+	require.Nil(
+		t, err,
+	)
 
-	obtained, err := parseNumericalTimeOffset(
-		buff, &cursor, l,
+	require.Len(
+		t,
+		fields["message"],
+		MAX_PACKET_LEN-len(start),
 	)
 
+	require.Equal(t, true, fields["truncated"])
+
+	// ---
+
+	msg = start + " hello "
+	p = NewParser([]byte(msg))
+	err = p.Parse()
+	fields = p.Dump()
+
 	require.Nil(t, err)
+	require.Equal(t, "hello", fields["message"])
+	require.NotContains(t, fields, "truncated")
+}
 
-	expected := tmpTs.Location()
-	require.Equal(t, expected, obtained)
-	require.Equal(t, 6, cursor)
+func TestParseTruncated(t *testing.T) {
+	msg := `<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 [exampleSDID@32473 iut="3"] An application event log entry...`
+
+	// every prefix must be rejected or parsed, not panic
+	for i := 0; i <= len(msg); i++ {
+		p := NewParser([]byte(msg[:i]))
+
+		require.NotPanics(t, func() {
+			_ = p.Parse()
+		}, msg[:i])
+	}
 }
 
-func TestParseTimeOffset(t *testing.T) {
-	buff := []byte("Z")
-	cursor := 0
-	l := len(buff)
+func BenchmarkParseTimestamp(b *testing.B) {
+	buff := []byte("2003-08-24T05:14:15.000003-07:00")
 
-	obtained, err := parseTimeOffset(
-		buff, &cursor, l,
-	)
+	p := NewParser(buff)
 
-	require.Nil(t, err)
-	require.Equal(t, time.UTC, obtained)
-	require.Equal(t, 1, cursor)
+	for i := 0; i < b.N; i++ {
+		_, err := p.parseTimestamp()
+		if err != nil {
+			panic(err)
+		}
+
+		p.cursor.SetPos(0)
+	}
 }
 
-func TestGetHourMin(t *testing.T) {
-	buff := []byte("12:34")
-	cursor := 0
+func BenchmarkParseNumericalTimeOffset(b *testing.B) {
+	buff := []byte("-07:00")
 	l := len(buff)
 
-	expectedH := 12
-	expectedM := 34
+	for i := 0; i < b.N; i++ {
+		c := parsercommon.NewCursor(buff, l)
+
+		_, err := parseNumericalTimeOffset(&c)
+		if err != nil {
+			panic(err)
+		}
+	}
+}
 
-	obtainedH, obtainedM, err := getHourMinute(
-		buff, &cursor, l,
+func BenchmarkParseHeader(b *testing.B) {
+	buff := []byte(
+		"<165>1 2003-10-11T22:14:15.003Z mymachine.example.com su 123 ID47 ",
 	)
 
-	require.Nil(t, err)
-	require.Equal(t, expectedH, obtainedH)
-	require.Equal(t, expectedM, obtainedM)
-	require.Equal(t, l, cursor)
+	p := NewParser(buff)
+
+	for i := 0; i < b.N; i++ {
+		_, err := p.parseHeader()
+		if err != nil {
+			panic(err)
+		}
+
+		p.cursor.SetPos(0)
+	}
 }
 
-func TestParsePartialTime(t *testing.T) {
-	buff := []byte("05:14:15.000003")
-	cursor := 0
-	l := len(buff)
+func BenchmarkParseFull(b *testing.B) {
+	msg := `<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 [exampleSDID@32473 iut="3" eventSource="Application" eventID="1011"] An application event log entry...`
 
-	obtained, err := parsePartialTime(
-		buff, &cursor, l,
-	)
+	for i := 0; i < b.N; i++ {
+		p := NewParser(
+			[]byte(msg),
+		)
 
-	expected := &partialTime{
-		hour:    5,
-		minute:  14,
-		seconds: 15,
-		secFrac: 0.000003,
+		err := p.Parse()
+		if err != nil {
+			panic(err)
+		}
 	}
+}
 
-	require.Nil(t, err)
-	require.Equal(t, expected, obtained)
-	require.Equal(t, l, cursor)
+func BenchmarkParseFullZeroCopy(b *testing.B) {
+	msg := `<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 [exampleSDID@32473 iut="3" eventSource="Application" eventID="1011"] An application event log entry...`
+
+	for i := 0; i < b.N; i++ {
+		p := NewParser(
+			[]byte(msg),
+		)
+
+		p.WithZeroCopy()
+
+		err := p.Parse()
+		if err != nil {
+			panic(err)
+		}
+	}
 }
 
-func TestParseFullTime(t *testing.T) {
-	tz := "-02:00"
-	buff := []byte("05:14:15.000003" + tz)
-	cursor := 0
-	l := len(buff)
+func TestParseWithJSON(t *testing.T) {
+	testCases := []struct {
+		description   string
+		input         string
+		maxDepth      int
+		maxSize       int
+		expectedJSON  interface{}
+		expectedError interface{}
+	}{
+		{
+			description: "json",
+			input:       `<13>1 2003-10-11T22:14:15.003Z mymachine myapp - - - {"log":"started","stream":"stdout"}`,
+			maxDepth:    0,
+			maxSize:     0,
+			expectedJSON: map[string]interface{}{
+				"log":    "started",
+				"stream": "stdout",
+			},
+			expectedError: nil,
+		},
+		{
+			description:   "not json",
+			input:         `<13>1 2003-10-11T22:14:15.003Z mymachine myapp - - - {started}`,
+			maxDepth:      0,
+			maxSize:       0,
+			expectedJSON:  nil,
+			expectedError: nil,
+		},
+		{
+			description:   "too deep",
+			input:         `<13>1 2003-10-11T22:14:15.003Z mymachine myapp - - - {"attrs":{"tag":"web"}}`,
+			maxDepth:      1,
+			maxSize:       0,
+			expectedJSON:  nil,
+			expectedError: parsercommon.ErrJSONTooDeep.Error(),
+		},
+		{
+			description:   "too large",
+			input:         `<13>1 2003-10-11T22:14:15.003Z mymachine myapp - - - {"log":"started"}`,
+			maxDepth:      0,
+			maxSize:       8,
+			expectedJSON:  nil,
+			expectedError: parsercommon.ErrJSONTooLarge.Error(),
+		},
+	}
 
-	tmpTs, err := time.Parse("-07:00", string(tz))
-	require.Nil(t, err)
+	for _, tc := range testCases {
+		p := NewParser([]byte(tc.input))
+		p.WithJSON(tc.maxDepth, tc.maxSize)
 
-	obtained, err := parseFullTime(
-		buff, &cursor, l,
-	)
+		err := p.Parse()
+		require.Nil(t, err, tc.description)
 
-	expected := &fullTime{
-		pt: &partialTime{
-			hour:    5,
-			minute:  14,
-			seconds: 15,
-			secFrac: 0.000003,
+		obtained := p.Dump()
+		require.Equal(t, tc.expectedJSON, obtained["json"], tc.description)
+		require.Equal(t, tc.expectedError, obtained["json_error"], tc.description)
+	}
+}
+
+func TestParseTrailer(t *testing.T) {
+	testCases := []struct {
+		description     string
+		input           string
+		expectedMessage string
+	}{
+		{
+			description:     "LF",
+			input:           "<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 - An application event\n",
+			expectedMessage: "An application event",
+		},
+		{
+			description:     "CRLF",
+			input:           "<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 - An application event\r\n",
+			expectedMessage: "An application event",
+		},
+		{
+			description:     "NUL",
+			input:           "<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 - An application event\x00",
+			expectedMessage: "An application event",
+		},
+		{
+			description:     "no message",
+			input:           "<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 -\r\n",
+			expectedMessage: "",
 		},
-		loc: tmpTs.Location(),
 	}
 
-	require.Nil(t, err)
-	require.Equal(t, expected, obtained)
-	require.Equal(t, 21, cursor)
+	for _, tc := range testCases {
+		p := NewParser([]byte(tc.input))
+
+		err := p.Parse()
+		require.Nil(t, err, tc.description)
+		require.Equal(t, tc.expectedMessage, p.Dump()["message"], tc.description)
+	}
+}
+
+func TestParseWithMaxPacketLen(t *testing.T) {
+	start := "<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 - "
+	msg := start + strings.Repeat("a", 8192)
+
+	testCases := []struct {
+		description string
+		maxLen      int
+		expectedLen int
+	}{
+		{
+			description: "raised",
+			maxLen:      65536,
+			expectedLen: 8192,
+		},
+		{
+			description: "lowered",
+			maxLen:      len(start) + 16,
+			expectedLen: 16,
+		},
+		{
+			description: "unlimited",
+			maxLen:      0,
+			expectedLen: 8192,
+		},
+	}
+
+	for _, tc := range testCases {
+		p := NewParser([]byte(msg))
+		p.WithMaxPacketLen(tc.maxLen)
+
+		err := p.Parse()
+		require.Nil(t, err, tc.description)
+		require.Len(t, p.Dump()["message"], tc.expectedLen, tc.description)
+	}
 }
 
-func TestToNSec(t *testing.T) {
-	testCases := map[float64]int{
-		0.52:     520000000,
-		0.003:    3000000,
-		0.000003: 3000,
+func TestParseWithLenient(t *testing.T) {
+	inputs := []string{
+		`<999>1 2003-10-11T22:14:15.003Z mymachine evntslog - ID47 - hello`,
+		`1 2003-10-11T22:14:15.003Z mymachine evntslog - ID47 - hello`,
+		`<165>1 2003-10-11T22:14:15.003Z mymachine ` + strings.Repeat("a", 49) + ` 42 ID47 - hello`,
+		`<165>1 2003-10-11T22:14:15.003Z ` + strings.Repeat("a", MAX_HOSTNAME_LEN+1) + ` evntslog - ID47 - hello`,
 	}
 
-	for src, expected := range testCases {
-		obtained, err := toNSec(src)
-		require.Nil(t, err)
-		require.Equal(t, expected, obtained)
+	for _, input := range inputs {
+		p := NewParser([]byte(input))
+		require.NotNil(t, p.Parse(), input)
+
+		p = NewParser([]byte(input))
+		p.WithLenient()
+		require.Nil(t, p.Parse(), input)
+		require.Equal(t, "hello", p.Dump()["message"], input)
+
+		p = NewParser([]byte(input))
+		p.WithLenient()
+		p.WithStrict()
+		require.NotNil(t, p.Parse(), input)
 	}
 }
 
-func TestParseAppName(t *testing.T) {
+func TestParseWithSDValidation(t *testing.T) {
+	start := "<165>1 2003-10-11T22:14:15.003Z mymachine evntslog - ID47 "
+
 	testCases := []struct {
-		description       string
-		input             string
-		expectedAppName   string
-		expectedCursorPos int
-		expectedErr       error
+		description    string
+		sd             string
+		expectedErr    error
+		expectedOffset int
 	}{
 		{
-			description:       "valid",
-			input:             "su ",
-			expectedAppName:   "su",
-			expectedCursorPos: 2,
-			expectedErr:       nil,
+			description: "valid",
+			sd:          `[exampleSDID@32473 iut="3"][examplePriority@32473 class="high"]`,
 		},
 		{
-			description:       "too long",
-			input:             "suuuuuuuuuuuuuuuuuuuuuuuuuuuuuuuuuuuuuuuuuuuuuuuu ",
-			expectedAppName:   "",
-			expectedCursorPos: 48,
-			expectedErr:       ErrInvalidAppName,
+			description: "nil",
+			sd:          "-",
+		},
+		{
+			description:    "duplicate SD-ID",
+			sd:             `[origin ip="10.0.0.1"][meta sequenceId="1"][origin ip="10.0.0.2"]`,
+			expectedErr:    ErrDuplicateSDID,
+			expectedOffset: len(start) + 43,
+		},
+		{
+			description:    "SD-ID with quote",
+			sd:             `[origin ip="10.0.0.1"][me"ta x="1"]`,
+			expectedErr:    ErrInvalidSDName,
+			expectedOffset: len(start) + 22,
+		},
+		{
+			description:    "PARAM-NAME with equal sign",
+			sd:             `[origin i=p="10.0.0.1"]`,
+			expectedErr:    ErrInvalidStructuredData,
+			expectedOffset: len(start),
+		},
+		{
+			description:    "PARAM-NAME too long",
+			sd:             `[origin ` + strings.Repeat("a", 33) + `="1"]`,
+			expectedErr:    ErrInvalidSDName,
+			expectedOffset: len(start),
 		},
 	}
 
 	for _, tc := range testCases {
-		p := NewParser([]byte(tc.input))
-		obtained, err := p.parseAppName()
+		input := start + tc.sd + " hello"
 
-		require.Equal(
-			t, tc.expectedErr, err, tc.description,
-		)
+		p := NewParser([]byte(input))
+		p.WithSDValidation()
 
-		require.Equal(
-			t, tc.expectedAppName, obtained, tc.description,
-		)
+		err := p.Parse()
+		if tc.expectedErr == nil {
+			require.Nil(t, err, tc.description)
+			require.Equal(t, tc.sd, p.Dump()["structured_data"], tc.description)
+			continue
+		}
 
-		require.Equal(
-			t, tc.expectedCursorPos, p.cursor, tc.description,
-		)
+		require.ErrorIs(t, err, tc.expectedErr, tc.description)
+
+		pe := err.(*parsercommon.ParserError)
+		require.Equal(t, "structured_data", pe.Field(), tc.description)
+		require.Equal(t, tc.expectedOffset, pe.Offset(), tc.description)
+
+		// without validation STRUCTURED-DATA is kept as is
+		p = NewParser([]byte(input))
+		require.Nil(t, p.Parse(), tc.description)
 	}
 }
 
-func TestParseProcID(t *testing.T) {
+func TestParseLeapSecond(t *testing.T) {
+	p := NewParser([]byte(`<165>1 2016-12-31T23:59:60.5Z mymachine evntslog - ID47 - hello`))
+
+	err := p.Parse()
+	require.Nil(t, err)
+	require.Equal(
+		t,
+		time.Date(2017, time.January, 1, 0, 0, 0, 500*1000*1000, time.UTC),
+		p.Dump()["timestamp"],
+	)
+}
+
+func TestParseWithCalendarValidation(t *testing.T) {
 	testCases := []struct {
-		description       string
-		input             string
-		expectedProcID    string
-		expectedCursorPos int
-		expectedErr       error
+		description string
+		date        string
+		expectedErr error
 	}{
 		{
-			description:       "valid",
-			input:             "123foo ",
-			expectedProcID:    "123foo",
-			expectedCursorPos: 6,
-			expectedErr:       nil,
+			description: "last day of month",
+			date:        "2003-04-30",
 		},
 		{
-			description:       "too long",
-			input:             strings.Repeat("a", 129),
-			expectedProcID:    "",
-			expectedCursorPos: 128,
-			expectedErr:       ErrInvalidProcId,
+			description: "leap year",
+			date:        "2004-02-29",
+		},
+		{
+			description: "leap year every 400 years",
+			date:        "2000-02-29",
+		},
+		{
+			description: "no leap year every 100 years",
+			date:        "1900-02-29",
+			expectedErr: ErrDayInvalid,
+		},
+		{
+			description: "no leap year",
+			date:        "2003-02-29",
+			expectedErr: ErrDayInvalid,
+		},
+		{
+			description: "Feb 31",
+			date:        "2003-02-31",
+			expectedErr: ErrDayInvalid,
+		},
+		{
+			description: "30 days month",
+			date:        "2003-11-31",
+			expectedErr: ErrDayInvalid,
 		},
 	}
 
+	start := "<165>1 "
+
 	for _, tc := range testCases {
-		p := NewParser([]byte(tc.input))
-		obtained, err := p.parseProcId()
+		input := start + tc.date + "T22:14:15.003Z mymachine evntslog - ID47 - hello"
 
-		require.Equal(
-			t, tc.expectedErr, err, tc.description,
-		)
+		p := NewParser([]byte(input))
+		p.WithCalendarValidation()
 
-		require.Equal(
-			t, tc.expectedProcID, obtained, tc.description,
-		)
+		err := p.Parse()
+		if tc.expectedErr == nil {
+			require.Nil(t, err, tc.description)
+			continue
+		}
 
-		require.Equal(
-			t, tc.expectedCursorPos, p.cursor, tc.description,
-		)
+		require.ErrorIs(t, err, tc.expectedErr, tc.description)
+
+		pe := err.(*parsercommon.ParserError)
+		require.Equal(t, "timestamp", pe.Field(), tc.description)
+		require.Equal(t, len(start)+len("2006-01-"), pe.Offset(), tc.description)
+
+		// without validation the date is normalized by time.Date()
+		p = NewParser([]byte(input))
+		require.Nil(t, p.Parse(), tc.description)
 	}
 }
 
-func TestParseMsgID(t *testing.T) {
+func TestParseWithPrintUSASCII(t *testing.T) {
 	testCases := []struct {
-		description       string
-		input             string
-		expectedMsgID     string
-		expectedCursorPos int
-		expectedErr       error
+		description    string
+		input          string
+		expectedField  string
+		expectedErr    error
+		expectedOffset int
 	}{
 		{
-			description:       "valid",
-			input:             "123foo ",
-			expectedMsgID:     "123foo",
-			expectedCursorPos: 6,
-			expectedErr:       nil,
+			description: "valid",
+			input:       `<165>1 2003-10-11T22:14:15.003Z mymachine evntslog 42 ID47 - hello`,
 		},
 		{
-			description:       "too long",
-			input:             strings.Repeat("a", 33),
-			expectedMsgID:     "",
-			expectedCursorPos: 32,
-			expectedErr:       ErrInvalidMsgId,
+			description:    "hostname with UTF-8",
+			input:          "<165>1 2003-10-11T22:14:15.003Z m\xC3\xA9chine evntslog - ID47 - hello",
+			expectedField:  "hostname",
+			expectedErr:    ErrInvalidHostname,
+			expectedOffset: 33,
+		},
+		{
+			description:    "app name with NUL",
+			input:          "<165>1 2003-10-11T22:14:15.003Z mymachine evnt\x00slog - ID47 - hello",
+			expectedField:  "app_name",
+			expectedErr:    ErrInvalidAppName,
+			expectedOffset: 46,
+		},
+		{
+			description:    "proc id with UTF-8",
+			input:          "<165>1 2003-10-11T22:14:15.003Z mymachine evntslog 4\xC3\xA92 ID47 - hello",
+			expectedField:  "proc_id",
+			expectedErr:    ErrInvalidProcId,
+			expectedOffset: 52,
+		},
+		{
+			description:    "msg id with DEL",
+			input:          "<165>1 2003-10-11T22:14:15.003Z mymachine evntslog - ID\x7F47 - hello",
+			expectedField:  "msg_id",
+			expectedErr:    ErrInvalidMsgId,
+			expectedOffset: 55,
 		},
 	}
 
 	for _, tc := range testCases {
 		p := NewParser([]byte(tc.input))
-		obtained, err := p.parseMsgId()
+		p.WithPrintUSASCII()
 
-		require.Equal(
-			t, tc.expectedErr, err, tc.description,
-		)
+		err := p.Parse()
+		if tc.expectedErr == nil {
+			require.Nil(t, err, tc.description)
+			continue
+		}
 
-		require.Equal(
-			t, tc.expectedMsgID, obtained, tc.description,
-		)
+		require.ErrorIs(t, err, tc.expectedErr, tc.description)
 
-		require.Equal(
-			t, tc.expectedCursorPos, p.cursor, tc.description,
-		)
+		pe := err.(*parsercommon.ParserError)
+		require.Equal(t, tc.expectedField, pe.Field(), tc.description)
+		require.Equal(t, tc.expectedOffset, pe.Offset(), tc.description)
+
+		// without it header fields are kept as is
+		p = NewParser([]byte(tc.input))
+		require.Nil(t, p.Parse(), tc.description)
 	}
 }
 
-func TestParseStructuredData(t *testing.T) {
+func TestParseWithHostnameValidation(t *testing.T) {
 	testCases := []struct {
-		description       string
-		input             string
-		expectedData      string
-		expectedCursorPos int
-		expectedErr       error
+		description string
+		hostname    string
+		expectedErr error
 	}{
 		{
-			description:       "nil",
-			input:             "-",
-			expectedData:      "-",
-			expectedCursorPos: 1,
-			expectedErr:       nil,
+			description: "FQDN",
+			hostname:    "mymachine.example.com",
 		},
 		{
-			description:       "single",
-			input:             `[exampleSDID@32473 iut="3" eventSource="Application"eventID="1011"]`,
-			expectedData:      `[exampleSDID@32473 iut="3" eventSource="Application"eventID="1011"]`,
-			expectedCursorPos: 67,
-			expectedErr:       nil,
+			description: "IPv6",
+			hostname:    "2001:db8::1",
 		},
 		{
-			description:       "multiple",
-			input:             `[exampleSDID@32473 iut="3" eventSource="Application"eventID="1011"][examplePriority@32473 class="high"]`,
-			expectedData:      `[exampleSDID@32473 iut="3" eventSource="Application"eventID="1011"][examplePriority@32473 class="high"]`,
-			expectedCursorPos: 103,
-			expectedErr:       nil,
+			description: "NILVALUE",
+			hostname:    "-",
 		},
 		{
-			description:       "multiple invalid",
-			input:             `[exampleSDID@32473 iut="3" eventSource="Application"eventID="1011"] [examplePriority@32473 class="high"]`,
-			expectedData:      `[exampleSDID@32473 iut="3" eventSource="Application"eventID="1011"]`,
-			expectedCursorPos: 67,
-			expectedErr:       nil,
+			description: "colon",
+			hostname:    "mymachine:",
+			expectedErr: ErrInvalidHostname,
+		},
+		{
+			description: "control character",
+			hostname:    "my\x01machine",
+			expectedErr: ErrInvalidHostname,
+		},
+		{
+			description: "underscore",
+			hostname:    "my_machine",
+			expectedErr: ErrInvalidHostname,
 		},
 	}
 
 	for _, tc := range testCases {
-		cursor := 0
-		obtained, err := parseStructuredData(
-			[]byte(tc.input),
-			&cursor,
-			len(tc.input),
-		)
+		input := "<165>1 2003-10-11T22:14:15.003Z " + tc.hostname + " evntslog - ID47 - hello"
 
-		require.Equal(
-			t, tc.expectedErr, err, tc.description,
-		)
+		p := NewParser([]byte(input))
+		p.WithHostnameValidation()
 
-		require.Equal(
-			t, tc.expectedData, obtained, tc.description,
-		)
+		err := p.Parse()
+		if tc.expectedErr == nil {
+			require.Nil(t, err, tc.description)
+			continue
+		}
 
-		require.Equal(
-			t, tc.expectedCursorPos, cursor, tc.description,
-		)
+		require.ErrorIs(t, err, tc.expectedErr, tc.description)
+		require.Equal(t, "hostname", err.(*parsercommon.ParserError).Field(), tc.description)
+
+		// without validation any word is accepted
+		p = NewParser([]byte(input))
+		require.Nil(t, p.Parse(), tc.description)
 	}
 }
 
-func TestParseMessageSizeChecks(t *testing.T) {
-	start := `<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 [exampleSDID@32473 iut="3" eventSource="Application" eventID="1011"] `
-	msg := start + strings.Repeat("a", MAX_PACKET_LEN)
+func TestParseIPv6Hostname(t *testing.T) {
+	for _, hostname := range []string{"2001:db8::1", "[2001:db8::1]", "fe80::1%eth0"} {
+		p := NewParser(
+			[]byte("<165>1 2003-10-11T22:14:15.003Z " + hostname + " evntslog - ID47 - hello"),
+		)
+		p.WithHostnameType()
+		p.WithHostnameValidation()
 
-	p := NewParser([]byte(msg))
-	err := p.Parse()
-	fields := p.Dump()
+		err := p.Parse()
+		require.Nil(t, err, hostname)
 
-	require.Nil(
-		t, err,
-	)
+		obtained := p.Dump()
+		require.Equal(t, hostname, obtained["hostname"], hostname)
+		require.Equal(t, "evntslog", obtained["app_name"], hostname)
+		require.Equal(t, "hello", obtained["message"], hostname)
+		require.Equal(t, parsercommon.HOSTNAME_TYPE_IP6, obtained["hostname_type"], hostname)
+	}
+}
 
-	require.Len(
-		t,
-		fields["message"],
-		MAX_PACKET_LEN-len(start),
-	)
+func TestParseWithHostnameType(t *testing.T) {
+	testCases := []struct {
+		hostname     string
+		expectedType string
+	}{
+		{"192.0.2.1", parsercommon.HOSTNAME_TYPE_IP4},
+		{"2001:db8::1", parsercommon.HOSTNAME_TYPE_IP6},
+		{"[2001:db8::1]", parsercommon.HOSTNAME_TYPE_IP6},
+		{"mymachine.example.com", parsercommon.HOSTNAME_TYPE_FQDN},
+		{"mymachine", parsercommon.HOSTNAME_TYPE_NAME},
+	}
 
-	// ---
+	for _, tc := range testCases {
+		p := NewParser(
+			[]byte("<165>1 2003-10-11T22:14:15.003Z " + tc.hostname + " evntslog - ID47 - hello"),
+		)
+		p.WithHostnameType()
 
-	msg = start + " hello "
-	p = NewParser([]byte(msg))
-	err = p.Parse()
-	fields = p.Dump()
+		err := p.Parse()
+		require.Nil(t, err, tc.hostname)
+		require.Equal(t, tc.expectedType, p.Dump()["hostname_type"], tc.hostname)
+	}
 
-	require.Nil(t, err)
-	require.Equal(t, "hello", fields["message"])
-}
+	p := NewParser([]byte("<165>1 - - - - - -"))
+	p.WithHostnameType()
 
-func BenchmarkParseTimestamp(b *testing.B) {
-	buff := []byte("2003-08-24T05:14:15.000003-07:00")
+	require.Nil(t, p.Parse())
+	require.NotContains(t, p.Dump(), "hostname_type")
+}
 
-	p := NewParser(buff)
+func TestParseLongHostname(t *testing.T) {
+	start := "<165>1 2003-10-11T22:14:15.003Z "
+	input := start + strings.Repeat("a", MAX_HOSTNAME_LEN+1) + " evntslog - ID47 - hello"
 
-	for i := 0; i < b.N; i++ {
-		_, err := p.parseTimestamp()
-		if err != nil {
-			panic(err)
-		}
+	p := NewParser([]byte(input))
+	p.WithMaxPacketLen(0)
 
-		p.cursor = 0
-	}
-}
+	err := p.Parse()
+	require.ErrorIs(t, err, ErrInvalidHostname)
 
-func BenchmarkParseHeader(b *testing.B) {
-	buff := []byte(
-		"<165>1 2003-10-11T22:14:15.003Z mymachine.example.com su 123 ID47 ",
-	)
+	pe := err.(*parsercommon.ParserError)
+	require.Equal(t, "hostname", pe.Field())
+	require.Equal(t, len(start), pe.Offset())
 
-	p := NewParser(buff)
+	p = NewParser([]byte(input))
+	p.WithMaxPacketLen(0)
+	p.WithLongHostname()
 
-	for i := 0; i < b.N; i++ {
-		_, err := p.parseHeader()
-		if err != nil {
-			panic(err)
-		}
+	require.Nil(t, p.Parse())
+	require.Equal(t, strings.Repeat("a", MAX_HOSTNAME_LEN+1), p.Dump()["hostname"])
+	require.Equal(t, "evntslog", p.Dump()["app_name"])
 
-		p.cursor = 0
-	}
+	p = NewParser([]byte(start + strings.Repeat("a", MAX_HOSTNAME_LEN) + " evntslog - ID47 - hello"))
+	require.Nil(t, p.Parse())
 }
 
-func BenchmarkParseFull(b *testing.B) {
-	msg := `<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 [exampleSDID@32473 iut="3" eventSource="Application" eventID="1011"] An application event log entry...`
+func TestParseWithProgramSplit(t *testing.T) {
+	p := NewParser([]byte("<22>1 2003-10-11T22:14:15.003Z mail postfix/smtpd 1234 - - connect from unknown[192.0.2.1]"))
+	p.WithProgramSplit()
 
-	for i := 0; i < b.N; i++ {
-		p := NewParser(
-			[]byte(msg),
-		)
+	err := p.Parse()
+	require.Nil(t, err)
 
-		err := p.Parse()
-		if err != nil {
-			panic(err)
-		}
-	}
+	obtained := p.Dump()
+	require.Equal(t, "postfix/smtpd", obtained["app_name"])
+	require.Equal(t, "postfix", obtained["program"])
+	require.Equal(t, "smtpd", obtained["subprogram"])
+
+	p = NewParser([]byte("<22>1 2003-10-11T22:14:15.003Z mail sshd 1234 - - started"))
+	p.WithProgramSplit()
+
+	err = p.Parse()
+	require.Nil(t, err)
+	require.NotContains(t, p.Dump(), "program")
 }
@@ -0,0 +1,138 @@
+package rfc5424
+
+import (
+	"strings"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+)
+
+// Decodes STRUCTURED-DATA, as returned by Dump() under "structured_data",
+// into its elements, the reverse of FormatStructuredData(). Escaped '"', '\'
+// and ']' in parameter values are unescaped. NILVALUE gives no element.
+func ParseStructuredData(sd string) ([]SDElement, error) {
+	if sd == "" || parsercommon.IsNilString(sd) {
+		return nil, nil
+	}
+
+	var elements []SDElement
+
+	for len(sd) > 0 {
+		e, rest, err := parseSDElement(sd)
+		if err != nil {
+			return nil, err
+		}
+
+		elements = append(elements, e)
+		sd = rest
+	}
+
+	return elements, nil
+}
+
+// Checks the grammar of STRUCTURED-DATA, SD-NAMEs included, and that no SD-ID
+// is used twice. Returns the offset in sd of the invalid element.
+func validateStructuredData(sd string) (int, error) {
+	if sd == "" || parsercommon.IsNilString(sd) {
+		return 0, nil
+	}
+
+	seen := map[string]bool{}
+
+	for off := 0; off < len(sd); {
+		e, rest, err := parseSDElement(sd[off:])
+		if err != nil {
+			return off, err
+		}
+
+		if seen[e.ID] {
+			return off, ErrDuplicateSDID
+		}
+
+		seen[e.ID] = true
+		off = len(sd) - len(rest)
+	}
+
+	return 0, nil
+}
+
+// SD-ELEMENT = "[" SD-ID *(SP SD-PARAM) "]", returns the element and what
+// follows it
+func parseSDElement(s string) (SDElement, string, error) {
+	var e SDElement
+
+	if len(s) == 0 || s[0] != '[' {
+		return e, "", ErrInvalidStructuredData
+	}
+
+	s = s[1:]
+
+	end := strings.IndexAny(s, " ]")
+	if end < 0 {
+		return e, "", ErrInvalidStructuredData
+	}
+
+	e.ID = s[:end]
+	if !isSDName(e.ID) {
+		return e, "", ErrInvalidSDName
+	}
+
+	s = s[end:]
+
+	for len(s) > 0 && s[0] == ' ' {
+		p, rest, err := parseSDParam(s[1:])
+		if err != nil {
+			return e, "", err
+		}
+
+		e.Params = append(e.Params, p)
+		s = rest
+	}
+
+	if len(s) == 0 || s[0] != ']' {
+		return e, "", ErrInvalidStructuredData
+	}
+
+	return e, s[1:], nil
+}
+
+// SD-PARAM = PARAM-NAME "=" %d34 PARAM-VALUE %d34
+func parseSDParam(s string) (SDParam, string, error) {
+	var p SDParam
+
+	eq := strings.IndexByte(s, '=')
+	if eq < 0 {
+		return p, "", ErrInvalidStructuredData
+	}
+
+	p.Name = s[:eq]
+	if !isSDName(p.Name) {
+		return p, "", ErrInvalidSDName
+	}
+
+	s = s[eq+1:]
+	if len(s) == 0 || s[0] != '"' {
+		return p, "", ErrInvalidStructuredData
+	}
+
+	var b strings.Builder
+
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '\\':
+			// XXX : only '"', '\' and ']' are escaped, other backslashes are kept
+			if i+1 < len(s) && (s[i+1] == '"' || s[i+1] == '\\' || s[i+1] == ']') {
+				i++
+				c = s[i]
+			}
+
+			b.WriteByte(c)
+		case '"':
+			p.Value = b.String()
+			return p, s[i+1:], nil
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return p, "", ErrInvalidStructuredData
+}
@@ -0,0 +1,136 @@
+package rfc5424
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStructuredDataElements(t *testing.T) {
+	testCases := []struct {
+		description      string
+		input            string
+		expectedElements []SDElement
+	}{
+		{
+			description:      "nil value",
+			input:            "-",
+			expectedElements: nil,
+		},
+		{
+			description: "one element",
+			input:       `[exampleSDID@32473 iut="3" eventSource="Application" eventID="1011"]`,
+			expectedElements: []SDElement{
+				{
+					ID: "exampleSDID@32473",
+					Params: []SDParam{
+						{Name: "iut", Value: "3"},
+						{Name: "eventSource", Value: "Application"},
+						{Name: "eventID", Value: "1011"},
+					},
+				},
+			},
+		},
+		{
+			description: "several elements, no param",
+			input:       `[exampleSDID@32473 iut="3"][examplePriority@32473 class="high"][origin]`,
+			expectedElements: []SDElement{
+				{ID: "exampleSDID@32473", Params: []SDParam{{Name: "iut", Value: "3"}}},
+				{ID: "examplePriority@32473", Params: []SDParam{{Name: "class", Value: "high"}}},
+				{ID: "origin"},
+			},
+		},
+		{
+			description: "escapes",
+			input:       `[test@32473 v="a \"quoted\" \] \\ C:\temp"]`,
+			expectedElements: []SDElement{
+				{ID: "test@32473", Params: []SDParam{{Name: "v", Value: `a "quoted" ] \ C:\temp`}}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		obtained, err := ParseStructuredData(tc.input)
+		require.Nil(t, err, tc.description)
+		require.Equal(t, tc.expectedElements, obtained, tc.description)
+	}
+}
+
+func TestParseStructuredDataErrors(t *testing.T) {
+	testCases := []struct {
+		description string
+		input       string
+		expectedErr error
+	}{
+		{
+			description: "no bracket",
+			input:       `exampleSDID@32473 iut="3"`,
+			expectedErr: ErrInvalidStructuredData,
+		},
+		{
+			description: "unterminated element",
+			input:       `[exampleSDID@32473 iut="3"`,
+			expectedErr: ErrInvalidStructuredData,
+		},
+		{
+			description: "unquoted value",
+			input:       `[exampleSDID@32473 iut=3]`,
+			expectedErr: ErrInvalidStructuredData,
+		},
+		{
+			description: "unterminated value",
+			input:       `[exampleSDID@32473 iut="3]`,
+			expectedErr: ErrInvalidStructuredData,
+		},
+		{
+			description: "invalid id",
+			input:       `[ iut="3"]`,
+			expectedErr: ErrInvalidSDName,
+		},
+		{
+			description: "garbage between elements",
+			input:       `[a@1 b="c"] [d@1]`,
+			expectedErr: ErrInvalidStructuredData,
+		},
+	}
+
+	for _, tc := range testCases {
+		_, err := ParseStructuredData(tc.input)
+		require.ErrorIs(t, err, tc.expectedErr, tc.description)
+	}
+}
+
+func TestParseStructuredDataRoundTrip(t *testing.T) {
+	elements := []SDElement{
+		{ID: "test@32473", Params: []SDParam{{Name: "v", Value: `a "b" ] \`}}},
+		{ID: "origin", Params: []SDParam{{Name: "ip", Value: "10.0.0.1"}}},
+	}
+
+	sd, err := FormatStructuredData(elements)
+	require.Nil(t, err)
+
+	obtained, err := ParseStructuredData(sd)
+	require.Nil(t, err)
+	require.Equal(t, elements, obtained)
+}
+
+func TestParseStructuredDataThroughParser(t *testing.T) {
+	elements := []SDElement{
+		{ID: "sd@1", Params: []SDParam{{Name: "msg", Value: "a ] b"}}},
+		{ID: "sd@2", Params: []SDParam{{Name: "v", Value: `c "] d`}}},
+	}
+
+	sd, err := FormatStructuredData(elements)
+	require.Nil(t, err)
+
+	p := NewParser([]byte("<165>1 2003-10-11T22:14:15.003Z mymachine evntslog - ID47 " + sd + " hello"))
+	require.Nil(t, p.Parse())
+
+	parts := p.Dump()
+	require.Equal(t, sd, parts["structured_data"])
+	require.Equal(t, "hello", parts["message"])
+
+	obtained, err := ParseStructuredData(sd)
+	require.Nil(t, err)
+	require.Equal(t, elements, obtained)
+}
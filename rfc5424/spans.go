@@ -0,0 +1,53 @@
+package rfc5424
+
+import (
+	"github.com/jeromer/syslogparser/parsercommon"
+)
+
+// fields whose span is recorded, named as in Dump()
+var spanFields = [...]string{
+	"priority",
+	"version",
+	"timestamp",
+	"hostname",
+	"app_name",
+	"proc_id",
+	"msg_id",
+	"structured_data",
+	"message",
+}
+
+// Offsets of MSG in the parsed buffer, surrounding spaces excluded, so it can
+// be forwarded without being copied. The boot offset stripped by
+// WithKernelOffset() is part of it.
+func (p *Parser) MessageSpan() parsercommon.Span {
+	return p.spans[len(spanFields)-1]
+}
+
+// Offsets in the parsed buffer of the header fields, STRUCTURED-DATA and MSG,
+// keyed as in Dump(). NILVALUEs are part of the spans, fields set with
+// WithHostname() or WithPriority() have empty ones.
+func (p *Parser) FieldSpans() map[string]parsercommon.Span {
+	spans := make(map[string]parsercommon.Span, len(spanFields))
+
+	for i, f := range spanFields {
+		spans[f] = p.spans[i]
+	}
+
+	return spans
+}
+
+// Records the span of the field being parsed, from its beginning to the
+// cursor
+func (p *Parser) endSpan() {
+	p.setSpan(p.field, p.fieldPos, p.cursor.Pos())
+}
+
+func (p *Parser) setSpan(field string, start int, end int) {
+	for i, f := range spanFields {
+		if f == field {
+			p.spans[i] = parsercommon.Span{Start: start, End: end}
+			return
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package rfc5424
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldSpans(t *testing.T) {
+	testCases := []struct {
+		description string
+		input       string
+		longAppName bool
+		expected    map[string]string
+	}{
+		{
+			description: "all fields",
+			input:       `<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog 42 ID47 [exampleSDID@32473 iut="3"] An application event log entry...`,
+			expected: map[string]string{
+				"priority":        "<165>",
+				"version":         "1",
+				"timestamp":       "2003-10-11T22:14:15.003Z",
+				"hostname":        "mymachine.example.com",
+				"app_name":        "evntslog",
+				"proc_id":         "42",
+				"msg_id":          "ID47",
+				"structured_data": `[exampleSDID@32473 iut="3"]`,
+				"message":         "An application event log entry...",
+			},
+		},
+		{
+			description: "nil values",
+			input:       `<165>1 - - - - - -`,
+			expected: map[string]string{
+				"priority":        "<165>",
+				"version":         "1",
+				"timestamp":       "-",
+				"hostname":        "-",
+				"app_name":        "-",
+				"proc_id":         "-",
+				"msg_id":          "-",
+				"structured_data": "-",
+				"message":         "",
+			},
+		},
+		{
+			description: "message surrounded by spaces",
+			input:       "<165>1 2003-10-11T22:14:15.003Z mymachine evntslog - ID47 -   hello  ",
+			expected: map[string]string{
+				"priority":        "<165>",
+				"version":         "1",
+				"timestamp":       "2003-10-11T22:14:15.003Z",
+				"hostname":        "mymachine",
+				"app_name":        "evntslog",
+				"proc_id":         "-",
+				"msg_id":          "ID47",
+				"structured_data": "-",
+				"message":         "hello",
+			},
+		},
+		{
+			description: "recovered long app name",
+			input:       `<165>1 2003-10-11T22:14:15.003Z mymachine ` + strings.Repeat("a", 49) + ` 42 ID47 - hello`,
+			longAppName: true,
+			expected: map[string]string{
+				"priority":        "<165>",
+				"version":         "1",
+				"timestamp":       "2003-10-11T22:14:15.003Z",
+				"hostname":        "mymachine",
+				"app_name":        strings.Repeat("a", 49),
+				"proc_id":         "42",
+				"msg_id":          "ID47",
+				"structured_data": "-",
+				"message":         "hello",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		buff := []byte(tc.input)
+
+		p := NewParser(buff)
+		if tc.longAppName {
+			p.WithLongAppName()
+		}
+
+		require.Nil(t, p.Parse(), tc.description)
+
+		obtained := make(map[string]string)
+		for k, s := range p.FieldSpans() {
+			obtained[k] = string(s.Bytes(buff))
+		}
+
+		require.Equal(t, tc.expected, obtained, tc.description)
+		require.Equal(
+			t, tc.expected["message"], string(p.MessageSpan().Bytes(buff)), tc.description,
+		)
+	}
+}
+
+func TestFieldSpansForced(t *testing.T) {
+	buff := []byte(`1 2003-10-11T22:14:15.003Z mymachine evntslog - ID47 - hello`)
+
+	p := NewParser(buff)
+	p.WithDefaultPriority()
+
+	require.Nil(t, p.Parse())
+	require.Equal(t, 0, p.FieldSpans()["priority"].Len())
+	require.Equal(t, "1", string(p.FieldSpans()["version"].Bytes(buff)))
+}
@@ -0,0 +1,104 @@
+package rfc5424
+
+import (
+	"bytes"
+	"unicode/utf8"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+)
+
+const (
+	// https://tools.ietf.org/html/rfc5424#section-6.1
+	// receivers SHOULD accept messages up to 2048 bytes, larger ones only
+	// by agreement
+	STRICT_MAX_PACKET_LEN = 2048
+
+	// https://tools.ietf.org/html/rfc5424#section-6.2.2
+	STRICT_VERSION = 1
+
+	// "Leap seconds MUST NOT be used"
+	// https://tools.ietf.org/html/rfc5424#section-6.2.3
+	LEAP_SECOND = 60
+)
+
+var (
+	ErrMessageTooLong = &parsercommon.ParserError{ErrorString: "Message too long"}
+)
+
+// UTF-8 BOM a MSG-UTF8 starts with
+var bom = []byte{0xEF, 0xBB, 0xBF}
+
+// Enforces the grammar of RFC 5424 instead of tolerating common deviations,
+// ie. to validate emitters or certify devices:
+//   - messages are at most STRICT_MAX_PACKET_LEN bytes long
+//   - VERSION is 1, see WithVersionPolicy()
+//   - timestamps have no leap second, use '.' before TIME-SECFRAC and their
+//     day exists, see WithCalendarValidation()
+//   - HOSTNAME, APP-NAME, PROCID and MSGID only hold PRINTUSASCII, see
+//     WithPrintUSASCII()
+//   - STRUCTURED-DATA is valid, see WithSDValidation()
+//   - messages starting with a BOM are valid UTF-8
+//
+// WithLenientPriority(), WithDefaultPriority(), WithLongAppName() and
+// WithLongHostname() are ignored.
+func (p *Parser) WithStrict() {
+	p.strict = true
+}
+
+func (p *Parser) checkLength() error {
+	if p.cursor.Len() > STRICT_MAX_PACKET_LEN || p.cursor.Truncated() {
+		return ErrMessageTooLong
+	}
+
+	return nil
+}
+
+// VERSION = NONZERO-DIGIT 0*2DIGIT, followed by SP. Versions other than
+// STRICT_VERSION are rejected by checkVersionPolicy().
+func (p *Parser) checkVersion(ver int) error {
+	if next, _ := p.cursor.Peek(); next != ' ' {
+		return ErrInvalidVersion
+	}
+
+	return nil
+}
+
+func (p *Parser) checkHostname(h []byte) error {
+	if len(h) == 0 || len(h) > MAX_HOSTNAME_LEN {
+		return ErrInvalidHostname
+	}
+
+	return p.checkPrintUSASCII(h, ErrInvalidHostname)
+}
+
+// The error is located at the first byte of b which is not PRINTUSASCII
+func (p *Parser) checkPrintUSASCII(b []byte, e *parsercommon.ParserError) error {
+	for i, c := range b {
+		if !isPrintUSASCII(c) {
+			p.fieldPos += i
+			return e
+		}
+	}
+
+	return nil
+}
+
+// sd is the whole STRUCTURED-DATA, NILVALUE included. The error is located
+// at the invalid element.
+func (p *Parser) checkStructuredData(sd []byte) error {
+	off, err := validateStructuredData(string(sd))
+	if err != nil {
+		p.fieldPos += off
+	}
+
+	return err
+}
+
+// MSG = MSG-ANY / MSG-UTF8
+func (p *Parser) checkMessage(msg []byte) error {
+	if bytes.HasPrefix(msg, bom) && !utf8.Valid(msg[len(bom):]) {
+		return ErrInvalidMessage
+	}
+
+	return nil
+}
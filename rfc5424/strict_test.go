@@ -0,0 +1,161 @@
+package rfc5424
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWithStrict(t *testing.T) {
+	testCases := []struct {
+		description   string
+		input         string
+		expectedField string
+		expectedErr   error
+	}{
+		{
+			description: "valid",
+			input:       `<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 [exampleSDID@32473 iut="3" eventSource="Application"] An application event log entry...`,
+		},
+		{
+			description: "valid nil values",
+			input:       `<165>1 - - - - - -`,
+		},
+		{
+			description: "valid UTF-8 message",
+			input:       "<165>1 2003-10-11T22:14:15.003Z mymachine evntslog - ID47 - \xEF\xBB\xBFcaf\xC3\xA9",
+		},
+		{
+			description:   "too long",
+			input:         `<165>1 2003-10-11T22:14:15.003Z mymachine evntslog - ID47 - ` + strings.Repeat("a", STRICT_MAX_PACKET_LEN),
+			expectedField: "",
+			expectedErr:   ErrMessageTooLong,
+		},
+		{
+			description:   "version 2",
+			input:         `<165>2 2003-10-11T22:14:15.003Z mymachine evntslog - ID47 - hello`,
+			expectedField: "version",
+			expectedErr:   ErrUnsupportedVersion,
+		},
+		{
+			description:   "version with 2 digits",
+			input:         `<165>12 2003-10-11T22:14:15.003Z mymachine evntslog - ID47 - hello`,
+			expectedField: "version",
+			expectedErr:   ErrUnsupportedVersion,
+		},
+		{
+			description:   "version not followed by SP",
+			input:         `<165>1- 2003-10-11T22:14:15.003Z mymachine evntslog - ID47 - hello`,
+			expectedField: "version",
+			expectedErr:   ErrInvalidVersion,
+		},
+		{
+			description:   "leap second",
+			input:         `<165>1 2016-12-31T23:59:60Z mymachine evntslog - ID47 - hello`,
+			expectedField: "timestamp",
+			expectedErr:   ErrSecondInvalid,
+		},
+		{
+			description:   "day not in month",
+			input:         `<165>1 2003-02-29T22:14:15.003Z mymachine evntslog - ID47 - hello`,
+			expectedField: "timestamp",
+			expectedErr:   ErrDayInvalid,
+		},
+		{
+			description:   "comma before secfrac",
+			input:         `<165>1 2003-10-11T22:14:15,003Z mymachine evntslog - ID47 - hello`,
+			expectedField: "timestamp",
+			expectedErr:   ErrSecFracInvalid,
+		},
+		{
+			description:   "hostname not PRINTUSASCII",
+			input:         "<165>1 2003-10-11T22:14:15.003Z m\xC3\xA9chine evntslog - ID47 - hello",
+			expectedField: "hostname",
+			expectedErr:   ErrInvalidHostname,
+		},
+		{
+			description:   "app name not PRINTUSASCII",
+			input:         "<165>1 2003-10-11T22:14:15.003Z mymachine evnt\x01slog - ID47 - hello",
+			expectedField: "app_name",
+			expectedErr:   ErrInvalidAppName,
+		},
+		{
+			description:   "proc id not PRINTUSASCII",
+			input:         "<165>1 2003-10-11T22:14:15.003Z mymachine evntslog \xC3\xA9 ID47 - hello",
+			expectedField: "proc_id",
+			expectedErr:   ErrInvalidProcId,
+		},
+		{
+			description:   "msg id not PRINTUSASCII",
+			input:         "<165>1 2003-10-11T22:14:15.003Z mymachine evntslog - ID\x7F47 - hello",
+			expectedField: "msg_id",
+			expectedErr:   ErrInvalidMsgId,
+		},
+		{
+			description:   "invalid SD-ID",
+			input:         `<165>1 2003-10-11T22:14:15.003Z mymachine evntslog - ID47 [exampleSDID@32473"x iut="3"] hello`,
+			expectedField: "structured_data",
+			expectedErr:   ErrInvalidSDName,
+		},
+		{
+			description:   "unquoted PARAM-VALUE",
+			input:         `<165>1 2003-10-11T22:14:15.003Z mymachine evntslog - ID47 [exampleSDID@32473 iut=3] hello`,
+			expectedField: "structured_data",
+			expectedErr:   ErrInvalidStructuredData,
+		},
+		{
+			description:   "invalid UTF-8 after BOM",
+			input:         "<165>1 2003-10-11T22:14:15.003Z mymachine evntslog - ID47 - \xEF\xBB\xBFcaf\xE9",
+			expectedField: "message",
+			expectedErr:   ErrInvalidMessage,
+		},
+		{
+			description:   "long app name not recovered",
+			input:         `<165>1 2003-10-11T22:14:15.003Z mymachine ` + strings.Repeat("a", 49) + ` - ID47 - hello`,
+			expectedField: "app_name",
+			expectedErr:   ErrInvalidAppName,
+		},
+		{
+			description:   "missing priority not defaulted",
+			input:         `1 2003-10-11T22:14:15.003Z mymachine evntslog - ID47 - hello`,
+			expectedField: "priority",
+			expectedErr:   parsercommon.ErrPriorityNoStart,
+		},
+	}
+
+	for _, tc := range testCases {
+		p := NewParser([]byte(tc.input))
+		p.WithLongAppName()
+		p.WithDefaultPriority()
+		p.WithStrict()
+
+		err := p.Parse()
+		if tc.expectedErr == nil {
+			require.Nil(t, err, tc.description)
+			continue
+		}
+
+		require.ErrorIs(t, err, tc.expectedErr, tc.description)
+		require.Equal(
+			t, tc.expectedField, err.(*parsercommon.ParserError).Field(), tc.description,
+		)
+	}
+}
+
+func TestParseWithoutStrict(t *testing.T) {
+	// tolerated deviations
+	inputs := []string{
+		`<165>2 2003-10-11T22:14:15.003Z mymachine evntslog - ID47 - hello`,
+		"<165>1 2003-10-11T22:14:15.003Z m\xC3\xA9chine evntslog - ID47 - hello",
+		`<165>1 2003-10-11T22:14:15.003Z mymachine evntslog - ID47 [exampleSDID@32473 iut=3] hello`,
+		"<165>1 2003-10-11T22:14:15.003Z mymachine evntslog - ID47 - \xEF\xBB\xBFcaf\xE9",
+		`<165>1 2003-10-11T22:14:15,003Z mymachine evntslog - ID47 - hello`,
+	}
+
+	for _, input := range inputs {
+		p := NewParser([]byte(input))
+		require.Nil(t, p.Parse(), input)
+	}
+}
@@ -0,0 +1,45 @@
+package rfc5424
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+)
+
+// Tells which VERSION values are accepted
+type VersionPolicy uint8
+
+const (
+	// any VERSION is accepted and reported as is, ie. 2 or 10
+	VERSION_ANY VersionPolicy = iota
+
+	// only VERSION 1, the one of RFC 5424, is accepted
+	VERSION_1_ONLY
+)
+
+var (
+	ErrUnsupportedVersion = &parsercommon.ParserError{ErrorString: "Unsupported version"}
+)
+
+// Sets which VERSION values are accepted, VERSION_ANY by default.
+// With VERSION_1_ONLY others are rejected with ErrUnsupportedVersion whose
+// Unwrap() returns the version found. WithStrict() implies VERSION_1_ONLY.
+func (p *Parser) WithVersionPolicy(vp VersionPolicy) {
+	p.versionPolicy = vp
+}
+
+func (p *Parser) checkVersionPolicy(ver int) error {
+	if ver == STRICT_VERSION {
+		return nil
+	}
+
+	if p.strict || p.versionPolicy == VERSION_1_ONLY {
+		return parsercommon.Wrap(
+			ErrUnsupportedVersion,
+			errors.New("version "+strconv.Itoa(ver)),
+		)
+	}
+
+	return nil
+}
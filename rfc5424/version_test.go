@@ -0,0 +1,83 @@
+package rfc5424
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWithVersionPolicy(t *testing.T) {
+	testCases := []struct {
+		description     string
+		version         string
+		policy          VersionPolicy
+		expectedVersion int
+		expectedErr     error
+	}{
+		{
+			description:     "any, version 1",
+			version:         "1",
+			policy:          VERSION_ANY,
+			expectedVersion: 1,
+		},
+		{
+			description:     "any, version 2",
+			version:         "2",
+			policy:          VERSION_ANY,
+			expectedVersion: 2,
+		},
+		{
+			description:     "any, version 10",
+			version:         "10",
+			policy:          VERSION_ANY,
+			expectedVersion: 10,
+		},
+		{
+			description:     "any, version 999",
+			version:         "999",
+			policy:          VERSION_ANY,
+			expectedVersion: 999,
+		},
+		{
+			description:     "1 only, version 1",
+			version:         "1",
+			policy:          VERSION_1_ONLY,
+			expectedVersion: 1,
+		},
+		{
+			description: "1 only, version 2",
+			version:     "2",
+			policy:      VERSION_1_ONLY,
+			expectedErr: ErrUnsupportedVersion,
+		},
+		{
+			description: "1 only, version 10",
+			version:     "10",
+			policy:      VERSION_1_ONLY,
+			expectedErr: ErrUnsupportedVersion,
+		},
+	}
+
+	for _, tc := range testCases {
+		p := NewParser(
+			[]byte("<165>" + tc.version + " 2003-10-11T22:14:15.003Z mymachine evntslog - ID47 - hello"),
+		)
+		p.WithVersionPolicy(tc.policy)
+
+		err := p.Parse()
+		if tc.expectedErr == nil {
+			require.Nil(t, err, tc.description)
+			require.Equal(t, tc.expectedVersion, p.Dump()["version"], tc.description)
+			require.Equal(t, "mymachine", p.Dump()["hostname"], tc.description)
+			continue
+		}
+
+		require.ErrorIs(t, err, tc.expectedErr, tc.description)
+
+		pe := err.(*parsercommon.ParserError)
+		require.Equal(t, "version", pe.Field(), tc.description)
+		require.Equal(t, "version "+tc.version, errors.Unwrap(err).Error(), tc.description)
+	}
+}
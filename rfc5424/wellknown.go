@@ -0,0 +1,181 @@
+package rfc5424
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+)
+
+// IANA registered SD-IDs
+// https://tools.ietf.org/html/rfc5424#section-7
+const (
+	SD_ID_TIME_QUALITY = "timeQuality"
+	SD_ID_ORIGIN       = "origin"
+	SD_ID_META         = "meta"
+)
+
+var (
+	ErrInvalidSDParamValue = &parsercommon.ParserError{ErrorString: "Invalid SD param value"}
+)
+
+// https://tools.ietf.org/html/rfc5424#section-7.1
+type TimeQuality struct {
+	TzKnown  bool
+	IsSynced bool
+
+	// microseconds, only meaningful when HasSyncAccuracy is set
+	SyncAccuracy    int
+	HasSyncAccuracy bool
+}
+
+// https://tools.ietf.org/html/rfc5424#section-7.2
+type Origin struct {
+	// "ip" may be given several times
+	IPs          []string
+	EnterpriseId string
+	Software     string
+	SwVersion    string
+}
+
+// https://tools.ietf.org/html/rfc5424#section-7.3
+type Meta struct {
+	// zero when not given, sequence IDs starting at 1
+	SequenceId int
+
+	// time since the sender booted, converted from hundredths of second
+	SysUpTime    time.Duration
+	HasSysUpTime bool
+
+	Language string
+}
+
+// Well known elements of a message, nil when not found
+type WellKnown struct {
+	TimeQuality *TimeQuality
+	Origin      *Origin
+	Meta        *Meta
+}
+
+// Decodes the timeQuality, origin and meta elements of STRUCTURED-DATA, as
+// returned by Dump() under "structured_data". Other elements are ignored,
+// unknown parameters of well known elements as well.
+func DecodeWellKnown(sd string) (WellKnown, error) {
+	var wk WellKnown
+
+	elements, err := ParseStructuredData(sd)
+	if err != nil {
+		return wk, err
+	}
+
+	for _, e := range elements {
+		switch e.ID {
+		case SD_ID_TIME_QUALITY:
+			wk.TimeQuality, err = decodeTimeQuality(e.Params)
+		case SD_ID_ORIGIN:
+			wk.Origin = decodeOrigin(e.Params)
+		case SD_ID_META:
+			wk.Meta, err = decodeMeta(e.Params)
+		}
+
+		if err != nil {
+			return WellKnown{}, err
+		}
+	}
+
+	return wk, nil
+}
+
+func decodeTimeQuality(params []SDParam) (*TimeQuality, error) {
+	tq := &TimeQuality{}
+
+	for _, p := range params {
+		var err error
+
+		switch p.Name {
+		case "tzKnown":
+			tq.TzKnown, err = parseSDFlag(p.Value)
+		case "isSynced":
+			tq.IsSynced, err = parseSDFlag(p.Value)
+		case "syncAccuracy":
+			tq.SyncAccuracy, err = parseSDUint(p.Value)
+			tq.HasSyncAccuracy = err == nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return tq, nil
+}
+
+func decodeOrigin(params []SDParam) *Origin {
+	o := &Origin{}
+
+	for _, p := range params {
+		switch p.Name {
+		case "ip":
+			o.IPs = append(o.IPs, p.Value)
+		case "enterpriseId":
+			o.EnterpriseId = p.Value
+		case "software":
+			o.Software = p.Value
+		case "swVersion":
+			o.SwVersion = p.Value
+		}
+	}
+
+	return o
+}
+
+func decodeMeta(params []SDParam) (*Meta, error) {
+	m := &Meta{}
+
+	for _, p := range params {
+		var err error
+		var n int
+
+		switch p.Name {
+		case "sequenceId":
+			m.SequenceId, err = parseSDUint(p.Value)
+		case "sysUpTime":
+			n, err = parseSDUint(p.Value)
+			m.SysUpTime = time.Duration(n) * 10 * time.Millisecond
+			m.HasSysUpTime = err == nil
+		case "language":
+			m.Language = p.Value
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+// "0" or "1"
+func parseSDFlag(s string) (bool, error) {
+	switch s {
+	case "0":
+		return false, nil
+	case "1":
+		return true, nil
+	}
+
+	return false, ErrInvalidSDParamValue
+}
+
+func parseSDUint(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, parsercommon.Wrap(ErrInvalidSDParamValue, err)
+	}
+
+	if n < 0 {
+		return 0, ErrInvalidSDParamValue
+	}
+
+	return n, nil
+}
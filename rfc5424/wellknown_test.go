@@ -0,0 +1,95 @@
+package rfc5424
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeWellKnown(t *testing.T) {
+	testCases := []struct {
+		description string
+		input       string
+		expected    WellKnown
+	}{
+		{
+			description: "nil",
+			input:       "-",
+			expected:    WellKnown{},
+		},
+		{
+			description: "all",
+			input:       `[timeQuality tzKnown="1" isSynced="0" syncAccuracy="60000"][origin ip="192.0.2.1" ip="2001:db8::1" enterpriseId="32473.1" software="evntslog" swVersion="1.2"][meta sequenceId="42" sysUpTime="1234" language="en"]`,
+			expected: WellKnown{
+				TimeQuality: &TimeQuality{
+					TzKnown:         true,
+					IsSynced:        false,
+					SyncAccuracy:    60000,
+					HasSyncAccuracy: true,
+				},
+				Origin: &Origin{
+					IPs:          []string{"192.0.2.1", "2001:db8::1"},
+					EnterpriseId: "32473.1",
+					Software:     "evntslog",
+					SwVersion:    "1.2",
+				},
+				Meta: &Meta{
+					SequenceId:   42,
+					SysUpTime:    12340 * time.Millisecond,
+					HasSysUpTime: true,
+					Language:     "en",
+				},
+			},
+		},
+		{
+			description: "partial and other elements",
+			input:       `[exampleSDID@32473 iut="3"][timeQuality isSynced="1"][meta language="fr"]`,
+			expected: WellKnown{
+				TimeQuality: &TimeQuality{IsSynced: true},
+				Meta:        &Meta{Language: "fr"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		obtained, err := DecodeWellKnown(tc.input)
+		require.Nil(t, err, tc.description)
+		require.Equal(t, tc.expected, obtained, tc.description)
+	}
+}
+
+func TestDecodeWellKnownErrors(t *testing.T) {
+	testCases := []struct {
+		description string
+		input       string
+		expectedErr error
+	}{
+		{
+			description: "invalid flag",
+			input:       `[timeQuality tzKnown="yes"]`,
+			expectedErr: ErrInvalidSDParamValue,
+		},
+		{
+			description: "invalid sync accuracy",
+			input:       `[timeQuality syncAccuracy="-1"]`,
+			expectedErr: ErrInvalidSDParamValue,
+		},
+		{
+			description: "invalid sequence ID",
+			input:       `[meta sequenceId="first"]`,
+			expectedErr: strconv.ErrSyntax,
+		},
+		{
+			description: "invalid structured data",
+			input:       `[meta sequenceId=1]`,
+			expectedErr: ErrInvalidStructuredData,
+		},
+	}
+
+	for _, tc := range testCases {
+		_, err := DecodeWellKnown(tc.input)
+		require.ErrorIs(t, err, tc.expectedErr, tc.description)
+	}
+}
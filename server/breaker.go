@@ -0,0 +1,174 @@
+package server
+
+import (
+	"errors"
+	"net"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// peers tracked by the circuit breaker before idle ones are forgotten
+	MAX_BREAKER_PEERS = 65536
+)
+
+var (
+	ErrPeerQuarantined = errors.New("Peer quarantined")
+)
+
+type QuarantinedPeer struct {
+	Host    string
+	Until   time.Time
+	Dropped uint64
+}
+
+// Counts errors per peer host over windows of a fixed number of messages
+type breaker struct {
+	threshold float64
+	window    int
+	duration  time.Duration
+	now       func() time.Time
+
+	mu    sync.Mutex
+	peers map[string]*peerStats
+}
+
+type peerStats struct {
+	messages int
+	errors   int
+	until    time.Time
+	dropped  uint64
+}
+
+// Quarantines a peer for d once at least threshold (between 0 and 1) of
+// the last window messages it sent could not be parsed. Messages sent by a
+// quarantined peer are counted but neither parsed nor given to the handler,
+// which only receives ErrPeerQuarantined when the quarantine starts.
+// Peers are identified by host, regardless of the port.
+func (s *Server) WithCircuitBreaker(threshold float64, window int, d time.Duration) {
+	s.breaker = &breaker{
+		threshold: threshold,
+		window:    window,
+		duration:  d,
+		now:       time.Now,
+		peers:     make(map[string]*peerStats),
+	}
+}
+
+// Returns the peers currently quarantined by the circuit breaker, sorted by
+// host
+func (s *Server) Quarantined() []QuarantinedPeer {
+	if s.breaker == nil {
+		return nil
+	}
+
+	return s.breaker.quarantined()
+}
+
+// Returns true, and counts the message, if host is quarantined
+func (b *breaker) drop(host string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ps, ok := b.peers[host]
+	if !ok || ps.until.IsZero() {
+		return false
+	}
+
+	if b.now().Before(ps.until) {
+		ps.dropped++
+		return true
+	}
+
+	// quarantine is over, start again from a clean state
+	*ps = peerStats{}
+
+	return false
+}
+
+// Records the outcome of a message, returns true if host has just been
+// quarantined
+func (b *breaker) record(host string, failed bool) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ps, ok := b.peers[host]
+	if !ok {
+		if len(b.peers) >= MAX_BREAKER_PEERS {
+			b.prune()
+		}
+
+		ps = &peerStats{}
+		b.peers[host] = ps
+	}
+
+	ps.messages++
+	if failed {
+		ps.errors++
+	}
+
+	if ps.messages < b.window {
+		return false
+	}
+
+	tripped := float64(ps.errors)/float64(ps.messages) >= b.threshold
+
+	ps.messages = 0
+	ps.errors = 0
+
+	if tripped {
+		ps.until = b.now().Add(b.duration)
+	}
+
+	return tripped
+}
+
+// Forgets peers which are not quarantined
+func (b *breaker) prune() {
+	now := b.now()
+
+	for host, ps := range b.peers {
+		if !now.Before(ps.until) {
+			delete(b.peers, host)
+		}
+	}
+}
+
+func (b *breaker) quarantined() []QuarantinedPeer {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+
+	var peers []QuarantinedPeer
+
+	for host, ps := range b.peers {
+		if now.Before(ps.until) {
+			peers = append(peers, QuarantinedPeer{
+				Host:    host,
+				Until:   ps.until,
+				Dropped: ps.dropped,
+			})
+		}
+	}
+
+	sort.Slice(peers, func(i, j int) bool {
+		return peers[i].Host < peers[j].Host
+	})
+
+	return peers
+}
+
+func peerHost(addr net.Addr) string {
+	switch a := addr.(type) {
+	case nil:
+		return ""
+	case *net.UDPAddr:
+		return a.IP.String()
+	case *net.TCPAddr:
+		return a.IP.String()
+	}
+
+	return addr.String()
+}
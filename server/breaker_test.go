@@ -0,0 +1,124 @@
+package server
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBreaker(t *testing.T) {
+	s, _ := newTestServer()
+	s.WithCircuitBreaker(0.5, 4, time.Minute)
+
+	now := time.Date(2003, time.October, 11, 22, 14, 15, 0, time.UTC)
+	b := s.breaker
+	b.now = func() time.Time {
+		return now
+	}
+
+	// 1 error out of 4
+	for _, failed := range []bool{true, false, false, false} {
+		require.False(t, b.drop("a"))
+		require.False(t, b.record("a", failed))
+	}
+
+	// 2 errors out of 4
+	for i, failed := range []bool{true, false, true, false} {
+		require.False(t, b.drop("a"))
+		require.Equal(t, i == 3, b.record("a", failed))
+	}
+
+	// other peers are not affected
+	require.False(t, b.drop("b"))
+
+	require.True(t, b.drop("a"))
+	require.True(t, b.drop("a"))
+
+	require.Equal(
+		t,
+		[]QuarantinedPeer{
+			{Host: "a", Until: now.Add(time.Minute), Dropped: 2},
+		},
+		s.Quarantined(),
+	)
+
+	now = now.Add(time.Minute)
+
+	require.False(t, b.drop("a"))
+	require.Empty(t, s.Quarantined())
+
+	// counters were reset
+	for _, failed := range []bool{true, true, true} {
+		require.False(t, b.record("a", failed))
+	}
+}
+
+func TestBreakerPrune(t *testing.T) {
+	s, _ := newTestServer()
+	s.WithCircuitBreaker(1, 1, time.Minute)
+
+	b := s.breaker
+
+	require.True(t, b.record("a", true))
+	require.False(t, b.record("b", false))
+
+	b.prune()
+
+	require.Len(t, b.peers, 1)
+	require.True(t, b.drop("a"))
+}
+
+func TestServePacketConnCircuitBreaker(t *testing.T) {
+	s, c := newTestServer()
+	s.WithCircuitBreaker(0.5, 2, time.Hour)
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.Nil(t, err)
+
+	done := make(chan error)
+	go func() {
+		done <- s.ServePacketConn(conn)
+	}()
+
+	client, err := net.Dial("udp", conn.LocalAddr().String())
+	require.Nil(t, err)
+	defer client.Close()
+
+	invalid := []byte("<34>1 2003-13-11T22:14:15.003Z mymachine su - ID47 - message")
+
+	for i := 0; i < 2; i++ {
+		_, err = client.Write(invalid)
+		require.Nil(t, err)
+
+		r := receive(t, c)
+		require.NotNil(t, r.err)
+		require.NotEqual(t, ErrPeerQuarantined, r.err)
+	}
+
+	r := receive(t, c)
+	require.Equal(t, ErrPeerQuarantined, r.err)
+	require.Equal(t, client.LocalAddr().String(), r.source.String())
+
+	_, err = client.Write([]byte("<34>Oct 11 22:14:15 mymachine su: 'su root' failed for lonvick on /dev/pts/8"))
+	require.Nil(t, err)
+
+	require.Eventually(t, func() bool {
+		peers := s.Quarantined()
+		return len(peers) == 1 && peers[0].Dropped == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.Equal(t, "127.0.0.1", s.Quarantined()[0].Host)
+	require.Len(t, c, 0)
+
+	require.Nil(t, s.Close())
+	require.Nil(t, <-done)
+}
+
+func TestPeerHost(t *testing.T) {
+	require.Equal(t, "", peerHost(nil))
+	require.Equal(t, "192.0.2.1", peerHost(&net.UDPAddr{IP: net.ParseIP("192.0.2.1"), Port: 514}))
+	require.Equal(t, "2001:db8::1", peerHost(&net.TCPAddr{IP: net.ParseIP("2001:db8::1"), Port: 514}))
+	require.Equal(t, "/dev/log", peerHost(&net.UnixAddr{Name: "/dev/log", Net: "unixgram"}))
+}
@@ -0,0 +1,138 @@
+package server
+
+import (
+	"time"
+
+	"github.com/jeromer/syslogparser"
+	"github.com/jeromer/syslogparser/parsercommon"
+)
+
+// Parser options applied to every received message. The configuration can be
+// replaced at any time with SetConfig(), messages being parsed keep the one
+// in use when they were received.
+type Config struct {
+	// location of RFC3164 timestamps, UTC when nil
+	Location *time.Location
+
+	// dialects tried before the built-in parsers when set
+	Registry *syslogparser.Registry
+
+	// adds "facility_name" and "severity_name" (see WithNames() in parsers)
+	Names bool
+
+	// bytes of every message which are parsed (see WithMaxPacketLen() in
+	// parsers), MAX_PACKET_LEN of the detected RFC when zero
+	MaxPacketLen int
+
+	// maps received severities to the ones reported to the handler. Priority
+	// and severity_name are updated accordingly.
+	SeverityMap map[int]int
+
+	// messages whose timestamp is older than MaxAge, as sent by devices
+	// flushing their buffer after a reboot, are dropped. Disabled when zero.
+	MaxAge time.Duration
+
+	// flags stale messages with "stale": true instead of dropping them
+	FlagStale bool
+
+	// called once the message has been parsed, the message is dropped when
+	// false is returned
+	Filter func(parts syslogparser.LogParts) bool
+}
+
+// Replaces the configuration atomically. Maps and functions held by cfg
+// MUST NOT be modified afterwards, give a new Config instead.
+func (s *Server) SetConfig(cfg Config) {
+	s.cfg.Store(&cfg)
+}
+
+// Returns the configuration in use
+func (s *Server) Config() Config {
+	return *s.config()
+}
+
+func (s *Server) config() *Config {
+	return s.cfg.Load().(*Config)
+}
+
+type namer interface {
+	WithNames()
+}
+
+type packetLimiter interface {
+	WithMaxPacketLen(n int)
+}
+
+func (cfg *Config) configure(p syslogparser.LogParser) {
+	if cfg.Location != nil {
+		p.WithLocation(cfg.Location)
+	}
+
+	if cfg.Names {
+		if n, ok := p.(namer); ok {
+			n.WithNames()
+		}
+	}
+
+	if cfg.MaxPacketLen != 0 {
+		if l, ok := p.(packetLimiter); ok {
+			l.WithMaxPacketLen(cfg.MaxPacketLen)
+		}
+	}
+}
+
+// Applies the severity map, the age limit and the filter. Returns false when
+// the message must be dropped.
+func (cfg *Config) apply(parts syslogparser.LogParts, now time.Time) bool {
+	if len(cfg.SeverityMap) > 0 {
+		mapSeverity(parts, cfg.SeverityMap)
+	}
+
+	if cfg.MaxAge > 0 && isStale(parts, now.Add(-cfg.MaxAge)) {
+		if !cfg.FlagStale {
+			return false
+		}
+
+		parts["stale"] = true
+	}
+
+	if cfg.Filter != nil {
+		return cfg.Filter(parts)
+	}
+
+	return true
+}
+
+func mapSeverity(parts syslogparser.LogParts, m map[int]int) {
+	f, ok := parts["facility"].(int)
+	if !ok {
+		return
+	}
+
+	sev, ok := parts["severity"].(int)
+	if !ok {
+		return
+	}
+
+	mapped, ok := m[sev]
+	if !ok || mapped < 0 || mapped > 7 {
+		return
+	}
+
+	parts["severity"] = mapped
+	parts["priority"] = f*8 + mapped
+
+	if _, ok := parts["severity_name"]; ok {
+		parts["severity_name"] = parsercommon.Severity{Value: mapped}.Name()
+	}
+}
+
+// Messages without timestamp are never stale
+func isStale(parts syslogparser.LogParts, limit time.Time) bool {
+	ts, ok := parts["timestamp"].(time.Time)
+	if !ok || ts.IsZero() {
+		return false
+	}
+
+	return ts.Before(limit)
+}
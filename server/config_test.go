@@ -0,0 +1,245 @@
+package server
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeromer/syslogparser"
+	"github.com/jeromer/syslogparser/rfc3164"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetConfig(t *testing.T) {
+	s, c := newTestServer()
+
+	require.Equal(t, Config{}, s.Config())
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.Nil(t, err)
+
+	done := make(chan error)
+	go func() {
+		done <- s.ServePacketConn(conn)
+	}()
+
+	client, err := net.Dial("udp", conn.LocalAddr().String())
+	require.Nil(t, err)
+	defer client.Close()
+
+	msg := []byte("<34>Oct 11 22:14:15 mymachine su: 'su root' failed for lonvick on /dev/pts/8")
+
+	_, err = client.Write(msg)
+	require.Nil(t, err)
+
+	r := receive(t, c)
+	require.Nil(t, r.err)
+	require.Equal(t, 2, r.parts["severity"])
+	require.NotContains(t, r.parts, "severity_name")
+
+	loc := time.FixedZone("", 2*60*60)
+
+	s.SetConfig(Config{
+		Location:    loc,
+		Names:       true,
+		SeverityMap: map[int]int{2: 4},
+		Filter: func(parts syslogparser.LogParts) bool {
+			return parts["hostname"] != "noisy"
+		},
+	})
+
+	_, err = client.Write([]byte("<34>Oct 11 22:14:15 noisy su: dropped"))
+	require.Nil(t, err)
+
+	_, err = client.Write(msg)
+	require.Nil(t, err)
+
+	r = receive(t, c)
+	require.Nil(t, r.err)
+	require.Equal(t, "mymachine", r.parts["hostname"])
+	require.Equal(t, 4, r.parts["severity"])
+	require.Equal(t, 36, r.parts["priority"])
+	require.Equal(t, "warning", r.parts["severity_name"])
+	require.Equal(t, "auth", r.parts["facility_name"])
+	require.Equal(t, loc, r.parts["timestamp"].(time.Time).Location())
+
+	require.True(t, s.Config().Names)
+
+	require.Nil(t, s.Close())
+	require.Nil(t, <-done)
+
+	require.Len(t, c, 0)
+}
+
+func TestConfigMaxPacketLen(t *testing.T) {
+	msg := []byte("<34>Oct 11 22:14:15 mymachine su: " + strings.Repeat("a", 4096))
+
+	parts, err := parse(&Config{}, msg)
+	require.Nil(t, err)
+	require.Len(t, parts["content"], rfc3164.MAX_PACKET_LEN-34)
+
+	parts, err = parse(&Config{MaxPacketLen: MAX_FRAME_LEN}, msg)
+	require.Nil(t, err)
+	require.Len(t, parts["content"], 4096)
+}
+
+func TestMapSeverity(t *testing.T) {
+	testCases := []struct {
+		description   string
+		input         syslogparser.LogParts
+		severityMap   map[int]int
+		expectedParts syslogparser.LogParts
+	}{
+		{
+			description: "mapped",
+			input: syslogparser.LogParts{
+				"priority": 165, "facility": 20, "severity": 5,
+			},
+			severityMap: map[int]int{5: 6},
+			expectedParts: syslogparser.LogParts{
+				"priority": 166, "facility": 20, "severity": 6,
+			},
+		},
+		{
+			description: "not mapped",
+			input: syslogparser.LogParts{
+				"priority": 165, "facility": 20, "severity": 5,
+			},
+			severityMap: map[int]int{4: 6},
+			expectedParts: syslogparser.LogParts{
+				"priority": 165, "facility": 20, "severity": 5,
+			},
+		},
+		{
+			description: "out of range",
+			input: syslogparser.LogParts{
+				"priority": 165, "facility": 20, "severity": 5,
+			},
+			severityMap: map[int]int{5: 8},
+			expectedParts: syslogparser.LogParts{
+				"priority": 165, "facility": 20, "severity": 5,
+			},
+		},
+		{
+			description: "with name",
+			input: syslogparser.LogParts{
+				"priority": 34, "facility": 4, "severity": 2, "severity_name": "crit",
+			},
+			severityMap: map[int]int{2: 3},
+			expectedParts: syslogparser.LogParts{
+				"priority": 35, "facility": 4, "severity": 3, "severity_name": "err",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		mapSeverity(tc.input, tc.severityMap)
+
+		require.Equal(
+			t, tc.expectedParts, tc.input, tc.description,
+		)
+	}
+}
+
+func TestConfigMaxAge(t *testing.T) {
+	now := time.Date(2003, time.October, 11, 22, 14, 15, 0, time.UTC)
+
+	testCases := []struct {
+		description   string
+		cfg           Config
+		timestamp     interface{}
+		expectedKept  bool
+		expectedStale bool
+	}{
+		{
+			description:  "disabled",
+			cfg:          Config{},
+			timestamp:    now.Add(-24 * time.Hour),
+			expectedKept: true,
+		},
+		{
+			description:  "recent",
+			cfg:          Config{MaxAge: time.Hour},
+			timestamp:    now.Add(-time.Minute),
+			expectedKept: true,
+		},
+		{
+			description:  "in the future",
+			cfg:          Config{MaxAge: time.Hour},
+			timestamp:    now.Add(time.Minute),
+			expectedKept: true,
+		},
+		{
+			description:  "stale",
+			cfg:          Config{MaxAge: time.Hour},
+			timestamp:    now.Add(-24 * 7 * time.Hour),
+			expectedKept: false,
+		},
+		{
+			description:   "stale flagged",
+			cfg:           Config{MaxAge: time.Hour, FlagStale: true},
+			timestamp:     now.Add(-24 * 7 * time.Hour),
+			expectedKept:  true,
+			expectedStale: true,
+		},
+		{
+			description:  "unknown timestamp",
+			cfg:          Config{MaxAge: time.Hour},
+			timestamp:    time.Time{},
+			expectedKept: true,
+		},
+		{
+			description:  "no timestamp",
+			cfg:          Config{MaxAge: time.Hour},
+			timestamp:    nil,
+			expectedKept: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		parts := syslogparser.LogParts{"timestamp": tc.timestamp}
+
+		kept := tc.cfg.apply(parts, now)
+
+		require.Equal(t, tc.expectedKept, kept, tc.description)
+
+		if tc.expectedStale {
+			require.Equal(t, true, parts["stale"], tc.description)
+		} else {
+			require.NotContains(t, parts, "stale", tc.description)
+		}
+	}
+}
+
+func TestConfigRegistry(t *testing.T) {
+	r := syslogparser.NewRegistry()
+
+	err := r.Register(syslogparser.Dialect{
+		Name: "acme",
+		Detect: func(buff []byte) bool {
+			return strings.HasPrefix(string(buff), "ACME: ")
+		},
+		New: func(buff []byte) syslogparser.LogParser {
+			p := rfc3164.NewParser(buff[len("ACME: "):])
+			p.WithTag("acme")
+
+			return p
+		},
+	})
+	require.Nil(t, err)
+
+	buff := []byte("ACME: <34>Oct 11 22:14:15 mymachine 'su root' failed")
+
+	_, err = parse(&Config{}, buff)
+	require.NotNil(t, err)
+
+	parts, err := parse(&Config{Registry: r}, buff)
+	require.Nil(t, err)
+	require.Equal(t, "acme", parts["tag"])
+	require.Equal(t, "mymachine", parts["hostname"])
+
+	parts, err = parse(&Config{Registry: r}, buff[len("ACME: "):])
+	require.Nil(t, err)
+	require.Equal(t, "'su", parts["tag"])
+}
@@ -0,0 +1,167 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+)
+
+var (
+	ErrFrameTooLong = errors.New("Frame too long")
+	ErrInvalidFrame = errors.New("Invalid frame length")
+)
+
+const (
+	FRAMING_UNKNOWN = iota
+	// https://tools.ietf.org/html/rfc6587#section-3.4.1
+	FRAMING_OCTET_COUNTING
+	// https://tools.ietf.org/html/rfc6587#section-3.4.2
+	FRAMING_NON_TRANSPARENT
+)
+
+// Splits a stream into messages. The framing method is detected on the first
+// frame: octet counting when it starts with a digit, LF delimited otherwise.
+type framer struct {
+	r       *bufio.Reader
+	maxLen  int
+	framing int
+	buff    []byte
+
+	// messages may also be terminated by NUL, as sent by glibc syslog(3)
+	// over stream sockets
+	nulDelimited bool
+}
+
+func newFramer(r io.Reader, maxLen int) *framer {
+	return &framer{
+		r:      bufio.NewReaderSize(r, maxLen),
+		maxLen: maxLen,
+	}
+}
+
+// Returns the next message. The returned slice is only valid until the next
+// call.
+func (f *framer) next() ([]byte, error) {
+	if f.framing == FRAMING_UNKNOWN {
+		b, err := f.r.Peek(1)
+		if err != nil {
+			return nil, err
+		}
+
+		f.framing = FRAMING_NON_TRANSPARENT
+		if parsercommon.IsDigit(b[0]) {
+			f.framing = FRAMING_OCTET_COUNTING
+		}
+	}
+
+	if f.framing == FRAMING_OCTET_COUNTING {
+		return f.nextOctetCounted()
+	}
+
+	return f.nextLine()
+}
+
+// SYSLOG-FRAME = MSG-LEN SP SYSLOG-MSG
+func (f *framer) nextOctetCounted() ([]byte, error) {
+	// MSG-LEN is at most 10 digits long
+	maxDigits := 10
+	l := 0
+
+	for i := 0; ; i++ {
+		c, err := f.r.ReadByte()
+		if err != nil {
+			if err == io.EOF && i > 0 {
+				return nil, io.ErrUnexpectedEOF
+			}
+
+			return nil, err
+		}
+
+		if c == ' ' && i > 0 {
+			break
+		}
+
+		if !parsercommon.IsDigit(c) || i >= maxDigits {
+			return nil, ErrInvalidFrame
+		}
+
+		l = (l * 10) + int(c-'0')
+
+		if l > f.maxLen {
+			return nil, ErrFrameTooLong
+		}
+	}
+
+	if cap(f.buff) < l {
+		f.buff = make([]byte, l)
+	}
+
+	f.buff = f.buff[:l]
+
+	_, err := io.ReadFull(f.r, f.buff)
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+
+		return nil, err
+	}
+
+	return f.buff, nil
+}
+
+func (f *framer) nextLine() ([]byte, error) {
+	for {
+		line, err := f.readLine()
+
+		if err == bufio.ErrBufferFull {
+			return nil, ErrFrameTooLong
+		}
+
+		// last message of the stream may not be followed by LF
+		if err == io.EOF && len(line) > 0 {
+			err = nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		line = bytes.TrimRight(line, "\r\n\x00")
+		if len(line) > 0 {
+			return line, nil
+		}
+	}
+}
+
+// Same as ReadSlice('\n') also stopping at NUL when nulDelimited is set
+func (f *framer) readLine() ([]byte, error) {
+	if !f.nulDelimited {
+		return f.r.ReadSlice('\n')
+	}
+
+	scanned := 0
+
+	for {
+		// fills the buffer with at least one byte not scanned yet
+		_, err := f.r.Peek(scanned + 1)
+		b, _ := f.r.Peek(f.r.Buffered())
+
+		for i := scanned; i < len(b); i++ {
+			if b[i] == '\n' || b[i] == 0 {
+				f.r.Discard(i + 1)
+				return b[:i+1], nil
+			}
+		}
+
+		scanned = len(b)
+
+		if err != nil {
+			f.r.Discard(scanned)
+			return b, err
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package server
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFramer(t *testing.T) {
+	testCases := []struct {
+		description    string
+		input          string
+		maxLen         int
+		nulDelimited   bool
+		expectedFrames []string
+		expectedErr    error
+	}{
+		{
+			description:    "octet counting",
+			input:          "5 <34>a3 <1>",
+			maxLen:         1024,
+			expectedFrames: []string{"<34>a", "<1>"},
+			expectedErr:    io.EOF,
+		},
+		{
+			description:    "octet counting with LF in message",
+			input:          "7 <34>a\nb",
+			maxLen:         1024,
+			expectedFrames: []string{"<34>a\nb"},
+			expectedErr:    io.EOF,
+		},
+		{
+			description:    "octet counting truncated",
+			input:          "10 <34>a",
+			maxLen:         1024,
+			expectedFrames: nil,
+			expectedErr:    io.ErrUnexpectedEOF,
+		},
+		{
+			description:    "octet counting too long",
+			input:          "2000 <34>a",
+			maxLen:         1024,
+			expectedFrames: nil,
+			expectedErr:    ErrFrameTooLong,
+		},
+		{
+			description:    "octet counting invalid length",
+			input:          "5 <34>a12a <34>a",
+			maxLen:         1024,
+			expectedFrames: []string{"<34>a"},
+			expectedErr:    ErrInvalidFrame,
+		},
+		{
+			description:    "LF delimited",
+			input:          "<34>a\n<34>b\r\n\n<34>c",
+			maxLen:         1024,
+			expectedFrames: []string{"<34>a", "<34>b", "<34>c"},
+			expectedErr:    io.EOF,
+		},
+		{
+			description:    "LF delimited too long",
+			input:          "<34>" + strings.Repeat("a", 32) + "\n",
+			maxLen:         16,
+			expectedFrames: nil,
+			expectedErr:    ErrFrameTooLong,
+		},
+		{
+			description:    "NUL delimited",
+			input:          "<34>a\x00<34>b\x00\n<34>c\n<34>d",
+			maxLen:         1024,
+			nulDelimited:   true,
+			expectedFrames: []string{"<34>a", "<34>b", "<34>c", "<34>d"},
+			expectedErr:    io.EOF,
+		},
+		{
+			description:    "NUL delimited too long",
+			input:          "<34>" + strings.Repeat("a", 32) + "\x00",
+			maxLen:         16,
+			nulDelimited:   true,
+			expectedFrames: nil,
+			expectedErr:    ErrFrameTooLong,
+		},
+		{
+			description:    "NUL kept when not delimiting",
+			input:          "<34>a\x00<34>b\n",
+			maxLen:         1024,
+			expectedFrames: []string{"<34>a\x00<34>b"},
+			expectedErr:    io.EOF,
+		},
+	}
+
+	for _, tc := range testCases {
+		f := newFramer(strings.NewReader(tc.input), tc.maxLen)
+		f.nulDelimited = tc.nulDelimited
+
+		var obtained []string
+		var err error
+
+		for {
+			var frame []byte
+
+			frame, err = f.next()
+			if err != nil {
+				break
+			}
+
+			obtained = append(obtained, string(frame))
+		}
+
+		require.Equal(
+			t, tc.expectedFrames, obtained, tc.description,
+		)
+
+		require.Equal(
+			t, tc.expectedErr, err, tc.description,
+		)
+	}
+}
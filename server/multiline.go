@@ -0,0 +1,194 @@
+package server
+
+import (
+	"errors"
+	"regexp"
+	"sync"
+	"time"
+)
+
+const (
+	// default maximum number of lines joined into a single message
+	MAX_MULTILINE_LINES = 1000
+)
+
+var (
+	ErrInvalidMultiline = errors.New("Invalid multiline configuration")
+)
+
+// Tells how continuation lines are recognized
+type MultilineMode uint8
+
+const (
+	// lines starting with a space or a tab continue the previous one, as
+	// the "\tat com.example..." lines of Java stack traces
+	MULTILINE_INDENT MultilineMode = iota
+
+	// lines not matching Multiline.Start continue the previous one
+	MULTILINE_PATTERN
+
+	// lines received less than Multiline.Timeout after the previous one
+	// continue it
+	MULTILINE_TIMEOUT
+)
+
+// Joins continuation lines of stream connections, with LF, into the message
+// they belong to before it is parsed
+type Multiline struct {
+	Mode MultilineMode
+
+	// first line of a message with MULTILINE_PATTERN, ie. `^<\d+>`
+	Start *regexp.Regexp
+
+	// a pending message is parsed once no line has been received for
+	// Timeout, otherwise when the next message starts or the connection is
+	// closed. Required with MULTILINE_TIMEOUT.
+	Timeout time.Duration
+
+	// lines joined into a single message, MAX_MULTILINE_LINES when zero
+	MaxLines int
+}
+
+// Aggregates continuation lines of messages received over stream connections
+// (TCP, TLS and unix sockets), see Multiline. Joined messages are at most
+// WithMaxMessageLen() long, a line which does not fit starts a new message.
+// Datagrams are not aggregated.
+func (s *Server) WithMultiline(m Multiline) error {
+	switch m.Mode {
+	case MULTILINE_INDENT:
+	case MULTILINE_PATTERN:
+		if m.Start == nil {
+			return ErrInvalidMultiline
+		}
+	case MULTILINE_TIMEOUT:
+		if m.Timeout <= 0 {
+			return ErrInvalidMultiline
+		}
+	default:
+		return ErrInvalidMultiline
+	}
+
+	if m.Timeout < 0 || m.MaxLines < 0 {
+		return ErrInvalidMultiline
+	}
+
+	if m.MaxLines == 0 {
+		m.MaxLines = MAX_MULTILINE_LINES
+	}
+
+	s.multiline = &m
+
+	return nil
+}
+
+// Joins the lines of a single connection and gives complete messages to
+// flush, which is never called concurrently
+type aggregator struct {
+	m      *Multiline
+	maxLen int
+	flush  func(msg []byte)
+	now    func() time.Time
+
+	mu      sync.Mutex
+	pending []byte
+	lines   int
+	last    time.Time
+	timer   *time.Timer
+	closed  bool
+}
+
+func newAggregator(m *Multiline, maxLen int, flush func(msg []byte)) *aggregator {
+	return &aggregator{
+		m:      m,
+		maxLen: maxLen,
+		flush:  flush,
+		now:    time.Now,
+	}
+}
+
+// Adds a line, which is copied
+func (a *aggregator) add(line []byte) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.now()
+
+	if a.lines > 0 && a.lines < a.m.MaxLines &&
+		len(a.pending)+1+len(line) <= a.maxLen && a.continues(line, now) {
+		a.pending = append(a.pending, '\n')
+		a.pending = append(a.pending, line...)
+		a.lines++
+	} else {
+		a.flushPending()
+
+		a.pending = append([]byte(nil), line...)
+		a.lines = 1
+	}
+
+	a.last = now
+
+	if a.m.Timeout == 0 {
+		return
+	}
+
+	if a.timer == nil {
+		a.timer = time.AfterFunc(a.m.Timeout, a.expire)
+	} else {
+		a.timer.Reset(a.m.Timeout)
+	}
+}
+
+// Flushes the pending message, lines added afterwards are dropped
+func (a *aggregator) close() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.closed = true
+
+	if a.timer != nil {
+		a.timer.Stop()
+	}
+
+	a.flushPending()
+}
+
+func (a *aggregator) continues(line []byte, now time.Time) bool {
+	switch a.m.Mode {
+	case MULTILINE_PATTERN:
+		return !a.m.Start.Match(line)
+	case MULTILINE_TIMEOUT:
+		return now.Sub(a.last) < a.m.Timeout
+	default:
+		return len(line) > 0 && (line[0] == ' ' || line[0] == '\t')
+	}
+}
+
+func (a *aggregator) expire() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.closed {
+		return
+	}
+
+	// XXX : the timer may fire while add() resets it
+	elapsed := a.now().Sub(a.last)
+	if elapsed < a.m.Timeout {
+		a.timer.Reset(a.m.Timeout - elapsed)
+		return
+	}
+
+	a.flushPending()
+}
+
+func (a *aggregator) flushPending() {
+	if a.lines == 0 {
+		return
+	}
+
+	msg := a.pending
+	a.pending = nil
+	a.lines = 0
+
+	a.flush(msg)
+}
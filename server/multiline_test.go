@@ -0,0 +1,183 @@
+package server
+
+import (
+	"net"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregator(t *testing.T) {
+	testCases := []struct {
+		description string
+		multiline   Multiline
+		maxLen      int
+		lines       []string
+		expected    []string
+	}{
+		{
+			description: "indent",
+			multiline:   Multiline{Mode: MULTILINE_INDENT},
+			lines: []string{
+				"<11>Oct 11 22:14:15 app java: java.lang.NullPointerException",
+				"\tat com.example.Foo.bar(Foo.java:42)",
+				"  at com.example.Main.main(Main.java:7)",
+				"<14>Oct 11 22:14:16 app java: done",
+			},
+			expected: []string{
+				"<11>Oct 11 22:14:15 app java: java.lang.NullPointerException\n\tat com.example.Foo.bar(Foo.java:42)\n  at com.example.Main.main(Main.java:7)",
+				"<14>Oct 11 22:14:16 app java: done",
+			},
+		},
+		{
+			description: "pattern",
+			multiline: Multiline{
+				Mode:  MULTILINE_PATTERN,
+				Start: regexp.MustCompile(`^<\d+>`),
+			},
+			lines: []string{
+				"<11>Oct 11 22:14:15 app java: Exception in thread main",
+				"Caused by: java.io.IOException",
+				"<14>Oct 11 22:14:16 app java: done",
+			},
+			expected: []string{
+				"<11>Oct 11 22:14:15 app java: Exception in thread main\nCaused by: java.io.IOException",
+				"<14>Oct 11 22:14:16 app java: done",
+			},
+		},
+		{
+			description: "max lines",
+			multiline:   Multiline{Mode: MULTILINE_INDENT, MaxLines: 2},
+			lines:       []string{"a", " b", " c", " d"},
+			expected:    []string{"a\n b", " c\n d"},
+		},
+		{
+			description: "max length",
+			multiline:   Multiline{Mode: MULTILINE_INDENT},
+			maxLen:      8,
+			lines:       []string{"abc", " def", " g"},
+			expected:    []string{"abc\n def", " g"},
+		},
+		{
+			description: "continuation first",
+			multiline:   Multiline{Mode: MULTILINE_INDENT},
+			lines:       []string{" a", "b"},
+			expected:    []string{" a", "b"},
+		},
+	}
+
+	for _, tc := range testCases {
+		require.Nil(t, (&Server{}).WithMultiline(tc.multiline), tc.description)
+
+		if tc.multiline.MaxLines == 0 {
+			tc.multiline.MaxLines = MAX_MULTILINE_LINES
+		}
+
+		if tc.maxLen == 0 {
+			tc.maxLen = MAX_FRAME_LEN
+		}
+
+		var obtained []string
+
+		a := newAggregator(&tc.multiline, tc.maxLen, func(msg []byte) {
+			obtained = append(obtained, string(msg))
+		})
+
+		buff := make([]byte, 0, 128)
+		for _, l := range tc.lines {
+			// lines given by the framer are reused
+			buff = append(buff[:0], l...)
+			a.add(buff)
+		}
+
+		a.close()
+
+		require.Equal(t, tc.expected, obtained, tc.description)
+	}
+}
+
+func TestAggregatorTimeout(t *testing.T) {
+	now := time.Date(2003, time.October, 11, 22, 14, 15, 0, time.UTC)
+
+	m := Multiline{Mode: MULTILINE_TIMEOUT, Timeout: time.Second, MaxLines: MAX_MULTILINE_LINES}
+
+	var obtained []string
+
+	a := newAggregator(&m, MAX_FRAME_LEN, func(msg []byte) {
+		obtained = append(obtained, string(msg))
+	})
+	a.now = func() time.Time { return now }
+
+	a.add([]byte("a"))
+	now = now.Add(500 * time.Millisecond)
+	a.add([]byte("b"))
+	now = now.Add(2 * time.Second)
+	a.add([]byte("c"))
+
+	a.close()
+
+	require.Equal(t, []string{"a\nb", "c"}, obtained)
+}
+
+func TestWithMultilineErrors(t *testing.T) {
+	testCases := []struct {
+		description string
+		multiline   Multiline
+	}{
+		{
+			description: "pattern without start",
+			multiline:   Multiline{Mode: MULTILINE_PATTERN},
+		},
+		{
+			description: "timeout without timeout",
+			multiline:   Multiline{Mode: MULTILINE_TIMEOUT},
+		},
+		{
+			description: "unknown mode",
+			multiline:   Multiline{Mode: 42},
+		},
+		{
+			description: "negative max lines",
+			multiline:   Multiline{Mode: MULTILINE_INDENT, MaxLines: -1},
+		},
+	}
+
+	for _, tc := range testCases {
+		err := (&Server{}).WithMultiline(tc.multiline)
+		require.Equal(t, ErrInvalidMultiline, err, tc.description)
+	}
+}
+
+func TestServeListenerMultiline(t *testing.T) {
+	s, c := newTestServer()
+	require.Nil(t, s.WithMultiline(Multiline{
+		Mode:    MULTILINE_INDENT,
+		Timeout: 50 * time.Millisecond,
+	}))
+
+	l, done := newTestListener(t, s)
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	require.Nil(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte(
+		"<11>Oct 11 22:14:15 app java: java.lang.NullPointerException\n" +
+			"\tat com.example.Foo.bar(Foo.java:42)\n",
+	))
+	require.Nil(t, err)
+
+	// flushed by the timeout, the connection being still open
+	r := receive(t, c)
+	require.Nil(t, r.err)
+	require.Equal(
+		t,
+		"java.lang.NullPointerException\n\tat com.example.Foo.bar(Foo.java:42)",
+		r.parts["content"],
+	)
+
+	require.Nil(t, s.Close())
+	require.Nil(t, <-done)
+}
@@ -0,0 +1,160 @@
+package server
+
+import (
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jeromer/syslogparser"
+)
+
+const (
+	// addresses cached by the resolver before expired ones are forgotten
+	MAX_RESOLVER_ENTRIES = 65536
+
+	// addresses resolved at once, others are not resolved until one is done
+	MAX_PENDING_LOOKUPS = 64
+)
+
+// Returns the canonical hostname of a peer IP, ie. from reverse DNS or an
+// inventory
+type Resolver interface {
+	Resolve(ip string) (string, error)
+}
+
+type ResolverFunc func(ip string) (string, error)
+
+func (f ResolverFunc) Resolve(ip string) (string, error) {
+	return f(ip)
+}
+
+// Resolves addresses using reverse DNS, the first name found is returned
+// without its trailing dot
+type DNSResolver struct{}
+
+func (DNSResolver) Resolve(ip string) (string, error) {
+	names, err := net.LookupAddr(ip)
+	if err != nil {
+		return "", err
+	}
+
+	if len(names) == 0 {
+		return "", &net.DNSError{Err: "no such host", Name: ip, IsNotFound: true}
+	}
+
+	return strings.TrimSuffix(names[0], "."), nil
+}
+
+// Caches resolved names, and failures, for ttl. Addresses are resolved in
+// the background so slow lookups never hold up the receive loops.
+type cachingResolver struct {
+	resolver Resolver
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]resolved
+	pending map[string]struct{}
+	wg      sync.WaitGroup
+}
+
+type resolved struct {
+	hostname string
+	ok       bool
+	expires  time.Time
+}
+
+// Adds "hostname_resolved" to messages received from IP peers, holding the
+// name r returns for the peer IP. Results are cached for ttl, failures
+// included, in which case the field is not set.
+// Peers are resolved in the background: messages received before the name
+// of their peer is known do not get the field, those received while an
+// expired name is refreshed get the expired one.
+// Resolution happens before the configuration filter is applied.
+func (s *Server) WithResolver(r Resolver, ttl time.Duration) {
+	s.resolver = &cachingResolver{
+		resolver: r,
+		ttl:      ttl,
+		now:      time.Now,
+		entries:  make(map[string]resolved),
+		pending:  make(map[string]struct{}),
+	}
+}
+
+// Returns the cached name of ip, starting its lookup when it is not cached
+// or expired. Concurrent calls for the same ip share a single lookup.
+func (cr *cachingResolver) resolve(ip string) (string, bool) {
+	now := cr.now()
+
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	e, found := cr.entries[ip]
+	if found && now.Before(e.expires) {
+		return e.hostname, e.ok
+	}
+
+	_, resolving := cr.pending[ip]
+	if !resolving && len(cr.pending) < MAX_PENDING_LOOKUPS {
+		cr.pending[ip] = struct{}{}
+		cr.wg.Add(1)
+
+		go cr.lookup(ip)
+	}
+
+	return e.hostname, e.ok
+}
+
+func (cr *cachingResolver) lookup(ip string) {
+	defer cr.wg.Done()
+
+	h, err := cr.resolver.Resolve(ip)
+	now := cr.now()
+
+	e := resolved{
+		hostname: h,
+		ok:       err == nil && h != "",
+		expires:  now.Add(cr.ttl),
+	}
+
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	delete(cr.pending, ip)
+
+	if len(cr.entries) >= MAX_RESOLVER_ENTRIES {
+		cr.prune(now)
+	}
+
+	cr.entries[ip] = e
+}
+
+// Waits for pending lookups to complete
+func (cr *cachingResolver) wait() {
+	cr.wg.Wait()
+}
+
+// Forgets expired entries, or all of them when none expired
+func (cr *cachingResolver) prune(now time.Time) {
+	for ip, e := range cr.entries {
+		if !now.Before(e.expires) {
+			delete(cr.entries, ip)
+		}
+	}
+
+	if len(cr.entries) >= MAX_RESOLVER_ENTRIES {
+		cr.entries = make(map[string]resolved)
+	}
+}
+
+func (cr *cachingResolver) enrich(parts syslogparser.LogParts, addr net.Addr) {
+	ip := peerHost(addr)
+	if net.ParseIP(ip) == nil {
+		return
+	}
+
+	if h, ok := cr.resolve(ip); ok {
+		parts["hostname_resolved"] = h
+	}
+}
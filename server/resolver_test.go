@@ -0,0 +1,194 @@
+package server
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jeromer/syslogparser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCachingResolver(t *testing.T) {
+	calls := map[string]int{}
+	var mu sync.Mutex
+
+	s, _ := newTestServer()
+	s.WithResolver(
+		ResolverFunc(func(ip string) (string, error) {
+			mu.Lock()
+			calls[ip]++
+			mu.Unlock()
+
+			if ip == "192.0.2.2" {
+				return "", errors.New("Not found")
+			}
+
+			return "host-" + ip, nil
+		}),
+		time.Minute,
+	)
+
+	now := time.Date(2003, time.October, 11, 22, 14, 15, 0, time.UTC)
+
+	cr := s.resolver
+	cr.now = func() time.Time {
+		return now
+	}
+
+	// not resolved yet
+	_, ok := cr.resolve("192.0.2.1")
+	require.False(t, ok)
+
+	cr.resolve("192.0.2.2")
+	cr.wait()
+
+	for i := 0; i < 2; i++ {
+		h, ok := cr.resolve("192.0.2.1")
+		require.True(t, ok)
+		require.Equal(t, "host-192.0.2.1", h)
+
+		_, ok = cr.resolve("192.0.2.2")
+		require.False(t, ok)
+	}
+
+	cr.wait()
+	require.Equal(t, map[string]int{"192.0.2.1": 1, "192.0.2.2": 1}, calls)
+
+	now = now.Add(time.Minute)
+
+	// expired name returned while being refreshed
+	h, ok := cr.resolve("192.0.2.1")
+	require.True(t, ok)
+	require.Equal(t, "host-192.0.2.1", h)
+
+	cr.wait()
+	require.Equal(t, 2, calls["192.0.2.1"])
+
+	cr.resolve("192.0.2.1")
+	cr.wait()
+	require.Equal(t, 2, calls["192.0.2.1"])
+
+	parts := syslogparser.LogParts{}
+	cr.enrich(parts, &net.UnixAddr{Name: "/dev/log", Net: "unixgram"})
+	require.Empty(t, parts)
+
+	cr.enrich(parts, &net.UDPAddr{IP: net.ParseIP("192.0.2.2"), Port: 514})
+	require.Empty(t, parts)
+
+	cr.enrich(parts, &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 514})
+	require.Equal(t, syslogparser.LogParts{"hostname_resolved": "host-192.0.2.1"}, parts)
+}
+
+func TestCachingResolverSingleLookup(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	calls := 0
+
+	s, _ := newTestServer()
+	s.WithResolver(
+		ResolverFunc(func(ip string) (string, error) {
+			calls++
+			close(started)
+			<-release
+
+			return "mymachine", nil
+		}),
+		time.Minute,
+	)
+
+	cr := s.resolver
+
+	// slow lookups do not block callers
+	for i := 0; i < 10; i++ {
+		_, ok := cr.resolve("192.0.2.1")
+		require.False(t, ok)
+	}
+
+	<-started
+	close(release)
+	cr.wait()
+
+	h, ok := cr.resolve("192.0.2.1")
+	require.True(t, ok)
+	require.Equal(t, "mymachine", h)
+	require.Equal(t, 1, calls)
+}
+
+func TestCachingResolverPrune(t *testing.T) {
+	s, _ := newTestServer()
+	s.WithResolver(
+		ResolverFunc(func(ip string) (string, error) {
+			return ip, nil
+		}),
+		time.Minute,
+	)
+
+	now := time.Date(2003, time.October, 11, 22, 14, 15, 0, time.UTC)
+
+	cr := s.resolver
+	cr.entries["expired"] = resolved{expires: now}
+	cr.entries["valid"] = resolved{expires: now.Add(time.Second)}
+
+	cr.prune(now)
+
+	require.Len(t, cr.entries, 1)
+	require.Contains(t, cr.entries, "valid")
+}
+
+func TestServePacketConnResolver(t *testing.T) {
+	s, c := newTestServer()
+	s.WithResolver(
+		ResolverFunc(func(ip string) (string, error) {
+			return "mymachine.example.com", nil
+		}),
+		time.Minute,
+	)
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.Nil(t, err)
+
+	done := make(chan error)
+	go func() {
+		done <- s.ServePacketConn(conn)
+	}()
+
+	client, err := net.Dial("udp", conn.LocalAddr().String())
+	require.Nil(t, err)
+	defer client.Close()
+
+	msg := []byte("<34>Oct 11 22:14:15 localhost su: 'su root' failed for lonvick on /dev/pts/8")
+
+	_, err = client.Write(msg)
+	require.Nil(t, err)
+
+	// received before the peer is resolved
+	r := receive(t, c)
+	require.Nil(t, r.err)
+	require.NotContains(t, r.parts, "hostname_resolved")
+
+	s.resolver.wait()
+
+	_, err = client.Write(msg)
+	require.Nil(t, err)
+
+	r = receive(t, c)
+	require.Nil(t, r.err)
+	require.Equal(t, "localhost", r.parts["hostname"])
+	require.Equal(t, "mymachine.example.com", r.parts["hostname_resolved"])
+
+	require.Nil(t, s.Close())
+	require.Nil(t, <-done)
+}
+
+func TestDNSResolver(t *testing.T) {
+	h, err := DNSResolver{}.Resolve("127.0.0.1")
+	if err != nil {
+		t.Skip("reverse DNS not available:", err)
+	}
+
+	require.NotEmpty(t, h)
+	require.NotEqual(t, '.', h[len(h)-1])
+}
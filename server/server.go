@@ -0,0 +1,265 @@
+// Package server provides syslog listeners delivering parsed messages to a
+// user provided handler. The RFC of every message is auto detected.
+package server
+
+import (
+	"errors"
+	"net"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jeromer/syslogparser"
+)
+
+const (
+	// maximum size of an UDP datagram payload
+	MAX_DATAGRAM_LEN = 65535
+
+	// default maximum size of a message received over a stream
+	MAX_FRAME_LEN = 65536
+)
+
+var (
+	ErrServerClosed = errors.New("Server closed")
+)
+
+// Called for every received message with the address of the sender.
+// When the message can not be parsed err is set and parts is nil.
+type Handler func(parts syslogparser.LogParts, source net.Addr, err error)
+
+type Server struct {
+	handler Handler
+
+	readTimeout    time.Duration
+	maxFrameLen    int
+	maxConnections int
+	localHostname  string
+	socketMode     os.FileMode
+	breaker        *breaker
+	resolver       *cachingResolver
+	multiline      *Multiline
+	cfg            atomic.Value
+
+	mu      sync.Mutex
+	closed  bool
+	closers map[closer]struct{}
+	conns   int
+	wg      sync.WaitGroup
+}
+
+type closer interface {
+	Close() error
+}
+
+func NewServer(h Handler) *Server {
+	hostname, _ := os.Hostname()
+
+	s := &Server{
+		handler:       h,
+		maxFrameLen:   MAX_FRAME_LEN,
+		localHostname: hostname,
+		closers:       make(map[closer]struct{}),
+	}
+
+	s.SetConfig(Config{})
+
+	return s
+}
+
+// Hostname reported for messages received on unix sockets, which do not
+// contain any. Defaults to os.Hostname().
+func (s *Server) WithLocalHostname(h string) {
+	s.localHostname = h
+}
+
+// Permissions set on unix sockets created by ListenUnixgram() and
+// ListenUnix(), ie. 0666 to let any local process log. The umask applies by
+// default.
+func (s *Server) WithSocketMode(mode os.FileMode) {
+	s.socketMode = mode
+}
+
+// Closes stream connections on which no data has been received for the given
+// duration. No timeout is used by default.
+func (s *Server) WithReadTimeout(d time.Duration) {
+	s.readTimeout = d
+}
+
+// Maximum length of a single message received over a stream. Connections
+// sending longer messages are closed. Defaults to MAX_FRAME_LEN.
+func (s *Server) WithMaxMessageLen(n int) {
+	s.maxFrameLen = n
+}
+
+// Maximum number of simultaneous stream connections. Connections exceeding
+// the limit are closed right away. Unlimited by default.
+func (s *Server) WithMaxConnections(n int) {
+	s.maxConnections = n
+}
+
+// Listens for datagrams on the given UDP address.
+// Messages are served in the background until Close() is called.
+func (s *Server) ListenUDP(addr string) error {
+	conn, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return err
+	}
+
+	s.serveInBackground(func() error {
+		return s.ServePacketConn(conn)
+	})
+
+	return nil
+}
+
+// Reads datagrams from conn, one message per datagram, until conn is closed.
+// Returns nil when the server was closed.
+func (s *Server) ServePacketConn(conn net.PacketConn) error {
+	return s.servePacketConn(conn, parse)
+}
+
+func (s *Server) servePacketConn(conn net.PacketConn, parse parseFunc) error {
+	if err := s.track(conn); err != nil {
+		return err
+	}
+
+	defer s.untrack(conn)
+
+	buff := make([]byte, MAX_DATAGRAM_LEN)
+
+	for {
+		n, addr, err := conn.ReadFrom(buff)
+		if err != nil {
+			if s.isClosed() {
+				return nil
+			}
+
+			return err
+		}
+
+		s.handle(buff[:n], addr, parse)
+	}
+}
+
+// Stops all listeners and waits for them to return
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	closers := s.closers
+	s.closers = make(map[closer]struct{})
+	s.mu.Unlock()
+
+	var err error
+	for l := range closers {
+		if e := l.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+
+	s.wg.Wait()
+
+	return err
+}
+
+func (s *Server) serveInBackground(serve func() error) {
+	s.wg.Add(1)
+
+	go func() {
+		defer s.wg.Done()
+		serve()
+	}()
+}
+
+func (s *Server) track(l closer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		l.Close()
+		return ErrServerClosed
+	}
+
+	s.closers[l] = struct{}{}
+
+	return nil
+}
+
+func (s *Server) untrack(l closer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.closers, l)
+}
+
+func (s *Server) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.closed
+}
+
+func (s *Server) handle(buff []byte, addr net.Addr, parse parseFunc) {
+	cfg := s.config()
+
+	if s.breaker == nil {
+		s.dispatch(cfg, buff, addr, parse)
+		return
+	}
+
+	host := peerHost(addr)
+	if s.breaker.drop(host) {
+		return
+	}
+
+	err := s.dispatch(cfg, buff, addr, parse)
+
+	if s.breaker.record(host, err != nil) {
+		s.handler(nil, addr, ErrPeerQuarantined)
+	}
+}
+
+// Parses buff and calls the handler unless the message is filtered out
+func (s *Server) dispatch(cfg *Config, buff []byte, addr net.Addr, parse parseFunc) error {
+	parts, err := parse(cfg, buff)
+	if err == nil {
+		if s.resolver != nil {
+			s.resolver.enrich(parts, addr)
+		}
+
+		if !cfg.apply(parts, time.Now()) {
+			return nil
+		}
+	}
+
+	s.handler(parts, addr, err)
+
+	return err
+}
+
+type parseFunc func(cfg *Config, buff []byte) (syslogparser.LogParts, error)
+
+func parse(cfg *Config, buff []byte) (syslogparser.LogParts, error) {
+	var p syslogparser.LogParser
+	var err error
+
+	if cfg.Registry != nil {
+		p, _, err = cfg.Registry.NewAutoParser(buff)
+	} else {
+		p, _, err = syslogparser.NewAutoParser(buff)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.configure(p)
+
+	err = p.Parse()
+	if err != nil {
+		return nil, err
+	}
+
+	return p.Dump(), nil
+}
@@ -0,0 +1,109 @@
+package server
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/jeromer/syslogparser"
+	"github.com/jeromer/syslogparser/parsercommon"
+	"github.com/stretchr/testify/require"
+)
+
+type received struct {
+	parts  syslogparser.LogParts
+	source net.Addr
+	err    error
+}
+
+func newTestServer() (*Server, chan received) {
+	c := make(chan received, 16)
+
+	s := NewServer(func(parts syslogparser.LogParts, source net.Addr, err error) {
+		c <- received{parts, source, err}
+	})
+
+	return s, c
+}
+
+func receive(t *testing.T, c chan received) received {
+	select {
+	case r := <-c:
+		return r
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "no message received")
+	}
+
+	return received{}
+}
+
+func TestServePacketConn(t *testing.T) {
+	s, c := newTestServer()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.Nil(t, err)
+
+	done := make(chan error)
+	go func() {
+		done <- s.ServePacketConn(conn)
+	}()
+
+	client, err := net.Dial("udp", conn.LocalAddr().String())
+	require.Nil(t, err)
+	defer client.Close()
+
+	_, err = client.Write([]byte("<34>Oct 11 22:14:15 mymachine su: 'su root' failed for lonvick on /dev/pts/8"))
+	require.Nil(t, err)
+
+	r := receive(t, c)
+	require.Nil(t, r.err)
+	require.Equal(t, "mymachine", r.parts["hostname"])
+	require.Equal(t, "su", r.parts["tag"])
+	require.Equal(t, client.LocalAddr().String(), r.source.String())
+
+	_, err = client.Write([]byte(`<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 - An application event log entry...`))
+	require.Nil(t, err)
+
+	r = receive(t, c)
+	require.Nil(t, r.err)
+	require.Equal(t, "evntslog", r.parts["app_name"])
+
+	_, err = client.Write([]byte("<34>1 2003-13-11T22:14:15.003Z mymachine su - ID47 - message"))
+	require.Nil(t, err)
+
+	r = receive(t, c)
+	require.Nil(t, r.parts)
+	require.NotNil(t, r.err)
+
+	require.Nil(t, s.Close())
+	require.Nil(t, <-done)
+}
+
+func TestListenUDP(t *testing.T) {
+	s, _ := newTestServer()
+
+	err := s.ListenUDP("127.0.0.1:0")
+	require.Nil(t, err)
+
+	require.Nil(t, s.Close())
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.Nil(t, err)
+
+	require.Equal(t, ErrServerClosed, s.ServePacketConn(conn))
+}
+
+func TestParse(t *testing.T) {
+	parts, err := parse(&Config{}, []byte("<34>Oct 11 22:14:15 mymachine su: 'su root' failed for lonvick on /dev/pts/8"))
+	require.Nil(t, err)
+	require.Equal(t, 34, parts["priority"])
+
+	parts, err = parse(&Config{}, []byte("<34>1 2003-13-11T22:14:15.003Z mymachine su - ID47 - message"))
+	require.Nil(t, parts)
+	require.NotNil(t, err)
+	require.IsType(t, &parsercommon.ParserError{}, err)
+
+	parts, err = parse(&Config{}, []byte("Oct 11 22:14:15 mymachine su: 'su root' failed for lonvick on /dev/pts/8"))
+	require.Nil(t, err)
+	require.Equal(t, parsercommon.DEFAULT_PRIORITY, parts["priority"])
+}
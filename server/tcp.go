@@ -0,0 +1,126 @@
+package server
+
+import (
+	"io"
+	"net"
+	"time"
+)
+
+// Listens for stream connections on the given TCP address.
+// Messages are served in the background until Close() is called.
+func (s *Server) ListenTCP(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	s.serveInBackground(func() error {
+		return s.ServeListener(l)
+	})
+
+	return nil
+}
+
+// Accepts connections on l and serves each of them in its own goroutine
+// until l is closed. Framing (octet counting or LF delimited) is detected
+// for every connection.
+// Returns nil when the server was closed.
+func (s *Server) ServeListener(l net.Listener) error {
+	return s.serveListener(l, parse, false)
+}
+
+// Messages are also delimited by NUL when nulDelimited is set
+func (s *Server) serveListener(l net.Listener, parse parseFunc, nulDelimited bool) error {
+	if err := s.track(l); err != nil {
+		return err
+	}
+
+	defer s.untrack(l)
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if s.isClosed() {
+				return nil
+			}
+
+			return err
+		}
+
+		if !s.acquireConn(conn) {
+			conn.Close()
+			continue
+		}
+
+		s.wg.Add(1)
+
+		go func() {
+			defer s.wg.Done()
+			defer s.releaseConn(conn)
+
+			s.serveConn(conn, parse, nulDelimited)
+		}()
+	}
+}
+
+func (s *Server) serveConn(conn net.Conn, parse parseFunc, nulDelimited bool) {
+	f := newFramer(conn, s.maxFrameLen)
+	f.nulDelimited = nulDelimited
+	addr := conn.RemoteAddr()
+
+	emit := func(frame []byte) {
+		s.handle(frame, addr, parse)
+	}
+
+	if s.multiline != nil {
+		a := newAggregator(s.multiline, s.maxFrameLen, emit)
+		defer a.close()
+
+		emit = a.add
+	}
+
+	for {
+		if s.readTimeout > 0 {
+			conn.SetReadDeadline(time.Now().Add(s.readTimeout))
+		}
+
+		frame, err := f.next()
+		if err != nil {
+			if err == ErrFrameTooLong || err == ErrInvalidFrame {
+				s.handler(nil, addr, err)
+			}
+
+			return
+		}
+
+		emit(frame)
+	}
+}
+
+func (s *Server) acquireConn(conn net.Conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return false
+	}
+
+	if s.maxConnections > 0 && s.conns >= s.maxConnections {
+		return false
+	}
+
+	s.conns++
+	s.closers[conn] = struct{}{}
+
+	return true
+}
+
+func (s *Server) releaseConn(conn io.Closer) {
+	conn.Close()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.conns--
+	delete(s.closers, conn)
+}
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestListener(t *testing.T, s *Server) (net.Listener, chan error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+
+	done := make(chan error)
+	go func() {
+		done <- s.ServeListener(l)
+	}()
+
+	return l, done
+}
+
+func TestServeListener(t *testing.T) {
+	s, c := newTestServer()
+	l, done := newTestListener(t, s)
+
+	octetCounted, err := net.Dial("tcp", l.Addr().String())
+	require.Nil(t, err)
+	defer octetCounted.Close()
+
+	msg := "<34>Oct 11 22:14:15 mymachine su: 'su root' failed for lonvick on /dev/pts/8"
+	frame := strconv.Itoa(len(msg)) + " " + msg
+	_, err = octetCounted.Write([]byte(frame + frame))
+	require.Nil(t, err)
+
+	for i := 0; i < 2; i++ {
+		r := receive(t, c)
+		require.Nil(t, r.err)
+		require.Equal(t, "su", r.parts["tag"])
+		require.Equal(t, octetCounted.LocalAddr().String(), r.source.String())
+	}
+
+	delimited, err := net.Dial("tcp", l.Addr().String())
+	require.Nil(t, err)
+	defer delimited.Close()
+
+	_, err = delimited.Write([]byte(
+		"<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 - An application event log entry...\r\n",
+	))
+	require.Nil(t, err)
+
+	r := receive(t, c)
+	require.Nil(t, r.err)
+	require.Equal(t, "evntslog", r.parts["app_name"])
+
+	require.Nil(t, s.Close())
+	require.Nil(t, <-done)
+}
+
+func TestServeListenerLimits(t *testing.T) {
+	s, c := newTestServer()
+	s.WithMaxMessageLen(64)
+	s.WithMaxConnections(1)
+	s.WithReadTimeout(100 * time.Millisecond)
+
+	l, done := newTestListener(t, s)
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	require.Nil(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte("<34>Oct 11 22:14:15 mymachine su: ok\n"))
+	require.Nil(t, err)
+
+	r := receive(t, c)
+	require.Nil(t, r.err)
+
+	// over the connection limit
+	extra, err := net.Dial("tcp", l.Addr().String())
+	require.Nil(t, err)
+	defer extra.Close()
+
+	extra.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, err = extra.Read(make([]byte, 1))
+	require.NotNil(t, err)
+
+	// idle connection is closed after the read timeout
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, err = conn.Read(make([]byte, 1))
+	require.NotNil(t, err)
+
+	tooLong, err := net.Dial("tcp", l.Addr().String())
+	require.Nil(t, err)
+	defer tooLong.Close()
+
+	_, err = tooLong.Write([]byte("100 <34>Oct 11 22:14:15 mymachine su: too long"))
+	require.Nil(t, err)
+
+	r = receive(t, c)
+	require.Nil(t, r.parts)
+	require.Equal(t, ErrFrameTooLong, r.err)
+
+	require.Nil(t, s.Close())
+	require.Nil(t, <-done)
+}
@@ -0,0 +1,65 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io/ioutil"
+)
+
+var (
+	ErrNoCACertificate = errors.New("No CA certificate found")
+)
+
+// Listens for TLS connections on the given TCP address as described in
+// https://tools.ietf.org/html/rfc5425. Messages are served in the background
+// until Close() is called.
+// Octet counting framing, mandated by RFC5425, is expected but LF delimited
+// messages are also accepted as with ListenTCP().
+func (s *Server) ListenTLS(addr string, config *tls.Config) error {
+	l, err := tls.Listen("tcp", addr, config)
+	if err != nil {
+		return err
+	}
+
+	s.serveInBackground(func() error {
+		return s.ServeListener(l)
+	})
+
+	return nil
+}
+
+// Builds a server side TLS configuration from PEM encoded files.
+// When clientCAFile is not empty client certificates are verified against
+// the CAs it contains according to clientAuth
+// (ie. tls.RequireAndVerifyClientCert).
+func NewTLSConfig(certFile string, keyFile string, clientCAFile string, clientAuth tls.ClientAuthType) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+		ClientAuth:   clientAuth,
+	}
+
+	if clientCAFile == "" {
+		return config, nil
+	}
+
+	pem, err := ioutil.ReadFile(clientCAFile)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, ErrNoCACertificate
+	}
+
+	config.ClientCAs = pool
+
+	return config, nil
+}
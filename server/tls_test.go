@@ -0,0 +1,176 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	der  []byte
+}
+
+func newTestCert(t *testing.T, cn string, parent *testCert) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage: []x509.ExtKeyUsage{
+			x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth,
+		},
+	}
+
+	signer, signerKey := tmpl, key
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+		tmpl.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	require.Nil(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.Nil(t, err)
+
+	return &testCert{cert: cert, key: key, der: der}
+}
+
+func (c *testCert) write(t *testing.T, dir string, name string) (string, string) {
+	certFile := filepath.Join(dir, name+".crt")
+	keyFile := filepath.Join(dir, name+".key")
+
+	err := ioutil.WriteFile(
+		certFile,
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.der}),
+		0600,
+	)
+	require.Nil(t, err)
+
+	k, err := x509.MarshalECPrivateKey(c.key)
+	require.Nil(t, err)
+
+	err = ioutil.WriteFile(
+		keyFile,
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: k}),
+		0600,
+	)
+	require.Nil(t, err)
+
+	return certFile, keyFile
+}
+
+func (c *testCert) tls() tls.Certificate {
+	return tls.Certificate{
+		Certificate: [][]byte{c.der},
+		PrivateKey:  c.key,
+	}
+}
+
+func TestListenTLS(t *testing.T) {
+	dir, err := ioutil.TempDir("", "syslogparser")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	ca := newTestCert(t, "ca", nil)
+	srv := newTestCert(t, "server", ca)
+	client := newTestCert(t, "client", ca)
+
+	caFile, _ := ca.write(t, dir, "ca")
+	certFile, keyFile := srv.write(t, dir, "server")
+
+	config, err := NewTLSConfig(certFile, keyFile, caFile, tls.RequireAndVerifyClientCert)
+	require.Nil(t, err)
+
+	s, c := newTestServer()
+
+	l, err := tls.Listen("tcp", "127.0.0.1:0", config)
+	require.Nil(t, err)
+
+	done := make(chan error)
+	go func() {
+		done <- s.ServeListener(l)
+	}()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	conn, err := tls.Dial("tcp", l.Addr().String(), &tls.Config{
+		RootCAs:      roots,
+		Certificates: []tls.Certificate{client.tls()},
+	})
+	require.Nil(t, err)
+	defer conn.Close()
+
+	msg := "<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 - An application event log entry..."
+	_, err = conn.Write([]byte(strconv.Itoa(len(msg)) + " " + msg))
+	require.Nil(t, err)
+
+	r := receive(t, c)
+	require.Nil(t, r.err)
+	require.Equal(t, "evntslog", r.parts["app_name"])
+
+	// no client certificate
+	anonymous, err := tls.Dial("tcp", l.Addr().String(), &tls.Config{
+		RootCAs: roots,
+	})
+
+	if err == nil {
+		defer anonymous.Close()
+
+		_, err = anonymous.Write([]byte(strconv.Itoa(len(msg)) + " " + msg))
+		if err == nil {
+			anonymous.SetReadDeadline(time.Now().Add(5 * time.Second))
+			_, err = anonymous.Read(make([]byte, 1))
+		}
+	}
+
+	require.NotNil(t, err)
+
+	require.Nil(t, s.Close())
+	require.Nil(t, <-done)
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "syslogparser")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	ca := newTestCert(t, "ca", nil)
+	srv := newTestCert(t, "server", ca)
+	certFile, keyFile := srv.write(t, dir, "server")
+
+	config, err := NewTLSConfig(certFile, keyFile, "", tls.NoClientCert)
+	require.Nil(t, err)
+	require.Len(t, config.Certificates, 1)
+	require.Nil(t, config.ClientCAs)
+
+	_, err = NewTLSConfig(certFile, keyFile, keyFile, tls.RequireAndVerifyClientCert)
+	require.Equal(t, ErrNoCACertificate, err)
+
+	_, err = NewTLSConfig(filepath.Join(dir, "missing"), keyFile, "", tls.NoClientCert)
+	require.NotNil(t, err)
+}
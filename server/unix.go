@@ -0,0 +1,140 @@
+package server
+
+import (
+	"errors"
+	"net"
+	"os"
+	"syscall"
+
+	"github.com/jeromer/syslogparser"
+	"github.com/jeromer/syslogparser/rfc3164"
+)
+
+// Listens for local datagrams on the given unix socket path (ie. /dev/log).
+// Messages are served in the background until Close() is called.
+// See ServeLocalPacketConn() for the expected format.
+func (s *Server) ListenUnixgram(path string) error {
+	err := removeStaleSocket(path)
+	if err != nil {
+		return err
+	}
+
+	conn, err := net.ListenPacket("unixgram", path)
+	if err != nil {
+		return err
+	}
+
+	err = s.chmodSocket(path)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	s.serveInBackground(func() error {
+		return s.ServeLocalPacketConn(conn)
+	})
+
+	return nil
+}
+
+// Listens for local stream connections on the given unix socket path.
+// Messages are served in the background until Close() is called.
+// See ServeLocalPacketConn() for the expected format.
+func (s *Server) ListenUnix(path string) error {
+	err := removeStaleSocket(path)
+	if err != nil {
+		return err
+	}
+
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return err
+	}
+
+	err = s.chmodSocket(path)
+	if err != nil {
+		l.Close()
+		return err
+	}
+
+	s.serveInBackground(func() error {
+		return s.ServeLocalListener(l)
+	})
+
+	return nil
+}
+
+// Same as ServePacketConn() for messages written by local processes, as with
+// syslog(3), using the BSD format without hostname:
+// <PRI>Mmm dd hh:mm:ss tag[pid]: message
+// Messages are parsed as RFC3164 and the hostname is set to the one given to
+// WithLocalHostname(), os.Hostname() by default.
+// Unix sockets senders are usually unnamed, the source given to the handler
+// may then be nil or empty.
+func (s *Server) ServeLocalPacketConn(conn net.PacketConn) error {
+	return s.servePacketConn(conn, s.parseLocal)
+}
+
+// Same as ServeLocalPacketConn() for stream connections. Messages are
+// delimited by LF or NUL, glibc syslog(3) terminating them with NUL only.
+func (s *Server) ServeLocalListener(l net.Listener) error {
+	return s.serveListener(l, s.parseLocal, true)
+}
+
+func (s *Server) parseLocal(cfg *Config, buff []byte) (syslogparser.LogParts, error) {
+	p := rfc3164.NewParser(buff)
+	p.WithLocal()
+	cfg.configure(p)
+	p.WithHostname(s.localHostname)
+
+	err := p.Parse()
+	if err != nil {
+		return nil, err
+	}
+
+	return p.Dump(), nil
+}
+
+func removeStaleSocket(path string) error {
+	fi, err := os.Lstat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	if fi.Mode()&os.ModeSocket == 0 {
+		return &os.PathError{Op: "listen", Path: path, Err: os.ErrExist}
+	}
+
+	// the socket may belong to a running daemon, ie. journald on /dev/log,
+	// it is only stale when nobody listens on it any more
+	for _, network := range []string{"unix", "unixgram"} {
+		conn, err := net.Dial(network, path)
+		if err == nil {
+			conn.Close()
+			return &os.PathError{Op: "listen", Path: path, Err: syscall.EADDRINUSE}
+		}
+
+		if errors.Is(err, syscall.ECONNREFUSED) {
+			return os.Remove(path)
+		}
+
+		// socket of the other type
+		if !errors.Is(err, syscall.EPROTOTYPE) {
+			return err
+		}
+	}
+
+	return &os.PathError{Op: "listen", Path: path, Err: syscall.EADDRINUSE}
+}
+
+func (s *Server) chmodSocket(path string) error {
+	if s.socketMode == 0 {
+		return nil
+	}
+
+	return os.Chmod(path, s.socketMode)
+}
@@ -0,0 +1,192 @@
+package server
+
+import (
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListenUnixgram(t *testing.T) {
+	dir, err := ioutil.TempDir("", "syslogparser")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "log")
+
+	s, c := newTestServer()
+	s.WithLocalHostname("localhost")
+
+	err = s.ListenUnixgram(path)
+	require.Nil(t, err)
+
+	conn, err := net.Dial("unixgram", path)
+	require.Nil(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte("<13>Oct 11 22:14:15 myapp[42]: started"))
+	require.Nil(t, err)
+
+	r := receive(t, c)
+	require.Nil(t, r.err)
+	require.Equal(t, "localhost", r.parts["hostname"])
+	require.Equal(t, "myapp", r.parts["tag"])
+	require.Equal(t, "started", r.parts["content"])
+
+	require.Nil(t, s.Close())
+}
+
+func TestListenUnix(t *testing.T) {
+	dir, err := ioutil.TempDir("", "syslogparser")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "log")
+
+	// stale socket left by a previous run
+	stale, err := net.Listen("unix", path)
+	require.Nil(t, err)
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+
+	s, c := newTestServer()
+	s.WithLocalHostname("localhost")
+
+	err = s.ListenUnix(path)
+	require.Nil(t, err)
+
+	conn, err := net.Dial("unix", path)
+	require.Nil(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte("<13>Oct 11 22:14:15 myapp: started\n<13>Oct 11 22:14:16 myapp: stopped\n"))
+	require.Nil(t, err)
+
+	for _, expected := range []string{"started", "stopped"} {
+		r := receive(t, c)
+		require.Nil(t, r.err)
+		require.Equal(t, "localhost", r.parts["hostname"])
+		require.Equal(t, "myapp", r.parts["tag"])
+		require.Equal(t, expected, r.parts["content"])
+	}
+
+	require.Nil(t, s.Close())
+}
+
+func TestListenUnixNULDelimited(t *testing.T) {
+	dir, err := ioutil.TempDir("", "syslogparser")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "log")
+
+	s, c := newTestServer()
+	s.WithLocalHostname("localhost")
+
+	err = s.ListenUnix(path)
+	require.Nil(t, err)
+
+	conn, err := net.Dial("unix", path)
+	require.Nil(t, err)
+	defer conn.Close()
+
+	// as sent by glibc syslog(3) over SOCK_STREAM
+	expected := []string{"started", "running", "stopped"}
+	for _, msg := range expected {
+		_, err = conn.Write([]byte("<13>Oct 11 22:14:15 tag: " + msg + "\x00"))
+		require.Nil(t, err)
+	}
+
+	for _, msg := range expected {
+		r := receive(t, c)
+		require.Nil(t, r.err)
+		require.Equal(t, "tag", r.parts["tag"])
+		require.Equal(t, msg, r.parts["content"])
+	}
+
+	require.Nil(t, s.Close())
+}
+
+func TestListenUnixLiveSocket(t *testing.T) {
+	dir, err := ioutil.TempDir("", "syslogparser")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "log")
+
+	// socket of another running daemon
+	live, err := net.ListenPacket("unixgram", path)
+	require.Nil(t, err)
+	defer live.Close()
+
+	s, _ := newTestServer()
+
+	err = s.ListenUnix(path)
+	require.True(t, errors.Is(err, syscall.EADDRINUSE))
+
+	err = s.ListenUnixgram(path)
+	require.True(t, errors.Is(err, syscall.EADDRINUSE))
+
+	_, err = os.Stat(path)
+	require.Nil(t, err)
+}
+
+func TestListenUnixgramStaleSocket(t *testing.T) {
+	dir, err := ioutil.TempDir("", "syslogparser")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "log")
+
+	stale, err := net.ListenPacket("unixgram", path)
+	require.Nil(t, err)
+	stale.Close()
+
+	s, _ := newTestServer()
+
+	require.Nil(t, s.ListenUnixgram(path))
+	require.Nil(t, s.Close())
+}
+
+func TestListenUnixSocketMode(t *testing.T) {
+	dir, err := ioutil.TempDir("", "syslogparser")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "log")
+
+	s, _ := newTestServer()
+	s.WithSocketMode(0600)
+
+	err = s.ListenUnixgram(path)
+	require.Nil(t, err)
+
+	fi, err := os.Stat(path)
+	require.Nil(t, err)
+	require.Equal(t, os.FileMode(0600), fi.Mode().Perm())
+
+	require.Nil(t, s.Close())
+}
+
+func TestListenUnixNotASocket(t *testing.T) {
+	dir, err := ioutil.TempDir("", "syslogparser")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "log")
+	err = ioutil.WriteFile(path, nil, 0600)
+	require.Nil(t, err)
+
+	s, _ := newTestServer()
+
+	require.NotNil(t, s.ListenUnixgram(path))
+	require.NotNil(t, s.ListenUnix(path))
+
+	_, err = os.Stat(path)
+	require.Nil(t, err)
+}
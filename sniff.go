@@ -0,0 +1,175 @@
+package syslogparser
+
+import (
+	"github.com/jeromer/syslogparser/parsercommon"
+)
+
+var (
+	ErrImplausibleHeader = &parsercommon.ParserError{ErrorString: "Implausible header"}
+)
+
+// What Sniff() tells about a buffer
+type Probe struct {
+	RFC      RFC
+	Priority parsercommon.Priority
+
+	// offset of the byte following the header: the SP before STRUCTURED-DATA
+	// for RFC 5424, the one before TAG for RFC 3164
+	HeaderEnd int
+}
+
+// Cheaply checks buff looks like a syslog message, ie. on ports receiving
+// mixed traffic, without allocating: PRI must be present and the header
+// plausible. RFC 5424 headers are VERSION followed by five words, the
+// timestamp being NILVALUE or starting with a year. RFC 3164 ones are a
+// "Jan _2 15:04:05" timestamp, followed by a word taken as the hostname if
+// any.
+// Priority errors are returned as is, ErrImplausibleHeader otherwise.
+func Sniff(buff []byte) (Probe, error) {
+	var probe Probe
+
+	c := parsercommon.NewCursor(buff, len(buff))
+
+	pri, err := c.ParsePriorityValue()
+	if err != nil {
+		return probe, err
+	}
+
+	probe.Priority = pri
+
+	from := c.Pos()
+
+	if end, ok := sniff5424(buff, from); ok {
+		probe.RFC = RFC_5424
+		probe.HeaderEnd = end
+		return probe, nil
+	}
+
+	if end, ok := sniff3164(buff, from); ok {
+		probe.RFC = RFC_3164
+		probe.HeaderEnd = end
+		return probe, nil
+	}
+
+	return probe, ErrImplausibleHeader
+}
+
+// Same as Sniff() only telling whether buff looks like a syslog message
+func Valid(buff []byte) bool {
+	_, err := Sniff(buff)
+
+	return err == nil
+}
+
+// VERSION SP TIMESTAMP SP HOSTNAME SP APP-NAME SP PROCID SP MSGID
+func sniff5424(buff []byte, from int) (int, bool) {
+	i := from
+
+	for i < len(buff) && i-from < 3 && parsercommon.IsDigit(buff[i]) {
+		i++
+	}
+
+	if i == from || buff[from] == '0' || i >= len(buff) || buff[i] != ' ' {
+		return 0, false
+	}
+
+	i++
+
+	if !isTimestamp5424(buff[i:]) {
+		return 0, false
+	}
+
+	// TIMESTAMP, HOSTNAME, APP-NAME, PROCID and MSGID
+	maxLens := [...]int{32, 255, 48, 128, 32}
+
+	for n, maxLen := range maxLens {
+		start := i
+
+		for i < len(buff) && buff[i] != ' ' {
+			i++
+		}
+
+		if i == start || i-start > maxLen {
+			return 0, false
+		}
+
+		if n < len(maxLens)-1 {
+			if i >= len(buff) {
+				return 0, false
+			}
+
+			i++
+		}
+	}
+
+	return i, true
+}
+
+// NILVALUE or FULL-DATE, ie. "2003-"
+func isTimestamp5424(b []byte) bool {
+	if len(b) > 0 && b[0] == parsercommon.NILVALUE {
+		return len(b) == 1 || b[1] == ' '
+	}
+
+	if len(b) < 5 || b[4] != '-' {
+		return false
+	}
+
+	for _, d := range b[:4] {
+		if !parsercommon.IsDigit(d) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// TIMESTAMP [SP HOSTNAME]
+func sniff3164(buff []byte, from int) (int, bool) {
+	i := from
+
+	if i < len(buff) && buff[i] == ' ' {
+		i++
+	}
+
+	if !isTimestamp3164(buff[i:]) {
+		return 0, false
+	}
+
+	i += len("Jan _2 15:04:05")
+
+	if i+1 >= len(buff) || buff[i] != ' ' || buff[i+1] == ' ' {
+		return i, true
+	}
+
+	i++
+
+	for i < len(buff) && buff[i] != ' ' {
+		i++
+	}
+
+	return i, true
+}
+
+// "Jan _2 15:04:05"
+func isTimestamp3164(b []byte) bool {
+	if len(b) < len("Jan _2 15:04:05") || !parsercommon.StartsWithMonth(b) {
+		return false
+	}
+
+	if b[4] != ' ' && !parsercommon.IsDigit(b[4]) {
+		return false
+	}
+
+	pattern := "0 00:00:00"
+
+	for i := 0; i < len(pattern); i++ {
+		d := b[5+i]
+
+		if (pattern[i] == '0' && !parsercommon.IsDigit(d)) || (pattern[i] != '0' && d != pattern[i]) {
+			return false
+		}
+	}
+
+	return true
+}
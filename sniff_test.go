@@ -0,0 +1,135 @@
+package syslogparser
+
+import (
+	"testing"
+
+	"github.com/jeromer/syslogparser/parsercommon"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSniff(t *testing.T) {
+	testCases := []struct {
+		description       string
+		input             string
+		expectedRFC       RFC
+		expectedPri       int
+		expectedHeaderEnd int
+		expectedErr       error
+	}{
+		{
+			description:       "RFC 5424",
+			input:             `<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 [exampleSDID@32473 iut="3"] hello`,
+			expectedRFC:       RFC_5424,
+			expectedPri:       165,
+			expectedHeaderEnd: 69,
+		},
+		{
+			description:       "RFC 5424 nil values",
+			input:             `<165>1 - - - - - -`,
+			expectedRFC:       RFC_5424,
+			expectedPri:       165,
+			expectedHeaderEnd: 16,
+		},
+		{
+			description:       "RFC 5424 header only",
+			input:             `<165>1 - - - - -`,
+			expectedRFC:       RFC_5424,
+			expectedPri:       165,
+			expectedHeaderEnd: 16,
+		},
+		{
+			description:       "RFC 3164",
+			input:             `<34>Oct 11 22:14:15 mymachine su: 'su root' failed`,
+			expectedRFC:       RFC_3164,
+			expectedPri:       34,
+			expectedHeaderEnd: 29,
+		},
+		{
+			description:       "RFC 3164 single digit day",
+			input:             `<34>Oct  1 22:14:15 mymachine su: 'su root' failed`,
+			expectedRFC:       RFC_3164,
+			expectedPri:       34,
+			expectedHeaderEnd: 29,
+		},
+		{
+			description:       "RFC 3164 timestamp only",
+			input:             `<34>Oct 11 22:14:15`,
+			expectedRFC:       RFC_3164,
+			expectedPri:       34,
+			expectedHeaderEnd: 19,
+		},
+		{
+			description: "no priority",
+			input:       `GET / HTTP/1.1`,
+			expectedErr: parsercommon.ErrPriorityNoStart,
+		},
+		{
+			description: "RFC 5424 missing MSGID",
+			input:       `<165>1 2003-10-11T22:14:15.003Z mymachine evntslog -`,
+			expectedPri: 165,
+			expectedErr: ErrImplausibleHeader,
+		},
+		{
+			description: "RFC 5424 invalid timestamp",
+			input:       `<165>1 Oct 11 22:14:15 mymachine evntslog - ID47 - hello`,
+			expectedPri: 165,
+			expectedErr: ErrImplausibleHeader,
+		},
+		{
+			description: "RFC 3164 invalid timestamp",
+			input:       `<34>Oct 11 22:14 mymachine su: hello`,
+			expectedPri: 34,
+			expectedErr: ErrImplausibleHeader,
+		},
+		{
+			description: "garbage after priority",
+			input:       `<34>hello world`,
+			expectedPri: 34,
+			expectedErr: ErrImplausibleHeader,
+		},
+	}
+
+	for _, tc := range testCases {
+		probe, err := Sniff([]byte(tc.input))
+
+		require.Equal(t, tc.expectedErr, err, tc.description)
+		require.Equal(t, tc.expectedErr == nil, Valid([]byte(tc.input)), tc.description)
+		require.Equal(t, tc.expectedRFC, probe.RFC, tc.description)
+		require.Equal(t, tc.expectedHeaderEnd, probe.HeaderEnd, tc.description)
+
+		if tc.expectedPri > 0 {
+			require.Equal(t, *parsercommon.NewPriority(tc.expectedPri), probe.Priority, tc.description)
+		}
+	}
+}
+
+func TestSniffAllocs(t *testing.T) {
+	inputs := [][]byte{
+		[]byte(`<165>1 2003-10-11T22:14:15.003Z mymachine evntslog - ID47 - hello`),
+		[]byte(`<34>Oct 11 22:14:15 mymachine su: 'su root' failed`),
+		[]byte(`<34>hello world`),
+	}
+
+	for _, buff := range inputs {
+		allocs := testing.AllocsPerRun(100, func() {
+			_, _ = Sniff(buff)
+		})
+
+		require.Equal(t, float64(0), allocs, string(buff))
+	}
+}
+
+func BenchmarkSniff(b *testing.B) {
+	buff := []byte(
+		`<165>1 2003-10-11T22:14:15.003Z mymachine evntslog - ID47 - hello`,
+	)
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		_, err := Sniff(buff)
+		if err != nil {
+			panic(err)
+		}
+	}
+}
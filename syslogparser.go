@@ -16,7 +16,12 @@ const (
 	RFC_5424
 )
 
-type LogParts map[string]interface{}
+var (
+	ErrUnknownRFC     = &parsercommon.ParserError{ErrorString: "Unknown RFC"}
+	ErrBufferTooShort = &parsercommon.ParserError{ErrorString: "Buffer too short"}
+)
+
+type LogParts = parsercommon.LogParts
 
 type LogParser interface {
 	Parse() error
@@ -27,14 +32,35 @@ type LogParser interface {
 	WithTag(string)
 }
 
+// Tells which RFC buff is formatted with from its first bytes.
+// ErrBufferTooShort is returned when buff ends before the byte following the
+// priority, buff being untrusted it is never read past its end.
+// Messages without PRI are recognized when they start with a version (RFC
+// 5424) or a month (RFC 3164), parse them using WithDefaultPriority().
 func DetectRFC(buff []byte) (RFC, error) {
 	max := 10
 	var v int
 	var err error
 
+	if len(buff) > 0 && buff[0] != '<' {
+		return detectWithoutPriority(buff)
+	}
+
+	short := len(buff) < max
+	if short {
+		max = len(buff)
+	}
+
+	found := false
+
 	for i := 0; i < max; i++ {
-		if buff[i] == '>' && i < max {
+		if buff[i] == '>' {
 			x := i + 1
+			found = true
+
+			if x >= len(buff) {
+				return RFC_UNKNOWN, ErrBufferTooShort
+			}
 
 			v, err = parsercommon.ParseVersion(
 				buff, &x, max,
@@ -44,6 +70,10 @@ func DetectRFC(buff []byte) (RFC, error) {
 		}
 	}
 
+	if !found && short {
+		return RFC_UNKNOWN, ErrBufferTooShort
+	}
+
 	if err != nil {
 		return RFC_UNKNOWN, err
 	}
@@ -54,3 +84,29 @@ func DetectRFC(buff []byte) (RFC, error) {
 
 	return RFC_5424, nil
 }
+
+// Parses only the "<PRI>" buff starts with, without allocating, so
+// high-volume receivers can drop messages, ie. debug ones, before paying for
+// a full parse. Errors are the ones of parsercommon.Cursor.ParsePriority().
+func PeekPriority(buff []byte) (parsercommon.Priority, error) {
+	c := parsercommon.NewCursor(buff, len(buff))
+
+	return c.ParsePriorityValue()
+}
+
+// "1 2003-10-11T22:14:15.003Z ..." or "Oct 11 22:14:15 ..."
+func detectWithoutPriority(buff []byte) (RFC, error) {
+	if len(buff) >= 2 && parsercommon.IsDigit(buff[0]) && buff[1] == ' ' {
+		return RFC_5424, nil
+	}
+
+	if len(buff) < 4 {
+		return RFC_UNKNOWN, ErrBufferTooShort
+	}
+
+	if parsercommon.StartsWithMonth(buff) {
+		return RFC_3164, nil
+	}
+
+	return RFC_UNKNOWN, ErrUnknownRFC
+}
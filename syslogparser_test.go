@@ -3,6 +3,7 @@ package syslogparser
 import (
 	"testing"
 
+	"github.com/jeromer/syslogparser/parsercommon"
 	"github.com/stretchr/testify/require"
 )
 
@@ -28,6 +29,183 @@ func TestDetectRFC_5424(t *testing.T) {
 	require.Equal(t, p, RFC(RFC_5424))
 }
 
+func TestDetectRFCShortBuffer(t *testing.T) {
+	testCases := []struct {
+		description string
+		input       []byte
+		expectedRFC RFC
+		expectedErr error
+	}{
+		{
+			description: "nil",
+			input:       nil,
+			expectedRFC: RFC_UNKNOWN,
+			expectedErr: ErrBufferTooShort,
+		},
+		{
+			description: "empty",
+			input:       []byte{},
+			expectedRFC: RFC_UNKNOWN,
+			expectedErr: ErrBufferTooShort,
+		},
+		{
+			description: "priority only",
+			input:       []byte("<34>"),
+			expectedRFC: RFC_UNKNOWN,
+			expectedErr: ErrBufferTooShort,
+		},
+		{
+			description: "truncated priority",
+			input:       []byte("<3"),
+			expectedRFC: RFC_UNKNOWN,
+			expectedErr: ErrBufferTooShort,
+		},
+		{
+			description: "short rfc3164",
+			input:       []byte("<34>a"),
+			expectedRFC: RFC_3164,
+			expectedErr: nil,
+		},
+		{
+			description: "short rfc5424",
+			input:       []byte("<34>1"),
+			expectedRFC: RFC_5424,
+			expectedErr: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		obtained, err := DetectRFC(tc.input)
+
+		require.Equal(t, tc.expectedErr, err, tc.description)
+		require.Equal(t, tc.expectedRFC, obtained, tc.description)
+	}
+}
+
+func TestDetectRFCWithoutPriority(t *testing.T) {
+	testCases := []struct {
+		description string
+		input       []byte
+		expectedRFC RFC
+		expectedErr error
+	}{
+		{
+			description: "rfc3164",
+			input:       []byte("Oct 11 22:14:15 mymachine su: 'su root' failed"),
+			expectedRFC: RFC_3164,
+			expectedErr: nil,
+		},
+		{
+			description: "rfc5424",
+			input:       []byte("1 2003-10-11T22:14:15.003Z mymachine.example.com su - ID47 - msg"),
+			expectedRFC: RFC_5424,
+			expectedErr: nil,
+		},
+		{
+			description: "short",
+			input:       []byte("Oct"),
+			expectedRFC: RFC_UNKNOWN,
+			expectedErr: ErrBufferTooShort,
+		},
+		{
+			description: "not a month",
+			input:       []byte("Foo 11 22:14:15 mymachine su: msg"),
+			expectedRFC: RFC_UNKNOWN,
+			expectedErr: ErrUnknownRFC,
+		},
+		{
+			description: "garbage",
+			input:       []byte("hello world"),
+			expectedRFC: RFC_UNKNOWN,
+			expectedErr: ErrUnknownRFC,
+		},
+	}
+
+	for _, tc := range testCases {
+		obtained, err := DetectRFC(tc.input)
+
+		require.Equal(t, tc.expectedErr, err, tc.description)
+		require.Equal(t, tc.expectedRFC, obtained, tc.description)
+	}
+}
+
+func TestPeekPriority(t *testing.T) {
+	testCases := []struct {
+		description string
+		input       []byte
+		expectedPri parsercommon.Priority
+		expectedErr error
+	}{
+		{
+			description: "RFC 3164",
+			input:       []byte("<34>Oct 11 22:14:15 ..."),
+			expectedPri: *parsercommon.NewPriority(34),
+		},
+		{
+			description: "RFC 5424",
+			input:       []byte("<165>1 2003-10-11T22:14:15.003Z ..."),
+			expectedPri: *parsercommon.NewPriority(165),
+		},
+		{
+			description: "priority only",
+			input:       []byte("<7>"),
+			expectedPri: *parsercommon.NewPriority(7),
+		},
+		{
+			description: "empty",
+			input:       []byte{},
+			expectedErr: parsercommon.ErrPriorityEmpty,
+		},
+		{
+			description: "no priority",
+			input:       []byte("Oct 11 22:14:15 ..."),
+			expectedErr: parsercommon.ErrPriorityNoStart,
+		},
+		{
+			description: "unterminated",
+			input:       []byte("<34"),
+			expectedErr: parsercommon.ErrPriorityNoEnd,
+		},
+		{
+			description: "above 191",
+			input:       []byte("<192>Oct 11 22:14:15 ..."),
+			expectedErr: parsercommon.ErrPriorityInvalid,
+		},
+	}
+
+	for _, tc := range testCases {
+		obtained, err := PeekPriority(tc.input)
+
+		require.Equal(t, tc.expectedErr, err, tc.description)
+		require.Equal(t, tc.expectedPri, obtained, tc.description)
+	}
+}
+
+func TestPeekPriorityAllocs(t *testing.T) {
+	buff := []byte("<165>1 2003-10-11T22:14:15.003Z ...")
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = PeekPriority(buff)
+	})
+
+	require.Equal(t, float64(0), allocs)
+}
+
+func BenchmarkPeekPriority(b *testing.B) {
+	buff := []byte(
+		"<165>1 2003-10-11T22:14:15.003Z ...",
+	)
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		_, err := PeekPriority(buff)
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
 func BenchmarkDetectRFC(b *testing.B) {
 	buff := []byte(
 		"<165>1 2003-10-11T22:14:15.003Z ...",